@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	"acsp/internal/config"
+	"acsp/internal/infrastructure/db"
+	"acsp/internal/repository"
+)
+
+const usage = `usage: app [command]
+
+Runs the server when no command is given.
+
+commands:
+  config                    print the effective configuration with the secrets redacted
+  keygen [-alg ALG]         generate a key signing the tokens (EdDSA by default) in ./keys
+  migrate up                apply all pending migrations
+  migrate down [-steps N]   revert the N latest migrations (1 by default)
+  migrate status            list the migrations and when they were applied
+  migrate baseline [-to N]  record the migrations up to N (1 by default) as applied without running them
+  migrate create NAME       create the scripts of a new migration in ./migrations
+  mock-idp [-addr ADDR]     serve a mock OpenID Connect provider for the local single sign-on
+  schema check              check that the tables and columns used by the code exist in the database
+  seed [-dir DIR]           load the development fixtures (./fixtures by default), existing rows are kept`
+
+// runCommand runs a command given in the arguments of the binary instead of the server
+func runCommand(ctx context.Context, args []string, p *config.ProviderChain) error {
+	switch args[0] {
+	case "config":
+		return runConfig(p)
+
+	case "keygen":
+		return runKeygen(args[1:])
+
+	case "migrate":
+		return runMigrate(ctx, args[1:], p)
+
+	case "seed":
+		return runSeed(ctx, args[1:], p)
+
+	case "mock-idp":
+		return runMockIDP(args[1:])
+
+	case "schema":
+		if len(args) < 2 || args[1] != "check" {
+			return errors.New(usage)
+		}
+
+		dbClient, closeDB, err := connectDB(ctx, p)
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		err = db.CheckSchema(ctx, dbClient, repository.Schema)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("database schema matches the code")
+
+		return nil
+
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args[0], usage)
+	}
+}
+
+// connectDB connects to the database of the configuration, the returned function closes the connection
+func connectDB(ctx context.Context, p config.Provider) (*sqlx.DB, func(), error) {
+	postgresConfig, err := db.LoadPostgresConfig(p)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error when loading database config")
+	}
+
+	dbCtx, cancel := context.WithCancel(ctx)
+
+	dbClient, err := db.NewDBClient(dbCtx, cancel, postgresConfig)
+	if err != nil {
+		cancel()
+
+		return nil, nil, err
+	}
+
+	return dbClient, func() {
+		_ = dbClient.Close()
+
+		cancel()
+	}, nil
+}
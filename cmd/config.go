@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"acsp/internal/config"
+	"acsp/internal/infrastructure/db"
+)
+
+const (
+	defaultEnvFile    = "base.env"
+	defaultConfigFile = "configs/config.yml"
+)
+
+// newConfigProvider chains the configuration sources from the highest precedence to the lowest: the environment
+// variables, the .env file (ENV_FILE, base.env by default) and the YAML file (CONFIG_FILE, configs/config.yml
+// by default). The default files are skipped when they don't exist.
+func newConfigProvider() (*config.ProviderChain, error) {
+	providers := []config.Provider{config.NewEnvProvider("")}
+
+	envFile, envFileSet := os.LookupEnv("ENV_FILE")
+	if !envFileSet {
+		envFile = defaultEnvFile
+	}
+
+	if envFileSet || fileExists(envFile) {
+		p, err := config.NewDotenvProvider(envFile)
+		if err != nil {
+			return nil, err
+		}
+
+		providers = append(providers, p)
+	}
+
+	configFile, configFileSet := os.LookupEnv("CONFIG_FILE")
+	if !configFileSet {
+		configFile = defaultConfigFile
+	}
+
+	if configFileSet || fileExists(configFile) {
+		p, err := config.NewYAMLProvider(configFile)
+		if err != nil {
+			return nil, err
+		}
+
+		providers = append(providers, p)
+	}
+
+	return config.NewProviderChain(providers...), nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+
+	return err == nil
+}
+
+// runConfig prints the effective configuration with the secrets redacted
+func runConfig(p *config.ProviderChain) error {
+	// Reading the configs of the databases too, so that all keys are listed
+	_, pErr := db.LoadPostgresConfig(p)
+	_, rErr := db.LoadRedisConfig(p)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+
+	for _, s := range p.Settings() {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Key, s.Value, s.Source)
+	}
+
+	err := w.Flush()
+	if err != nil {
+		return err
+	}
+
+	for _, err := range []error{pErr, rErr} {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"acsp/internal/jwks"
+)
+
+// runKeygen generates a key signing the tokens into the -dir directory, see JWT_SIGNING_KEY_ID
+func runKeygen(args []string) error {
+	flags := flag.NewFlagSet("keygen", flag.ContinueOnError)
+	algorithm := flags.String("alg", jwks.EdDSA, "algorithm of the key, RS256 or EdDSA")
+	dir := flags.String("dir", "./keys", "directory of the keys, see JWT_KEYS_DIR")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	b, err := jwks.Generate(*algorithm)
+	if err != nil {
+		return err
+	}
+
+	id, err := jwks.NewKeyID()
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(*dir, 0o700)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(*dir, id+".pem")
+
+	// The previous keys are kept, they verify the tokens signed before the rotation
+	err = os.WriteFile(path, b, 0o600)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("generated %s, set JWT_SIGNING_KEY_ID=%s to sign the tokens with it\n", path, id)
+
+	return nil
+}
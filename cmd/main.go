@@ -7,15 +7,14 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
-	"github.com/go-redis/redis/v9"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
-	"github.com/jmoiron/sqlx"
 
 	"go.uber.org/zap"
 
@@ -24,9 +23,14 @@ import (
 	"acsp/internal/handler"
 	"acsp/internal/infrastructure/db"
 	awsS3 "acsp/internal/infrastructure/s3"
+	"acsp/internal/jwks"
+	"acsp/internal/lifecycle"
 	"acsp/internal/logging"
+	"acsp/internal/metrics"
+	"acsp/internal/model"
 	"acsp/internal/repository"
 	"acsp/internal/service"
+	"acsp/internal/tracing"
 )
 
 // @title ACSP Backend
@@ -41,14 +45,13 @@ import (
 func main() {
 	fallbackLogger := log.New(os.Stderr, "ERROR ", log.Ldate|log.Ltime|log.Lshortfile|log.LUTC|log.Lmsgprefix)
 
-	baseProvider, err := config.NewDotenvProvider("base.env")
+	configProvider, err := newConfigProvider()
 	if err != nil {
 		fallbackLogger.Println("couldn't create config provider:", err)
 
 		return
 	}
 
-	configProvider := config.NewProviderChain(baseProvider)
 	appConfig, err := config.NewBaseConfig(configProvider)
 	if err != nil {
 		fallbackLogger.Println("couldn't create config:", err)
@@ -56,9 +59,13 @@ func main() {
 		return
 	}
 
+	// Initializing log level, it is changed by the runtime settings
+	logLevel := zap.NewAtomicLevelAt(appConfig.Logger.Level)
+
 	//	Initializing logger
 	appLogger, err := logging.NewBuilder().
 		WithFallbackLogger(fallbackLogger).
+		WithLevel(logLevel).
 		WithLoggerConfig(appConfig.Logger).
 		WithHostConfig(appConfig.Host).
 		NewLogger()
@@ -81,8 +88,32 @@ func main() {
 		appLogger = appLogger.With(zap.String("host", hostname))
 	}
 
+	// Running a command instead of the server, e.g. "migrate up"
+	if len(os.Args) > 1 {
+		err := runCommand(logging.ContextWithLogger(context.Background(), appLogger), os.Args[1:], configProvider)
+		if err != nil {
+			fallbackLogger.Println(err)
+
+			os.Exit(1)
+		}
+
+		return
+	}
+
 	appLogger.Info("starting")
 
+	// Initializing lifecycle manager, the started components are registered to it and stopped
+	// in the reverse order within SERVER_SHUTDOWNTIMEOUT
+	lifecycleManager := lifecycle.NewManager(time.Duration(appConfig.Host.ShutdownTimeout) * time.Second)
+
+	// Initializing tracer provider, the spans left are flushed on shutdown
+	shutdownTracing, err := tracing.NewProvider(context.Background(), *appConfig.Tracing, appConfig.Environment)
+	if err != nil {
+		appLogger.Fatal("Error occurred when initializing tracing: ", zap.Error(err))
+	}
+
+	lifecycleManager.Register("tracer", shutdownTracing)
+
 	// Initializing fiber config
 	fiberConfig := config.FiberConfig(appConfig)
 
@@ -98,17 +129,13 @@ func main() {
 		appLogger.Fatal("Error occurred when initializing database config: ", zap.Error(err))
 	}
 
-	appLogger.Info("Initializing S3 configuration")
-	s3Session, err := awsS3.NewSessionBuilder().
-		WithAWSConfig(appConfig.Bucket).
-		NewSession()
+	appLogger.Info("Initializing object storage", zap.String("driver", appConfig.Storage.Driver))
+	objectStorage, err := newObjectStorage(appConfig)
 	if err != nil {
-		appLogger.Fatal("Error occurred when initializing S3 session: ", zap.Error(err))
+		appLogger.Fatal("Error occurred when initializing object storage: ", zap.Error(err))
 	}
 
-	appLogger.Info("Creating new session")
-
-	// Initializing context with timeout and logger for graceful shutdown
+	// Initializing context with timeout and logger for the start-up
 	ctx, cancel := context.WithTimeout(context.Background(), constants.ContextTimeoutSeconds*time.Second)
 	ctx = logging.ContextWithLogger(ctx, appLogger)
 
@@ -119,14 +146,30 @@ func main() {
 	if err != nil {
 		appLogger.Fatal("Error when initializing Postgres Client", zap.Error(err))
 	}
-	defer func(dbClient *sqlx.DB) {
-		appLogger.Info("Closing database client")
 
-		err := dbClient.Close()
+	lifecycleManager.Register("postgres", func(context.Context) error {
+		return dbClient.Close()
+	})
+
+	// Applying the pending migrations, so that the schema matches the code
+	if postgresConfig.AutoMigrate {
+		appLogger.Info("Applying database migrations")
+
+		applied, err := migrateUp(logging.ContextWithLogger(context.Background(), appLogger), dbClient)
 		if err != nil {
-			appLogger.Fatal("Error when closing Postgres Client", zap.Error(err))
+			appLogger.Fatal("Error when applying database migrations", zap.Error(err))
 		}
-	}(dbClient)
+
+		appLogger.Info("Database migrations applied", zap.Int("count", len(applied)))
+	}
+
+	// Failing fast when the queries reference tables or columns the database doesn't have
+	if postgresConfig.SchemaCheck {
+		err := db.CheckSchema(context.Background(), dbClient, repository.Schema)
+		if err != nil {
+			appLogger.Fatal("Error when checking the database schema", zap.Error(err))
+		}
+	}
 
 	appLogger.Info("Initializing redis client")
 
@@ -136,14 +179,19 @@ func main() {
 		appLogger.Fatal("Error when initializing Redis Client", zap.Error(err))
 	}
 
-	defer func(redisClient *redis.Client) {
-		appLogger.Info("Closing redis client")
+	lifecycleManager.Register("redis", func(context.Context) error {
+		return redisClient.Close()
+	})
 
-		err := redisClient.Close()
-		if err != nil {
-			appLogger.Fatal("Error when closing Redis Client", zap.Error(err))
-		}
-	}(redisClient)
+	// Reporting the connection pool of the database and the latency of the redis commands,
+	// the redis commands are traced as well
+	err = metrics.RegisterDB(dbClient.DB, constants.DatabaseName)
+	if err != nil {
+		appLogger.Fatal("Error when registering database metrics", zap.Error(err))
+	}
+
+	redisClient.AddHook(metrics.RedisHook{})
+	redisClient.AddHook(tracing.RedisHook{})
 
 	// Initializing database engine with database client and redis client
 	dbEngine := db.NewDBEngine(dbClient, *redisClient)
@@ -153,15 +201,61 @@ func main() {
 	// Initializing fiber app with fiber config and logger
 	app := fiber.New(fiberConfig)
 
-	// Initializing built-in logger and recover middlewares
-	app.Use(logger.New())
+	// Initializing request logger, tracing, metrics and built-in recover middlewares, the request logger
+	// goes first so that it logs the status of the recovered panics
+	app.Use(handler.RequestLogger(appLogger))
+	// The client behind the proxy, so that the rate limits and the lockouts are per client rather than
+	// shared by everyone behind the proxy
+	app.Use(handler.ClientIP(appConfig.HTTP.ProxyHeader, appConfig.HTTP.TrustedProxies))
+	app.Use(handler.Tracing())
+	app.Use(handler.Metrics())
 	app.Use(recover.New(
 		recover.Config{
 			EnableStackTrace: true,
 		}))
-	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowMethods: "GET, POST, PUT, DELETE",
+
+	// Initializing builder of public URLs of the stored objects
+	urlBuilder, err := service.NewURLBuilder(appConfig)
+	if err != nil {
+		appLogger.Fatal("Error occurred when initializing URL builder: ", zap.Error(err))
+	}
+
+	// Initializing keys signing the tokens, the previous keys of the directory still verify them
+	signingKey := []byte(appConfig.Auth.JWT.SigningKey)
+	if f := appConfig.Auth.JWT.SigningKeyFile; f != "" {
+		signingKey, err = os.ReadFile(f)
+		if err != nil {
+			appLogger.Fatal("Error occurred when reading the token signing key: ", zap.Error(err))
+		}
+	}
+
+	tokenKeys, err := jwks.Load(appConfig.Auth.JWT.KeysDir, appConfig.Auth.JWT.SigningKeyID, signingKey)
+	if err != nil {
+		appLogger.Fatal("Error occurred when loading the token keys: ", zap.Error(err))
+	}
+
+	// Initializing app repository, service and handler
+	appRepository := repository.NewRepository(dbEngine.DB, &dbEngine.Cache, objectStorage)
+	appService := service.NewService(appRepository, &dbEngine.Cache, *appConfig.Auth, tokenKeys, *appConfig.Storage,
+		*appConfig.Outbox, *appConfig.Settings, *appConfig.Features, *appConfig.RateLimit, *appConfig.Health, urlBuilder)
+	appHandler := handler.NewHandler(appService)
+
+	// Applying the runtime settings, they are reloaded by the settings worker below
+	appService.Settings.OnChange(func(s model.RuntimeSettings) {
+		err := logLevel.UnmarshalText([]byte(s.LogLevel))
+		if err != nil {
+			appLogger.Error("Error when applying the log level", zap.String("level", s.LogLevel), zap.Error(err))
+		}
+	})
+
+	err = appService.Settings.Reload(ctx)
+	if err != nil {
+		appLogger.Error("Error when loading the runtime settings, using the config", zap.Error(err))
+	}
+
+	// Initializing CORS middleware, the allowed origins follow the runtime settings
+	app.Use(appHandler.CORS(cors.Config{
+		AllowMethods: "GET, POST, PUT, PATCH, DELETE",
 		AllowHeaders: `Origin, Content-Type, Accept, Authorization, 
 						Content-Length, Accept-Language, 
 						Accept-Encoding, Connection, Access-Control-Allow-Origin, 
@@ -170,40 +264,118 @@ func main() {
 		AllowCredentials: true,
 	}))
 
-	// Initializing app repository, service and handler
-	appRepository := repository.NewRepository(dbEngine.DB, s3Session)
-	appService := service.NewService(appRepository, &dbEngine.Cache, *appConfig.Auth, appConfig.Bucket.BucketName)
-	appHandler := handler.NewHandler(appService)
+	// Serving objects of the local storage, so that the app can run without a bucket. The staged uploads are
+	// private like in the bucket until they are moved to their final keys
+	if appConfig.Storage.Driver == config.StorageDriverLocal {
+		staging := appConfig.Storage.LocalRoute + "/" + constants.StagingFolder + "/"
+
+		app.Static(appConfig.Storage.LocalRoute, appConfig.Storage.LocalRoot, fiber.Static{
+			Next: func(c *fiber.Ctx) bool {
+				return strings.HasPrefix(c.Path(), staging)
+			},
+		})
+	}
+
+	// Initializing background workers, they are stopped before the clients they use
+	workersCtx, stopWorkers := context.WithCancel(logging.ContextWithLogger(context.Background(), appLogger))
+
+	var workers sync.WaitGroup
+
+	for _, run := range []func(context.Context){
+		appService.Janitor.Run,
+		appService.Outbox.Run,
+		appService.Settings.Run,
+	} {
+		workers.Add(1)
+
+		go func(run func(context.Context)) {
+			defer workers.Done()
+
+			run(workersCtx)
+		}(run)
+	}
+
+	lifecycleManager.Register("workers", func(ctx context.Context) error {
+		stopWorkers()
+		workers.Wait()
+
+		return nil
+	})
 
 	appLogger.Info("Initializing app routes and handlers")
 
 	// Initializing routes with fiber app
 	app = appHandler.InitRoutesFiber(app)
 
-	// Initializing graceful shutdown with fiber app, port and logger
-	go start(app, appConfig.HTTP.Port, appLogger)
+	// Starting the server, it is stopped first, the in-flight requests are drained before the workers
+	// and the clients are stopped
+	serverErrors := start(app, appConfig.HTTP.Port, appLogger)
+
+	// Starting the server of the metrics on the internal port, it is stopped after the app
+	metricsApp := handler.MetricsApp()
+	metricsErrors := start(metricsApp, appConfig.HTTP.MetricsPort, appLogger)
+
+	lifecycleManager.Register("metrics server", metricsApp.ShutdownWithContext)
+	lifecycleManager.Register("http server", app.ShutdownWithContext)
 
-	// Initializing graceful shutdown with context, fiber app and logger
 	stopChannel, closeChannel := createChannel()
 	defer closeChannel()
 
-	// Waiting for stop signal
-	appLogger.Info("Notified ", zap.Any("Channel ", <-stopChannel))
+	// Waiting for stop signal or the failure of the server
+	select {
+	case sig := <-stopChannel:
+		appLogger.Info("Notified ", zap.Any("Channel ", sig))
+	case err := <-serverErrors:
+		appLogger.Error("Server stopped unexpectedly", zap.Error(err))
+	case err := <-metricsErrors:
+		appLogger.Error("Metrics server stopped unexpectedly", zap.Error(err))
+	}
 
-	// Graceful shutdown with context, fiber app and logger
-	shutdown(ctx, app, appLogger)
+	err = lifecycleManager.Shutdown(logging.ContextWithLogger(context.Background(), appLogger))
+	if err != nil {
+		appLogger.Error("Application shutdown incomplete", zap.Error(err))
+
+		os.Exit(1)
+	}
+
+	appLogger.Info("Application shutdown")
 }
 
-// start starts the server and listens for shutdown signals to gracefully stop the server
-func start(server *fiber.App, port string, appLogger *zap.Logger) {
-	appLogger.Info("Application started")
-	if err := server.Listen(":" + port); err != nil && !errors.Is(err, http.ErrServerClosed) {
-		panic(err)
-	} else {
-		appLogger.Info("Application stopped gracefully")
+// newObjectStorage creates the object storage backend selected by the config
+func newObjectStorage(appConfig *config.Config) (repository.ObjectStorage, error) {
+	switch appConfig.Storage.Driver {
+	case config.StorageDriverLocal:
+		return repository.NewLocalStorageRepository(appConfig.Storage.LocalRoot), nil
+
+	default:
+		s3Session, err := awsS3.NewSessionBuilder().
+			WithAWSConfig(appConfig.Bucket).
+			NewSession()
+		if err != nil {
+			return nil, err
+		}
+
+		return repository.NewS3BucketRepository(s3Session, appConfig.Bucket.BucketName), nil
 	}
 }
 
+// start starts the server in the background, the returned channel gets the error of the server when it fails
+func start(server *fiber.App, port string, appLogger *zap.Logger) <-chan error {
+	serverErrors := make(chan error, 1)
+
+	go func() {
+		appLogger.Info("Application started", zap.String("port", port))
+
+		if err := server.Listen(":" + port); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErrors <- err
+		} else {
+			appLogger.Info("Application stopped gracefully", zap.String("port", port))
+		}
+	}()
+
+	return serverErrors
+}
+
 // createChannel creates a channel to listen for shutdown signals
 func createChannel() (chan os.Signal, func()) {
 	stopChannel := make(chan os.Signal, 1)
@@ -213,16 +385,3 @@ func createChannel() (chan os.Signal, func()) {
 		close(stopChannel)
 	}
 }
-
-// shutdown gracefully shuts down the server
-func shutdown(ctx context.Context, app *fiber.App, appLogger *zap.Logger) {
-	ctx, cancel := context.WithTimeout(ctx, constants.ContextTimeoutSeconds*time.Second)
-	defer cancel()
-
-	// Shutdown the server with a timeout of 10 seconds and log the error if any
-	if err := app.Shutdown(); err != nil {
-		panic(err)
-	} else {
-		appLogger.Info("Application shutdown")
-	}
-}
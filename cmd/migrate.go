@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	"acsp/internal/config"
+	"acsp/internal/infrastructure/db"
+	"acsp/migrations"
+)
+
+// runMigrate runs the migrate command with the migrations embedded into the binary
+func runMigrate(ctx context.Context, args []string, p config.Provider) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+
+	// Creating a migration doesn't need a database
+	if args[0] == "create" {
+		fs := flag.NewFlagSet("migrate create", flag.ContinueOnError)
+		dir := fs.String("dir", "migrations", "directory of the migrations")
+
+		err := fs.Parse(args[1:])
+		if err != nil {
+			return err
+		}
+
+		if fs.NArg() == 0 {
+			return errors.New(usage)
+		}
+
+		up, down, err := db.CreateMigration(*dir, strings.Join(fs.Args(), " "))
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("created %s\ncreated %s\n", up, down)
+
+		return nil
+	}
+
+	dbClient, closeDB, err := connectDB(ctx, p)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	migrator, err := db.NewMigrator(dbClient, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("applied %06d_%s\n", m.Version, m.Name)
+		}
+
+		if err == nil && len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+
+		return err
+
+	case "down":
+		fs := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+		steps := fs.Int("steps", 1, "number of migrations to revert")
+
+		err := fs.Parse(args[1:])
+		if err != nil {
+			return err
+		}
+
+		reverted, err := migrator.Down(ctx, *steps)
+		for _, m := range reverted {
+			fmt.Printf("reverted %06d_%s\n", m.Version, m.Name)
+		}
+
+		return err
+
+	case "baseline":
+		fs := flag.NewFlagSet("migrate baseline", flag.ContinueOnError)
+		version := fs.Int64("to", 1, "version of the latest migration the schema already has")
+
+		err := fs.Parse(args[1:])
+		if err != nil {
+			return err
+		}
+
+		recorded, err := migrator.Baseline(ctx, *version)
+		for _, m := range recorded {
+			fmt.Printf("recorded %06d_%s as applied\n", m.Version, m.Name)
+		}
+
+		return err
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+
+			fmt.Fprintf(w, "%06d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+
+		return w.Flush()
+
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", args[0], usage)
+	}
+}
+
+// migrateUp applies the pending migrations embedded into the binary
+func migrateUp(ctx context.Context, dbClient *sqlx.DB) ([]db.Migration, error) {
+	migrator, err := db.NewMigrator(dbClient, migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+
+	return migrator.Up(ctx)
+}
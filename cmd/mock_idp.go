@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"acsp/internal/oidc/oidctest"
+)
+
+// runMockIDP serves a mock OpenID Connect provider signing in the -email user, see OIDC_ISSUER
+func runMockIDP(args []string) error {
+	flags := flag.NewFlagSet("mock-idp", flag.ContinueOnError)
+	addr := flags.String("addr", "localhost:9000", "address the provider listens on")
+	clientID := flags.String("client-id", "acsp", "client id, see OIDC_CLIENT_ID")
+	clientSecret := flags.String("client-secret", "", "client secret, see OIDC_CLIENT_SECRET")
+	email := flags.String("email", "student@astanait.edu.kz", "email of the signed in user")
+	name := flags.String("name", "Mock Student", "name of the signed in user")
+	groups := flags.String("groups", "", "comma separated groups of the signed in user")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	user := oidctest.User{Subject: *email, Email: *email, EmailVerified: true, Name: *name}
+	if *groups != "" {
+		user.Groups = strings.Split(*groups, ",")
+	}
+
+	issuer := "http://" + *addr
+
+	provider, err := oidctest.NewProvider(issuer, *clientID, *clientSecret, user)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("mock identity provider is serving %s at %s\n", *email, issuer)
+
+	return http.ListenAndServe(*addr, provider)
+}
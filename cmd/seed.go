@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"acsp/fixtures"
+	"acsp/internal/config"
+	"acsp/internal/seed"
+)
+
+// runSeed loads the fixtures embedded into the binary, or the ones of the -dir directory, into the database
+func runSeed(ctx context.Context, args []string, p config.Provider) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	dir := flags.String("dir", "", "directory of the fixtures, the embedded fixtures are used by default")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	var fsys fs.FS = fixtures.FS
+	if *dir != "" {
+		fsys = os.DirFS(*dir)
+	}
+
+	f, err := seed.Load(fsys)
+	if err != nil {
+		return err
+	}
+
+	dbClient, closeDB, err := connectDB(ctx, p)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	report, err := seed.NewSeeder(dbClient).Seed(ctx, f)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("created %d users, %d courses, %d modules, %d lessons, %d articles, %d comments, %d cards, %d invitations\n",
+		report.Users, report.Courses, report.Modules, report.Lessons,
+		report.Articles, report.Comments, report.Cards, report.Invitations)
+
+	return nil
+}
@@ -0,0 +1,9 @@
+// Package fixtures embeds the local development data into the binary, it is loaded by the seed command.
+package fixtures
+
+import "embed"
+
+// FS holds the .yml and .json fixtures, see seed.Fixtures for their format.
+//
+//go:embed *.yml *.json
+var FS embed.FS
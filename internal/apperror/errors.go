@@ -38,4 +38,29 @@ const (
 	ErrCreatingDiscipline   = Error("error occurred when creating a discipline")
 	ErrCreatingContest      = Error("error occurred when creating a contest")
 	ErrCreatingModule       = Error("error occurred when creating a module")
+	ErrInvalidObjectKey     = Error("invalid object key")
+	ErrObjectNotFound       = Error("object not found in the storage")
+	ErrFeatureFlagNotFound  = Error("feature flag not found")
+	ErrFeatureFlagExists    = Error("feature flag already exists")
+	ErrTooManyAttempts      = Error("too many failed sign-in attempts, try again later")
+	ErrAccountLocked        = Error("account is temporarily locked after too many failed sign-in attempts")
+	ErrTwoFactorEnabled     = Error("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnrolled = Error("two-factor authentication is not enrolled")
+	ErrTwoFactorNotEnabled  = Error("two-factor authentication is not enabled")
+	ErrTwoFactorRequired    = Error("two-factor authentication is required for this role")
+	ErrInvalidMFACode       = Error("invalid two-factor authentication code")
+	ErrMFAChallengeNotFound = Error("two-factor authentication challenge is invalid or expired")
+	ErrSSODisabled          = Error("single sign-on is not enabled")
+	ErrSSOStateNotFound     = Error("single sign-on is expired or was already completed")
+	ErrSSOStateMismatch     = Error("single sign-on was started in another browser")
+	ErrSSOCodeNotFound      = Error("the single sign-on code is expired or was already used")
+	ErrSSORefused           = Error("the identity provider refused the sign-in")
+	ErrSSOEmailNotVerified  = Error("the email of the identity provider is not verified")
+	ErrSSOEmailDomain       = Error("the email domain is not allowed")
+	ErrTokenNotFound        = Error("personal access token not found")
+	ErrInvalidToken         = Error("personal access token is invalid or expired")
+	ErrTooManyTokens        = Error("too many personal access tokens, revoke unused ones")
+	ErrTokenScope           = Error("personal access token doesn't have the scope")
+	ErrSessionRequired      = Error("personal access tokens may not call this endpoint, sign in")
+	ErrNotificationNotFound = Error("notification not found")
 )
@@ -0,0 +1,131 @@
+package apperror
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// ProblemContentType is the content type of the problem details of RFC 7807.
+const ProblemContentType = "application/problem+json"
+
+// Problem is the body of the error responses, the problem details of RFC 7807.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// Status returns the HTTP status of the kind.
+func (k Kind) Status() int {
+	switch k {
+	case KindNotFound:
+		return fiber.StatusNotFound
+	case KindConflict:
+		return fiber.StatusConflict
+	case KindValidation:
+		return fiber.StatusBadRequest
+	case KindForbidden:
+		return fiber.StatusForbidden
+	case KindUnauthorized:
+		return fiber.StatusUnauthorized
+	case KindTooManyRequests:
+		return fiber.StatusTooManyRequests
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// kindDetails are the details of the errors without a message written for the clients, e.g. the errors
+// of the database driver given a kind by the repositories.
+var kindDetails = map[Kind]string{
+	KindNotFound:        "The resource is not found",
+	KindConflict:        "The resource already exists",
+	KindValidation:      "The input is invalid",
+	KindForbidden:       "Access to the resource is forbidden",
+	KindUnauthorized:    "Authentication is required",
+	KindTooManyRequests: "Too many requests, try again later",
+}
+
+// NewProblem describes the error, the details of the internal errors are left out.
+func NewProblem(err error) Problem {
+	var p Problem
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		p.Status = fiberErr.Code
+		p.Detail = fiberErr.Message
+	} else {
+		kind := KindOf(err)
+		p.Status = kind.Status()
+
+		if kind != KindInternal {
+			p.Detail = publicDetail(err, kind)
+		}
+
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) {
+			p.Errors = validationErr.Fields
+		}
+	}
+
+	if p.Status >= fiber.StatusInternalServerError {
+		p.Detail = ""
+	}
+
+	p.Type = "about:blank"
+	p.Title = utils.StatusMessage(p.Status)
+
+	return p
+}
+
+// publicDetail returns the message of the error written for the clients: the message of the sentinel error,
+// of the validation error or given to New. The other messages, e.g. of the database driver or of the wrapping
+// of the errors, are logged only, the clients get the message of the kind.
+func publicDetail(err error, kind Kind) string {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		switch e := e.(type) {
+		case Error:
+			return string(e)
+		case *ValidationError:
+			return e.Error()
+		case *KindError:
+			if e.public {
+				return e.Err.Error()
+			}
+		}
+	}
+
+	return kindDetails[kind]
+}
+
+// ErrorHandler is the error handler of the app, it renders the errors returned by the handlers as
+// problem details. The internal errors are logged by the RequestLogger middleware. The instance is the path
+// without the query, which may carry codes or text of anyone (e.g. the callback of the single sign-on).
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	p := NewProblem(err)
+	p.Instance = c.Path()
+	p.RequestID = c.GetRespHeader(fiber.HeaderXRequestID)
+
+	if err := c.Status(p.Status).JSON(p); err != nil {
+		return err
+	}
+
+	// JSON sets application/json
+	c.Set(fiber.HeaderContentType, ProblemContentType)
+
+	var retryErr *RetryError
+	if errors.As(err, &retryErr) {
+		// Rounded up, so that the clients don't retry too early
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retryErr.RetryAfter.Seconds()))))
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package apperror
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKindOf(t *testing.T) {
+	_, numErr := strconv.Atoi("abc")
+
+	tests := []struct {
+		name string
+		err  error
+		want Kind
+	}{
+		{"sentinel", ErrUserNotFound, KindNotFound},
+		{"wrapped sentinel", errors.Wrap(ErrEmailAlreadyExists, "error when creating the user"), KindConflict},
+		{"with kind", WithKind(sql.ErrNoRows, KindNotFound), KindNotFound},
+		{"outermost kind", WithKind(ErrUserNotFound, KindUnauthorized), KindUnauthorized},
+		{"validation", NewValidationError(FieldError{Field: "email", Code: "required", Message: "email is required"}), KindValidation},
+		{"number", numErr, KindValidation},
+		{"sentinel without kind", ErrCreatingArticle, KindInternal},
+		{"unknown", errors.New("connection refused"), KindInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, KindOf(tt.err))
+		})
+	}
+
+	assert.Nil(t, WithKind(nil, KindNotFound))
+	assert.ErrorIs(t, WithKind(sql.ErrNoRows, KindNotFound), sql.ErrNoRows)
+}
+
+func TestErrorHandler(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Get("/not-found", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderXRequestID, "request-1")
+
+		return errors.Wrap(ErrUserNotFound, "error when getting the user")
+	})
+	app.Get("/validation", func(c *fiber.Ctx) error {
+		return NewValidationError(FieldError{Field: "email", Code: "required", Message: "email is required"})
+	})
+	app.Get("/conflict", func(c *fiber.Ctx) error {
+		err := errors.New(`pq: duplicate key value violates unique constraint "users_email_key"`)
+
+		return WithKind(errors.Wrap(err, "error when executing query"), KindConflict)
+	})
+	app.Get("/forbidden", func(c *fiber.Ctx) error {
+		return errors.Wrap(New(KindForbidden, "You are not allowed to call this endpoint"), "error when authorizing")
+	})
+	app.Get("/internal", func(c *fiber.Ctx) error {
+		return errors.New("dial tcp 10.0.0.1:5432: connection refused")
+	})
+	app.Get("/fiber", func(c *fiber.Ctx) error {
+		return fiber.ErrMethodNotAllowed
+	})
+
+	tests := []struct {
+		path string
+		want Problem
+	}{
+		{"/not-found", Problem{
+			Type:      "about:blank",
+			Title:     "Not Found",
+			Status:    fiber.StatusNotFound,
+			Detail:    ErrUserNotFound.Error(),
+			Instance:  "/not-found",
+			RequestID: "request-1",
+		}},
+		{"/validation", Problem{
+			Type:     "about:blank",
+			Title:    "Bad Request",
+			Status:   fiber.StatusBadRequest,
+			Detail:   "invalid input",
+			Instance: "/validation",
+			Errors:   []FieldError{{Field: "email", Code: "required", Message: "email is required"}},
+		}},
+		// The messages of the driver aren't shown, the kind is
+		{"/conflict", Problem{
+			Type:     "about:blank",
+			Title:    "Conflict",
+			Status:   fiber.StatusConflict,
+			Detail:   "The resource already exists",
+			Instance: "/conflict",
+		}},
+		{"/forbidden", Problem{
+			Type:     "about:blank",
+			Title:    "Forbidden",
+			Status:   fiber.StatusForbidden,
+			Detail:   "You are not allowed to call this endpoint",
+			Instance: "/forbidden",
+		}},
+		// The details of the internal errors aren't shown
+		{"/internal", Problem{
+			Type:     "about:blank",
+			Title:    "Internal Server Error",
+			Status:   fiber.StatusInternalServerError,
+			Instance: "/internal",
+		}},
+		{"/fiber", Problem{
+			Type:     "about:blank",
+			Title:    "Method Not Allowed",
+			Status:   fiber.StatusMethodNotAllowed,
+			Detail:   "Method Not Allowed",
+			Instance: "/fiber",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest("GET", tt.path, nil))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.want.Status, resp.StatusCode)
+			assert.Equal(t, ProblemContentType, resp.Header.Get(fiber.HeaderContentType))
+
+			var got Problem
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestErrorHandler_RetryAfter(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Post("/sign-in", func(c *fiber.Ctx) error {
+		return WithRetryAfter(ErrAccountLocked, 1500*time.Millisecond)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/sign-in", nil))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+	// The retry is rounded up to a second
+	assert.Equal(t, "2", resp.Header.Get(fiber.HeaderRetryAfter))
+
+	var got Problem
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	assert.Equal(t, ErrAccountLocked.Error(), got.Detail)
+}
@@ -0,0 +1,166 @@
+package apperror
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Kind classifies the errors, the kind of an error selects the status of its response.
+type Kind int
+
+const (
+	// KindInternal is a failure of the app or its dependencies, its details aren't shown to the clients.
+	KindInternal Kind = iota
+	KindNotFound
+	KindConflict
+	KindValidation
+	KindForbidden
+	KindUnauthorized
+	KindTooManyRequests
+)
+
+// kinds of the sentinel errors, the other ones are internal
+var kinds = map[Error]Kind{
+	ErrUserNotFound:         KindNotFound,
+	ErrEmailNotFound:        KindNotFound,
+	ErrObjectNotFound:       KindNotFound,
+	ErrFeatureFlagNotFound:  KindNotFound,
+	ErrEmailAlreadyExists:   KindConflict,
+	ErrFeatureFlagExists:    KindConflict,
+	ErrBodyParsed:           KindValidation,
+	ErrBadInputBody:         KindValidation,
+	ErrParameterNotFound:    KindValidation,
+	ErrInvalidParameter:     KindValidation,
+	ErrInvalidObjectKey:     KindValidation,
+	ErrIncorrectRole:        KindForbidden,
+	ErrPasswordMismatch:     KindUnauthorized,
+	ErrUserIDNotFound:       KindUnauthorized,
+	ErrBadSigningMethod:     KindUnauthorized,
+	ErrBadClaimsType:        KindUnauthorized,
+	ErrTooManyAttempts:      KindTooManyRequests,
+	ErrAccountLocked:        KindTooManyRequests,
+	ErrTwoFactorEnabled:     KindConflict,
+	ErrTwoFactorNotEnrolled: KindConflict,
+	ErrTwoFactorNotEnabled:  KindConflict,
+	ErrTwoFactorRequired:    KindForbidden,
+	ErrInvalidMFACode:       KindUnauthorized,
+	ErrMFAChallengeNotFound: KindUnauthorized,
+	ErrSSODisabled:          KindNotFound,
+	ErrSSOStateNotFound:     KindUnauthorized,
+	ErrSSOStateMismatch:     KindUnauthorized,
+	ErrSSOCodeNotFound:      KindUnauthorized,
+	ErrSSORefused:           KindUnauthorized,
+	ErrSSOEmailNotVerified:  KindForbidden,
+	ErrSSOEmailDomain:       KindForbidden,
+	ErrTokenNotFound:        KindNotFound,
+	ErrInvalidToken:         KindUnauthorized,
+	ErrTooManyTokens:        KindConflict,
+	ErrTokenScope:           KindForbidden,
+	ErrSessionRequired:      KindForbidden,
+	ErrNotificationNotFound: KindNotFound,
+}
+
+// Kind returns the kind of the sentinel error.
+func (e Error) Kind() Kind {
+	return kinds[e]
+}
+
+// KindError gives an error a kind, the error is still matched by errors.Is and errors.As.
+type KindError struct {
+	Kind Kind
+	Err  error
+	// public tells that the message of Err is written for the clients, see New
+	public bool
+}
+
+func (e *KindError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *KindError) Unwrap() error {
+	return e.Err
+}
+
+// New creates an error of the kind, the message is shown to the clients.
+func New(kind Kind, message string) error {
+	return &KindError{Kind: kind, Err: errors.New(message), public: true}
+}
+
+// WithKind gives err the kind, nil stays nil. The message of err isn't shown to the clients, they get
+// the message of the kind, see NewProblem.
+func WithKind(err error, kind Kind) error {
+	if err == nil {
+		return nil
+	}
+
+	return &KindError{Kind: kind, Err: err}
+}
+
+// RetryError is an error of a request that may be retried after RetryAfter, it is sent in the Retry-After header.
+type RetryError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+// WithRetryAfter tells the client to retry the request after d.
+func WithRetryAfter(err error, d time.Duration) error {
+	return &RetryError{Err: err, RetryAfter: d}
+}
+
+func (e *RetryError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// FieldError is a problem of a field of an invalid input. The code is the rule that failed, the clients
+// may match on it, the message is for the users.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError is an invalid input with the problems of its fields.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// NewValidationError creates a ValidationError of the fields.
+func NewValidationError(fields ...FieldError) *ValidationError {
+	return &ValidationError{Fields: fields}
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid input"
+}
+
+// KindOf returns the kind of the error: the outermost kind given by WithKind, otherwise the kind of
+// the sentinel error it wraps. Unknown errors are internal.
+func KindOf(err error) Kind {
+	var kindErr *KindError
+	if errors.As(err, &kindErr) {
+		return kindErr.Kind
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return KindValidation
+	}
+
+	var sentinel Error
+	if errors.As(err, &sentinel) {
+		return sentinel.Kind()
+	}
+
+	// The numbers are parsed from the path parameters and the queries of the requests
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		return KindValidation
+	}
+
+	return KindInternal
+}
@@ -1,22 +1,39 @@
 package config
 
 import (
-	"encoding/json"
-	"fmt"
+	"net"
+	"net/url"
 	"strconv"
 	"time"
 
-	"github.com/joho/godotenv"
-	"github.com/pkg/errors"
-	"github.com/spf13/viper"
 	"go.uber.org/zap/zapcore"
 
-	"acsp/internal/apperror"
 	"acsp/internal/constants"
 )
 
 const (
-	EnvLocal = "local"
+	// StorageDriverS3 stores objects in an S3-compatible bucket.
+	StorageDriverS3 = "s3"
+	// StorageDriverLocal stores objects on the local filesystem and serves them under a static route.
+	StorageDriverLocal = "local"
+)
+
+const (
+	// RateLimitKeyIP counts the requests of a rate limit policy per IP.
+	RateLimitKeyIP = "ip"
+	// RateLimitKeyUser counts the requests of a rate limit policy per user, anonymous requests per IP.
+	RateLimitKeyUser = "user"
+)
+
+const (
+	// TracingExporterNone doesn't record spans.
+	TracingExporterNone = "none"
+	// TracingExporterOTLP sends spans to an OTLP/HTTP collector, e.g. Jaeger or the OpenTelemetry Collector.
+	TracingExporterOTLP = "otlp"
+	// TracingExporterStdout prints spans to the standard output.
+	TracingExporterStdout = "stdout"
+	// TracingExporterFile writes spans to a file, so that traces can be looked at offline.
+	TracingExporterFile = "file"
 )
 
 const (
@@ -33,11 +50,162 @@ type (
 		Auth        *AuthConfig
 		Logger      *LoggerConfig
 		Host        *HostConfig
+		Storage     *StorageConfig
 		Bucket      *S3Config
+		Outbox      *OutboxConfig
+		Settings    *SettingsConfig
+		Features    *FeatureFlagsConfig
+		RateLimit   *RateLimitConfig
+		Tracing     *TracingConfig
+		Health      *HealthConfig
 	}
 
 	AuthConfig struct {
-		JWT JWTConfig
+		JWT     JWTConfig
+		Lockout LockoutConfig
+		MFA     MFAConfig
+		OIDC    OIDCConfig
+		Tokens  PersonalAccessTokensConfig
+	}
+
+	// PersonalAccessTokensConfig limits the personal access tokens of the users.
+	PersonalAccessTokensConfig struct {
+		// DefaultTTL is the lifetime of a token created without one
+		DefaultTTL time.Duration `envconfig:"PAT_DEFAULT_TTL"`
+		MaxTTL     time.Duration `envconfig:"PAT_MAX_TTL"`
+		MaxPerUser int           `envconfig:"PAT_MAX_PER_USER"`
+	}
+
+	// OIDCConfig controls the single sign-on with the OpenID Connect provider of the university.
+	OIDCConfig struct {
+		Enabled      bool     `envconfig:"OIDC_ENABLED"`
+		Issuer       string   `envconfig:"OIDC_ISSUER"`
+		ClientID     string   `envconfig:"OIDC_CLIENT_ID"`
+		ClientSecret string   `envconfig:"OIDC_CLIENT_SECRET"`
+		RedirectURL  string   `envconfig:"OIDC_REDIRECT_URL"`
+		Scopes       []string `envconfig:"OIDC_SCOPES"`
+		// GroupsClaim is the claim of the ID token listing the groups of the user
+		GroupsClaim string `envconfig:"OIDC_GROUPS_CLAIM"`
+		// GroupRoles maps the groups of the provider to the roles, the roles are granted on every sign-in
+		GroupRoles map[string]string `envconfig:"OIDC_GROUP_ROLES"`
+		// EmailDomain is the domain of the emails of the users signing in, empty allows any domain
+		EmailDomain string `envconfig:"OIDC_EMAIL_DOMAIN"`
+		// StateTTL is the time to sign in at the provider
+		StateTTL time.Duration `envconfig:"OIDC_STATE_TTL"`
+		// FrontendURL is the page of the frontend the callback redirects to with the one-time code
+		FrontendURL string `envconfig:"OIDC_FRONTEND_URL"`
+		// CodeTTL is the time to exchange the one-time code for the token pair
+		CodeTTL time.Duration `envconfig:"OIDC_CODE_TTL"`
+	}
+
+	// MFAConfig controls the two-factor authentication with TOTP.
+	MFAConfig struct {
+		// Issuer is shown by the authenticator apps next to the codes
+		Issuer string `envconfig:"MFA_ISSUER"`
+		// ChallengeTTL is the time to enter the code after the password
+		ChallengeTTL  time.Duration `envconfig:"MFA_CHALLENGE_TTL"`
+		RecoveryCodes int           `envconfig:"MFA_RECOVERY_CODES"`
+		// RequiredRoles may only be used by the users with two-factor authentication enabled
+		RequiredRoles []string `envconfig:"MFA_REQUIRED_ROLES"`
+	}
+
+	// LockoutConfig protects the sign-in from brute force. The failures are counted per account and per IP
+	// for FailureWindow after the first one, the sign-ins are slowed down after DelayAfter failures and locked for
+	// LockoutDuration after the max failures.
+	LockoutConfig struct {
+		MaxFailures     int           `envconfig:"LOGIN_MAX_FAILURES"`
+		IPMaxFailures   int           `envconfig:"LOGIN_IP_MAX_FAILURES"`
+		FailureWindow   time.Duration `envconfig:"LOGIN_FAILURE_WINDOW"`
+		LockoutDuration time.Duration `envconfig:"LOGIN_LOCKOUT_DURATION"`
+		DelayAfter      int           `envconfig:"LOGIN_DELAY_AFTER"`
+		// Delay doubles with every failure after DelayAfter up to MaxDelay
+		Delay    time.Duration `envconfig:"LOGIN_DELAY"`
+		MaxDelay time.Duration `envconfig:"LOGIN_MAX_DELAY"`
+	}
+
+	// StorageConfig selects and configures the object storage backend.
+	StorageConfig struct {
+		Driver     string `envconfig:"STORAGE_DRIVER"`
+		LocalRoot  string `envconfig:"STORAGE_LOCAL_ROOT"`
+		LocalRoute string `envconfig:"STORAGE_LOCAL_ROUTE"`
+		// PublicURL overrides the base URL of the stored objects, e.g. a CDN in front of the bucket
+		PublicURL string `envconfig:"STORAGE_PUBLIC_URL"`
+		Janitor   JanitorConfig
+	}
+
+	// JanitorConfig controls the removal of stored objects whose owners are gone.
+	JanitorConfig struct {
+		// Interval between two runs, the janitor is disabled when it is zero
+		Interval time.Duration `envconfig:"STORAGE_JANITOR_INTERVAL"`
+		// GracePeriod keeps fresh objects whose owners may not be committed yet
+		GracePeriod time.Duration `envconfig:"STORAGE_JANITOR_GRACE_PERIOD"`
+		BatchSize   int           `envconfig:"STORAGE_JANITOR_BATCH_SIZE"`
+		// DryRun only reports the orphaned objects without deleting them
+		DryRun bool `envconfig:"STORAGE_JANITOR_DRY_RUN"`
+	}
+
+	// OutboxConfig controls the worker performing the side effects written to the outbox.
+	OutboxConfig struct {
+		PollInterval time.Duration `envconfig:"OUTBOX_POLL_INTERVAL"`
+		BatchSize    int           `envconfig:"OUTBOX_BATCH_SIZE"`
+		// Lease is the time an event is hidden from other workers while it is processed
+		Lease       time.Duration `envconfig:"OUTBOX_LEASE"`
+		MaxAttempts int           `envconfig:"OUTBOX_MAX_ATTEMPTS"`
+		RetryDelay  time.Duration `envconfig:"OUTBOX_RETRY_DELAY"`
+		// MaxRetryDelay caps the exponential backoff of the retries
+		MaxRetryDelay time.Duration `envconfig:"OUTBOX_MAX_RETRY_DELAY"`
+	}
+
+	// SettingsConfig holds the defaults of the runtime settings, they are used until an admin changes the settings.
+	SettingsConfig struct {
+		// ReloadInterval is how often the instances pick up the settings changed by other instances
+		ReloadInterval time.Duration `envconfig:"SETTINGS_RELOAD_INTERVAL"`
+		// LogLevel is the LOGGER_LEVEL
+		LogLevel        string
+		CORSOrigins     []string      `envconfig:"CORS_ORIGINS"`
+		RateLimitMax    int           `envconfig:"RATE_LIMIT_MAX"`
+		RateLimitWindow time.Duration `envconfig:"RATE_LIMIT_WINDOW"`
+	}
+
+	// FeatureFlagsConfig controls the evaluation of the feature flags.
+	FeatureFlagsConfig struct {
+		// CacheTTL is how long the flags are cached in redis, the cache is also dropped on every change
+		CacheTTL time.Duration `envconfig:"FEATURE_FLAGS_CACHE_TTL"`
+	}
+
+	// RateLimitConfig holds the policies of the rate limiter of the routes. The default policy of all routes
+	// is a runtime setting, see SettingsConfig.
+	RateLimitConfig struct {
+		Enabled bool `envconfig:"RATE_LIMIT_ENABLED"`
+		// Auth limits the sign-ins and sign-ups
+		Auth RateLimitPolicy
+		// Write limits the creation of comments and invitations
+		Write RateLimitPolicy
+	}
+
+	// RateLimitPolicy allows Max requests per sliding Window to a client, Key tells the clients apart.
+	RateLimitPolicy struct {
+		Max    int
+		Window time.Duration
+		Key    string
+	}
+
+	// TracingConfig selects the exporter of the OpenTelemetry spans.
+	TracingConfig struct {
+		Exporter string `envconfig:"TRACING_EXPORTER"`
+		// OTLPEndpoint is the host:port of the OTLP/HTTP collector
+		OTLPEndpoint string `envconfig:"TRACING_OTLP_ENDPOINT"`
+		OTLPInsecure bool   `envconfig:"TRACING_OTLP_INSECURE"`
+		// File is written by the file exporter, one JSON span per line
+		File string `envconfig:"TRACING_FILE"`
+		// SampleRatio is the share of the traces started by the app that are recorded
+		SampleRatio float64 `envconfig:"TRACING_SAMPLE_RATIO"`
+	}
+
+	// HealthConfig controls the readiness checks of the dependencies.
+	HealthConfig struct {
+		// CheckTimeout is the time a dependency has to answer, it is reported down otherwise
+		CheckTimeout time.Duration `envconfig:"HEALTH_CHECK_TIMEOUT"`
 	}
 
 	S3Config struct {
@@ -49,18 +217,32 @@ type (
 	}
 
 	JWTConfig struct {
-		AccessTokenTTL     time.Duration `envconfig:"ACCESS_TOKEN_TTL"`
-		RefreshTokenTTL    time.Duration `envconfig:"REFRESH_TOKEN_TTL"`
-		AccessTokenSecret  string        `envconfig:"ACCESS_TOKEN_SECRET_KEY"`
-		RefreshTokenSecret string        `envconfig:"REFRESH_TOKEN_SECRET_KEY"`
+		AccessTokenTTL  time.Duration `envconfig:"ACCESS_TOKEN_TTL"`
+		RefreshTokenTTL time.Duration `envconfig:"REFRESH_TOKEN_TTL"`
+		// Issuer is the iss claim of the tokens
+		Issuer string `envconfig:"ISSUER"`
+		// KeysDir holds the <kid>.pem keys verifying the tokens, see jwks.LoadDir
+		KeysDir string `envconfig:"KEYS_DIR"`
+		// SigningKeyID is the id of the key signing the new tokens, of KeysDir or of SigningKey
+		SigningKeyID string `envconfig:"SIGNING_KEY_ID"`
+		// SigningKey is the PEM of the signing key given by a secret, SigningKeyFile is the file of the secret
+		SigningKey     string `envconfig:"SIGNING_KEY"`
+		SigningKeyFile string `envconfig:"SIGNING_KEY_FILE"`
 	}
 
 	HTTPConfig struct {
-		Host               string        `envconfig:"HOST"`
-		Port               string        `envconfig:"PORT"`
+		Host string `envconfig:"HOST"`
+		Port string `envconfig:"PORT"`
+		// MetricsPort serves the metrics, it is internal, so that the metrics aren't public
+		MetricsPort        string        `envconfig:"METRICS_PORT"`
 		ReadTimeout        time.Duration `envconfig:"READ_TIMEOUT"`
 		WriteTimeout       time.Duration `envconfig:"WRITE_TIMEOUT"`
 		MaxHeaderMegabytes int           `envconfig:"MAX_HEADER_BYTES"`
+		// ProxyHeader has the IP of the client behind a proxy, e.g. X-Real-IP, the IP of the
+		// connection is used when it is empty
+		ProxyHeader string `envconfig:"PROXY_HEADER"`
+		// TrustedProxies are the IPs and CIDRs the proxy header is accepted from, required with ProxyHeader
+		TrustedProxies []string `envconfig:"TRUSTED_PROXIES"`
 	}
 
 	LoggerConfig struct {
@@ -85,298 +267,373 @@ type (
 	}
 )
 
-func Init(configsDir string) (*Config, error) {
-	viper.SetConfigFile("base.env")
-	if err := viper.ReadInConfig(); err != nil {
-		return nil, err
-	}
-
-	if err := parseConfigFile(configsDir, viper.GetString("APP_ENV")); err != nil {
-		return nil, err
-	}
+// NewBaseConfig creates a BaseConfig. Every missing or invalid key is reported at once in a *ValidationError.
+func NewBaseConfig(p Provider) (*Config, error) {
+	l := NewLoader(p)
 
-	var cfg Config
-	if err := unmarshal(&cfg); err != nil {
-		return nil, err
+	c := Config{
+		Environment: l.String("ENVIRONMENT", string(EnvironmentDevelopment)),
+		HTTP:        newHTTPConfig(l),
+		Host:        newHostConfig(l),
+		Logger:      newLoggerConfig(l),
+		Auth:        newAuthConfig(l),
+		Storage:     newStorageConfig(l),
+		Outbox:      newOutboxConfig(l),
+		Features:    newFeatureFlagsConfig(l),
+		RateLimit:   newRateLimitConfig(l),
+		Tracing:     newTracingConfig(l),
+		Health:      newHealthConfig(l),
 	}
 
-	return &cfg, nil
-}
+	c.Settings = newSettingsConfig(l, c.Logger)
 
-// NewBaseConfig creates a BaseConfig.
-func NewBaseConfig(p Provider) (*Config, error) {
-	c := Config{
-		Environment: p.Get("ENVIRONMENT", "development"),
-		HTTP:        &HTTPConfig{},
+	// S3 credentials are only required when the bucket is actually used
+	if c.Storage.Driver == StorageDriverS3 {
+		c.Bucket = newS3BucketConfig(l)
 	}
 
-	http, err := newHTTPConfig(p)
+	err := l.Err()
 	if err != nil {
 		return nil, err
 	}
 
-	c.HTTP = http
+	return &c, nil
+}
 
-	h, err := newHostConfig(p)
-	if err != nil {
-		return nil, err
+func newHostConfig(l *Loader) *HostConfig {
+	return &HostConfig{
+		Environment: Environment(l.OneOf("ENVIRONMENT", string(EnvironmentDevelopment),
+			string(EnvironmentDevelopment), string(EnvironmentProduction))),
+		ShutdownTimeout: l.Int("SERVER_SHUTDOWNTIMEOUT", 10),
 	}
+}
 
-	c.Host = h
+func newHTTPConfig(l *Loader) *HTTPConfig {
+	const prefix = "HTTP"
 
-	l, err := newLoggerConfig(p)
-	if err != nil {
-		return nil, err
+	c := &HTTPConfig{
+		Host:               l.String(prefix+"_HOST", "localhost"),
+		Port:               l.String(prefix+"_PORT", "8080"),
+		MetricsPort:        l.String(prefix+"_METRICS_PORT", "9090"),
+		ReadTimeout:        l.Duration(prefix+"_READ_TIMEOUT", constants.FallBackDurationSeconds*time.Second),
+		WriteTimeout:       l.Duration(prefix+"_WRITE_TIMEOUT", constants.FallBackDurationSeconds*time.Second),
+		MaxHeaderMegabytes: l.Int(prefix+"_MAX_HEADER_BYTES", 1),
+		ProxyHeader:        l.String(prefix+"_PROXY_HEADER", ""),
+		TrustedProxies:     trustedProxies(l, prefix+"_TRUSTED_PROXIES"),
 	}
 
-	c.Logger = l
-
-	a, err := newAuthConfig(p)
-	if err != nil {
-		return nil, err
+	// Any client reaching the app directly could set the header, so it is only read from the known proxies
+	if c.ProxyHeader != "" && len(c.TrustedProxies) == 0 {
+		l.Fail(prefix+"_TRUSTED_PROXIES", "is required with %s_PROXY_HEADER", prefix)
 	}
 
-	c.Auth = a
+	return c
+}
 
-	s, err := newS3BucketConfig(p)
-	if err != nil {
-		return nil, err
-	}
+// trustedProxies returns the IPs and CIDRs of the key, the invalid ones are reported.
+func trustedProxies(l *Loader, key string) []string {
+	proxies := l.Strings(key, []string{})
 
-	c.Bucket = s
+	for _, proxy := range proxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				l.Fail(key, "must be IPs or CIDRs, got %q", proxy)
+			}
+		}
+	}
 
-	return &c, nil
+	return proxies
 }
 
-func newHostConfig(p Provider) (*HostConfig, error) {
-	e, err := parseEnvironment(p.Get("ENVIRONMENT", string(EnvironmentDevelopment)))
-	if err != nil {
-		return nil, err
+func newStorageConfig(l *Loader) *StorageConfig {
+	const prefix = "STORAGE"
+
+	return &StorageConfig{
+		Driver:     l.OneOf(prefix+"_DRIVER", StorageDriverS3, StorageDriverS3, StorageDriverLocal),
+		LocalRoot:  l.String(prefix+"_LOCAL_ROOT", "./storage"),
+		LocalRoute: l.String(prefix+"_LOCAL_ROUTE", "/storage"),
+		PublicURL:  l.String(prefix+"_PUBLIC_URL", ""),
+		Janitor: JanitorConfig{
+			Interval:    l.Duration(prefix+"_JANITOR_INTERVAL", time.Hour),
+			GracePeriod: l.Duration(prefix+"_JANITOR_GRACE_PERIOD", 24*time.Hour),
+			BatchSize:   l.Int(prefix+"_JANITOR_BATCH_SIZE", 100),
+			DryRun:      l.Bool(prefix+"_JANITOR_DRY_RUN", false),
+		},
 	}
-
-	return &HostConfig{
-		Environment:     e,
-		ShutdownTimeout: GetInt(p, "SERVER_SHUTDOWNTIMEOUT", 10),
-	}, nil
 }
 
-type dotenvProvider struct {
-	values map[string]string
-}
+func newOutboxConfig(l *Loader) *OutboxConfig {
+	const prefix = "OUTBOX"
 
-func (p *dotenvProvider) Get(key, fallback string) string {
-	v, ok := p.values[key]
-	if ok {
-		return v
+	return &OutboxConfig{
+		PollInterval:  l.Duration(prefix+"_POLL_INTERVAL", time.Second),
+		BatchSize:     l.Int(prefix+"_BATCH_SIZE", 10),
+		Lease:         l.Duration(prefix+"_LEASE", time.Minute),
+		MaxAttempts:   l.Int(prefix+"_MAX_ATTEMPTS", 10),
+		RetryDelay:    l.Duration(prefix+"_RETRY_DELAY", 5*time.Second),
+		MaxRetryDelay: l.Duration(prefix+"_MAX_RETRY_DELAY", time.Hour),
 	}
-
-	return fallback
 }
 
-// NewDotenvProvider creates a .env file-backed Provider.
-func NewDotenvProvider(filepath string) (Provider, error) {
-	vs, err := godotenv.Read(filepath)
-	if err != nil {
-		return nil, err
+func newSettingsConfig(l *Loader, logger *LoggerConfig) *SettingsConfig {
+	return &SettingsConfig{
+		ReloadInterval:  l.Duration("SETTINGS_RELOAD_INTERVAL", 10*time.Second),
+		LogLevel:        logger.Level.String(),
+		CORSOrigins:     l.Strings("CORS_ORIGINS", []string{"*"}),
+		RateLimitMax:    l.Int("RATE_LIMIT_MAX", 100),
+		RateLimitWindow: l.Duration("RATE_LIMIT_WINDOW", time.Minute),
 	}
-
-	return &dotenvProvider{
-		values: vs,
-	}, nil
 }
 
-type providerChain struct {
-	providers []Provider
+func newFeatureFlagsConfig(l *Loader) *FeatureFlagsConfig {
+	return &FeatureFlagsConfig{
+		CacheTTL: l.Duration("FEATURE_FLAGS_CACHE_TTL", time.Minute),
+	}
 }
 
-func (c *providerChain) Get(key, fallback string) string {
-	for _, p := range c.providers {
-		v := p.Get(key, fallback)
-		if v != fallback {
-			return v
-		}
-	}
+func newRateLimitConfig(l *Loader) *RateLimitConfig {
+	const prefix = "RATE_LIMIT"
 
-	return fallback
+	return &RateLimitConfig{
+		Enabled: l.Bool(prefix+"_ENABLED", true),
+		Auth:    newRateLimitPolicy(l, prefix+"_AUTH", 10, time.Minute, RateLimitKeyIP),
+		Write:   newRateLimitPolicy(l, prefix+"_WRITE", 30, time.Minute, RateLimitKeyUser),
+	}
 }
 
-// NewProviderChain allows value overriding by chaining multiple Provider.
-func NewProviderChain(ps ...Provider) Provider {
-	return &providerChain{
-		providers: ps,
+func newRateLimitPolicy(l *Loader, prefix string, max int, window time.Duration, key string) RateLimitPolicy {
+	p := RateLimitPolicy{
+		Max:    l.Int(prefix+"_MAX", max),
+		Window: l.Duration(prefix+"_WINDOW", window),
+		Key:    l.OneOf(prefix+"_KEY", key, RateLimitKeyIP, RateLimitKeyUser),
+	}
+
+	if p.Max < 1 {
+		l.Fail(prefix+"_MAX", "must be positive, got %d", p.Max)
 	}
-}
 
-func unmarshal(cfg *Config) error {
-	if err := viper.UnmarshalKey("http", &cfg.HTTP); err != nil {
-		return err
+	if p.Window <= 0 {
+		l.Fail(prefix+"_WINDOW", "must be positive, got %s", p.Window)
 	}
 
-	return viper.UnmarshalKey("auth", &cfg.Auth.JWT)
+	return p
 }
 
-func parseConfigFile(folder, fileName string) error {
-	viper.AddConfigPath(folder)
-	viper.SetConfigName("config")
+func newTracingConfig(l *Loader) *TracingConfig {
+	const prefix = "TRACING"
 
-	if err := viper.ReadInConfig(); err != nil {
-		return err
+	c := &TracingConfig{
+		Exporter: l.OneOf(prefix+"_EXPORTER", TracingExporterNone,
+			TracingExporterNone, TracingExporterOTLP, TracingExporterStdout, TracingExporterFile),
+		OTLPEndpoint: l.String(prefix+"_OTLP_ENDPOINT", "localhost:4318"),
+		OTLPInsecure: l.Bool(prefix+"_OTLP_INSECURE", true),
+		File:         l.String(prefix+"_FILE", "traces.json"),
+		SampleRatio:  l.Float64(prefix+"_SAMPLE_RATIO", 1),
 	}
 
-	if fileName == EnvLocal {
-		return nil
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		l.Fail(prefix+"_SAMPLE_RATIO", "must be between 0 and 1, got %v", c.SampleRatio)
 	}
 
-	viper.SetConfigName(fileName)
+	return c
+}
 
-	return viper.MergeInConfig()
+func newHealthConfig(l *Loader) *HealthConfig {
+	return &HealthConfig{
+		CheckTimeout: l.Duration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+	}
 }
 
-func parseEnvironment(s string) (Environment, error) {
-	switch Environment(s) {
-	case EnvironmentDevelopment, EnvironmentProduction:
-		return Environment(s), nil
+func newS3BucketConfig(l *Loader) *S3Config {
+	const prefix = "S3"
 
-	default:
-		return "", fmt.Errorf("unknown environment: %v", s)
+	return &S3Config{
+		AccessToken: l.Required(prefix + "_ACCESS_TOKEN"),
+		SecretKey:   l.Required(prefix + "_SECRET_KEY"),
+		Region:      l.Required(prefix + "_REGION"),
+		BucketName:  l.Required(prefix + "_BUCKET_NAME"),
+		Endpoint:    l.Required(prefix + "_ENDPOINT"),
 	}
 }
 
-// Provider represents a configuration store backed by a key-value mapping.
-type Provider interface {
-	Get(key, fallback string) string
-}
+func newLoggerConfig(l *Loader) *LoggerConfig {
+	const prefix = "LOGGER"
 
-func newHTTPConfig(p Provider) (*HTTPConfig, error) {
-	const prefix = "HTTP"
+	var level zapcore.Level
+	v := l.String(prefix+"_LEVEL", "info")
+	if err := level.UnmarshalText([]byte(v)); err != nil {
+		l.Fail(prefix+"_LEVEL", "unknown level %q", v)
+	}
+
+	var le zapcore.LevelEncoder
+	v = l.String(prefix+"_LEVELENCODER", "capitalColor")
+	if err := le.UnmarshalText([]byte(v)); err != nil {
+		l.Fail(prefix+"_LEVELENCODER", "unknown level encoder %q", v)
+	}
 
-	return &HTTPConfig{
-		Host:               p.Get(prefix+"_HOST", "localhost"),
-		Port:               p.Get(prefix+"_PORT", "8080"),
-		ReadTimeout:        getDuration(p, prefix+"_READ_TIMEOUT", constants.FallBackDurationSeconds*time.Second),
-		WriteTimeout:       getDuration(p, prefix+"_WRITE_TIMEOUT", constants.FallBackDurationSeconds*time.Second),
-		MaxHeaderMegabytes: GetInt(p, prefix+"_MAX_HEADER_BYTES", 1),
-	}, nil
+	f := l.Bool(prefix+"_ENABLEFILE", true)
+	s := l.Bool(prefix+"_ENABLESTDOUT", true)
+	if !f && !s {
+		l.Fail(prefix+"_ENABLESTDOUT", "at least one sink must be enabled")
+	}
+
+	return &LoggerConfig{
+		Level:        level,
+		Encoding:     l.OneOf(prefix+"_ENCODING", "console", "console", "json"),
+		LevelEncoder: le,
+		Sinks:        l.Strings(prefix+"_SINKS", []string{"stdout"}),
+		ErrorSinks:   l.Strings(prefix+"_ERRORSINKS", []string{"stderr"}),
+		MaxSizeMB:    l.Int(prefix+"_MAXSIZEMB", 128),
+		MaxAgeDays:   l.Int(prefix+"_MAXAGEDAYS", 168),
+		MaxBackups:   l.Int(prefix+"_MAXBACKUPS", 16),
+		BatchSize:    l.Uint(prefix+"_BATCHSIZE", 2),
+	}
 }
 
-func newS3BucketConfig(p Provider) (*S3Config, error) {
-	const prefix = "S3"
+func newAuthConfig(l *Loader) *AuthConfig {
+	const prefix = "JWT"
 
-	a := p.Get(prefix+"_ACCESS_TOKEN", "")
-	if a == "" {
-		return nil, fmt.Errorf("%sACCESS_TOKEN is required", prefix)
+	jwt := JWTConfig{
+		AccessTokenTTL:  l.RequiredDuration(prefix + "_ACCESS_TOKEN_TTL"),
+		RefreshTokenTTL: l.RequiredDuration(prefix + "_REFRESH_TOKEN_TTL"),
+		Issuer:          l.String(prefix+"_ISSUER", "acsp"),
+		KeysDir:         l.String(prefix+"_KEYS_DIR", "./keys"),
+		SigningKeyID:    l.Required(prefix + "_SIGNING_KEY_ID"),
+		SigningKey:      l.String(prefix+"_SIGNING_KEY", ""),
+		SigningKeyFile:  l.String(prefix+"_SIGNING_KEY_FILE", ""),
 	}
 
-	s := p.Get(prefix+"_SECRET_KEY", "")
-	if s == "" {
-		return nil, fmt.Errorf("%sSECRET_KEY is required", prefix)
+	if jwt.SigningKey != "" && jwt.SigningKeyFile != "" {
+		l.Fail(prefix+"_SIGNING_KEY_FILE", "must not be set with %s_SIGNING_KEY", prefix)
 	}
 
-	r := p.Get(prefix+"_REGION", "")
-	if r == "" {
-		return nil, fmt.Errorf("%sREGION is required", prefix)
+	return &AuthConfig{
+		JWT:     jwt,
+		Lockout: newLockoutConfig(l),
+		MFA:     newMFAConfig(l),
+		OIDC:    newOIDCConfig(l),
+		Tokens:  newPersonalAccessTokensConfig(l),
 	}
+}
 
-	b := p.Get(prefix+"_BUCKET_NAME", "")
-	if b == "" {
-		return nil, fmt.Errorf("%sBUCKET_NAME is required", prefix)
+func newPersonalAccessTokensConfig(l *Loader) PersonalAccessTokensConfig {
+	const prefix = "PAT"
+
+	c := PersonalAccessTokensConfig{
+		DefaultTTL: l.Duration(prefix+"_DEFAULT_TTL", 30*24*time.Hour),
+		MaxTTL:     l.Duration(prefix+"_MAX_TTL", 365*24*time.Hour),
+		MaxPerUser: l.Int(prefix+"_MAX_PER_USER", 20),
 	}
 
-	e := p.Get(prefix+"_ENDPOINT", "")
-	if e == "" {
-		return nil, fmt.Errorf("%sENDPOINT is required", prefix)
+	if c.DefaultTTL <= 0 || c.DefaultTTL > c.MaxTTL {
+		l.Fail(prefix+"_DEFAULT_TTL", "must be positive and at most PAT_MAX_TTL, got %s", c.DefaultTTL)
 	}
 
-	return &S3Config{
-		AccessToken: a,
-		SecretKey:   s,
-		Region:      r,
-		BucketName:  b,
-		Endpoint:    e,
-	}, nil
+	if c.MaxPerUser < 1 {
+		l.Fail(prefix+"_MAX_PER_USER", "must be positive, got %d", c.MaxPerUser)
+	}
+
+	return c
 }
 
-func newLoggerConfig(p Provider) (*LoggerConfig, error) {
-	const prefix = "LOGGER"
+func newOIDCConfig(l *Loader) OIDCConfig {
+	const prefix = "OIDC"
+
+	c := OIDCConfig{
+		Enabled:      l.Bool(prefix+"_ENABLED", false),
+		ClientSecret: l.String(prefix+"_CLIENT_SECRET", ""),
+		Scopes:       l.Strings(prefix+"_SCOPES", []string{"openid", "email", "profile"}),
+		GroupsClaim:  l.String(prefix+"_GROUPS_CLAIM", "groups"),
+		GroupRoles:   l.StringMap(prefix+"_GROUP_ROLES", map[string]string{}),
+		EmailDomain:  l.String(prefix+"_EMAIL_DOMAIN", "astanait.edu.kz"),
+		StateTTL:     l.Duration(prefix+"_STATE_TTL", 10*time.Minute),
+		CodeTTL:      l.Duration(prefix+"_CODE_TTL", time.Minute),
+	}
+
+	// The provider is only required when the single sign-on is enabled
+	if c.Enabled {
+		c.Issuer = l.Required(prefix + "_ISSUER")
+		c.ClientID = l.Required(prefix + "_CLIENT_ID")
+		c.RedirectURL = l.Required(prefix + "_REDIRECT_URL")
+		c.FrontendURL = l.Required(prefix + "_FRONTEND_URL")
+	} else {
+		c.Issuer = l.String(prefix+"_ISSUER", "")
+		c.ClientID = l.String(prefix+"_CLIENT_ID", "")
+		c.RedirectURL = l.String(prefix+"_REDIRECT_URL", "")
+		c.FrontendURL = l.String(prefix+"_FRONTEND_URL", "")
+	}
+
+	if c.FrontendURL != "" {
+		if u, err := url.Parse(c.FrontendURL); err != nil || !u.IsAbs() {
+			l.Fail(prefix+"_FRONTEND_URL", "must be an absolute URL, got %q", c.FrontendURL)
+		}
+	}
 
-	var l zapcore.Level
-	err := l.UnmarshalText([]byte(p.Get(prefix+"_LEVEL", "info")))
-	if err != nil {
-		return nil, err
+	if c.StateTTL <= 0 {
+		l.Fail(prefix+"_STATE_TTL", "must be positive, got %s", c.StateTTL)
 	}
 
-	var le zapcore.LevelEncoder
-	err = le.UnmarshalText([]byte(p.Get(prefix+"_LEVELENCODER", "capitalColor")))
-	if err != nil {
-		return nil, err
+	if c.CodeTTL <= 0 {
+		l.Fail(prefix+"_CODE_TTL", "must be positive, got %s", c.CodeTTL)
 	}
 
-	f := getBool(p, prefix+"_ENABLEFILE", true)
-	s := getBool(p, prefix+"_ENABLESTDOUT", true)
-	if !f && !s {
-		return nil, fmt.Errorf("at least one sink must be enabled")
+	return c
+}
+
+func newMFAConfig(l *Loader) MFAConfig {
+	const prefix = "MFA"
+
+	c := MFAConfig{
+		Issuer:        l.String(prefix+"_ISSUER", "ACSP"),
+		ChallengeTTL:  l.Duration(prefix+"_CHALLENGE_TTL", 5*time.Minute),
+		RecoveryCodes: l.Int(prefix+"_RECOVERY_CODES", 10),
+		RequiredRoles: l.Strings(prefix+"_REQUIRED_ROLES", []string{}),
 	}
 
-	ss := []string(nil)
-	err = json.Unmarshal([]byte(p.Get(prefix+"_SINKS", `["stdout"]`)), &ss)
-	if err != nil {
-		return nil, errors.Wrap(err, "no sinks set")
+	if c.ChallengeTTL <= 0 {
+		l.Fail(prefix+"_CHALLENGE_TTL", "must be positive, got %s", c.ChallengeTTL)
 	}
 
-	ess := []string(nil)
-	err = json.Unmarshal([]byte(p.Get(prefix+"_ERRORSINKS", `["stderr"]`)), &ess)
-	if err != nil {
-		return nil, errors.Wrap(err, "no error sinks set")
+	if c.RecoveryCodes < 1 {
+		l.Fail(prefix+"_RECOVERY_CODES", "must be positive, got %d", c.RecoveryCodes)
 	}
 
-	return &LoggerConfig{
-		Level:        l,
-		Encoding:     p.Get("LOGGER_ENCODING", "console"),
-		LevelEncoder: le,
-		Sinks:        ss,
-		ErrorSinks:   ess,
-		MaxSizeMB:    GetInt(p, "LOGGER_MAXSIZEMB", 128),
-		MaxAgeDays:   GetInt(p, "LOGGER_MAXAGEDAYS", 168),
-		MaxBackups:   GetInt(p, "LOGGER_MAXBACKUPS", 16),
-		BatchSize:    getUint(p, "LOGGER_BATCHSIZE", 2),
-	}, nil
+	return c
 }
 
-func newAuthConfig(p Provider) (*AuthConfig, error) {
+func newLockoutConfig(l *Loader) LockoutConfig {
+	const prefix = "LOGIN"
 
-	const prefix = "JWT"
+	c := LockoutConfig{
+		MaxFailures:     l.Int(prefix+"_MAX_FAILURES", 5),
+		IPMaxFailures:   l.Int(prefix+"_IP_MAX_FAILURES", 20),
+		FailureWindow:   l.Duration(prefix+"_FAILURE_WINDOW", 15*time.Minute),
+		LockoutDuration: l.Duration(prefix+"_LOCKOUT_DURATION", 15*time.Minute),
+		DelayAfter:      l.Int(prefix+"_DELAY_AFTER", 3),
+		Delay:           l.Duration(prefix+"_DELAY", time.Second),
+		MaxDelay:        l.Duration(prefix+"_MAX_DELAY", 30*time.Second),
+	}
 
-	accessTokenKey := p.Get(prefix+"_ACCESS_TOKEN_SECRET_KEY", "")
-	if accessTokenKey == "" {
-		return nil, apperror.ErrEnvVariableParsing
+	if c.MaxFailures < 1 {
+		l.Fail(prefix+"_MAX_FAILURES", "must be positive, got %d", c.MaxFailures)
 	}
 
-	refreshTokenKey := p.Get(prefix+"_REFRESH_TOKEN_SECRET_KEY", "")
-	if refreshTokenKey == "" {
-		return nil, apperror.ErrEnvVariableParsing
+	if c.IPMaxFailures < 1 {
+		l.Fail(prefix+"_IP_MAX_FAILURES", "must be positive, got %d", c.IPMaxFailures)
 	}
 
-	accessTokenTTL := getDuration(p, prefix+"_ACCESS_TOKEN_TTL", 0)
-	if accessTokenTTL == 0 {
-		return nil, apperror.ErrEnvVariableParsing
+	if c.FailureWindow <= 0 {
+		l.Fail(prefix+"_FAILURE_WINDOW", "must be positive, got %s", c.FailureWindow)
 	}
 
-	refreshTokenTTL := getDuration(p, prefix+"_REFRESH_TOKEN_TTL", 0)
-	if refreshTokenTTL == 0 {
-		return nil, apperror.ErrEnvVariableParsing
+	if c.LockoutDuration <= 0 {
+		l.Fail(prefix+"_LOCKOUT_DURATION", "must be positive, got %s", c.LockoutDuration)
 	}
 
-	return &AuthConfig{
-		JWT: JWTConfig{
-			AccessTokenTTL:     accessTokenTTL,
-			RefreshTokenTTL:    refreshTokenTTL,
-			AccessTokenSecret:  accessTokenKey,
-			RefreshTokenSecret: refreshTokenKey,
-		},
-	}, nil
+	return c
 }
 
-func getBool(p Provider, key string, fallback bool) bool {
+func GetBool(p Provider, key string, fallback bool) bool {
 	v := p.Get(key, strconv.FormatBool(fallback))
 	b, err := strconv.ParseBool(v)
 	if err == nil {
@@ -387,7 +644,7 @@ func getBool(p Provider, key string, fallback bool) bool {
 }
 
 func GetInt(p Provider, key string, fallback int) int {
-	v := p.Get(key, "")
+	v := p.Get(key, strconv.Itoa(fallback))
 	i, err := strconv.Atoi(v)
 	if err == nil {
 		return i
@@ -395,43 +652,3 @@ func GetInt(p Provider, key string, fallback int) int {
 
 	return fallback
 }
-
-func getFloat64(p Provider, key string, fallback float64) float64 {
-	v := p.Get(key, "")
-	f64, err := strconv.ParseFloat(v, 64)
-	if err == nil {
-		return f64
-	}
-
-	return fallback
-}
-
-func getInt64(p Provider, key string, fallback int64) int64 {
-	v := p.Get(key, "")
-	i64, err := strconv.ParseInt(v, 10, 64)
-	if err == nil {
-		return i64
-	}
-
-	return fallback
-}
-
-func getUint(p Provider, key string, fallback uint) uint {
-	v := p.Get(key, "")
-	u, err := strconv.ParseUint(v, 10, 0)
-	if err == nil {
-		return uint(u)
-	}
-
-	return fallback
-}
-
-func getDuration(p Provider, key string, fallback time.Duration) time.Duration {
-	v := p.Get(key, "")
-	d, err := time.ParseDuration(v)
-	if err == nil {
-		return d
-	}
-
-	return fallback
-}
@@ -2,6 +2,8 @@ package config
 
 import (
 	"github.com/gofiber/fiber/v2"
+
+	"acsp/internal/apperror"
 )
 
 // FiberConfig returns a fiber.Config.
@@ -9,5 +11,6 @@ func FiberConfig(appCfg *Config) fiber.Config {
 	return fiber.Config{
 		ReadTimeout:  appCfg.HTTP.ReadTimeout,
 		WriteTimeout: appCfg.HTTP.WriteTimeout,
+		ErrorHandler: apperror.ErrorHandler,
 	}
 }
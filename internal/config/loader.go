@@ -0,0 +1,158 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationError lists every missing or invalid key of a configuration.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Loader reads typed values of a Provider. Instead of failing on the first bad key it collects
+// the problems of every key, see Err.
+type Loader struct {
+	p        Provider
+	problems []string
+}
+
+// NewLoader creates a Loader of the provider.
+func NewLoader(p Provider) *Loader {
+	return &Loader{p: p}
+}
+
+// Err returns a *ValidationError of the problems found so far, nil if there are none.
+func (l *Loader) Err() error {
+	if len(l.problems) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Problems: l.problems}
+}
+
+// Fail records a problem of the key.
+func (l *Loader) Fail(key, format string, args ...interface{}) {
+	l.problems = append(l.problems, key+": "+fmt.Sprintf(format, args...))
+}
+
+// String returns the value of the key, or the fallback when it is not set.
+func (l *Loader) String(key, fallback string) string {
+	return l.p.Get(key, fallback)
+}
+
+// Required returns the value of the key, which must be set and not empty.
+func (l *Loader) Required(key string) string {
+	v := l.p.Get(key, "")
+	if v == "" {
+		l.Fail(key, "is required")
+	}
+
+	return v
+}
+
+// OneOf returns the value of the key, which must be one of the allowed values.
+func (l *Loader) OneOf(key, fallback string, allowed ...string) string {
+	v := l.p.Get(key, fallback)
+	for _, a := range allowed {
+		if v == a {
+			return v
+		}
+	}
+
+	l.Fail(key, "must be one of %s, got %q", strings.Join(allowed, ", "), v)
+
+	return v
+}
+
+func (l *Loader) Int(key string, fallback int) int {
+	return parse(l, key, fallback, "an integer", strconv.Atoi)
+}
+
+func (l *Loader) Uint(key string, fallback uint) uint {
+	return parse(l, key, fallback, "a non-negative integer", func(v string) (uint, error) {
+		u, err := strconv.ParseUint(v, 10, 0)
+
+		return uint(u), err
+	})
+}
+
+func (l *Loader) Bool(key string, fallback bool) bool {
+	return parse(l, key, fallback, "a boolean", strconv.ParseBool)
+}
+
+func (l *Loader) Float64(key string, fallback float64) float64 {
+	return parse(l, key, fallback, "a number", func(v string) (float64, error) {
+		return strconv.ParseFloat(v, 64)
+	})
+}
+
+// Duration parses values like 1h30m.
+func (l *Loader) Duration(key string, fallback time.Duration) time.Duration {
+	return parse(l, key, fallback, "a duration like 30s or 1h", time.ParseDuration)
+}
+
+// RequiredDuration returns the duration of the key, which must be set and positive.
+func (l *Loader) RequiredDuration(key string) time.Duration {
+	v := l.p.Get(key, "")
+	if v == "" {
+		l.Fail(key, "is required")
+
+		return 0
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		l.Fail(key, "must be a positive duration like 30s or 1h, got %q", v)
+
+		return 0
+	}
+
+	return d
+}
+
+// Strings parses a JSON array of strings, e.g. ["stdout"].
+func (l *Loader) Strings(key string, fallback []string) []string {
+	return parse(l, key, fallback, `a JSON array like ["a", "b"]`, func(v string) ([]string, error) {
+		var ss []string
+		err := json.Unmarshal([]byte(v), &ss)
+
+		return ss, err
+	})
+}
+
+// StringMap parses a JSON object of strings, e.g. {"a": "b"}.
+func (l *Loader) StringMap(key string, fallback map[string]string) map[string]string {
+	return parse(l, key, fallback, `a JSON object like {"a": "b"}`, func(v string) (map[string]string, error) {
+		var m map[string]string
+		err := json.Unmarshal([]byte(v), &m)
+
+		return m, err
+	})
+}
+
+func parse[T any](l *Loader, key string, fallback T, kind string, fn func(string) (T, error)) T {
+	v, ok := l.p.Lookup(key)
+	if !ok {
+		// Reading the fallback through the provider, so that it shows up in the effective settings
+		l.p.Get(key, fmt.Sprint(fallback))
+
+		return fallback
+	}
+
+	t, err := fn(v)
+	if err != nil {
+		l.Fail(key, "must be %s, got %q", kind, v)
+
+		return fallback
+	}
+
+	return t
+}
@@ -0,0 +1,248 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/joho/godotenv"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Provider represents a configuration store backed by a key-value mapping.
+type Provider interface {
+	// Lookup returns the value of the key and whether the key is set, an empty value is a set value.
+	Lookup(key string) (string, bool)
+	// Get returns the value of the key, or the fallback when the key is not set.
+	Get(key, fallback string) string
+}
+
+// mapProvider is a Provider of a fixed set of values.
+type mapProvider struct {
+	name   string
+	values map[string]string
+}
+
+func (p *mapProvider) Lookup(key string) (string, bool) {
+	v, ok := p.values[key]
+
+	return v, ok
+}
+
+func (p *mapProvider) Get(key, fallback string) string {
+	return get(p, key, fallback)
+}
+
+func (p *mapProvider) String() string {
+	return p.name
+}
+
+// NewMapProvider creates a Provider of the given values, e.g. for tests.
+func NewMapProvider(name string, values map[string]string) Provider {
+	return &mapProvider{name: name, values: values}
+}
+
+// NewDotenvProvider creates a .env file-backed Provider.
+func NewDotenvProvider(filepath string) (Provider, error) {
+	vs, err := godotenv.Read(filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mapProvider{
+		name:   filepath,
+		values: vs,
+	}, nil
+}
+
+// NewYAMLProvider creates a YAML file-backed Provider. Nested keys are joined with underscores and upper-cased,
+// so that
+//
+//	http:
+//	  read_timeout: 10s
+//
+// is the HTTP_READ_TIMEOUT key. Lists are kept as JSON arrays, e.g. LOGGER_SINKS.
+func NewYAMLProvider(filepath string) (Provider, error) {
+	body, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	var tree map[string]interface{}
+
+	err = yaml.Unmarshal(body, &tree)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when parsing %s", filepath)
+	}
+
+	values := map[string]string{}
+
+	err = flatten("", tree, values)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when parsing %s", filepath)
+	}
+
+	return &mapProvider{
+		name:   filepath,
+		values: values,
+	}, nil
+}
+
+func flatten(prefix string, tree map[string]interface{}, values map[string]string) error {
+	for k, v := range tree {
+		key := strings.ToUpper(k)
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+
+		switch v := v.(type) {
+		case map[string]interface{}:
+			err := flatten(key, v, values)
+			if err != nil {
+				return err
+			}
+
+		case []interface{}:
+			b, err := json.Marshal(v)
+			if err != nil {
+				return errors.Wrapf(err, "invalid list %s", key)
+			}
+
+			values[key] = string(b)
+
+		case nil:
+			values[key] = ""
+
+		default:
+			values[key] = fmt.Sprint(v)
+		}
+	}
+
+	return nil
+}
+
+// envProvider is a Provider of the environment variables.
+type envProvider struct {
+	prefix string
+}
+
+// NewEnvProvider creates a Provider of the environment variables, the prefix is prepended to the keys.
+func NewEnvProvider(prefix string) Provider {
+	return &envProvider{prefix: prefix}
+}
+
+func (p *envProvider) Lookup(key string) (string, bool) {
+	return os.LookupEnv(p.prefix + key)
+}
+
+func (p *envProvider) Get(key, fallback string) string {
+	return get(p, key, fallback)
+}
+
+func (p *envProvider) String() string {
+	return "env"
+}
+
+func get(p Provider, key, fallback string) string {
+	v, ok := p.Lookup(key)
+	if ok {
+		return v
+	}
+
+	return fallback
+}
+
+// Setting is a key read by the application with its effective value.
+type Setting struct {
+	Key   string
+	Value string
+	// Source is the provider the value comes from, "default" when no provider has the key
+	Source string
+}
+
+// ProviderChain allows value overriding by chaining multiple Provider, the first provider having a key wins.
+// It remembers every key read, see Settings.
+type ProviderChain struct {
+	providers []Provider
+
+	mu   sync.Mutex
+	read map[string]Setting
+}
+
+// NewProviderChain allows value overriding by chaining multiple Provider.
+func NewProviderChain(ps ...Provider) *ProviderChain {
+	return &ProviderChain{
+		providers: ps,
+		read:      map[string]Setting{},
+	}
+}
+
+func (c *ProviderChain) Lookup(key string) (string, bool) {
+	for _, p := range c.providers {
+		v, ok := p.Lookup(key)
+		if ok {
+			c.remember(Setting{Key: key, Value: v, Source: fmt.Sprint(p)})
+
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
+func (c *ProviderChain) Get(key, fallback string) string {
+	v, ok := c.Lookup(key)
+	if ok {
+		return v
+	}
+
+	c.remember(Setting{Key: key, Value: fallback, Source: "default"})
+
+	return fallback
+}
+
+func (c *ProviderChain) remember(s Setting) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.read[s.Key] = s
+}
+
+// Settings returns the keys read so far ordered by name, the values of secrets are redacted.
+func (c *ProviderChain) Settings() []Setting {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	settings := make([]Setting, 0, len(c.read))
+	for _, s := range c.read {
+		s.Value = Redact(s.Key, s.Value)
+		settings = append(settings, s)
+	}
+
+	sort.Slice(settings, func(i, j int) bool {
+		return settings[i].Key < settings[j].Key
+	})
+
+	return settings
+}
+
+// Redact hides the value of a secret key, e.g. OIDC_CLIENT_SECRET, PSQL_PASSWORD or S3_ACCESS_TOKEN.
+func Redact(key, value string) string {
+	if value == "" {
+		return value
+	}
+
+	key = strings.ToUpper(key)
+	parts := strings.Split(key, "_")
+	last := parts[len(parts)-1]
+
+	if strings.Contains(key, "SECRET") || strings.Contains(key, "PASSWORD") || last == "TOKEN" || last == "KEY" {
+		return "******"
+	}
+
+	return value
+}
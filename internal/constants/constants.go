@@ -19,19 +19,31 @@ const (
 	CourseModulesTable               = "course_modules"
 	CourseModuleLessonsTable         = "course_module_lessons"
 	CourseLessonCommentsTable        = "course_lesson_comments"
-	CourseLessonCommentsAnswersTable = "course_lesson_comments_answers"
+	CourseLessonCommentsAnswersTable = "course_lesson_comment_answers"
+	StoredObjectsTable               = "stored_objects"
+	OutboxEventsTable                = "outbox_events"
+	SchemaMigrationsTable            = "schema_migrations"
+	SettingsChangesTable             = "settings_changes"
+	FeatureFlagsTable                = "feature_flags"
+	AccountLockoutsTable             = "account_lockouts"
+	UserTOTPTable                    = "user_totp"
+	RecoveryCodesTable               = "mfa_recovery_codes"
+	UserIdentitiesTable              = "user_identities"
+	PersonalAccessTokensTable        = "personal_access_tokens"
+	AuditEventsTable                 = "audit_events"
+	NotificationsTable               = "notifications"
 	DatabaseName                     = "postgres"
 )
 
 const (
-	BucketName                  = "acsp-avatars"
-	EndPoint                    = "object.pscloud.io"
 	UsersAvatarsFolder          = "user-avatars"
 	ArticlesImagesFolder        = "articles"
 	MaterialsImagesFolder       = "materials"
 	ProjectsImagesFolder        = "projects"
 	ProjectsModulesImagesFolder = "projects-modules"
 	DisciplinesImagesFolder     = "disciplines"
+	CoursesImagesFolder         = "courses"
+	StagingFolder               = "staging" // uploads waiting for their owners to be committed
 )
 
 type VoteType int
@@ -48,3 +60,115 @@ const (
 	AcceptedStatus = "ACCEPTED"
 	DeclinedStatus = "DECLINED"
 )
+
+const (
+	OutboxPendingStatus = "PENDING"
+	OutboxDoneStatus    = "DONE"
+	OutboxFailedStatus  = "FAILED"
+)
+
+const (
+	// FinalizeUploadEvent moves a staged upload to its final key once its owner is committed
+	FinalizeUploadEvent = "storage.finalize_upload"
+	// SendNotificationEvent notifies a user once the change they are notified about is committed
+	SendNotificationEvent = "notification.send"
+)
+
+const (
+	// NotificationCardInvitation tells the author of a card that someone wants to join it
+	NotificationCardInvitation = "card.invitation"
+	// NotificationsLimit is the number of the latest notifications returned to a user
+	NotificationsLimit = 50
+)
+
+const (
+	// RuntimeSettingsKey is the redis key of the runtime settings shared by all instances
+	RuntimeSettingsKey = "settings:runtime"
+	// FeatureFlagsKey is the redis key of the cached feature flags
+	FeatureFlagsKey = "feature_flags"
+	// RateLimitKeyPrefix is the prefix of the redis keys of the requests counted by the rate limiter
+	RateLimitKeyPrefix = "rate_limit:"
+	// LoginFailuresKeyPrefix is the prefix of the redis keys of the failed sign-ins of an account or an IP
+	LoginFailuresKeyPrefix = "login_failures:"
+	// LoginLockKeyPrefix is the prefix of the redis keys of the locked accounts and IPs
+	LoginLockKeyPrefix = "login_lock:"
+	// MFAChallengeKeyPrefix is the prefix of the redis keys of the sign-ins waiting for the second step
+	MFAChallengeKeyPrefix = "mfa_challenge:"
+	// TOTPUsedKeyPrefix is the prefix of the redis keys of the used TOTP codes, a code is accepted once
+	TOTPUsedKeyPrefix = "totp_used:"
+	// OIDCStateKeyPrefix is the prefix of the redis keys of the sign-ins at the OpenID Connect provider
+	OIDCStateKeyPrefix = "oidc_state:"
+	// OIDCCodeKeyPrefix is the prefix of the redis keys of the one-time codes of the completed sign-ins
+	OIDCCodeKeyPrefix = "oidc_code:"
+)
+
+const (
+	// DefaultRateLimitPolicy limits all requests, it is a runtime setting
+	DefaultRateLimitPolicy = "default"
+	// AuthRateLimitPolicy limits the sign-ins and sign-ups
+	AuthRateLimitPolicy = "auth"
+	// WriteRateLimitPolicy limits the creation of comments and invitations
+	WriteRateLimitPolicy = "write"
+)
+
+const (
+	// PersonalAccessTokenPrefix tells the personal access tokens from the JWTs
+	PersonalAccessTokenPrefix = "acsp_pat_"
+	// ScopeRead allows the personal access tokens to call the GET endpoints
+	ScopeRead = "read"
+	// ScopeWrite allows the personal access tokens to call all endpoints, the roles are scopes as well
+	ScopeWrite = "write"
+)
+
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionLock    = "lock"
+	AuditActionUnlock  = "unlock"
+	AuditActionEnable  = "enable"
+	AuditActionDisable = "disable"
+	AuditActionRevoke  = "revoke"
+)
+
+const (
+	AuditTargetUser                = "user"
+	AuditTargetContest             = "contest"
+	AuditTargetCourse              = "course"
+	AuditTargetDiscipline          = "discipline"
+	AuditTargetTwoFactor           = "two_factor"
+	AuditTargetPersonalAccessToken = "personal_access_token"
+	AuditTargetFeatureFlag         = "feature_flag"
+	AuditTargetSettings            = "settings"
+	// AuditTargetAccount is the email of a sign-in, it may not belong to a user
+	AuditTargetAccount = "account"
+	AuditTargetIP      = "ip"
+	// AuditSettingsID is the target ID of the runtime settings, there is one set of them
+	AuditSettingsID = "runtime"
+)
+
+const (
+	// DefaultAuditEventsLimit is the page size of the audit events when it is not given
+	DefaultAuditEventsLimit = 50
+	// MaxAuditEventsLimit is the largest page size of the audit events
+	MaxAuditEventsLimit = 200
+)
+
+const (
+	HealthStatusUp   = "up"
+	HealthStatusDown = "down"
+)
+
+const (
+	CodeConnectionFeature = "code-connection"
+	CodingLabFeature      = "coding-lab"
+)
+
+const (
+	BeginnerLevel     = "beginner"
+	IntermediateLevel = "intermediate"
+	AdvancedLevel     = "advanced"
+)
+
+// ProjectLevels are the levels of the projects
+var ProjectLevels = []string{BeginnerLevel, IntermediateLevel, AdvancedLevel}
@@ -0,0 +1,13 @@
+package dto
+
+// GetAuditEvents DTO for filtering the audit events, the query parameters of /admin/audit
+type GetAuditEvents struct {
+	ActorID    int    `query:"actor_id" json:"actor_id" validate:"min=0"`
+	Action     string `query:"action" json:"action" validate:"max=50"`
+	TargetType string `query:"target_type" json:"target_type" validate:"max=50"`
+	TargetID   string `query:"target_id" json:"target_id" validate:"max=100"`
+	From       string `query:"from" json:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To         string `query:"to" json:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Before     int64  `query:"before" json:"before" validate:"min=0"`
+	Limit      int    `query:"limit" json:"limit" validate:"min=0,max=200"`
+}
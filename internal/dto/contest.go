@@ -1,17 +1,17 @@
 package dto
 
 type CreateContest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Link        string `json:"link"`
-	StartDate   string `json:"start_date"`
-	EndDate     string `json:"end_date"`
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description" validate:"required"`
+	Link        string `json:"link" validate:"required,httpurl"`
+	StartDate   string `json:"start_date" validate:"required,date"`
+	EndDate     string `json:"end_date" validate:"required,date,dateafter=start_date"`
 }
 
 type UpdateContest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Link        string `json:"link"`
-	StartDate   string `json:"start_date"`
-	EndDate     string `json:"end_date"`
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description" validate:"required"`
+	Link        string `json:"link" validate:"required,httpurl"`
+	StartDate   string `json:"start_date" validate:"required,date"`
+	EndDate     string `json:"end_date" validate:"required,date,dateafter=start_date"`
 }
@@ -29,14 +29,14 @@ type UpdateCourseModule struct {
 type CreateCourseModuleLesson struct {
 	Title        string `json:"title" validate:"required"`
 	Description  string `json:"description" validate:"required"`
-	ReferenceURL string `json:"reference_url"`
+	ReferenceURL string `json:"reference_url" validate:"omitempty,httpurl"`
 }
 
 // UpdateCourseModuleLesson DTO for Updating Course Module Lesson
 type UpdateCourseModuleLesson struct {
 	Title        string `json:"title" validate:"required"`
 	Description  string `json:"description" validate:"required"`
-	ReferenceURL string `json:"reference_url"`
+	ReferenceURL string `json:"reference_url" validate:"omitempty,httpurl"`
 }
 
 // CreateLessonComment DTO for Creating Course Module Lesson Comment
@@ -0,0 +1,20 @@
+package dto
+
+// CreateFeatureFlag DTO for creating a feature flag
+type CreateFeatureFlag struct {
+	Key         string   `json:"key" validate:"required,max=100"`
+	Description string   `json:"description"`
+	Enabled     bool     `json:"enabled"`
+	Roles       []string `json:"roles" validate:"omitempty,dive,required"`
+	UserIDs     []int64  `json:"user_ids" validate:"omitempty,dive,min=1"`
+	Percentage  int      `json:"percentage" validate:"min=0,max=100"`
+}
+
+// UpdateFeatureFlag DTO for updating a feature flag, the rules are replaced
+type UpdateFeatureFlag struct {
+	Description string   `json:"description"`
+	Enabled     bool     `json:"enabled"`
+	Roles       []string `json:"roles" validate:"omitempty,dive,required"`
+	UserIDs     []int64  `json:"user_ids" validate:"omitempty,dive,min=1"`
+	Percentage  int      `json:"percentage" validate:"min=0,max=100"`
+}
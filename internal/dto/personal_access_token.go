@@ -0,0 +1,9 @@
+package dto
+
+// CreatePersonalAccessToken DTO for creating a personal access token
+type CreatePersonalAccessToken struct {
+	Name   string   `json:"name" validate:"required,max=100"`
+	Scopes []string `json:"scopes" validate:"required,min=1,dive,required"`
+	// ExpiresInDays is the lifetime of the token, PAT_DEFAULT_TTL when it is zero
+	ExpiresInDays int `json:"expires_in_days" validate:"min=0"`
+}
@@ -1,15 +1,15 @@
 package dto
 
 type CreateProject struct {
-	Title       string `json:"title" form:"title" db:"title"`
-	Description string `json:"description" form:"description" db:"description"`
-	Level       string `json:"level" form:"level" db:"level"`
-	WorkHours   int    `json:"work_hours" form:"work_hours" db:"work_hours"`
+	Title       string `json:"title" form:"title" db:"title" validate:"required"`
+	Description string `json:"description" form:"description" db:"description" validate:"required"`
+	Level       string `json:"level" form:"level" db:"level" validate:"required,level"`
+	WorkHours   int    `json:"work_hours" form:"work_hours" db:"work_hours" validate:"min=1"`
 }
 
 type UpdateProject struct {
-	Title       string `json:"title" form:"title" db:"title"`
-	Description string `json:"description" form:"description" db:"description"`
-	Level       string `json:"level" form:"level" db:"level"`
-	WorkHours   int    `json:"work_hours" form:"work_hours" db:"work_hours"`
+	Title       string `json:"title" form:"title" db:"title" validate:"required"`
+	Description string `json:"description" form:"description" db:"description" validate:"required"`
+	Level       string `json:"level" form:"level" db:"level" validate:"required,level"`
+	WorkHours   int    `json:"work_hours" form:"work_hours" db:"work_hours" validate:"min=1"`
 }
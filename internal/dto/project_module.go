@@ -4,12 +4,12 @@ package dto
 type CreateProjectModule struct {
 	Title        string `json:"title" form:"title" binding:"required" validate:"required"`
 	Description  string `json:"description" form:"description" binding:"required" validate:"required"`
-	ReferenceURL string `json:"reference_url" form:"reference_url"`
+	ReferenceURL string `json:"reference_url" form:"reference_url" validate:"omitempty,httpurl"`
 }
 
 // UpdateProjectModule DTO for Updating Project Module
 type UpdateProjectModule struct {
 	Title        string `json:"title" form:"title" binding:"required" validate:"required"`
 	Description  string `json:"description" form:"description" binding:"required" validate:"required"`
-	ReferenceURL string `json:"reference_url" form:"reference_url"`
+	ReferenceURL string `json:"reference_url" form:"reference_url" validate:"omitempty,httpurl"`
 }
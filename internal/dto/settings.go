@@ -0,0 +1,13 @@
+package dto
+
+// PatchSettings changes the runtime settings, omitted fields are kept.
+type PatchSettings struct {
+	LogLevel    *string         `json:"log_level" validate:"omitempty,oneof=debug info warn error"`
+	CORSOrigins []string        `json:"cors_origins" validate:"omitempty,dive,required"`
+	RateLimit   *PatchRateLimit `json:"rate_limit"`
+}
+
+type PatchRateLimit struct {
+	Max           *int `json:"max" validate:"omitempty,min=1"`
+	WindowSeconds *int `json:"window_seconds" validate:"omitempty,min=1"`
+}
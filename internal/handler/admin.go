@@ -3,13 +3,11 @@ package handler
 import (
 	"net/http"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 
 	"acsp/internal/apperror"
 	"acsp/internal/dto"
 	"acsp/internal/logging"
-	"acsp/internal/validation"
 )
 
 // @Summary Get all users
@@ -20,23 +18,19 @@ import (
 // @Accept  json
 // @Produce  json
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/admin/users [get]
 func (h *Handler) getAllUsers(c *fiber.Ctx) error {
 	users, err := h.services.Users.GetAllUsers(c.UserContext())
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"errors": false,
-		"count":  len(users),
-		"users":  users,
+		"count": len(users),
+		"users": users,
 	})
 }
 
@@ -49,9 +43,9 @@ func (h *Handler) getAllUsers(c *fiber.Ctx) error {
 // @Produce  json
 // @Param id path int true "user id"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/admin/users/{id} [get]
 func (h *Handler) getUserByID(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -59,23 +53,16 @@ func (h *Handler) getUserByID(c *fiber.Ctx) error {
 
 	id := c.Params("id")
 	if id == "" {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	user, err := h.services.Users.GetUserByID(c.UserContext(), id)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(http.StatusOK).JSON(fiber.Map{
-		"errors": false,
-		"user":   user,
+		"user": user,
 	})
 }
 
@@ -89,9 +76,9 @@ func (h *Handler) getUserByID(c *fiber.Ctx) error {
 // @Param id path int true "user id"
 // @Param request body dto.UpdateUser true "user information"
 // @Success 200 {object} map[string]interface{} "User updated"
-// @Failure 400 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/admin/users/{id} [put]
 func (h *Handler) updateUser(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -99,37 +86,23 @@ func (h *Handler) updateUser(c *fiber.Ctx) error {
 
 	id := c.Params("id")
 	if id == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": "id is required",
-		})
+		return apperror.New(apperror.KindValidation, "id is required")
 	}
 
 	var input dto.UpdateUser
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return apperror.WithKind(err, apperror.KindValidation)
 	}
 
-	validate := validator.New()
-	if err := validate.Struct(input); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": validation.ValidatorErrors(err),
-		})
+	if err := validate(c, &input); err != nil {
+		return err
 	}
 
 	if err := h.services.Users.UpdateUser(c.UserContext(), id, input); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"errors":  false,
 		"message": "User updated",
 	})
 }
@@ -143,8 +116,8 @@ func (h *Handler) updateUser(c *fiber.Ctx) error {
 // @Produce  json
 // @Param id path string true "user id"
 // @Success 200 {object} string "User deleted"
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/admin/users/{id} [delete]
 func (h *Handler) deleteUser(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -152,26 +125,74 @@ func (h *Handler) deleteUser(c *fiber.Ctx) error {
 
 	id := c.Params("id")
 	if id == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": "id is required",
-		})
+		return apperror.New(apperror.KindValidation, "id is required")
 	}
 
 	err := h.services.Users.DeleteUser(c.UserContext(), id)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"errors":  false,
 		"message": "User deleted",
 	})
 }
 
+// @Summary Unlock a user by id
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description Unlock the sign-in of a user locked after failed sign-ins
+// @ID unlock-user-by-id
+// @Accept  json
+// @Produce  json
+// @Param id path int true "user id"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/admin/users/{id}/unlock [post]
+func (h *Handler) unlockUser(c *fiber.Ctx) error {
+	l := logging.LoggerFromContext(c.UserContext())
+	l.Info("unlocking a user...")
+
+	id := c.Params("id")
+	if id == "" {
+		return apperror.New(apperror.KindValidation, "id is required")
+	}
+
+	err := h.services.Lockout.Unlock(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "User unlocked",
+	})
+}
+
+// @Summary Get orphaned objects of the storage
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description Get stored objects whose owners are deleted or replaced, they are deleted by the storage janitor
+// @ID get-storage-orphans
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/admin/storage/orphans [get]
+func (h *Handler) getStorageOrphans(c *fiber.Ctx) error {
+	orphans, err := h.services.Janitor.FindOrphans(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"count":   len(orphans),
+		"orphans": orphans,
+	})
+}
+
 // @Summary Create a contest
 // @Security ApiKeyAuth
 // @Tags admin
@@ -181,9 +202,9 @@ func (h *Handler) deleteUser(c *fiber.Ctx) error {
 // @Produce  json
 // @Param request body dto.CreateContest true "contest information"
 // @Success 200 {integer} map[string]interface{} "message"
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/coding-lab/admin/contests [post]
 func (h *Handler) createContest(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -191,31 +212,20 @@ func (h *Handler) createContest(c *fiber.Ctx) error {
 
 	input := dto.CreateContest{}
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrBadInputBody,
-		})
+		return apperror.ErrBadInputBody
 	}
 
-	validate := validator.New()
-	err := validate.Struct(input)
+	err := validate(c, &input)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": validation.ValidatorErrors(err),
-		})
+		return err
 	}
 
 	err = h.services.Contests.Create(c.UserContext(), input)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"errors":  false,
 		"message": "Contest created",
 	})
 }
@@ -230,9 +240,9 @@ func (h *Handler) createContest(c *fiber.Ctx) error {
 // @Param id path int true "contest id"
 // @Param request body dto.UpdateContest true "contest information"
 // @Success 200 {object} map[string]interface{} "Contest updated"
-// @Failure 400 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/admin/contests/{id} [put]
 func (h *Handler) updateContest(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -242,33 +252,20 @@ func (h *Handler) updateContest(c *fiber.Ctx) error {
 
 	contestID := c.Params("id", "")
 	if contestID == "" {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrBodyParsed,
-		})
+		return apperror.ErrBodyParsed
 	}
 
-	validate := validator.New()
-	if err := validate.Struct(input); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": validation.ValidatorErrors(err),
-		})
+	if err := validate(c, &input); err != nil {
+		return err
 	}
 
 	err := h.services.Contests.Update(c.UserContext(), contestID, input)
 	if err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(http.StatusOK).JSON(fiber.Map{"message": "Contest updated"})
@@ -283,8 +280,8 @@ func (h *Handler) updateContest(c *fiber.Ctx) error {
 // @Produce  json
 // @Param id path string true "contest id"
 // @Success 200 {object} string "Contest deleted"
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/admin/contests/{id} [delete]
 func (h *Handler) deleteContest(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -292,22 +289,15 @@ func (h *Handler) deleteContest(c *fiber.Ctx) error {
 
 	contestID := c.Params("id", "")
 	if contestID == "" {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	err := h.services.Contests.Delete(c.UserContext(), contestID)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(http.StatusNoContent).JSON(fiber.Map{
-		"errors":  false,
 		"message": "Contest deleted",
 	})
 }
@@ -4,13 +4,11 @@ import (
 	"log"
 	"net/http"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 
 	"acsp/internal/apperror"
 	"acsp/internal/dto"
 	"acsp/internal/logging"
-	"acsp/internal/validation"
 )
 
 // @Summary Create an article
@@ -23,56 +21,39 @@ import (
 // @Param request body dto.CreateArticle true "article information"
 // @Param file formData file true "article image"
 // @Success 200 {integer} integer 1
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/scholar/articles [post]
 func (h *Handler) createArticle(c *fiber.Ctx) error {
 	log.Println("Creating an article... ")
 
 	userId, err := getUserId(c)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	input := dto.CreateArticle{}
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrBadInputBody,
-		})
+		return apperror.ErrBadInputBody
 	}
 
 	input.Image, err = c.FormFile("file")
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return apperror.WithKind(err, apperror.KindValidation)
 	}
 
-	validate := validator.New()
-	err = validate.Struct(input)
+	err = validate(c, &input)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": validation.ValidatorErrors(err),
-		})
+		return err
 	}
 
 	err = h.services.Articles.Create(c.UserContext(), userId, input)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(200).JSON(fiber.Map{
-		"errors":  false,
 		"message": "Created article",
 	})
 }
@@ -85,9 +66,9 @@ func (h *Handler) createArticle(c *fiber.Ctx) error {
 // @Accept  json
 // @Produce  json
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/scholar/articles [get]
 func (h *Handler) getAllArticles(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -95,15 +76,10 @@ func (h *Handler) getAllArticles(c *fiber.Ctx) error {
 
 	articles, err := h.services.Articles.GetAll(c.UserContext())
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":   false,
-		"message":  nil,
 		"count":    len(articles),
 		"user_id":  c.GetRespHeader(userCtx, ""),
 		"articles": articles,
@@ -119,9 +95,9 @@ func (h *Handler) getAllArticles(c *fiber.Ctx) error {
 // @Produce  json
 // @Param userID query string true "user id"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/scholar/articles/user [get]
 func (h *Handler) getAllArticlesByUserID(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -129,23 +105,15 @@ func (h *Handler) getAllArticlesByUserID(c *fiber.Ctx) error {
 
 	userId := c.Query("userID")
 	if userId == "" {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": "User id is required",
-		})
+		return apperror.New(apperror.KindValidation, "User id is required")
 	}
 
 	articles, err := h.services.Articles.GetAllByUserID(c.UserContext(), userId)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":   false,
-		"message":  nil,
 		"count":    len(articles),
 		"user_id":  c.GetRespHeader(userCtx, ""),
 		"articles": articles,
@@ -161,9 +129,9 @@ func (h *Handler) getAllArticlesByUserID(c *fiber.Ctx) error {
 // @Produce  json
 // @Param id path string true "article id"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/scholar/articles/{id} [get]
 func (h *Handler) getArticleByID(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -171,23 +139,15 @@ func (h *Handler) getArticleByID(c *fiber.Ctx) error {
 
 	articleID := c.Params("id", "")
 	if articleID == "" {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	article, err := h.services.Articles.GetByID(c.UserContext(), articleID)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":  false,
-		"message": nil,
 		"user_id": c.GetRespHeader(userCtx, ""),
 		"article": article,
 	})
@@ -203,9 +163,9 @@ func (h *Handler) getArticleByID(c *fiber.Ctx) error {
 // @Param id path string true "article id"
 // @Param input body dto.UpdateArticle true "article information"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/scholar/articles/{id} [put]
 func (h *Handler) updateArticle(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -213,43 +173,27 @@ func (h *Handler) updateArticle(c *fiber.Ctx) error {
 
 	userID, err := getUserId(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	var input dto.UpdateArticle
 
 	articleID := c.Params("id", "")
 	if articleID == "" {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrBodyParsed,
-		})
+		return apperror.ErrBodyParsed
 	}
 
-	validate := validator.New()
-	if err := validate.Struct(input); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": validation.ValidatorErrors(err),
-		})
+	if err := validate(c, &input); err != nil {
+		return err
 	}
 
 	err = h.services.Articles.Update(c.UserContext(), articleID, userID, input)
 	if err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(http.StatusOK).JSON(fiber.Map{"message": "Article updated"})
@@ -264,8 +208,8 @@ func (h *Handler) updateArticle(c *fiber.Ctx) error {
 // @Produce  json
 // @Param id path string true "article id"
 // @Success 200 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/scholar/articles/{id} [delete]
 func (h *Handler) deleteArticle(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -273,26 +217,17 @@ func (h *Handler) deleteArticle(c *fiber.Ctx) error {
 
 	userId, err := getUserId(c)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	id := c.Params("id")
 	if err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	err = h.services.Articles.Delete(c.UserContext(), userId, id)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(http.StatusNoContent).JSON(fiber.Map{"message": "Article deleted"})
@@ -308,9 +243,9 @@ func (h *Handler) deleteArticle(c *fiber.Ctx) error {
 // @Param id path string true "article id"
 // @Param input body dto.CreateComment true "comment information"
 // @Success 200 {integer} integer 1
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/scholar/articles/{id}/comments [post]
 func (h *Handler) commentArticle(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -318,42 +253,26 @@ func (h *Handler) commentArticle(c *fiber.Ctx) error {
 
 	userID, err := getUserId(c)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	articleID := c.Params("id", "")
 	if articleID == "" {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	var input dto.CreateComment
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrBodyParsed,
-		})
+		return apperror.ErrBodyParsed
 	}
 
-	validate := validator.New()
-	if err := validate.Struct(input); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": validation.ValidatorErrors(err),
-		})
+	if err := validate(c, &input); err != nil {
+		return err
 	}
 
 	err = h.services.Articles.CommentByID(c.UserContext(), articleID, userID, input)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(http.StatusNoContent).JSON(fiber.Map{"message": "Article commented"})
@@ -368,9 +287,9 @@ func (h *Handler) commentArticle(c *fiber.Ctx) error {
 // @Produce  json
 // @Param id path string true "article id"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/scholar/articles/{id}/comments [get]
 func (h *Handler) getCommentsByArticleID(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -378,23 +297,15 @@ func (h *Handler) getCommentsByArticleID(c *fiber.Ctx) error {
 
 	articleID := c.Params("id")
 	if articleID == "" {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	comments, err := h.services.Articles.GetCommentsByArticleID(c.UserContext(), articleID)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":   false,
-		"message":  nil,
 		"count":    len(comments),
 		"user_id":  c.GetRespHeader(userCtx, ""),
 		"comments": comments,
@@ -411,9 +322,9 @@ func (h *Handler) getCommentsByArticleID(c *fiber.Ctx) error {
 // @Param id path string true "article id"
 // @Param commentID path string true "comment id"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/scholar/articles/{id}/comments/{commentID}/replies [post]
 func (h *Handler) replyToCommentByID(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -421,42 +332,26 @@ func (h *Handler) replyToCommentByID(c *fiber.Ctx) error {
 
 	userID, err := getUserId(c)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	articleID := c.Params("id")
 	if articleID == "" {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	parentCommentID := c.Params("commentID")
 	if parentCommentID == "" {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	var input dto.ReplyToComment
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrBodyParsed,
-		})
+		return apperror.ErrBodyParsed
 	}
 
-	validate := validator.New()
-	if err := validate.Struct(input); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": validation.ValidatorErrors(err),
-		})
+	if err := validate(c, &input); err != nil {
+		return err
 	}
 
 	err = h.services.Articles.ReplyToCommentByArticleIDAndCommentID(c.UserContext(),
@@ -466,14 +361,10 @@ func (h *Handler) replyToCommentByID(c *fiber.Ctx) error {
 		input)
 
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":  false,
 		"message": "Replied to the comment successfully",
 	})
 }
@@ -488,9 +379,9 @@ func (h *Handler) replyToCommentByID(c *fiber.Ctx) error {
 // @Param id path string true "article id"
 // @Param commentID path string true "comment id"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/scholar/articles/{id}/comments/{commentID}/replies [get]
 func (h *Handler) getRepliesByCommentID(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -498,31 +389,20 @@ func (h *Handler) getRepliesByCommentID(c *fiber.Ctx) error {
 
 	articleID := c.Params("id")
 	if articleID == "" {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	commentID := c.Params("commentID")
 	if commentID == "" {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	comments, err := h.services.Articles.GetRepliesByArticleIDAndCommentID(c.UserContext(), articleID, commentID)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":   false,
-		"message":  nil,
 		"count":    len(comments),
 		"user_id":  c.GetRespHeader(userCtx, ""),
 		"comments": comments,
@@ -539,9 +419,9 @@ func (h *Handler) getRepliesByCommentID(c *fiber.Ctx) error {
 // @Param id path string true "article id"
 // @Param commentID path string true "comment id"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/scholar/articles/{id}/comments/{commentID}/upvote [post]
 func (h *Handler) upvoteCommentByID(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -549,39 +429,26 @@ func (h *Handler) upvoteCommentByID(c *fiber.Ctx) error {
 
 	userID, err := getUserId(c)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	articleID := c.Params("id")
 	if articleID == "" {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	commentID := c.Params("commentID")
 	if commentID == "" {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	err = h.services.Articles.UpvoteCommentByArticleIDAndCommentID(c.UserContext(), articleID, commentID, userID)
 
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":  false,
 		"message": "Up-voted the comment successfully",
 	})
 }
@@ -596,9 +463,9 @@ func (h *Handler) upvoteCommentByID(c *fiber.Ctx) error {
 // @Param id path string true "article id"
 // @Param commentID path string true "comment id"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/scholar/articles/{id}/comments/{commentID}/downvote [post]
 func (h *Handler) downvoteCommentByID(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -606,39 +473,26 @@ func (h *Handler) downvoteCommentByID(c *fiber.Ctx) error {
 
 	userID, err := getUserId(c)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	articleID := c.Params("id")
 	if articleID == "" {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	commentID := c.Params("commentID")
 	if commentID == "" {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	err = h.services.Articles.DownvoteCommentByArticleIDAndCommentID(c.UserContext(), articleID, commentID, userID)
 
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":  false,
 		"message": "Did upvote the comment successfully",
 	})
 }
@@ -653,9 +507,9 @@ func (h *Handler) downvoteCommentByID(c *fiber.Ctx) error {
 // @Param id path string true "article id"
 // @Param commentID path string true "comment id"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/scholar/articles/{id}/comments/{commentID}/votes [get]
 func (h *Handler) getVotesByCommentID(ctx *fiber.Ctx) error {
 	l := logging.LoggerFromContext(ctx.UserContext())
@@ -663,31 +517,20 @@ func (h *Handler) getVotesByCommentID(ctx *fiber.Ctx) error {
 
 	articleID := ctx.Params("id")
 	if articleID == "" {
-		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	commentID := ctx.Params("commentID")
 	if commentID == "" {
-		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	votes, err := h.services.Articles.GetVotesByArticleIDAndCommentID(ctx.UserContext(), articleID, commentID)
 	if err != nil {
-		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return ctx.JSON(fiber.Map{
-		"errors":  false,
-		"message": nil,
-		"votes":   votes,
+		"votes": votes,
 	})
 }
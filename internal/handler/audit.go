@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"acsp/internal/apperror"
+	"acsp/internal/dto"
+)
+
+// @Summary Get the audit events
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description Get the administrative and security-sensitive actions, the newest first.
+// @Description The next page is requested with before set to next_before of the response.
+// @ID get-audit-events
+// @Accept  json
+// @Produce  json
+// @Param actor_id query int false "ID of the user who made the action"
+// @Param action query string false "Action, e.g. update"
+// @Param target_type query string false "Type of the target, e.g. contest"
+// @Param target_id query string false "ID of the target"
+// @Param from query string false "RFC 3339 time, the events at or after it"
+// @Param to query string false "RFC 3339 time, the events before it"
+// @Param before query int false "Cursor of the page, the events older than the event with this ID"
+// @Param limit query int false "Page size, 50 by default and at most 200"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/admin/audit [get]
+func (h *Handler) getAuditEvents(c *fiber.Ctx) error {
+	var input dto.GetAuditEvents
+	if err := c.QueryParser(&input); err != nil {
+		return apperror.WithKind(err, apperror.KindValidation)
+	}
+
+	if err := validate(c, &input); err != nil {
+		return err
+	}
+
+	page, err := h.services.Audit.GetEvents(c.UserContext(), input)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"events":      page.Events,
+		"next_before": page.NextBefore,
+	})
+}
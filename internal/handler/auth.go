@@ -2,15 +2,15 @@ package handler
 
 import (
 	"net/http"
-	"time"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
 
 	"acsp/internal/apperror"
 	"acsp/internal/dto"
 	"acsp/internal/logging"
-	"acsp/internal/utils"
+	"acsp/internal/model"
 )
 
 // @Summary SignUp
@@ -21,9 +21,9 @@ import (
 // @Produce  json
 // @Param input body dto.CreateUser true "account info"
 // @Success 200 {integer} string "message"
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/auth/sign-up [post]
 func (h *Handler) signUp(ctx *fiber.Ctx) error {
 	l := logging.LoggerFromContext(ctx.UserContext())
@@ -31,34 +31,24 @@ func (h *Handler) signUp(ctx *fiber.Ctx) error {
 
 	var input dto.CreateUser
 	if err := ctx.BodyParser(&input); err != nil {
-		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error":   true,
-			"message": apperror.ErrBodyParsed,
-		})
+		return apperror.ErrBodyParsed
 	}
 
-	validate := validator.New()
-	if validationErr := validate.Struct(&input); validationErr != nil {
-		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error":   true,
-			"message": apperror.ErrBadInputBody,
-		})
+	if err := validate(ctx, &input); err != nil {
+		return err
 	}
 
 	err := h.services.Authorization.CreateUser(ctx.UserContext(), input)
 	if err != nil {
-		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"error":   true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return ctx.Status(http.StatusOK).JSON(fiber.Map{"message": "Successfully registered"})
 }
 
 type signInInput struct {
-	Email    string `json:"email" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Email    string `json:"email" binding:"required" validate:"required,email"`
+	Password string `json:"password" binding:"required" validate:"required"`
 }
 
 // @Summary SignIn
@@ -68,10 +58,11 @@ type signInInput struct {
 // @Accept  json
 // @Produce  json
 // @Param input body signInInput true "credentials"
-// @Success 200 {string} service.TokenDetails "token pair"
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Success 200 {string} service.TokenDetails "token pair, or model.MFAChallenge when two-factor authentication is enabled"
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 429 {object} apperror.Problem "the account or the IP is locked, see Retry-After"
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/auth/sign-in [post]
 func (h *Handler) signIn(ctx *fiber.Ctx) error {
 	l := logging.LoggerFromContext(ctx.UserContext())
@@ -79,149 +70,218 @@ func (h *Handler) signIn(ctx *fiber.Ctx) error {
 
 	var input signInInput
 	if err := ctx.BodyParser(&input); err != nil {
-		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"message": apperror.ErrBodyParsed,
-		})
+		return apperror.ErrBodyParsed
 	}
 
 	// Validate input data. If data is not valid, return error. Otherwise, continue.
-	validate := validator.New()
-	if validationErr := validate.Struct(&input); validationErr != nil {
-		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error":   true,
-			"message": apperror.ErrBadInputBody,
-		})
+	if err := validate(ctx, &input); err != nil {
+		return err
+	}
+
+	if err := h.checkLockout(ctx, input.Email); err != nil {
+		return err
+	}
+
+	user, err := h.services.Authorization.Authenticate(ctx.UserContext(), input.Email, input.Password)
+	if errors.Is(err, apperror.ErrUserNotFound) || errors.Is(err, apperror.ErrPasswordMismatch) {
+		h.failSignIn(ctx, input.Email)
+
+		// An unknown email is a wrong credential, it doesn't tell whether the email is registered
+		return apperror.ErrPasswordMismatch
 	}
 
-	// Generate token pair
-	tokenPair, err := h.services.Authorization.GenerateTokenPair(ctx.UserContext(), input.Email, input.Password)
 	if err != nil {
-		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"error":   true,
-			"message": err.Error(),
-		})
+		return err
+	}
+
+	return h.signInUser(ctx, user)
+}
+
+type signInTwoFactorInput struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	// Code is a code of the authenticator app or a recovery code
+	Code string `json:"code" validate:"required"`
+}
+
+// @Summary SignIn second step
+// @Tags auth
+// @Description completing the sign-in of a user with two-factor authentication enabled by a code of the authenticator app or a recovery code, the token pair is returned
+// @ID sign-in-2fa
+// @Accept  json
+// @Produce  json
+// @Param input body signInTwoFactorInput true "challenge and code"
+// @Success 200 {string} service.TokenDetails "token pair"
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem "the challenge is expired or the code is invalid"
+// @Failure 429 {object} apperror.Problem "the account or the IP is locked, see Retry-After"
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/auth/sign-in/2fa [post]
+func (h *Handler) signInTwoFactor(ctx *fiber.Ctx) error {
+	var input signInTwoFactorInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return apperror.ErrBodyParsed
+	}
+
+	if err := validate(ctx, &input); err != nil {
+		return err
+	}
+
+	userID, err := h.services.TwoFactor.ChallengeUserID(ctx.UserContext(), input.MFAToken)
+	if err != nil {
+		return err
+	}
+
+	user, err := h.services.Authorization.GetUserByID(ctx.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+
+	// The codes are guessed like the passwords, the failures are counted together
+	if err := h.checkLockout(ctx, user.Email); err != nil {
+		return err
+	}
+
+	err = h.services.TwoFactor.CompleteChallenge(ctx.UserContext(), input.MFAToken, input.Code)
+	if errors.Is(err, apperror.ErrInvalidMFACode) {
+		h.failSignIn(ctx, user.Email)
+
+		return err
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return h.completeSignIn(ctx, user)
+}
+
+// checkLockout returns an error when the account or the IP may not sign in now.
+func (h *Handler) checkLockout(ctx *fiber.Ctx, email string) error {
+	err := h.services.Lockout.Check(ctx.UserContext(), email, clientIP(ctx))
+	if apperror.KindOf(err) == apperror.KindTooManyRequests {
+		return err
+	}
+
+	if err != nil {
+		// The sign-ins aren't refused when the failures can't be counted
+		logging.LoggerFromContext(ctx.UserContext()).Error("Error when checking the lockout", zap.Error(err))
+	}
+
+	return nil
+}
+
+// failSignIn counts a failed sign-in of the account and the IP.
+func (h *Handler) failSignIn(ctx *fiber.Ctx, email string) {
+	if err := h.services.Lockout.Fail(ctx.UserContext(), email, clientIP(ctx)); err != nil {
+		logging.LoggerFromContext(ctx.UserContext()).Error("Error when counting the failed sign-in", zap.Error(err))
+	}
+}
+
+// signInUser returns the challenge of the second step when the user has two-factor authentication enabled,
+// the token pair otherwise.
+func (h *Handler) signInUser(ctx *fiber.Ctx, user model.User) error {
+	enabled, err := h.services.TwoFactor.Enabled(ctx.UserContext(), user.ID)
+	if err != nil {
+		return err
+	}
+
+	// The tokens are generated after the second step, see signInTwoFactor
+	if enabled {
+		challenge, err := h.services.TwoFactor.NewChallenge(ctx.UserContext(), user.ID)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(http.StatusOK).JSON(challenge)
+	}
+
+	return h.completeSignIn(ctx, user)
+}
+
+// completeSignIn generates and returns the token pair of the authenticated user.
+func (h *Handler) completeSignIn(ctx *fiber.Ctx, user model.User) error {
+	tokenPair, err := h.services.Authorization.GenerateTokenPair(ctx.UserContext(), user)
+	if err != nil {
+		return err
+	}
+
+	if err := h.services.Lockout.Succeed(ctx.UserContext(), user.Email); err != nil {
+		logging.LoggerFromContext(ctx.UserContext()).Error("Error when resetting the failed sign-ins", zap.Error(err))
 	}
 
 	// Save refresh token to database
 	err = h.services.Authorization.SaveRefreshToken(ctx.UserContext(), tokenPair.UserID, tokenPair)
 	if err != nil {
-		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"error":   true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	// Return token pair
 	return ctx.Status(http.StatusOK).JSON(tokenPair)
 }
 
+type refreshTokenInput struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
 // @Summary renew access and refresh tokens
 // @Description Renew access and refresh tokens by refresh token from request body and set new expiration time for refresh token in database
 // @Tags auth
 // @ID refresh-token
 // @Accept json
 // @Produce json
-// @Param refresh_token body string true "Refresh token"
+// @Param input body refreshTokenInput true "Refresh token"
 // @Success 200 {string} service.TokenDetails "token pair"
-// @Failure 400,401,404 {object} map[string]interface{} "error" "message"
-// @Failure 500 {object} map[string]interface{} "error" "message"
-// @Security ApiKeyAuth
-// @Router /api/v1/token/renew [post]
+// @Failure 400,401,404 {object} apperror.Problem "error" "message"
+// @Failure 500 {object} apperror.Problem "error" "message"
+// @Router /api/v1/auth/refresh [post]
 func (h *Handler) refreshToken(ctx *fiber.Ctx) error {
-	now := time.Now().Unix()
+	var input refreshTokenInput
 
-	// Get claims from JWT
-	claims, err := utils.ExtractTokenMetadata(ctx)
-	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error":   true,
-			"message": err.Error(),
-		})
+	// Get the refresh token from request body.
+	if err := ctx.BodyParser(&input); err != nil {
+		return apperror.ErrBodyParsed
 	}
 
-	// Check if token is expired
-	expiresAccessToken := claims.Expires
-
-	// If token is expired, return status 401 and error message.
-	if now > expiresAccessToken {
-		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error":   true,
-			"message": "unauthorized, token has expired",
-		})
+	if err := validate(ctx, &input); err != nil {
+		return err
 	}
 
-	var newToken string
+	// Check the signature and the expiration of the refresh token, it must be the latest one of the user
+	userID, err := h.services.Authorization.ParseRefreshToken(ctx.UserContext(), input.RefreshToken)
+	if err != nil {
+		return apperror.WithKind(err, apperror.KindUnauthorized)
+	}
 
-	// Get new token from request body.
-	if err := ctx.BodyParser(newToken); err != nil {
-		// Return, if JSON data is not correct.
-		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   true,
-			"message": err.Error(),
-		})
+	// Get user by ID
+	foundedUser, err := h.services.Authorization.GetUserByID(ctx.UserContext(), userID)
+	if apperror.KindOf(err) == apperror.KindNotFound {
+		return apperror.New(apperror.KindNotFound, "user with the given ID is not found")
 	}
 
-	// Set expiration time from Refresh token of current user
-	// and check if it is valid.
-	expiresRefreshToken, err := utils.ParseRefreshToken(newToken)
 	if err != nil {
-		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
-	// Check if refresh token is expired
-	if now < expiresRefreshToken {
-		// Define user ID
-		userID := claims.UserID
-
-		// Get user by ID
-		foundedUser, err := h.services.Authorization.GetUserByID(ctx.UserContext(), userID)
-		if err != nil {
-			// Return, if user not found.
-			return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error":   true,
-				"message": "user with the given ID is not found",
-			})
-		}
-
-		// Generate new token pair for user with new expiration time
-		tokenPair, err := h.services.Authorization.GenerateTokenPair(ctx.UserContext(), foundedUser.Email, foundedUser.Password)
-		if err != nil {
-			// Return status 500 and token generation error.
-			return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error":   true,
-				"message": err.Error(),
-			})
-		}
-
-		// Save refresh token to Redis database with new expiration time
-		// and check if it is valid. If not, return error.
-		err = h.services.SaveRefreshToken(ctx.UserContext(), userID, tokenPair)
-		if err != nil {
-			return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error":   true,
-				"message": err.Error(),
-			})
-		}
+	// Generate new token pair for user with new expiration time, the roles of the access token are updated
+	tokenPair, err := h.services.Authorization.GenerateTokenPair(ctx.UserContext(), foundedUser)
+	if err != nil {
+		return err
+	}
 
-		// Return new token pair
-		return ctx.JSON(fiber.Map{
-			"error": false,
-			"msg":   nil,
-			"tokens": fiber.Map{
-				"access_token":  tokenPair.AccessToken,
-				"refresh_token": tokenPair.RefreshToken,
-			},
-		})
-	} else {
-		// Return status 401 and error message, if refresh token is expired.
-		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error":   true,
-			"message": "unauthorized, your session was ended earlier",
-		})
+	// Save refresh token to Redis database with new expiration time, the previous one is replaced
+	err = h.services.SaveRefreshToken(ctx.UserContext(), userID, tokenPair)
+	if err != nil {
+		return err
 	}
+
+	// Return new token pair
+	return ctx.JSON(fiber.Map{
+		"tokens": fiber.Map{
+			"access_token":  tokenPair.AccessToken,
+			"refresh_token": tokenPair.RefreshToken,
+		},
+	})
 }
 
 // @Summary logout user
@@ -231,7 +291,7 @@ func (h *Handler) refreshToken(ctx *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Success 200 {string} status "ok"
-// @Failure 500 {object} map[string]interface{}
+// @Failure 500 {object} apperror.Problem
 // @Security ApiKeyAuth
 // @Router /api/v1/auth/logout [post]
 func (h *Handler) logout(ctx *fiber.Ctx) error {
@@ -241,19 +301,13 @@ func (h *Handler) logout(ctx *fiber.Ctx) error {
 	// Get user ID from context
 	userId, err := getUserId(ctx)
 	if err != nil {
-		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"error":   true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	// Delete refresh token from cache by user ID
 	err = h.services.Authorization.DeleteRefreshToken(ctx.UserContext(), userId)
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": true,
-			"msg":   err.Error(),
-		})
+		return err
 	}
 
 	// Return status 200 and message "ok" if everything is ok.
@@ -3,27 +3,30 @@ package handler
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http/httptest"
 	"testing"
 
-	mockService "acsp/internal/service/mocks"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"acsp/internal/apperror"
+	"acsp/internal/dto"
 	"acsp/internal/model"
 	"acsp/internal/service"
-
-	"github.com/gofiber/fiber/v2"
-	"github.com/golang/mock/gomock"
-	"github.com/magiconair/properties/assert"
+	mockService "acsp/internal/service/mocks"
 )
 
 func TestHandler_signUp(t *testing.T) {
-	type mockBehavior func(r *mockService.MockAuthorization, user model.User)
+	type mockBehavior func(r *mockService.MockAuthorization, user dto.CreateUser)
 
 	tests := []struct {
 		name                 string
 		inputBody            map[string]any
-		inputUser            model.User
+		inputUser            dto.CreateUser
 		mockBehavior         mockBehavior
 		expectedStatusCode   int
 		expectedResponseBody string
@@ -31,42 +34,55 @@ func TestHandler_signUp(t *testing.T) {
 		{
 			name: "Ok",
 			inputBody: map[string]any{
-				"email":    "eldos2020@gmail.com",
+				"email":    "eldos@astanait.edu.kz",
 				"name":     "testname",
 				"password": "qwerty123",
 			},
-			inputUser: model.User{
-				Email:    "eldos2020@gmail.com",
+			inputUser: dto.CreateUser{
+				Email:    "eldos@astanait.edu.kz",
 				Name:     "testname",
 				Password: "qwerty123",
 			},
+			mockBehavior: func(r *mockService.MockAuthorization, user dto.CreateUser) {
+				r.EXPECT().CreateUser(gomock.Any(), user).Return(nil)
+			},
 			expectedStatusCode:   200,
-			expectedResponseBody: `{"id":"1"}`,
+			expectedResponseBody: `{"message":"Successfully registered"}`,
 		},
 		{
 			name: "Service Error",
 			inputBody: map[string]any{
-				"email":    "eldos2020@gmail.com",
+				"email":    "eldos@astanait.edu.kz",
 				"name":     "testname",
 				"password": "qwerty123",
 			},
-			inputUser: model.User{
-				Email:    "eldos2020@gmail.com",
+			inputUser: dto.CreateUser{
+				Email:    "eldos@astanait.edu.kz",
 				Name:     "testname",
 				Password: "qwerty123",
 			},
-			expectedStatusCode:   500,
-			expectedResponseBody: `{"message":"something went wrong"}`,
+			mockBehavior: func(r *mockService.MockAuthorization, user dto.CreateUser) {
+				r.EXPECT().CreateUser(gomock.Any(), user).Return(errors.New("something went wrong"))
+			},
+			expectedStatusCode: 500,
 		},
 		{
 			name: "Wrong Input",
 			inputBody: map[string]any{
-				"email": "eldos2020@gmail.com",
+				"email": "eldos@astanait.edu.kz",
 			},
-			inputUser:            model.User{},
-			mockBehavior:         func(r *mockService.MockAuthorization, user model.User) {},
-			expectedStatusCode:   400,
-			expectedResponseBody: `{"message":"invalid input body"}`,
+			mockBehavior:       func(r *mockService.MockAuthorization, user dto.CreateUser) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: "Wrong Email Domain",
+			inputBody: map[string]any{
+				"email":    "eldos2020@gmail.com",
+				"name":     "testname",
+				"password": "qwerty123",
+			},
+			mockBehavior:       func(r *mockService.MockAuthorization, user dto.CreateUser) {},
+			expectedStatusCode: 400,
 		},
 	}
 
@@ -78,23 +94,62 @@ func TestHandler_signUp(t *testing.T) {
 			repo := mockService.NewMockAuthorization(c)
 			testCase.mockBehavior(repo, testCase.inputUser)
 
-			services := &service.Service{Authorization: repo}
-			handler := Handler{services}
+			handler := NewHandler(&service.Service{Authorization: repo})
 
-			app := fiber.New()
+			app := fiber.New(fiber.Config{ErrorHandler: apperror.ErrorHandler})
 			app.Post("/sign-up", handler.signUp)
 
 			requestBody, _ := json.Marshal(testCase.inputBody)
 
-			request := httptest.NewRequest("POST", "/sign-up",
-				bytes.NewReader(requestBody))
+			request := httptest.NewRequest("POST", "/sign-up", bytes.NewReader(requestBody))
 			request.Header.Add("Content-Type", "application/json")
 
-			response, _ := app.Test(request)
+			response, err := app.Test(request)
+			require.NoError(t, err)
 			data, _ := io.ReadAll(response.Body)
 
-			assert.Equal(t, response.StatusCode, testCase.expectedStatusCode)
-			assert.Matches(t, string(data), testCase.expectedResponseBody)
+			assert.Equal(t, testCase.expectedStatusCode, response.StatusCode)
+
+			if testCase.expectedResponseBody != "" {
+				assert.JSONEq(t, testCase.expectedResponseBody, string(data))
+			} else {
+				assert.Equal(t, apperror.ProblemContentType, response.Header.Get(fiber.HeaderContentType))
+			}
+		})
+	}
+}
+
+func TestHandler_refreshToken(t *testing.T) {
+	tests := []struct {
+		name               string
+		userErr            error
+		expectedStatusCode int
+	}{
+		{name: "User Not Found", userErr: apperror.ErrUserNotFound, expectedStatusCode: 404},
+		// A failure of the database isn't a missing user
+		{name: "Database Error", userErr: errors.New("connection refused"), expectedStatusCode: 500},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mockService.NewMockAuthorization(c)
+			auth.EXPECT().ParseRefreshToken(gomock.Any(), "token").Return("1", nil)
+			auth.EXPECT().GetUserByID(gomock.Any(), "1").Return(model.User{}, testCase.userErr)
+
+			handler := NewHandler(&service.Service{Authorization: auth})
+
+			app := fiber.New(fiber.Config{ErrorHandler: apperror.ErrorHandler})
+			app.Post("/refresh", handler.refreshToken)
+
+			request := httptest.NewRequest("POST", "/refresh", bytes.NewReader([]byte(`{"refresh_token":"token"}`)))
+			request.Header.Add("Content-Type", "application/json")
+
+			response, err := app.Test(request)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedStatusCode, response.StatusCode)
 		})
 	}
 }
@@ -4,13 +4,11 @@ import (
 	"log"
 	"net/http"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 
 	"acsp/internal/apperror"
 	"acsp/internal/dto"
 	"acsp/internal/logging"
-	"acsp/internal/validation"
 )
 
 // @Summary Create a card
@@ -22,48 +20,34 @@ import (
 // @Produce  json
 // @Param request body dto.CreateCard true "card information"
 // @Success 200 {integer} integer 1
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/code-connection/cards [post]
 func (h *Handler) createCard(c *fiber.Ctx) error {
 	log.Println("Creating a card... ")
 
 	userId, err := getUserId(c)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	input := dto.CreateCard{}
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrBadInputBody,
-		})
+		return apperror.ErrBadInputBody
 	}
 
-	validate := validator.New()
-	err = validate.Struct(input)
+	err = validate(c, &input)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": validation.ValidatorErrors(err),
-		})
+		return err
 	}
 
 	err = h.services.Cards.Create(c.UserContext(), userId, input)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(200).JSON(fiber.Map{
-		"errors":  false,
 		"message": "Created card",
 	})
 }
@@ -78,9 +62,9 @@ func (h *Handler) createCard(c *fiber.Ctx) error {
 // @Param id path int true "card id"
 // @Param request body dto.UpdateCard true "card information"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/code-connection/cards/{id} [put]
 func (h *Handler) updateCard(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -88,48 +72,29 @@ func (h *Handler) updateCard(c *fiber.Ctx) error {
 
 	userID, err := getUserId(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	var input dto.UpdateCard
 
 	cardID, err := c.ParamsInt("id", -1)
 	if cardID == -1 {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrBodyParsed,
-		})
+		return apperror.ErrBodyParsed
 	}
 
-	validate := validator.New()
-	if err := validate.Struct(input); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": validation.ValidatorErrors(err),
-		})
+	if err := validate(c, &input); err != nil {
+		return err
 	}
 
 	err = h.services.Cards.Update(c.UserContext(), userID, cardID, input)
 	if err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(http.StatusOK).JSON(fiber.Map{"message": "Card updated"})
@@ -144,8 +109,8 @@ func (h *Handler) updateCard(c *fiber.Ctx) error {
 // @Produce  json
 // @Param id path int true "Card ID"
 // @Success 200 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/code-connection/cards/{id} [delete]
 func (h *Handler) deleteCard(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -153,31 +118,19 @@ func (h *Handler) deleteCard(c *fiber.Ctx) error {
 
 	userId, err := getUserId(c)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	id, err := c.ParamsInt("id", -1)
 	if id == -1 {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	err = h.services.Cards.Delete(c.UserContext(), userId, id)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(http.StatusNoContent).JSON(fiber.Map{"message": "Card deleted"})
@@ -191,9 +144,9 @@ func (h *Handler) deleteCard(c *fiber.Ctx) error {
 // @Accept  json
 // @Produce  json
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/code-connection/cards [get]
 func (h *Handler) getAllCardsByUserID(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -201,23 +154,15 @@ func (h *Handler) getAllCardsByUserID(c *fiber.Ctx) error {
 
 	userId, err := getUserId(c)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	cards, err := h.services.Cards.GetAllByUserID(c.UserContext(), userId)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":  false,
-		"message": nil,
 		"count":   len(*cards),
 		"user_id": c.GetRespHeader(userCtx, ""),
 		"cards":   cards,
@@ -232,9 +177,9 @@ func (h *Handler) getAllCardsByUserID(c *fiber.Ctx) error {
 // @Accept  json
 // @Produce  json
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/code-connection/applicants [get]
 func (h *Handler) getAllCards(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -242,17 +187,12 @@ func (h *Handler) getAllCards(c *fiber.Ctx) error {
 
 	applicants, err := h.services.Cards.GetAll(c.UserContext())
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":  false,
-		"message": nil,
-		"count":   len(applicants),
-		"cards":   applicants,
+		"count": len(applicants),
+		"cards": applicants,
 	})
 }
 
@@ -265,9 +205,9 @@ func (h *Handler) getAllCards(c *fiber.Ctx) error {
 // @Produce  json
 // @Param id path int true "applicant id"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/code-connection/applicants/{id} [get]
 func (h *Handler) getCardByID(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -275,28 +215,17 @@ func (h *Handler) getCardByID(c *fiber.Ctx) error {
 
 	cardID, err := c.ParamsInt("id", -1)
 	if cardID == -1 {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	card, err := h.services.Cards.GetByID(c.UserContext(), cardID)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":  false,
-		"message": nil,
 		"user_id": c.GetRespHeader(userCtx, ""),
 		"card":    card,
 	})
@@ -311,44 +240,31 @@ func (h *Handler) getCardByID(c *fiber.Ctx) error {
 // @Produce  json
 // @Param id path int true "card id"
 // @Success 200 {integer} string "success"
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/code-connection/cards/{id}/invitations [post]
 func (h *Handler) createInvitation(c *fiber.Ctx) error {
 	log.Println("Creating a card... ")
 
 	userID, err := getUserId(c)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	cardID, err := c.ParamsInt("id", -1)
 	if cardID == -1 {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	err = h.services.Cards.CreateInvitation(c.UserContext(), userID, cardID)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(200).JSON(fiber.Map{
-		"errors":  false,
 		"message": "Created an invitation",
 	})
 }
@@ -361,9 +277,9 @@ func (h *Handler) createInvitation(c *fiber.Ctx) error {
 // @Accept  json
 // @Produce  json
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/code-connection/cards/invitations [get]
 func (h *Handler) getInvitations(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -371,25 +287,17 @@ func (h *Handler) getInvitations(c *fiber.Ctx) error {
 
 	userID, err := getUserId(c)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	invitations, err := h.services.Cards.GetInvitationsByUserID(c.UserContext(), userID)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":  false,
-		"message": nil,
-		"count":   len(invitations),
-		"cards":   invitations,
+		"count": len(invitations),
+		"cards": invitations,
 	})
 }
 
@@ -403,49 +311,35 @@ func (h *Handler) getInvitations(c *fiber.Ctx) error {
 // @Param id path int true "card id"
 // @Param invitationID path int true "invitation id"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/code-connection/cards/{id}/invitations/{invitationID} [get]
 func (h *Handler) getInvitationByID(c *fiber.Ctx) error {
 	log.Println("Creating a card... ")
 
 	userID, err := getUserId(c)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	cardID := c.Params("id", "")
 	if cardID == "" {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	invitationID := c.Params("invitationID", "")
 	if invitationID == "" {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	invitation, err := h.services.Cards.GetInvitationByID(c.UserContext(), userID, cardID, invitationID)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":  false,
-		"message": nil,
-		"card":    invitation,
+		"card": invitation,
 	})
 }
 
@@ -458,42 +352,31 @@ func (h *Handler) getInvitationByID(c *fiber.Ctx) error {
 // @Produce  json
 // @Param id path int true "card id"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/code-connection/cards/{id}/invitations [get]
 func (h *Handler) getInvitationsByCardID(c *fiber.Ctx) error {
 	log.Println("Getting invitation by card id... ")
 
 	userID, err := getUserId(c)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	cardID := c.Params("id", "")
 	if cardID == "" {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	invitations, err := h.services.Cards.GetInvitationsByCardID(c.UserContext(), userID, cardID)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":  false,
-		"message": nil,
-		"count":   len(invitations),
-		"cards":   invitations,
+		"count": len(invitations),
+		"cards": invitations,
 	})
 }
 
@@ -508,55 +391,43 @@ func (h *Handler) getInvitationsByCardID(c *fiber.Ctx) error {
 // @Param invitationID path int true "invitation id"
 // @Param request body dto.AnswerInvitation true "accept invitation feedback"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/code-connection/cards/{id}/invitations/{invitationID}/accept [post]
 func (h *Handler) acceptInvitation(c *fiber.Ctx) error {
 	log.Println("Accepting an invitation... ")
 
 	userID, err := getUserId(c)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	cardID := c.Params("id", "")
 	if cardID == "" {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	invitationID := c.Params("id", "")
 	if invitationID == "" {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	var input dto.AnswerInvitation
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return apperror.WithKind(err, apperror.KindValidation)
+	}
+
+	if err := validate(c, &input); err != nil {
+		return err
 	}
 
 	err = h.services.Cards.AcceptInvitation(c.UserContext(), userID, cardID, invitationID, input)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":  false,
 		"message": "Successfully accepted invitation",
 	})
 }
@@ -572,55 +443,43 @@ func (h *Handler) acceptInvitation(c *fiber.Ctx) error {
 // @Param invitationID path int true "invitation id"
 // @Param request body dto.AnswerInvitation true "decline invitation feedback"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/code-connection/cards/{id}/invitations/{invitationID}/decline [post]
 func (h *Handler) declineInvitation(c *fiber.Ctx) error {
 	log.Println("Creating a card... ")
 
 	userID, err := getUserId(c)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	cardID := c.Params("id", "")
 	if cardID == "" {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	invitationID := c.Params("id", "")
 	if invitationID == "" {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	var input dto.AnswerInvitation
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return apperror.WithKind(err, apperror.KindValidation)
+	}
+
+	if err := validate(c, &input); err != nil {
+		return err
 	}
 
 	err = h.services.Cards.DeclineInvitation(c.UserContext(), userID, cardID, invitationID, input)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":  false,
 		"message": "Successfully declined invitation",
 	})
 }
@@ -633,33 +492,25 @@ func (h *Handler) declineInvitation(c *fiber.Ctx) error {
 // @Accept  json
 // @Produce  json
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/code-connection/cards/responses [get]
 func (h *Handler) getResponses(c *fiber.Ctx) error {
 	log.Println("Getting invitation by card id... ")
 
 	userID, err := getUserId(c)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	responses, err := h.services.Cards.GetResponsesByUserID(c.UserContext(), userID)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":  false,
-		"message": nil,
-		"count":   len(responses),
-		"cards":   responses,
+		"count": len(responses),
+		"cards": responses,
 	})
 }
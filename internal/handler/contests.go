@@ -1,8 +1,6 @@
 package handler
 
 import (
-	"net/http"
-
 	"github.com/gofiber/fiber/v2"
 	"github.com/pkg/errors"
 
@@ -19,9 +17,9 @@ import (
 // @Produce  json
 // @Param id path int true "contest id"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/contests/{id} [get]
 func (h *Handler) getContest(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -29,23 +27,15 @@ func (h *Handler) getContest(c *fiber.Ctx) error {
 
 	contestID := c.Params("id", "")
 	if contestID == "" {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": errors.Wrap(apperror.ErrInvalidParameter, "contest id is not correct"),
-		})
+		return errors.Wrap(apperror.ErrInvalidParameter, "contest id is not correct")
 	}
 
 	contest, err := h.services.Contests.GetByID(c.UserContext(), contestID)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":  false,
-		"message": nil,
 		"user_id": c.GetRespHeader(userCtx, ""),
 		"contest": contest,
 	})
@@ -59,9 +49,9 @@ func (h *Handler) getContest(c *fiber.Ctx) error {
 // @Accept  json
 // @Produce  json
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/contests [get]
 func (h *Handler) getAllContests(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -69,16 +59,11 @@ func (h *Handler) getAllContests(c *fiber.Ctx) error {
 
 	contests, err := h.services.Contests.GetAll(c.UserContext())
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":  false,
-		"message": nil,
-		"count":   len(contests),
-		"cards":   contests,
+		"count": len(contests),
+		"cards": contests,
 	})
 }
@@ -4,13 +4,11 @@ import (
 	"log"
 	"net/http"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 
 	"acsp/internal/apperror"
 	"acsp/internal/dto"
 	"acsp/internal/logging"
-	"acsp/internal/validation"
 )
 
 // @Summary Create a module for a course
@@ -23,9 +21,9 @@ import (
 // @Param request body dto.CreateCourseModule true "module information"
 // @Param id path int true "course id"
 // @Success 200 {integer} map[string]interface{} "message"
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/courses/{id}/modules [post]
 func (h *Handler) createCourseModule(c *fiber.Ctx) error {
 	log.Println("Creating a module for a course... ")
@@ -33,44 +31,27 @@ func (h *Handler) createCourseModule(c *fiber.Ctx) error {
 	// get project id from request
 	courseID, err := c.ParamsInt("id", -1)
 	if courseID == -1 {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrBadInputBody,
-		})
+		return apperror.ErrBadInputBody
 	}
 
 	input := dto.CreateCourseModule{}
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrBadInputBody,
-		})
+		return apperror.ErrBadInputBody
 	}
 
-	validate := validator.New()
-	err = validate.Struct(input)
+	err = validate(c, &input)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": validation.ValidatorErrors(err),
-		})
+		return err
 	}
 
 	err = h.services.CourseModules.Create(c.UserContext(), courseID, input)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(200).JSON(fiber.Map{
-		"errors":  false,
 		"message": "Created module for a course",
 	})
 }
@@ -86,9 +67,9 @@ func (h *Handler) createCourseModule(c *fiber.Ctx) error {
 // @Param moduleID path int true "module id"
 // @Param request body dto.UpdateCourseModule true "module information"
 // @Success 200 {object} string "Course updated"
-// @Failure 400 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/courses/{id}/modules/{moduleID} [put]
 func (h *Handler) updateCourseModule(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -98,51 +79,29 @@ func (h *Handler) updateCourseModule(c *fiber.Ctx) error {
 
 	courseID, err := c.ParamsInt("id", -1)
 	if courseID == -1 {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	moduleID, err := c.ParamsInt("moduleID", -1)
 	if moduleID == -1 {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrBodyParsed,
-		})
+		return apperror.ErrBodyParsed
 	}
 
-	validate := validator.New()
-	if err := validate.Struct(input); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": validation.ValidatorErrors(err),
-		})
+	if err := validate(c, &input); err != nil {
+		return err
 	}
 
 	err = h.services.CourseModules.Update(c.UserContext(), courseID, moduleID, input)
 	if err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(http.StatusOK).JSON(fiber.Map{"message": "Course module is updated"})
@@ -158,8 +117,8 @@ func (h *Handler) updateCourseModule(c *fiber.Ctx) error {
 // @Param id path int true "project id"
 // @Param moduleID path int true "module id"
 // @Success 200 {object} string "Course module deleted"
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/courses/{id}/modules/{moduleID} [delete]
 func (h *Handler) deleteCourseModule(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -167,36 +126,21 @@ func (h *Handler) deleteCourseModule(c *fiber.Ctx) error {
 
 	courseID, err := c.ParamsInt("id", -1)
 	if courseID == -1 {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
-	moduleID, err := c.ParamsInt("id", -1)
+	moduleID, err := c.ParamsInt("moduleID", -1)
 	if moduleID == -1 {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	err = h.services.CourseModules.Delete(c.UserContext(), courseID, moduleID)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(http.StatusNoContent).JSON(fiber.Map{"message": "Course module deleted"})
@@ -212,51 +156,34 @@ func (h *Handler) deleteCourseModule(c *fiber.Ctx) error {
 // @Param id path int true "course id"
 // @Param moduleID path int true "module id"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
-// @Router /api/v1/course/{id}/modules/{moduleID} [get]
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/courses/{id}/modules/{moduleID} [get]
 func (h *Handler) getCourseModuleByID(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
 	l.Info("Getting course module by id... ")
 
 	courseID, err := c.ParamsInt("id", -1)
 	if courseID == -1 {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	moduleID, err := c.ParamsInt("moduleID", -1)
 	if moduleID == -1 {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	module, err := h.services.CourseModules.GetByID(c.UserContext(), moduleID)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":  false,
-		"message": nil,
 		"user_id": c.GetRespHeader(userCtx, ""),
 		"module":  module,
 	})
@@ -270,9 +197,9 @@ func (h *Handler) getCourseModuleByID(c *fiber.Ctx) error {
 // @Accept  json
 // @Produce  json
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/courses/{id}/modules [get]
 func (h *Handler) getAllCourseModules(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -280,28 +207,17 @@ func (h *Handler) getAllCourseModules(c *fiber.Ctx) error {
 
 	courseID, err := c.ParamsInt("id", -1)
 	if courseID == -1 {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	modules, err := h.services.CourseModules.GetAll(c.UserContext(), courseID)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":  false,
-		"message": nil,
 		"count":   len(modules),
 		"modules": modules,
 	})
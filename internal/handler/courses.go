@@ -4,13 +4,11 @@ import (
 	"log"
 	"net/http"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 
 	"acsp/internal/apperror"
 	"acsp/internal/dto"
 	"acsp/internal/logging"
-	"acsp/internal/validation"
 )
 
 // @Summary Create a course
@@ -22,40 +20,29 @@ import (
 // @Produce  json
 // @Param request body dto.CreateCourse true "course information"
 // @Success 200 {integer} map[string]interface{} "message"
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/courses [post]
 func (h *Handler) createCourse(c *fiber.Ctx) error {
 	log.Println("Creating a course... ")
 
 	input := dto.CreateCourse{}
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrBadInputBody,
-		})
+		return apperror.ErrBadInputBody
 	}
 
-	validate := validator.New()
-	err := validate.Struct(input)
+	err := validate(c, &input)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": validation.ValidatorErrors(err),
-		})
+		return err
 	}
 
 	err = h.services.Courses.Create(c.UserContext(), input)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(200).JSON(fiber.Map{
-		"errors":  false,
 		"message": "Created a course",
 	})
 }
@@ -70,9 +57,9 @@ func (h *Handler) createCourse(c *fiber.Ctx) error {
 // @Param id path int true "course id"
 // @Param request body dto.UpdateCourse true "course information"
 // @Success 200 {object} string "Course updated"
-// @Failure 400 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/courses/{id} [put]
 func (h *Handler) updateCourse(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -80,39 +67,23 @@ func (h *Handler) updateCourse(c *fiber.Ctx) error {
 
 	courseID, err := c.ParamsInt("id", -1)
 	if courseID == -1 {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	var input dto.UpdateCourse
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrBodyParsed,
-		})
+		return apperror.ErrBodyParsed
 	}
 
-	validate := validator.New()
-	if err := validate.Struct(input); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": validation.ValidatorErrors(err),
-		})
+	if err := validate(c, &input); err != nil {
+		return err
 	}
 
 	err = h.services.Courses.Update(c.UserContext(), courseID, input)
 	if err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(http.StatusOK).JSON(fiber.Map{"message": "Course is updated"})
@@ -127,8 +98,8 @@ func (h *Handler) updateCourse(c *fiber.Ctx) error {
 // @Produce  json
 // @Param id path int true "course id"
 // @Success 200 {object} string "Project deleted"
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/courses/{id} [delete]
 func (h *Handler) deleteCourse(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -136,27 +107,17 @@ func (h *Handler) deleteCourse(c *fiber.Ctx) error {
 
 	courseID, err := c.ParamsInt("id", -1)
 	if courseID == -1 {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	err = h.services.Courses.Delete(c.UserContext(), courseID)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(http.StatusNoContent).JSON(fiber.Map{
-		"errors":  false,
 		"message": "Course is deleted",
 	})
 }
@@ -170,9 +131,9 @@ func (h *Handler) deleteCourse(c *fiber.Ctx) error {
 // @Produce  json
 // @Param id path int true "course id"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/courses/{id} [get]
 func (h *Handler) getCourseByID(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -180,28 +141,17 @@ func (h *Handler) getCourseByID(c *fiber.Ctx) error {
 
 	courseID, err := c.ParamsInt("id", -1)
 	if courseID == -1 {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	course, err := h.services.Courses.GetByID(c.UserContext(), courseID)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":  false,
-		"message": nil,
 		"user_id": c.GetRespHeader(userCtx, ""),
 		"course":  course,
 	})
@@ -215,9 +165,9 @@ func (h *Handler) getCourseByID(c *fiber.Ctx) error {
 // @Accept  json
 // @Produce  json
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/courses [get]
 func (h *Handler) getAllCourses(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -225,28 +175,17 @@ func (h *Handler) getAllCourses(c *fiber.Ctx) error {
 
 	disciplineID, err := c.ParamsInt("id", -1)
 	if disciplineID == -1 {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	courses, err := h.services.Courses.GetAll(c.UserContext())
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":  false,
-		"message": nil,
 		"count":   len(courses),
 		"courses": courses,
 	})
@@ -4,13 +4,11 @@ import (
 	"log"
 	"net/http"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 
 	"acsp/internal/apperror"
 	"acsp/internal/dto"
 	"acsp/internal/logging"
-	"acsp/internal/validation"
 )
 
 // @Summary Create a discipline
@@ -22,40 +20,29 @@ import (
 // @Produce  json
 // @Param request body dto.CreateDiscipline true "discipline information"
 // @Success 200 {integer} map[string]interface{} "message"
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/coding-lab/disciplines [post]
 func (h *Handler) createDiscipline(c *fiber.Ctx) error {
 	log.Println("Creating a discipline... ")
 
 	input := dto.CreateDiscipline{}
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrBadInputBody,
-		})
+		return apperror.ErrBadInputBody
 	}
 
-	validate := validator.New()
-	err := validate.Struct(input)
+	err := validate(c, &input)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": validation.ValidatorErrors(err),
-		})
+		return err
 	}
 
 	err = h.services.Disciplines.Create(c.UserContext(), input)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(200).JSON(fiber.Map{
-		"errors":  false,
 		"message": "Created discipline",
 	})
 }
@@ -70,9 +57,9 @@ func (h *Handler) createDiscipline(c *fiber.Ctx) error {
 // @Param id path int true "discipline id"
 // @Param request body dto.UpdateDiscipline true "discipline information"
 // @Success 200 {object} string "Discipline updated"
-// @Failure 400 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/coding-lab/disciplines/{id} [put]
 func (h *Handler) updateDiscipline(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -82,38 +69,22 @@ func (h *Handler) updateDiscipline(c *fiber.Ctx) error {
 
 	disciplineID, err := c.ParamsInt("id", -1)
 	if disciplineID == -1 {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrBodyParsed,
-		})
+		return apperror.ErrBodyParsed
 	}
 
-	validate := validator.New()
-	if err := validate.Struct(input); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": validation.ValidatorErrors(err),
-		})
+	if err := validate(c, &input); err != nil {
+		return err
 	}
 
 	err = h.services.Disciplines.Update(c.UserContext(), disciplineID, input)
 	if err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(http.StatusOK).JSON(fiber.Map{"message": "Discipline updated"})
@@ -128,8 +99,8 @@ func (h *Handler) updateDiscipline(c *fiber.Ctx) error {
 // @Produce  json
 // @Param id path int true "discipline id"
 // @Success 200 {object} string "Discipline deleted"
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/coding-lab/discipline/{id} [delete]
 func (h *Handler) deleteDiscipline(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -137,23 +108,14 @@ func (h *Handler) deleteDiscipline(c *fiber.Ctx) error {
 
 	id, err := c.ParamsInt("id", -1)
 	if id == -1 {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	err = h.services.Disciplines.Delete(c.UserContext(), id)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(http.StatusNoContent).JSON(fiber.Map{"message": "Discipline deleted"})
@@ -168,9 +130,9 @@ func (h *Handler) deleteDiscipline(c *fiber.Ctx) error {
 // @Produce  json
 // @Param id path int true "discipline id"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/coding-lab/discipline/{id} [get]
 func (h *Handler) getDisciplineByID(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -178,28 +140,17 @@ func (h *Handler) getDisciplineByID(c *fiber.Ctx) error {
 
 	disciplineID, err := c.ParamsInt("id", -1)
 	if disciplineID == -1 {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	discipline, err := h.services.Disciplines.GetByID(c.UserContext(), disciplineID)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":     false,
-		"message":    nil,
 		"user_id":    c.GetRespHeader(userCtx, ""),
 		"discipline": discipline,
 	})
@@ -213,9 +164,9 @@ func (h *Handler) getDisciplineByID(c *fiber.Ctx) error {
 // @Accept  json
 // @Produce  json
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/coding-lab/disciplines [get]
 func (h *Handler) getAllDisciplines(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -223,15 +174,10 @@ func (h *Handler) getAllDisciplines(c *fiber.Ctx) error {
 
 	disciplines, err := h.services.Disciplines.GetAll(c.UserContext())
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":   false,
-		"message":  nil,
 		"count":    len(disciplines),
 		"projects": disciplines,
 	})
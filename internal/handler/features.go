@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"acsp/internal/apperror"
+	"acsp/internal/dto"
+	"acsp/internal/logging"
+)
+
+// Feature is a middleware that hides the endpoints of a feature from the users who don't have it,
+// it must be used after the userIdentity middleware
+func (h *Handler) Feature(key string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		l := logging.LoggerFromContext(c.UserContext()).With(zap.String("feature", key))
+
+		userID, err := getUserId(c)
+		if err != nil {
+			return err
+		}
+
+		enabled, err := h.services.Features.IsEnabled(c.UserContext(), key, userID)
+		if err != nil {
+			l.Error("Error when evaluating the feature flag", zap.Error(err))
+
+			return err
+		}
+
+		// The feature looks like it doesn't exist to the users who don't have it
+		if !enabled {
+			return fiber.ErrNotFound
+		}
+
+		return c.Next()
+	}
+}
+
+// @Summary Get all feature flags
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description Get all feature flags with their rollout rules
+// @ID get-all-feature-flags
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/admin/features [get]
+func (h *Handler) getAllFeatureFlags(c *fiber.Ctx) error {
+	flags, err := h.services.Features.GetAll(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"count":    len(flags),
+		"features": flags,
+	})
+}
+
+// @Summary Get a feature flag by key
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description Get a feature flag with its rollout rules
+// @ID get-feature-flag-by-key
+// @Accept  json
+// @Produce  json
+// @Param key path string true "feature flag key"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/admin/features/{key} [get]
+func (h *Handler) getFeatureFlagByKey(c *fiber.Ctx) error {
+	flag, err := h.services.Features.GetByKey(c.UserContext(), c.Params("key"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"feature": flag,
+	})
+}
+
+// @Summary Create a feature flag
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description Create a feature flag, it is on for the listed users, the users with one of the roles and
+// @Description the given percentage of the other users
+// @ID create-feature-flag
+// @Accept  json
+// @Produce  json
+// @Param request body dto.CreateFeatureFlag true "feature flag"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400,409 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/admin/features [post]
+func (h *Handler) createFeatureFlag(c *fiber.Ctx) error {
+	l := logging.LoggerFromContext(c.UserContext())
+	l.Info("Creating a feature flag...")
+
+	var input dto.CreateFeatureFlag
+	if err := c.BodyParser(&input); err != nil {
+		return apperror.ErrBodyParsed
+	}
+
+	if err := validate(c, &input); err != nil {
+		return err
+	}
+
+	if err := h.services.Features.Create(c.UserContext(), input); err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusCreated).JSON(fiber.Map{
+		"message": "Feature flag created",
+	})
+}
+
+// @Summary Update a feature flag by key
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description Replace the rollout rules of a feature flag
+// @ID update-feature-flag-by-key
+// @Accept  json
+// @Produce  json
+// @Param key path string true "feature flag key"
+// @Param request body dto.UpdateFeatureFlag true "feature flag"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/admin/features/{key} [put]
+func (h *Handler) updateFeatureFlag(c *fiber.Ctx) error {
+	l := logging.LoggerFromContext(c.UserContext())
+	l.Info("Updating a feature flag...")
+
+	var input dto.UpdateFeatureFlag
+	if err := c.BodyParser(&input); err != nil {
+		return apperror.ErrBodyParsed
+	}
+
+	if err := validate(c, &input); err != nil {
+		return err
+	}
+
+	if err := h.services.Features.Update(c.UserContext(), c.Params("key"), input); err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Feature flag updated",
+	})
+}
+
+// @Summary Delete a feature flag by key
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description Delete a feature flag, the feature is off for everyone afterwards
+// @ID delete-feature-flag-by-key
+// @Accept  json
+// @Produce  json
+// @Param key path string true "feature flag key"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/admin/features/{key} [delete]
+func (h *Handler) deleteFeatureFlag(c *fiber.Ctx) error {
+	l := logging.LoggerFromContext(c.UserContext())
+	l.Info("Deleting a feature flag...")
+
+	if err := h.services.Features.Delete(c.UserContext(), c.Params("key")); err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Feature flag deleted",
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/gofiber/swagger"
 
 	"acsp/docs"
+	"acsp/internal/constants"
 	"acsp/internal/service"
 
 	_ "github.com/swaggo/files"
@@ -21,16 +22,33 @@ func NewHandler(s *service.Service) *Handler {
 func (h *Handler) InitRoutesFiber(app *fiber.App) *fiber.App {
 	docs.SwaggerInfo.BasePath = "/"
 
+	// The rate limits of the routes, the default one is applied to all of them
+	authLimit := h.RateLimit(constants.AuthRateLimitPolicy)
+	writeLimit := h.RateLimit(constants.WriteRateLimitPolicy)
+
 	// Define API routes
-	rest := app.Group("/api/v1")
+	rest := app.Group("/api/v1", h.RateLimit(constants.DefaultRateLimitPolicy))
 	{
 		// Define auth routes
 		auth := rest.Group("/auth")
 		{
-			auth.Post("/sign-up", h.signUp)
-			auth.Post("/sign-in", h.signIn)
+			auth.Post("/sign-up", authLimit, h.signUp)
+			auth.Post("/sign-in", authLimit, h.signIn)
+			auth.Post("/sign-in/2fa", authLimit, h.signInTwoFactor)
+			auth.Get("/oidc/login", authLimit, h.oidcLogin)
+			auth.Get("/oidc/callback", authLimit, h.oidcCallback)
+			auth.Post("/oidc/token", authLimit, h.oidcToken)
 			auth.Post("/refresh", h.refreshToken)
-			auth.Post("/logout", h.userIdentity, h.logout)
+			auth.Post("/logout", h.userIdentity, h.sessionOnly, h.logout)
+
+			// Define two-factor authentication routes
+			twoFactor := auth.Group("/2fa", h.userIdentity, h.sessionOnly, authLimit)
+			{
+				twoFactor.Post("/enroll", h.enrollTwoFactor)
+				twoFactor.Post("/enable", h.enableTwoFactor)
+				twoFactor.Post("/disable", h.disableTwoFactor)
+				twoFactor.Post("/recovery-codes", h.regenerateRecoveryCodes)
+			}
 		}
 
 		// Define user routes
@@ -39,6 +57,16 @@ func (h *Handler) InitRoutesFiber(app *fiber.App) *fiber.App {
 			users.Get("/profile", h.getUserProfile)
 			users.Post("/image", h.uploadUserImage)
 			users.Put("/profile", h.updateUserProfile)
+			users.Get("/notifications", h.getNotifications)
+			users.Post("/notifications/:id/read", h.readNotification)
+
+			// Define personal access token routes, the tokens are managed by the signed in users only
+			tokens := users.Group("/tokens", h.sessionOnly)
+			{
+				tokens.Get("/", h.getPersonalAccessTokens)
+				tokens.Post("/", h.createPersonalAccessToken)
+				tokens.Delete("/:id", h.revokePersonalAccessToken)
+			}
 		}
 
 		// Define user routes with authentication middleware (userIdentity) for all routes
@@ -55,13 +83,13 @@ func (h *Handler) InitRoutesFiber(app *fiber.App) *fiber.App {
 
 				comments := articles.Group("/:id/comments")
 				{
-					comments.Post("/", h.commentArticle)                         // comment an article
-					comments.Get("/", h.getCommentsByArticleID)                  // get all comments by article id
-					comments.Post("/:commentID/replies", h.replyToCommentByID)   // reply to comment by id and article id
-					comments.Get("/:commentID/replies", h.getRepliesByCommentID) // get all replies by comment id and article id
-					comments.Post("/:commentID/upvote", h.upvoteCommentByID)     // like comment by id and article id
-					comments.Post("/:commentID/downvote", h.downvoteCommentByID) // dislike comment by id and article id
-					comments.Get("/:commentID/votes", h.getVotesByCommentID)     // get all upvotes by comment id and article id
+					comments.Post("/", writeLimit, h.commentArticle)                       // comment an article
+					comments.Get("/", h.getCommentsByArticleID)                            // get all comments by article id
+					comments.Post("/:commentID/replies", writeLimit, h.replyToCommentByID) // reply to comment by id and article id
+					comments.Get("/:commentID/replies", h.getRepliesByCommentID)           // get all replies by comment id and article id
+					comments.Post("/:commentID/upvote", h.upvoteCommentByID)               // like comment by id and article id
+					comments.Post("/:commentID/downvote", h.downvoteCommentByID)           // dislike comment by id and article id
+					comments.Get("/:commentID/votes", h.getVotesByCommentID)               // get all upvotes by comment id and article id
 				}
 
 			}
@@ -78,7 +106,7 @@ func (h *Handler) InitRoutesFiber(app *fiber.App) *fiber.App {
 		}
 
 		// Define code connection routes
-		codeConnection := rest.Group("/code-connection", h.userIdentity)
+		codeConnection := rest.Group("/code-connection", h.userIdentity, h.Feature(constants.CodeConnectionFeature))
 		{
 			applicants := codeConnection.Group("/applicants")
 			{
@@ -97,7 +125,7 @@ func (h *Handler) InitRoutesFiber(app *fiber.App) *fiber.App {
 
 				invitations := cards.Group("/:id/invitations")
 				{
-					invitations.Post("/", h.createInvitation)                       // send an invitation to a user
+					invitations.Post("/", writeLimit, h.createInvitation)           // send an invitation to a user
 					invitations.Post("/:invitationID/accept", h.acceptInvitation)   // accept an invitation
 					invitations.Get("/:invitationID", h.getInvitationByID)          // get an invitation by card id and invitation id
 					invitations.Get("/", h.getInvitationsByCardID)                  // get all invitations of a card
@@ -125,30 +153,30 @@ func (h *Handler) InitRoutesFiber(app *fiber.App) *fiber.App {
 
 			modules := courses.Group("/:id/modules")
 			{
-				modules.Post("/", h.Authorize("admin"), h.createCourseModule)     // create a module
-				modules.Put("/:id", h.Authorize("admin"), h.updateCourseModule)   // update a module
-				modules.Delete(":id", h.Authorize("admin"), h.deleteCourseModule) // delete a module
-				modules.Get("/", h.getAllCourseModules)                           // get all modules
-				modules.Get("/:id", h.getCourseModuleByID)                        // get module by id
+				modules.Post("/", h.Authorize("admin"), h.createCourseModule)            // create a module
+				modules.Put("/:moduleID", h.Authorize("admin"), h.updateCourseModule)    // update a module
+				modules.Delete("/:moduleID", h.Authorize("admin"), h.deleteCourseModule) // delete a module
+				modules.Get("/", h.getAllCourseModules)                                  // get all modules
+				modules.Get("/:moduleID", h.getCourseModuleByID)                         // get module by id
 
-				lessons := modules.Group("/:id/lessons")
+				lessons := modules.Group("/:moduleID/lessons")
 				{
-					lessons.Post("/", h.Authorize("admin"), h.createCourseLesson) // create a lesson
-					lessons.Put("/:id", h.Authorize("admin"), h.updateLesson)     // update a lesson
-					lessons.Delete("/:id", h.Authorize("admin"), h.deleteLesson)  // delete a lesson
-					lessons.Get("/", h.getAllLessonsByModuleID)                   // get all lessons
-					lessons.Get("/:id", h.getLessonByID)                          // get lesson by id
+					lessons.Post("/", h.Authorize("admin"), h.createCourseLesson)      // create a lesson
+					lessons.Put("/:lessonID", h.Authorize("admin"), h.updateLesson)    // update a lesson
+					lessons.Delete("/:lessonID", h.Authorize("admin"), h.deleteLesson) // delete a lesson
+					lessons.Get("/", h.getAllLessonsByModuleID)                        // get all lessons
+					lessons.Get("/:lessonID", h.getLessonByID)                         // get lesson by id
 				}
 			}
 
 			lessonComments := courses.Group("/:id/lessons")
 			{
-				lessonComments.Post("/:id/comments", h.commentLesson)        // comment a lesson
-				lessonComments.Get("/:id/comments", h.getLessonCommentsByID) // get all comments of a lesson
+				lessonComments.Post("/:lessonID/comments", writeLimit, h.commentLesson) // comment a lesson
+				lessonComments.Get("/:lessonID/comments", h.getLessonCommentsByID)      // get all comments of a lesson
 			}
 		}
 
-		codingLab := rest.Group("/coding-lab", h.userIdentity)
+		codingLab := rest.Group("/coding-lab", h.userIdentity, h.Feature(constants.CodingLabFeature))
 		{
 			disciplines := codingLab.Group("/disciplines")
 			{
@@ -181,17 +209,31 @@ func (h *Handler) InitRoutesFiber(app *fiber.App) *fiber.App {
 		// Define admin routes
 		admin := rest.Group("/admin", h.userIdentity, h.Authorize("admin"))
 		{
-			admin.Get("/users", h.getAllUsers)       // get all users
-			admin.Get("/users/:id", h.getUserByID)   // get user by id
-			admin.Put("/users/:id", h.updateUser)    // update user by id
-			admin.Delete("/users/:id", h.deleteUser) // delete user by id
+			admin.Get("/users", h.getAllUsers)            // get all users
+			admin.Get("/users/:id", h.getUserByID)        // get user by id
+			admin.Put("/users/:id", h.updateUser)         // update user by id
+			admin.Delete("/users/:id", h.deleteUser)      // delete user by id
+			admin.Post("/users/:id/unlock", h.unlockUser) // unlock the sign-in of user by id
 			admin.Post("/contests", h.createContest)
 			admin.Post("/contests/:id", h.updateContest)
 			admin.Delete("/contests/:id", h.deleteContest)
+			admin.Get("/storage/orphans", h.getStorageOrphans)   // get orphaned objects of the storage
+			admin.Get("/settings", h.getSettings)                // get the runtime settings
+			admin.Patch("/settings", h.patchSettings)            // change the runtime settings
+			admin.Get("/settings/changes", h.getSettingsChanges) // get the changes of the runtime settings
+			admin.Get("/features", h.getAllFeatureFlags)         // get all feature flags
+			admin.Get("/features/:key", h.getFeatureFlagByKey)   // get feature flag by key
+			admin.Post("/features", h.createFeatureFlag)         // create a feature flag
+			admin.Put("/features/:key", h.updateFeatureFlag)     // update a feature flag
+			admin.Delete("/features/:key", h.deleteFeatureFlag)  // delete a feature flag
+			admin.Get("/audit", h.getAuditEvents)                // get the audit events
 		}
 	}
 
 	app.Get("/swagger/*", swagger.HandlerDefault)
+	app.Get("/healthz", h.healthz)
+	app.Get("/readyz", h.readyz)
+	app.Get("/.well-known/jwks.json", h.jwks)
 
 	return app
 }
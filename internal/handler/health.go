@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"acsp/internal/constants"
+)
+
+// @Summary Liveness probe
+// @Tags health
+// @Description Reports that the process is alive, the dependencies aren't checked
+// @ID healthz
+// @Produce  json
+// @Success 200 {object} map[string]interface{}
+// @Router /healthz [get]
+func (h *Handler) healthz(c *fiber.Ctx) error {
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"status": constants.HealthStatusUp,
+	})
+}
+
+// @Summary Readiness probe
+// @Tags health
+// @Description Checks postgres, redis and the object storage, 503 when one of them is down
+// @ID readyz
+// @Produce  json
+// @Success 200 {object} model.HealthReport
+// @Failure 503 {object} model.HealthReport
+// @Router /readyz [get]
+func (h *Handler) readyz(c *fiber.Ctx) error {
+	report := h.services.Health.Check(c.UserContext())
+
+	status := http.StatusOK
+	if report.Status != constants.HealthStatusUp {
+		status = http.StatusServiceUnavailable
+	}
+
+	return c.Status(status).JSON(report)
+}
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// @Summary JSON Web Key Set
+// @Tags auth
+// @Description Returns the public keys verifying the access tokens, the kid header of a token is the id of its key
+// @ID jwks
+// @Produce  json
+// @Success 200 {object} jwks.JWKSet
+// @Router /.well-known/jwks.json [get]
+func (h *Handler) jwks(c *fiber.Ctx) error {
+	// The keys change with the rotation only, the verifiers refetch them on an unknown kid
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+
+	return c.Status(http.StatusOK).JSON(h.services.Authorization.PublicKeys())
+}
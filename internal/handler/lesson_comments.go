@@ -1,107 +1,76 @@
 package handler
 
 import (
-	"log"
 	"net/http"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 
 	"acsp/internal/apperror"
 	"acsp/internal/dto"
 	"acsp/internal/logging"
-	"acsp/internal/validation"
 )
 
-// @Summary Create a module for a course
+// @Summary Comment a lesson
 // @Security ApiKeyAuth
 // @Tags courses
-// @Description Method for creating a module of a course
+// @Description Method for commenting a lesson of a course
 // @ID create-lesson-comment
 // @Accept  json
 // @Produce  json
 // @Param request body dto.CreateLessonComment true "comment information"
 // @Param id path int true "course id"
 // @Param lessonID path int true "lesson id"
-// @Success 200 {integer} map[string]interface{} "message"
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Success 201 {object} map[string]interface{} "message"
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/courses/{id}/lessons/{lessonID}/comments [post]
 func (h *Handler) commentLesson(c *fiber.Ctx) error {
-	log.Println("Commenting a lesson... ")
-
-	// get project id from request
 	courseID, err := c.ParamsInt("id", -1)
 	if courseID == -1 {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrBadInputBody,
-		})
+		return apperror.ErrBadInputBody
 	}
 
 	lessonID, err := c.ParamsInt("lessonID", -1)
 	if lessonID == -1 {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrBadInputBody,
-		})
+		return apperror.ErrBadInputBody
 	}
 
 	input := dto.CreateLessonComment{}
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrBadInputBody,
-		})
+		return apperror.ErrBadInputBody
 	}
 
-	validate := validator.New()
-	err = validate.Struct(input)
+	err = validate(c, &input)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": validation.ValidatorErrors(err),
-		})
+		return err
 	}
 
 	err = h.services.LessonComments.Create(c.UserContext(), lessonID, input)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
-	return c.Status(200).JSON(fiber.Map{
-		"errors":  false,
-		"message": "Created module for a course",
-	})
+	return c.Status(http.StatusCreated).JSON(fiber.Map{"message": "Lesson is commented"})
 }
 
-// @Summary Get a course module by id
+// @Summary Get the comments of a lesson
 // @Security ApiKeyAuth
 // @Tags courses
-// @Description Get a course module by id
+// @Description Get the comments of a lesson of a course
 // @ID get-lesson-comments-by-id
 // @Accept  json
 // @Produce  json
 // @Param id path int true "course id"
 // @Param lessonID path int true "lesson id"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/courses/{id}/lessons/{lessonID}/comments [get]
 func (h *Handler) getLessonCommentsByID(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -109,41 +78,24 @@ func (h *Handler) getLessonCommentsByID(c *fiber.Ctx) error {
 
 	courseID, err := c.ParamsInt("id", -1)
 	if courseID == -1 {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	lessonID, err := c.ParamsInt("lessonID", -1)
 	if lessonID == -1 {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	comments, err := h.services.LessonComments.GetAll(c.UserContext(), lessonID)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":   false,
-		"message":  nil,
 		"user_id":  c.GetRespHeader(userCtx, ""),
 		"comments": comments,
 	})
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"acsp/internal/apperror"
+	"acsp/internal/dto"
+	"acsp/internal/model"
+	"acsp/internal/service"
+	mockService "acsp/internal/service/mocks"
+)
+
+// newRoutesApp serves the routes of InitRoutesFiber, the requests are signed in as the user 1
+func newRoutesApp(t *testing.T, s *service.Service) *fiber.App {
+	c := gomock.NewController(t)
+	t.Cleanup(c.Finish)
+
+	auth := mockService.NewMockAuthorization(c)
+	auth.EXPECT().ParseToken("token").Return(model.Principal{UserID: "1"}, nil).AnyTimes()
+
+	s.Authorization = auth
+	s.RateLimit = &fakeRateLimiter{max: 100, counts: map[string]int{}}
+
+	return NewHandler(s).InitRoutesFiber(fiber.New(fiber.Config{ErrorHandler: apperror.ErrorHandler}))
+}
+
+func TestHandler_lessonComments(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	comments := mockService.NewMockLessonComments(c)
+	comments.EXPECT().Create(gomock.Any(), 7, dto.CreateLessonComment{Text: "Thanks"}).Return(nil)
+	comments.EXPECT().GetAll(gomock.Any(), 7).Return([]model.CourseModuleLessonComment{{Text: "Thanks"}}, nil)
+
+	app := newRoutesApp(t, &service.Service{LessonComments: comments})
+
+	request := httptest.NewRequest("POST", "/api/v1/courses/3/lessons/7/comments",
+		bytes.NewReader([]byte(`{"text":"Thanks"}`)))
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Authorization", "Bearer token")
+
+	response, err := app.Test(request)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, response.StatusCode)
+
+	data, _ := io.ReadAll(response.Body)
+	assert.JSONEq(t, `{"message":"Lesson is commented"}`, string(data))
+
+	request = httptest.NewRequest("GET", "/api/v1/courses/3/lessons/7/comments", nil)
+	request.Header.Add("Authorization", "Bearer token")
+
+	response, err = app.Test(request)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, response.StatusCode)
+
+	data, _ = io.ReadAll(response.Body)
+	assert.Contains(t, string(data), `"text":"Thanks"`)
+}
@@ -4,13 +4,11 @@ import (
 	"log"
 	"net/http"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 
 	"acsp/internal/apperror"
 	"acsp/internal/dto"
 	"acsp/internal/logging"
-	"acsp/internal/validation"
 )
 
 // @Summary Create a material
@@ -22,48 +20,34 @@ import (
 // @Produce  json
 // @Param input body dto.CreateMaterial true "material information"
 // @Success 200 {integer} integer 1
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/scholar/materials [post]
 func (h *Handler) createMaterial(c *fiber.Ctx) error {
 	log.Println("Creating a material... ")
 
 	userId, err := getUserId(c)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	input := dto.CreateMaterial{}
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrBadInputBody,
-		})
+		return apperror.ErrBadInputBody
 	}
 
-	validate := validator.New()
-	err = validate.Struct(input)
+	err = validate(c, &input)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": validation.ValidatorErrors(err),
-		})
+		return err
 	}
 
 	err = h.services.Materials.Create(c.UserContext(), userId, input)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(200).JSON(fiber.Map{
-		"errors":  false,
 		"message": "Created material",
 	})
 }
@@ -77,9 +61,9 @@ func (h *Handler) createMaterial(c *fiber.Ctx) error {
 // @Produce  json
 // @Param userID path string true "user id"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/scholar/materials/{userID} [get]
 func (h *Handler) getAllMaterialsByUserID(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -87,23 +71,15 @@ func (h *Handler) getAllMaterialsByUserID(c *fiber.Ctx) error {
 
 	userId, err := getUserId(c)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	materials, err := h.services.Materials.GetAllByUserID(c.UserContext(), userId)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":   false,
-		"message":  nil,
 		"count":    len(materials),
 		"user_id":  c.GetRespHeader(userCtx, ""),
 		"articles": materials,
@@ -118,9 +94,9 @@ func (h *Handler) getAllMaterialsByUserID(c *fiber.Ctx) error {
 // @Accept  json
 // @Produce  json
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/scholar/materials [get]
 func (h *Handler) getAllMaterials(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -128,15 +104,10 @@ func (h *Handler) getAllMaterials(c *fiber.Ctx) error {
 
 	materials, err := h.services.Materials.GetAll(c.UserContext())
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":    false,
-		"message":   nil,
 		"count":     len(materials),
 		"user_id":   c.GetRespHeader(userCtx, ""),
 		"materials": materials,
@@ -152,9 +123,9 @@ func (h *Handler) getAllMaterials(c *fiber.Ctx) error {
 // @Produce  json
 // @Param id path string true "material id"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/scholar/materials/{id} [get]
 func (h *Handler) getMaterialByID(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -162,23 +133,15 @@ func (h *Handler) getMaterialByID(c *fiber.Ctx) error {
 
 	materialID := c.Params("id", "")
 	if materialID == "" {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	material, err := h.services.Materials.GetByID(c.UserContext(), materialID)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":   false,
-		"message":  nil,
 		"user_id":  c.GetRespHeader(userCtx, ""),
 		"material": material,
 	})
@@ -194,9 +157,9 @@ func (h *Handler) getMaterialByID(c *fiber.Ctx) error {
 // @Param id path string true "material id"
 // @Param input body dto.UpdateMaterial true "material information"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/scholar/materials/{id} [put]
 func (h *Handler) updateMaterial(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -204,43 +167,27 @@ func (h *Handler) updateMaterial(c *fiber.Ctx) error {
 
 	userID, err := getUserId(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	var input dto.UpdateMaterial
 
 	materialID := c.Params("id", "")
 	if materialID == "" {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrBodyParsed,
-		})
+		return apperror.ErrBodyParsed
 	}
 
-	validate := validator.New()
-	if err := validate.Struct(input); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": validation.ValidatorErrors(err),
-		})
+	if err := validate(c, &input); err != nil {
+		return err
 	}
 
 	err = h.services.Materials.Update(c.UserContext(), materialID, userID, input)
 	if err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(http.StatusOK).JSON(fiber.Map{"message": "Material is updated"})
@@ -255,8 +202,8 @@ func (h *Handler) updateMaterial(c *fiber.Ctx) error {
 // @Produce  json
 // @Param id path string true "material id"
 // @Success 200 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/scholar/materials/{id} [delete]
 func (h *Handler) deleteMaterial(c *fiber.Ctx) error {
 	l := logging.LoggerFromContext(c.UserContext())
@@ -264,26 +211,17 @@ func (h *Handler) deleteMaterial(c *fiber.Ctx) error {
 
 	userId, err := getUserId(c)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	id := c.Params("id")
 	if err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": apperror.ErrParameterNotFound,
-		})
+		return apperror.ErrParameterNotFound
 	}
 
 	err = h.services.Materials.Delete(c.UserContext(), userId, id)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(http.StatusNoContent).JSON(fiber.Map{"message": "Material is deleted"})
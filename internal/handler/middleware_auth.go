@@ -1,21 +1,22 @@
 package handler
 
 import (
-	"fmt"
-	"net/http"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
 	"acsp/internal/apperror"
+	"acsp/internal/constants"
 	"acsp/internal/logging"
+	"acsp/internal/model"
+	"acsp/internal/service"
 )
 
 const (
 	authorizationHeader = "Authorization"
 	userCtx             = "userId"
+	principalCtx        = "principal"
 )
 
 func (h *Handler) userIdentity(c *fiber.Ctx) error {
@@ -24,9 +25,7 @@ func (h *Handler) userIdentity(c *fiber.Ctx) error {
 	header := c.Get(authorizationHeader)
 
 	if header == "" {
-		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
-			"message": "empty auth header",
-		})
+		return apperror.New(apperror.KindUnauthorized, "empty auth header")
 	}
 
 	// Split the header into Bearer and the token part
@@ -35,31 +34,58 @@ func (h *Handler) userIdentity(c *fiber.Ctx) error {
 	// Bearer token format
 	// https://tools.ietf.org/html/rfc6750#section-2.1
 	if len(headerParts) != 2 || headerParts[0] != "Bearer" {
-		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
-			"message": "invalid auth header",
-		})
+		return apperror.New(apperror.KindUnauthorized, "invalid auth header")
 	}
 
 	// Check if the token is empty or not
 	if len(headerParts[1]) == 0 {
-		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
-			"message": "token is empty",
-		})
+		return apperror.New(apperror.KindUnauthorized, "token is empty")
 	}
 
-	// Parse the token and get the user id
-	userId, err := h.services.Authorization.ParseToken(headerParts[1])
-	if err != nil {
-		l.Error("Error when parsing token", zap.Error(err))
+	// Parse the token and get the user id, the personal access tokens are looked up by their hash
+	var (
+		principal model.Principal
+		err       error
+	)
 
-		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
-			"message": "token parsing error",
-		})
+	if strings.HasPrefix(headerParts[1], constants.PersonalAccessTokenPrefix) {
+		principal, err = h.services.Tokens.Authenticate(c.UserContext(), headerParts[1])
+		if err != nil {
+			return err
+		}
+
+		if !allowsMethod(principal, c.Method()) {
+			return apperror.ErrTokenScope
+		}
+	} else {
+		principal, err = h.services.Authorization.ParseToken(headerParts[1])
+		if err != nil {
+			l.Error("Error when parsing token", zap.Error(err))
+
+			return apperror.New(apperror.KindUnauthorized, "token parsing error")
+		}
 	}
 
+	userId := principal.UserID
+
 	// Set the user id in the context so that it can be used in other handlers to get the user information
 	c.Set(userCtx, userId)
 
+	// The roles of the token are checked by Authorize
+	c.Locals(principalCtx, principal)
+
+	// Every log line of the request has the user id from now on
+	ctx := logging.ContextWithLogger(c.UserContext(), l.With(zap.String("userID", userId)))
+
+	// The audit events of the request are attributed to the user
+	requestID, _ := c.Locals(requestIDLocal).(string)
+	c.SetUserContext(service.ContextWithActor(ctx, model.AuditActor{
+		UserID:    userId,
+		TokenID:   principal.TokenID,
+		IP:        clientIP(c),
+		RequestID: requestID,
+	}))
+
 	// Call the next handler
 	return c.Next()
 }
@@ -67,37 +93,39 @@ func (h *Handler) userIdentity(c *fiber.Ctx) error {
 // Authorize is a middleware that checks if the user has the required role(-s) to access the endpoint
 func (h *Handler) Authorize(allowedRoles ...string) func(*fiber.Ctx) error {
 	return func(c *fiber.Ctx) error {
-		// Get the user id from the context that was set in the userIdentity middleware
-		userID, err := getUserId(c)
-		if len(userID) == 0 || userID == "" {
-			return errors.Wrap(err, apperror.ErrUserIDNotFound.Error())
+		// Get the user that was set in the userIdentity middleware
+		principal, ok := c.Locals(principalCtx).(model.Principal)
+		if !ok || principal.UserID == "" {
+			return apperror.ErrUserIDNotFound
 		}
-		fmt.Println(userID)
-		// Get the user roles from the database
-		roles, err := h.services.Roles.GetUserRoles(c.UserContext(), userID)
-		if err != nil {
-			return errors.Wrap(err, "Error when getting roles of user")
-		}
-		fmt.Println(roles)
+
+		// The roles are the ones of the access token, the database isn't queried
+		logging.LoggerFromContext(c.UserContext()).Debug("Authorizing the user", zap.Strings("roles", principal.Roles))
+
 		// Check if the user has the required role
-		roleAllowed := false
-
-		// Loop through the user roles
-		for _, role := range roles {
-			// Loop through the allowed roles
-			for _, allowedRole := range allowedRoles {
-				if role.Name == allowedRole {
-					roleAllowed = true
-					break
-				}
+		allowedRole := ""
+
+		// Loop through the allowed roles
+		for _, r := range allowedRoles {
+			if principal.HasRole(r) {
+				allowedRole = r
+				break
 			}
 		}
 
 		// If the user does not have the required role, return an error
-		if !roleAllowed {
-			return c.Status(http.StatusForbidden).JSON(fiber.Map{
-				"message": "You are not allowed to call this endpoint",
-			})
+		if allowedRole == "" {
+			return apperror.New(apperror.KindForbidden, "You are not allowed to call this endpoint")
+		}
+
+		// A personal access token uses the roles of its scopes only
+		if !principal.HasScope(allowedRole) {
+			return apperror.ErrTokenScope
+		}
+
+		// The role may require two-factor authentication
+		if err := h.services.TwoFactor.RequireForRole(allowedRole, principal.TwoFactor); err != nil {
+			return err
 		}
 
 		// Call the next handler if the user has the required role
@@ -105,6 +133,31 @@ func (h *Handler) Authorize(allowedRoles ...string) func(*fiber.Ctx) error {
 	}
 }
 
+// sessionOnly is a middleware that refuses the personal access tokens, e.g. a token may not create tokens
+func (h *Handler) sessionOnly(c *fiber.Ctx) error {
+	principal, ok := c.Locals(principalCtx).(model.Principal)
+	if !ok || principal.TokenID != 0 {
+		return apperror.ErrSessionRequired
+	}
+
+	return c.Next()
+}
+
+// allowsMethod returns whether the scopes of the principal allow the method, the read scope allows the
+// safe methods and the write scope all of them
+func allowsMethod(principal model.Principal, method string) bool {
+	if principal.HasScope(constants.ScopeWrite) {
+		return true
+	}
+
+	switch method {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		return principal.HasScope(constants.ScopeRead)
+	default:
+		return false
+	}
+}
+
 // getUserId gets the user id from the context
 func getUserId(c *fiber.Ctx) (string, error) {
 	// Get the user id from the context that was set in the userIdentity middleware
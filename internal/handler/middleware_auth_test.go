@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
@@ -10,7 +11,11 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"acsp/internal/apperror"
+	"acsp/internal/constants"
+	"acsp/internal/model"
 	"acsp/internal/service"
 	mockService "acsp/internal/service/mocks"
 )
@@ -29,11 +34,11 @@ func TestHandler_userIdentity(t *testing.T) {
 	}{
 		{
 			name:        "Ok",
-			headerName:  "Users",
+			headerName:  "Authorization",
 			headerValue: "Bearer token",
 			token:       "token",
 			mockBehavior: func(r *mockService.MockAuthorization, token string) {
-				r.EXPECT().ParseToken(token).Return("1", nil)
+				r.EXPECT().ParseToken(token).Return(model.Principal{UserID: "1"}, nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: "1",
@@ -45,36 +50,37 @@ func TestHandler_userIdentity(t *testing.T) {
 			token:                "token",
 			mockBehavior:         func(r *mockService.MockAuthorization, token string) {},
 			expectedStatusCode:   401,
-			expectedResponseBody: `{"message":"empty auth header"}`,
+			expectedResponseBody: "empty auth header",
 		},
 		{
 			name:                 "Invalid Header Value",
-			headerName:           "Users",
-			headerValue:          "Bearer token",
+			headerName:           "Authorization",
+			headerValue:          "Basic token",
 			token:                "token",
 			mockBehavior:         func(r *mockService.MockAuthorization, token string) {},
 			expectedStatusCode:   401,
-			expectedResponseBody: `{"message":"invalid auth header"}`,
+			expectedResponseBody: "invalid auth header",
 		},
 		{
+			// The trailing space of the header is trimmed, so there is no token part
 			name:                 "Empty Token",
-			headerName:           "Users",
+			headerName:           "Authorization",
 			headerValue:          "Bearer ",
 			token:                "token",
 			mockBehavior:         func(r *mockService.MockAuthorization, token string) {},
 			expectedStatusCode:   401,
-			expectedResponseBody: `{"message":"token is empty"}`,
+			expectedResponseBody: "invalid auth header",
 		},
 		{
 			name:        "Parse Error",
-			headerName:  "Users",
+			headerName:  "Authorization",
 			headerValue: "Bearer token",
 			token:       "token",
 			mockBehavior: func(r *mockService.MockAuthorization, token string) {
-				r.EXPECT().ParseToken(token).Return("0", errors.New("invalid token"))
+				r.EXPECT().ParseToken(token).Return(model.Principal{}, errors.New("invalid token"))
 			},
 			expectedStatusCode:   401,
-			expectedResponseBody: `{"message":"invalid token"}`,
+			expectedResponseBody: "token parsing error",
 		},
 	}
 
@@ -86,10 +92,9 @@ func TestHandler_userIdentity(t *testing.T) {
 			repo := mockService.NewMockAuthorization(c)
 			test.mockBehavior(repo, test.token)
 
-			services := &service.Service{Authorization: repo}
-			handler := Handler{services}
+			handler := NewHandler(&service.Service{Authorization: repo})
 
-			app := fiber.New()
+			app := fiber.New(fiber.Config{ErrorHandler: apperror.ErrorHandler})
 			app.Get("/identity", handler.userIdentity, func(ctx *fiber.Ctx) error {
 				id := ctx.GetRespHeader(userCtx)
 				return ctx.Status(http.StatusOK).SendString(id)
@@ -97,13 +102,142 @@ func TestHandler_userIdentity(t *testing.T) {
 
 			request := httptest.NewRequest("GET", "/identity", nil)
 			request.Header.Add("Content-Type", "application/json")
-			request.Header.Set(test.headerName, test.headerValue)
+			if test.headerName != "" {
+				request.Header.Set(test.headerName, test.headerValue)
+			}
+
+			response, err := app.Test(request)
+			require.NoError(t, err)
+			data, _ := io.ReadAll(response.Body)
+
+			assert.Equal(t, test.expectedStatusCode, response.StatusCode)
+
+			if response.StatusCode == http.StatusOK {
+				assert.Equal(t, test.expectedResponseBody, string(data))
+			} else {
+				var problem apperror.Problem
+				require.NoError(t, json.Unmarshal(data, &problem))
+				assert.Equal(t, test.expectedResponseBody, problem.Detail)
+			}
+		})
+	}
+}
+
+func TestAllowsMethod(t *testing.T) {
+	session := model.Principal{UserID: "1"}
+	read := model.Principal{UserID: "1", TokenID: 1, Scopes: []string{constants.ScopeRead}}
+	write := model.Principal{UserID: "1", TokenID: 2, Scopes: []string{constants.ScopeWrite}}
+	admin := model.Principal{UserID: "1", TokenID: 3, Scopes: []string{"admin"}}
+
+	assert.True(t, allowsMethod(session, fiber.MethodDelete))
+	assert.True(t, allowsMethod(read, fiber.MethodGet))
+	assert.False(t, allowsMethod(read, fiber.MethodPost))
+	assert.True(t, allowsMethod(write, fiber.MethodGet))
+	assert.True(t, allowsMethod(write, fiber.MethodPut))
+	assert.False(t, allowsMethod(admin, fiber.MethodGet))
+}
+
+func TestPersonalAccessTokenRoutes(t *testing.T) {
+	const token = constants.PersonalAccessTokenPrefix + "token"
+
+	read := model.Principal{UserID: "1", Roles: []string{"admin"}, TokenID: 1, Scopes: []string{constants.ScopeRead}}
+	write := model.Principal{UserID: "1", Roles: []string{"admin"}, TokenID: 2, Scopes: []string{constants.ScopeWrite}}
+
+	tests := []struct {
+		name           string
+		principal      model.Principal
+		method         string
+		path           string
+		mockBehavior   func(c *gomock.Controller, services *service.Service)
+		expectedStatus int
+		expectedDetail string
+	}{
+		{
+			name:           "Read Scope On POST",
+			principal:      read,
+			method:         fiber.MethodPost,
+			path:           "/api/v1/users/image",
+			expectedStatus: http.StatusForbidden,
+			expectedDetail: apperror.ErrTokenScope.Error(),
+		},
+		{
+			name:           "Token Lists Tokens",
+			principal:      read,
+			method:         fiber.MethodGet,
+			path:           "/api/v1/users/tokens",
+			expectedStatus: http.StatusForbidden,
+			expectedDetail: apperror.ErrSessionRequired.Error(),
+		},
+		{
+			name:           "Token Creates Tokens",
+			principal:      write,
+			method:         fiber.MethodPost,
+			path:           "/api/v1/users/tokens",
+			expectedStatus: http.StatusForbidden,
+			expectedDetail: apperror.ErrSessionRequired.Error(),
+		},
+		{
+			name:           "Role Is Not A Scope",
+			principal:      write,
+			method:         fiber.MethodGet,
+			path:           "/api/v1/admin/audit",
+			expectedStatus: http.StatusForbidden,
+			expectedDetail: apperror.ErrTokenScope.Error(),
+		},
+		{
+			name: "Admin Scope",
+			principal: model.Principal{
+				UserID: "1", Roles: []string{"admin"}, TokenID: 3, Scopes: []string{constants.ScopeRead, "admin"},
+			},
+			method: fiber.MethodGet,
+			path:   "/api/v1/admin/audit",
+			mockBehavior: func(c *gomock.Controller, services *service.Service) {
+				twoFactor := mockService.NewMockTwoFactor(c)
+				twoFactor.EXPECT().RequireForRole("admin", false).Return(nil)
+
+				audit := mockService.NewMockAuditor(c)
+				audit.EXPECT().GetEvents(gomock.Any(), gomock.Any()).Return(model.AuditEventsPage{}, nil)
+
+				services.TwoFactor, services.Audit = twoFactor, audit
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
 
-			response, _ := app.Test(request)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			tokens := mockService.NewMockPersonalAccessTokens(c)
+			tokens.EXPECT().Authenticate(gomock.Any(), token).Return(test.principal, nil)
+
+			limiter := mockService.NewMockRateLimiter(c)
+			limiter.EXPECT().Allow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(model.RateLimitResult{}, nil).AnyTimes()
+
+			services := &service.Service{Tokens: tokens, RateLimit: limiter}
+			if test.mockBehavior != nil {
+				test.mockBehavior(c, services)
+			}
+
+			handler := NewHandler(services)
+			app := handler.InitRoutesFiber(fiber.New(fiber.Config{ErrorHandler: apperror.ErrorHandler}))
+
+			request := httptest.NewRequest(test.method, test.path, nil)
+			request.Header.Set(authorizationHeader, "Bearer "+token)
+
+			response, err := app.Test(request)
+			require.NoError(t, err)
 			data, _ := io.ReadAll(response.Body)
 
-			assert.Equal(t, response.StatusCode, test.expectedStatusCode)
-			assert.Equal(t, string(data), test.expectedResponseBody)
+			assert.Equal(t, test.expectedStatus, response.StatusCode)
+
+			if test.expectedDetail != "" {
+				var problem apperror.Problem
+				require.NoError(t, json.Unmarshal(data, &problem))
+				assert.Equal(t, test.expectedDetail, problem.Detail)
+			}
 		})
 	}
 }
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const clientIPLocal = "clientIP"
+
+// ClientIP is a middleware that resolves the IP of the client behind the trusted proxies from the proxy header,
+// e.g. X-Real-IP or X-Forwarded-For. The header is only read from the trusted proxies and is walked from the
+// right: the proxies append the address they were connected from, so the right-most untrusted hop is the client
+// and anything left of it may be forged. It must precede the middlewares using clientIP.
+func ClientIP(proxyHeader string, trustedProxies []string) fiber.Handler {
+	trusted := make([]*net.IPNet, 0, len(trustedProxies))
+	for _, proxy := range trustedProxies {
+		if ip := net.ParseIP(proxy); ip != nil {
+			trusted = append(trusted, &net.IPNet{IP: ip, Mask: net.CIDRMask(8*len(ip), 8*len(ip))})
+
+			continue
+		}
+
+		// The config rejects the invalid ones
+		if _, n, err := net.ParseCIDR(proxy); err == nil {
+			trusted = append(trusted, n)
+		}
+	}
+
+	isTrusted := func(ip net.IP) bool {
+		for _, n := range trusted {
+			if n.Contains(ip) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return func(c *fiber.Ctx) error {
+		client := c.Context().RemoteIP()
+
+		if proxyHeader != "" && isTrusted(client) {
+			hops := strings.Split(c.Get(proxyHeader), ",")
+			for i := len(hops) - 1; i >= 0; i-- {
+				ip := net.ParseIP(strings.TrimSpace(hops[i]))
+				if ip == nil {
+					break
+				}
+
+				client = ip
+				if !isTrusted(ip) {
+					break
+				}
+			}
+		}
+
+		c.Locals(clientIPLocal, client.String())
+
+		return c.Next()
+	}
+}
+
+// clientIP returns the IP of the client resolved by ClientIP, the IP of the connection without it.
+func clientIP(c *fiber.Ctx) string {
+	if ip, ok := c.Locals(clientIPLocal).(string); ok {
+		return ip
+	}
+
+	return c.IP()
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"acsp/internal/logging"
+)
+
+const (
+	requestIDLocal = "requestID"
+
+	// maxRequestIDLength limits the request ids of the clients, longer ones are replaced
+	maxRequestIDLength = 128
+)
+
+// RequestLogger is a middleware that assigns a request id to every request, or keeps the X-Request-ID of
+// the client, and puts a logger with the request id into the user context, so that every log line of
+// the request can be found by the id. One access log line is written when the request is done.
+// It must be the first middleware, so that it sees the status of the errors and panics.
+func RequestLogger(base *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		requestID := c.Get(fiber.HeaderXRequestID)
+		if !validRequestID(requestID) {
+			requestID = utils.UUIDv4()
+		}
+
+		c.Set(fiber.HeaderXRequestID, requestID)
+		c.Locals(requestIDLocal, requestID)
+
+		l := base.With(
+			zap.String("requestID", requestID),
+			zap.String("method", c.Method()),
+			zap.String("path", c.Path()),
+		)
+		c.SetUserContext(logging.ContextWithLogger(c.UserContext(), l))
+
+		// The error is turned into the response here, so that the access log has its status
+		err := c.Next()
+		if err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		status := c.Response().StatusCode()
+
+		fields := []zap.Field{
+			zap.String("route", c.Route().Path),
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.String("ip", clientIP(c)),
+			zap.Int("bytes", len(c.Response().Body())),
+		}
+
+		// The details of the internal errors are only in the logs, the responses leave them out
+		if err != nil && status >= fiber.StatusInternalServerError {
+			fields = append(fields, zap.Error(err))
+		}
+
+		// The logger of the user context has the user id now, when the user was identified
+		logging.LoggerFromContext(c.UserContext()).Check(accessLogLevel(status), "Request handled").Write(fields...)
+
+		return nil
+	}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	// Only visible ASCII, the id is written to the logs and the response headers as is
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func accessLogLevel(status int) zapcore.Level {
+	switch {
+	case status >= fiber.StatusInternalServerError:
+		return zapcore.ErrorLevel
+	case status >= fiber.StatusBadRequest:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
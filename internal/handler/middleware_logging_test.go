@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"acsp/internal/logging"
+)
+
+func TestRequestLogger(t *testing.T) {
+	testTable := []struct {
+		name           string
+		requestID      string
+		keepsRequestID bool
+		path           string
+		expectedStatus int
+		expectedLevel  zapcore.Level
+	}{
+		{
+			name:           "Assigned",
+			path:           "/items/1",
+			expectedStatus: fiber.StatusOK,
+			expectedLevel:  zapcore.InfoLevel,
+		},
+		{
+			name:           "Propagated",
+			requestID:      "client-id-1",
+			keepsRequestID: true,
+			path:           "/items/1",
+			expectedStatus: fiber.StatusOK,
+			expectedLevel:  zapcore.InfoLevel,
+		},
+		{
+			name:           "Invalid",
+			requestID:      strings.Repeat("a", maxRequestIDLength+1),
+			path:           "/items/1",
+			expectedStatus: fiber.StatusOK,
+			expectedLevel:  zapcore.InfoLevel,
+		},
+		{
+			name:           "Error",
+			path:           "/failing",
+			expectedStatus: fiber.StatusInternalServerError,
+			expectedLevel:  zapcore.ErrorLevel,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+
+			app := fiber.New()
+			app.Use(RequestLogger(zap.New(core)))
+			app.Get("/items/:id", func(c *fiber.Ctx) error {
+				logging.LoggerFromContext(c.UserContext()).Info("Getting the item")
+
+				return c.SendStatus(fiber.StatusOK)
+			})
+			app.Get("/failing", func(c *fiber.Ctx) error {
+				return errors.New("failed")
+			})
+
+			req := httptest.NewRequest("GET", testCase.path, nil)
+			if testCase.requestID != "" {
+				req.Header.Set(fiber.HeaderXRequestID, testCase.requestID)
+			}
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedStatus, resp.StatusCode)
+
+			requestID := resp.Header.Get(fiber.HeaderXRequestID)
+			assert.NotEmpty(t, requestID)
+
+			if testCase.keepsRequestID {
+				assert.Equal(t, testCase.requestID, requestID)
+			} else {
+				assert.NotEqual(t, testCase.requestID, requestID)
+			}
+
+			// Every line of the request has its id
+			for _, entry := range logs.All() {
+				assert.Equal(t, requestID, entry.ContextMap()["requestID"])
+			}
+
+			accessLog := logs.FilterMessage("Request handled").All()
+			if assert.Len(t, accessLog, 1) {
+				assert.Equal(t, testCase.expectedLevel, accessLog[0].Level)
+				assert.Equal(t, int64(testCase.expectedStatus), accessLog[0].ContextMap()["status"])
+				assert.Equal(t, testCase.path, accessLog[0].ContextMap()["path"])
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+
+	"acsp/internal/metrics"
+)
+
+// MetricsApp serves the metrics at /metrics. It listens on the internal metrics port rather than the port
+// of the API, so that the routes, the traffic and the errors of the app aren't public.
+func MetricsApp() *fiber.App {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+
+	metricsHandler := fasthttpadaptor.NewFastHTTPHandler(promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{}))
+	app.Get("/metrics", func(c *fiber.Ctx) error {
+		metricsHandler(c.Context())
+
+		return nil
+	})
+
+	return app
+}
+
+// Metrics is a middleware that counts the requests and observes their latency by route template,
+// so that the ids in the paths don't make a series per request. It must follow RequestLogger.
+func Metrics() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		// The error is turned into the response here, so that the metrics have its status
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		method, route, status := c.Method(), c.Route().Path, strconv.Itoa(c.Response().StatusCode())
+
+		metrics.HTTPRequests.WithLabelValues(method, route, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(method, route, status).Observe(metrics.Since(start))
+
+		return nil
+	}
+}
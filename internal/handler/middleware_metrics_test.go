@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"acsp/internal/metrics"
+	"acsp/internal/service"
+)
+
+func TestMetrics(t *testing.T) {
+	app := fiber.New()
+	app.Use(Metrics())
+	app.Get("/metrics-test/:id", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Get("/metrics-test-failing", func(c *fiber.Ctx) error {
+		return errors.New("failed")
+	})
+
+	// The counters are global, so only their increase is checked
+	ok := metrics.HTTPRequests.WithLabelValues("GET", "/metrics-test/:id", "200")
+	failed := metrics.HTTPRequests.WithLabelValues("GET", "/metrics-test-failing", "500")
+	okBefore, failedBefore := testutil.ToFloat64(ok), testutil.ToFloat64(failed)
+
+	for _, path := range []string{"/metrics-test/1", "/metrics-test/2", "/metrics-test-failing"} {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+		assert.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+	}
+
+	// The requests are counted by route template, not by path
+	assert.Equal(t, float64(2), testutil.ToFloat64(ok)-okBefore)
+	assert.Equal(t, float64(1), testutil.ToFloat64(failed)-failedBefore)
+}
+
+func TestMetricsApp(t *testing.T) {
+	resp, err := MetricsApp().Test(httptest.NewRequest("GET", "/metrics", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	// The API doesn't serve the metrics
+	app := NewHandler(&service.Service{}).InitRoutesFiber(fiber.New())
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/metrics", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
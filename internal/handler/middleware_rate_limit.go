@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"acsp/internal/logging"
+)
+
+const (
+	headerRateLimitLimit     = "RateLimit-Limit"
+	headerRateLimitRemaining = "RateLimit-Remaining"
+	headerRateLimitReset     = "RateLimit-Reset"
+	headerRateLimitPolicy    = "RateLimit-Policy"
+)
+
+// RateLimit is a middleware that limits the requests of the clients by the policy, see constants.DefaultRateLimitPolicy.
+// The clients of the per user policies are identified by the userIdentity middleware, so they must be used after it.
+// The limits are sent in the RateLimit-* headers, the rejected requests get 429 with Retry-After.
+func (h *Handler) RateLimit(policy string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		res, err := h.services.RateLimit.Allow(c.UserContext(), policy, clientIP(c), c.GetRespHeader(userCtx, ""))
+		if err != nil {
+			// The requests are let through when redis is down, the limiter mustn't take the app down with it
+			logging.LoggerFromContext(c.UserContext()).
+				Error("Error when limiting the request rate", zap.String("policy", policy), zap.Error(err))
+
+			return c.Next()
+		}
+
+		// Unlimited
+		if res.Limit == 0 {
+			return c.Next()
+		}
+
+		reset := ceilSeconds(res.Reset)
+
+		c.Set(headerRateLimitLimit, strconv.Itoa(res.Limit))
+		c.Set(headerRateLimitRemaining, strconv.Itoa(res.Remaining))
+		c.Set(headerRateLimitReset, reset)
+		c.Set(headerRateLimitPolicy, strconv.Itoa(res.Limit)+";w="+ceilSeconds(res.Window))
+
+		if !res.Allowed {
+			c.Set(fiber.HeaderRetryAfter, reset)
+
+			return fiber.ErrTooManyRequests
+		}
+
+		return c.Next()
+	}
+}
+
+// ceilSeconds formats the duration in whole seconds, rounded up so that the clients don't retry too early
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
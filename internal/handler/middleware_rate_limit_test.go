@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"acsp/internal/apperror"
+	"acsp/internal/model"
+	"acsp/internal/service"
+)
+
+// fakeRateLimiter allows max requests per policy and client, it fails when err is set
+type fakeRateLimiter struct {
+	max    int
+	counts map[string]int
+	err    error
+}
+
+func (f *fakeRateLimiter) Allow(ctx context.Context, policy, ip, userID string) (model.RateLimitResult, error) {
+	if f.err != nil {
+		return model.RateLimitResult{}, f.err
+	}
+
+	f.counts[policy+ip+userID]++
+	count := f.counts[policy+ip+userID]
+
+	remaining := f.max - count
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return model.RateLimitResult{
+		Allowed:   count <= f.max,
+		Limit:     f.max,
+		Remaining: remaining,
+		Window:    time.Minute,
+		Reset:     1500 * time.Millisecond,
+	}, nil
+}
+
+func newRateLimitedApp(limiter service.RateLimiter, middlewares ...fiber.Handler) *fiber.App {
+	h := NewHandler(&service.Service{RateLimit: limiter})
+
+	app := fiber.New(fiber.Config{ErrorHandler: apperror.ErrorHandler})
+	for _, m := range middlewares {
+		app.Use(m)
+	}
+
+	app.Post("/sign-in", h.RateLimit("auth"), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	return app
+}
+
+func TestRateLimit(t *testing.T) {
+	app := newRateLimitedApp(&fakeRateLimiter{max: 2, counts: map[string]int{}})
+
+	for i, want := range []struct {
+		status    int
+		remaining string
+	}{
+		{fiber.StatusOK, "1"},
+		{fiber.StatusOK, "0"},
+		{fiber.StatusTooManyRequests, "0"},
+	} {
+		resp, err := app.Test(httptest.NewRequest("POST", "/sign-in", nil))
+		require.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+
+		assert.Equal(t, want.status, resp.StatusCode, i)
+		assert.Equal(t, "2", resp.Header.Get("RateLimit-Limit"))
+		assert.Equal(t, want.remaining, resp.Header.Get("RateLimit-Remaining"))
+		// The seconds are rounded up, so that the clients don't retry too early
+		assert.Equal(t, "2", resp.Header.Get("RateLimit-Reset"))
+		assert.Equal(t, "2;w=60", resp.Header.Get("RateLimit-Policy"))
+
+		if want.status == fiber.StatusTooManyRequests {
+			assert.Equal(t, "2", resp.Header.Get(fiber.HeaderRetryAfter))
+		} else {
+			assert.Empty(t, resp.Header.Get(fiber.HeaderRetryAfter))
+		}
+	}
+}
+
+func TestRateLimitFailOpen(t *testing.T) {
+	app := newRateLimitedApp(&fakeRateLimiter{err: errors.New("redis: connection refused")})
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/sign-in", nil))
+	require.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("RateLimit-Limit"))
+}
+
+func TestRateLimitBehindProxy(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		trustedProxies []string
+		// separate is true when the forwarded IPs get separate buckets
+		separate bool
+	}{
+		// app.Test connects from 0.0.0.0
+		{name: "Trusted Proxy", trustedProxies: []string{"0.0.0.0/8", "10.0.0.0/8"}, separate: true},
+		{name: "Untrusted Proxy", trustedProxies: []string{"10.0.0.0/8"}, separate: false},
+		{name: "No Trusted Proxies", separate: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			app := newRateLimitedApp(&fakeRateLimiter{max: 1, counts: map[string]int{}},
+				ClientIP(fiber.HeaderXForwardedFor, tc.trustedProxies))
+
+			signIn := func(forwardedFor string) int {
+				req := httptest.NewRequest("POST", "/sign-in", nil)
+				req.Header.Set(fiber.HeaderXForwardedFor, forwardedFor)
+
+				resp, err := app.Test(req)
+				require.NoError(t, err)
+				assert.NoError(t, resp.Body.Close())
+
+				return resp.StatusCode
+			}
+
+			assert.Equal(t, fiber.StatusOK, signIn("203.0.113.1, 10.0.0.1"))
+
+			if tc.separate {
+				assert.Equal(t, fiber.StatusOK, signIn("203.0.113.2, 10.0.0.1"))
+			} else {
+				// The header is ignored, so both clients are the proxy
+				assert.Equal(t, fiber.StatusTooManyRequests, signIn("203.0.113.2, 10.0.0.1"))
+			}
+
+			assert.Equal(t, fiber.StatusTooManyRequests, signIn("203.0.113.1, 10.0.0.1"))
+		})
+	}
+}
+
+func TestRateLimitForgedForwardedFor(t *testing.T) {
+	limiter := &fakeRateLimiter{max: 1, counts: map[string]int{}}
+	app := newRateLimitedApp(limiter, ClientIP(fiber.HeaderXForwardedFor, []string{"0.0.0.0/8", "10.0.0.1"}))
+
+	// nginx appends the address of the client to the header the client sent
+	for i, forwardedFor := range []string{
+		"203.0.113.1, 10.0.0.1",
+		"198.51.100.7, 203.0.113.1, 10.0.0.1",
+		"not-an-ip, 203.0.113.1, 10.0.0.1",
+		"10.0.0.1, 203.0.113.1, 10.0.0.1",
+	} {
+		req := httptest.NewRequest("POST", "/sign-in", nil)
+		req.Header.Set(fiber.HeaderXForwardedFor, forwardedFor)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+
+		want := fiber.StatusTooManyRequests
+		if i == 0 {
+			want = fiber.StatusOK
+		}
+
+		assert.Equal(t, want, resp.StatusCode, forwardedFor)
+	}
+
+	// The forged hops don't get buckets, the client is the right-most untrusted hop
+	assert.Equal(t, map[string]int{"auth203.0.113.1": 4}, limiter.counts)
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"acsp/internal/logging"
+	"acsp/internal/tracing"
+)
+
+// Tracing is a middleware that records a span per request, a child of the trace of the caller when
+// the request has a traceparent header. The logger of the user context gets the trace id, so it must
+// follow RequestLogger.
+func Tracing() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), requestHeaderCarrier{c: c})
+
+		ctx, span := tracing.Start(ctx, c.Method()+" "+c.Path(), trace.SpanKindServer,
+			semconv.HTTPMethod(c.Method()),
+			semconv.HTTPTarget(c.OriginalURL()),
+			semconv.HTTPScheme(c.Protocol()),
+			semconv.HTTPClientIP(clientIP(c)),
+			semconv.HTTPUserAgent(c.Get(fiber.HeaderUserAgent)),
+		)
+		defer span.End()
+
+		if requestID, ok := c.Locals(requestIDLocal).(string); ok {
+			span.SetAttributes(requestIDAttribute.String(requestID))
+		}
+
+		l := logging.LoggerFromContext(ctx).With(tracing.LogFields(ctx)...)
+		c.SetUserContext(logging.ContextWithLogger(ctx, l))
+
+		// The error is turned into the response here, so that the span has its status
+		err := c.Next()
+		if err != nil {
+			span.RecordError(err)
+
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		status := c.Response().StatusCode()
+
+		// The route template is known once the request is routed
+		span.SetName(c.Method() + " " + c.Route().Path)
+		span.SetAttributes(semconv.HTTPRoute(c.Route().Path), semconv.HTTPStatusCode(status))
+
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, utils.StatusMessage(status))
+		}
+
+		return nil
+	}
+}
+
+// requestIDAttribute is the id assigned to the request by RequestLogger
+const requestIDAttribute = attribute.Key("http.request_id")
+
+// requestHeaderCarrier reads the trace context of the request headers
+type requestHeaderCarrier struct {
+	c *fiber.Ctx
+}
+
+func (r requestHeaderCarrier) Get(key string) string {
+	return r.c.Get(key)
+}
+
+// Set isn't used, the trace context is only extracted from the requests
+func (r requestHeaderCarrier) Set(key, value string) {}
+
+func (r requestHeaderCarrier) Keys() []string {
+	keys := make([]string, 0)
+
+	r.c.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+
+	return keys
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"acsp/internal/logging"
+)
+
+func TestTracing(t *testing.T) {
+	// The provider and the propagator are global, the other tests get them back
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	})
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	app := fiber.New()
+	app.Use(RequestLogger(zap.New(core)))
+	app.Use(Tracing())
+	app.Get("/items/:id", func(c *fiber.Ctx) error {
+		logging.LoggerFromContext(c.UserContext()).Info("Getting the item")
+
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	req := httptest.NewRequest("GET", "/items/1", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 1) {
+		// The span continues the trace of the caller and is named by the route template
+		assert.Equal(t, traceID, spans[0].SpanContext().TraceID().String())
+		assert.Equal(t, "GET /items/:id", spans[0].Name())
+	}
+
+	// Every line of the request has the trace id
+	for _, entry := range logs.All() {
+		assert.Equal(t, traceID, entry.ContextMap()["traceID"])
+	}
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// @Summary Get notifications
+// @Security ApiKeyAuth
+// @Tags users
+// @Description Get the latest notifications of the user, e.g. the invitations to the cards of the user
+// @ID get-notifications
+// @Produce  json
+// @Success 200 {array} model.Notification
+// @Failure 401 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/users/notifications [get]
+func (h *Handler) getNotifications(c *fiber.Ctx) error {
+	userID, err := getUserId(c)
+	if err != nil {
+		return err
+	}
+
+	notifications, err := h.services.Notifications.GetByUserID(c.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(notifications)
+}
+
+// @Summary Mark a notification as read
+// @Security ApiKeyAuth
+// @Tags users
+// @Description Mark a notification of the user as read
+// @ID read-notification
+// @Produce  json
+// @Param id path int true "notification id"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/users/notifications/{id}/read [post]
+func (h *Handler) readNotification(c *fiber.Ctx) error {
+	userID, err := getUserId(c)
+	if err != nil {
+		return err
+	}
+
+	err = h.services.Notifications.MarkRead(c.UserContext(), userID, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Notification read",
+	})
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"acsp/internal/apperror"
+	"acsp/internal/dto"
+	"acsp/internal/logging"
+)
+
+// @Summary Get personal access tokens
+// @Security ApiKeyAuth
+// @Tags users
+// @Description Get the personal access tokens of the user, the tokens themselves aren't stored
+// @ID get-personal-access-tokens
+// @Produce  json
+// @Success 200 {array} model.PersonalAccessToken
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem "personal access tokens may not manage tokens"
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/users/tokens [get]
+func (h *Handler) getPersonalAccessTokens(c *fiber.Ctx) error {
+	userID, err := getUserId(c)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := h.services.Tokens.GetAll(c.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(tokens)
+}
+
+// @Summary Create a personal access token
+// @Security ApiKeyAuth
+// @Tags users
+// @Description Create a personal access token for the scripts, it is sent as a bearer token. The scopes are read, write and the roles of the user, the token is returned once
+// @ID create-personal-access-token
+// @Accept  json
+// @Produce  json
+// @Param input body dto.CreatePersonalAccessToken true "name, scopes and lifetime of the token"
+// @Success 201 {object} model.NewPersonalAccessToken
+// @Failure 400,401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem "personal access tokens may not manage tokens"
+// @Failure 409 {object} apperror.Problem "too many tokens"
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/users/tokens [post]
+func (h *Handler) createPersonalAccessToken(c *fiber.Ctx) error {
+	l := logging.LoggerFromContext(c.UserContext())
+	l.Info("Creating a personal access token...")
+
+	userID, err := getUserId(c)
+	if err != nil {
+		return err
+	}
+
+	var input dto.CreatePersonalAccessToken
+	if err := c.BodyParser(&input); err != nil {
+		return apperror.ErrBodyParsed
+	}
+
+	if err := validate(c, &input); err != nil {
+		return err
+	}
+
+	token, err := h.services.Tokens.Create(c.UserContext(), userID, input)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusCreated).JSON(token)
+}
+
+// @Summary Revoke a personal access token
+// @Security ApiKeyAuth
+// @Tags users
+// @Description Revoke a personal access token of the user, the requests with it are refused from now on
+// @ID revoke-personal-access-token
+// @Produce  json
+// @Param id path int true "token id"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem "personal access tokens may not manage tokens"
+// @Failure 404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/users/tokens/{id} [delete]
+func (h *Handler) revokePersonalAccessToken(c *fiber.Ctx) error {
+	l := logging.LoggerFromContext(c.UserContext())
+	l.Info("Revoking a personal access token...")
+
+	userID, err := getUserId(c)
+	if err != nil {
+		return err
+	}
+
+	err = h.services.Tokens.Revoke(c.UserContext(), userID, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Token revoked",
+	})
+}
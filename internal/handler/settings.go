@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+
+	"acsp/internal/apperror"
+	"acsp/internal/dto"
+	"acsp/internal/logging"
+	"acsp/internal/model"
+)
+
+// CORS returns a CORS middleware whose allowed origins follow the runtime settings,
+// the other fields of the config are kept.
+func (h *Handler) CORS(cfg cors.Config) fiber.Handler {
+	var current atomic.Value
+
+	h.services.Settings.OnChange(func(s model.RuntimeSettings) {
+		c := cfg
+		c.AllowOrigins = strings.Join(s.CORSOrigins, ",")
+
+		current.Store(cors.New(c))
+	})
+
+	return func(c *fiber.Ctx) error {
+		return current.Load().(fiber.Handler)(c)
+	}
+}
+
+// @Summary Get the runtime settings
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description Get the settings which are changed without a restart
+// @ID get-settings
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/admin/settings [get]
+func (h *Handler) getSettings(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"settings": h.services.Settings.Current(),
+	})
+}
+
+// @Summary Change the runtime settings
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description Change the settings without a restart, omitted fields are kept and the change is recorded
+// @ID patch-settings
+// @Accept  json
+// @Produce  json
+// @Param request body dto.PatchSettings true "settings"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/admin/settings [patch]
+func (h *Handler) patchSettings(c *fiber.Ctx) error {
+	l := logging.LoggerFromContext(c.UserContext())
+	l.Info("Changing the settings...")
+
+	userID, err := getUserId(c)
+	if err != nil {
+		return err
+	}
+
+	var input dto.PatchSettings
+	if err := c.BodyParser(&input); err != nil {
+		return apperror.ErrBodyParsed
+	}
+
+	if err := validate(c, &input); err != nil {
+		return err
+	}
+
+	settings, err := h.services.Settings.Patch(c.UserContext(), userID, input)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"settings": settings,
+	})
+}
+
+// @Summary Get the changes of the runtime settings
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description Get the latest changes of the settings, the newest first
+// @ID get-settings-changes
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/admin/settings/changes [get]
+func (h *Handler) getSettingsChanges(c *fiber.Ctx) error {
+	changes, err := h.services.Settings.GetChanges(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"count":   len(changes),
+		"changes": changes,
+	})
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"acsp/internal/apperror"
+	"acsp/internal/logging"
+)
+
+// oidcStateCookie binds the state of a sign-in to the browser that started it, a callback of another
+// browser is refused, so nobody can sign a user in to their own account (login CSRF).
+const oidcStateCookie = "oidc_state"
+
+// oidcCookiePath limits the cookie to the routes of the single sign-on.
+const oidcCookiePath = "/api/v1/auth/oidc"
+
+// @Summary Single sign-on
+// @Tags auth
+// @Description redirecting the user to the sign-in page of the university identity provider, the state of the sign-in is kept in a cookie
+// @ID oidc-login
+// @Success 302 "redirect to the identity provider"
+// @Failure 404 {object} apperror.Problem "single sign-on is not enabled"
+// @Failure 429 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/auth/oidc/login [get]
+func (h *Handler) oidcLogin(ctx *fiber.Ctx) error {
+	url, state, err := h.services.SSO.AuthURL(ctx.UserContext())
+	if err != nil {
+		return err
+	}
+
+	// Lax, the provider redirects back with a top-level GET
+	ctx.Cookie(&fiber.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     oidcCookiePath,
+		Secure:   true,
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+
+	return ctx.Redirect(url, http.StatusFound)
+}
+
+// @Summary Single sign-on callback
+// @Tags auth
+// @Description completing the sign-in at the university identity provider, the user is linked by the email or created on the first sign-in and redirected to the frontend with a one-time code exchanged at /api/v1/auth/oidc/token
+// @ID oidc-callback
+// @Param code query string true "authorization code"
+// @Param state query string true "state of the sign-in"
+// @Success 302 "redirect to the frontend with the one-time code"
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem "the sign-in is expired, was started in another browser or was refused by the identity provider"
+// @Failure 403 {object} apperror.Problem "the email is not verified or its domain is not allowed"
+// @Failure 404 {object} apperror.Problem "single sign-on is not enabled"
+// @Failure 429 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/auth/oidc/callback [get]
+func (h *Handler) oidcCallback(ctx *fiber.Ctx) error {
+	// The cookie is used once, whatever the outcome
+	cookie := ctx.Cookies(oidcStateCookie)
+	ctx.Cookie(&fiber.Cookie{
+		Name:     oidcStateCookie,
+		Path:     oidcCookiePath,
+		Expires:  time.Unix(0, 0),
+		Secure:   true,
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+
+	// The provider redirects with an error when the user cancels the sign-in or it is refused. The values are
+	// only logged, anyone can put their text in the URL of the callback
+	if e := ctx.Query("error"); e != "" {
+		logging.LoggerFromContext(ctx.UserContext()).Info("The identity provider refused the sign-in",
+			zap.String("error", e), zap.String("description", ctx.Query("error_description")))
+
+		return apperror.ErrSSORefused
+	}
+
+	code, state := ctx.Query("code"), ctx.Query("state")
+	if code == "" || state == "" {
+		return apperror.New(apperror.KindValidation, "Code and state are required")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(cookie), []byte(state)) != 1 {
+		return apperror.ErrSSOStateMismatch
+	}
+
+	url, err := h.services.SSO.Callback(ctx.UserContext(), code, state)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Redirect(url, http.StatusFound)
+}
+
+type oidcTokenInput struct {
+	// Code is the one-time code the callback redirected to the frontend with
+	Code string `json:"code" validate:"required"`
+}
+
+// @Summary Single sign-on token
+// @Tags auth
+// @Description exchanging the one-time code of a single sign-on for the token pair, a code is exchanged once
+// @ID oidc-token
+// @Accept  json
+// @Produce  json
+// @Param input body oidcTokenInput true "one-time code"
+// @Success 200 {string} service.TokenDetails "token pair, or model.MFAChallenge when two-factor authentication is enabled"
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem "the code is expired or was already used"
+// @Failure 404 {object} apperror.Problem "single sign-on is not enabled"
+// @Failure 429 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/auth/oidc/token [post]
+func (h *Handler) oidcToken(ctx *fiber.Ctx) error {
+	var input oidcTokenInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return apperror.ErrBodyParsed
+	}
+
+	if err := validate(ctx, &input); err != nil {
+		return err
+	}
+
+	user, err := h.services.SSO.Exchange(ctx.UserContext(), input.Code)
+	if err != nil {
+		return err
+	}
+
+	return h.signInUser(ctx, user)
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"acsp/internal/apperror"
+	"acsp/internal/model"
+	"acsp/internal/service"
+	mockService "acsp/internal/service/mocks"
+)
+
+func newSSOApp(sso service.SingleSignOn) *fiber.App {
+	handler := NewHandler(&service.Service{SSO: sso})
+
+	app := fiber.New(fiber.Config{ErrorHandler: apperror.ErrorHandler})
+	app.Get("/api/v1/auth/oidc/login", handler.oidcLogin)
+	app.Get("/api/v1/auth/oidc/callback", handler.oidcCallback)
+	app.Post("/api/v1/auth/oidc/token", handler.oidcToken)
+
+	return app
+}
+
+func TestHandler_oidcLogin(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	sso := mockService.NewMockSingleSignOn(c)
+	sso.EXPECT().AuthURL(gomock.Any()).Return("https://idp.example.com/auth?state=s1", "s1", nil)
+
+	response, err := newSSOApp(sso).Test(httptest.NewRequest("GET", "/api/v1/auth/oidc/login", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 302, response.StatusCode)
+	assert.Equal(t, "https://idp.example.com/auth?state=s1", response.Header.Get(fiber.HeaderLocation))
+
+	cookies := response.Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, oidcStateCookie, cookies[0].Name)
+	assert.Equal(t, "s1", cookies[0].Value)
+	assert.Equal(t, oidcCookiePath, cookies[0].Path)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.True(t, cookies[0].Secure)
+}
+
+func TestHandler_oidcCallback(t *testing.T) {
+	tests := []struct {
+		name               string
+		cookie             string
+		callback           bool
+		expectedStatusCode int
+		expectedLocation   string
+	}{
+		{
+			name:               "Ok",
+			cookie:             "s1",
+			callback:           true,
+			expectedStatusCode: 302,
+			expectedLocation:   "https://acsp.example.com/sso?code=c1",
+		},
+		// The sign-in was started in another browser, e.g. by an attacker signing the user in to their account
+		{name: "Other Browser", cookie: "s2", expectedStatusCode: 401},
+		{name: "No Cookie", expectedStatusCode: 401},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			sso := mockService.NewMockSingleSignOn(c)
+			if testCase.callback {
+				sso.EXPECT().Callback(gomock.Any(), "code", "s1").Return(testCase.expectedLocation, nil)
+			}
+
+			request := httptest.NewRequest("GET", "/api/v1/auth/oidc/callback?code=code&state=s1", nil)
+			if testCase.cookie != "" {
+				request.Header.Add(fiber.HeaderCookie, oidcStateCookie+"="+testCase.cookie)
+			}
+
+			response, err := newSSOApp(sso).Test(request)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, testCase.expectedLocation, response.Header.Get(fiber.HeaderLocation))
+
+			// The cookie is cleared whatever the outcome
+			cookies := response.Cookies()
+			require.Len(t, cookies, 1)
+			assert.Equal(t, oidcStateCookie, cookies[0].Name)
+			assert.Empty(t, cookies[0].Value)
+		})
+	}
+}
+
+func TestHandler_oidcCallbackRefused(t *testing.T) {
+	request := httptest.NewRequest("GET",
+		"/api/v1/auth/oidc/callback?error=access_denied&error_description=Call+us+at+555-0100", nil)
+	request.Header.Add(fiber.HeaderCookie, oidcStateCookie+"=s1")
+
+	response, err := newSSOApp(nil).Test(request)
+	require.NoError(t, err)
+	assert.Equal(t, 401, response.StatusCode)
+
+	// The text of the URL isn't reflected in the response
+	data, _ := io.ReadAll(response.Body)
+	assert.Contains(t, string(data), apperror.ErrSSORefused.Error())
+	assert.NotContains(t, string(data), "access_denied")
+	assert.NotContains(t, string(data), "555-0100")
+}
+
+func TestHandler_oidcToken(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	sso := mockService.NewMockSingleSignOn(c)
+	sso.EXPECT().Exchange(gomock.Any(), "c1").Return(model.User{}, apperror.ErrSSOCodeNotFound)
+
+	request := httptest.NewRequest("POST", "/api/v1/auth/oidc/token", bytes.NewReader([]byte(`{"code":"c1"}`)))
+	request.Header.Add("Content-Type", "application/json")
+
+	response, err := newSSOApp(sso).Test(request)
+	require.NoError(t, err)
+	assert.Equal(t, 401, response.StatusCode)
+
+	request = httptest.NewRequest("POST", "/api/v1/auth/oidc/token", bytes.NewReader([]byte(`{}`)))
+	request.Header.Add("Content-Type", "application/json")
+
+	response, err = newSSOApp(sso).Test(request)
+	require.NoError(t, err)
+	assert.Equal(t, 400, response.StatusCode)
+}
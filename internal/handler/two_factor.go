@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"acsp/internal/apperror"
+	"acsp/internal/logging"
+)
+
+type twoFactorCodeInput struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// @Summary Enroll two-factor authentication
+// @Security ApiKeyAuth
+// @Tags auth
+// @Description Generate a TOTP secret of the user, it is added to an authenticator app from the provisioning URI and enabled with a code
+// @ID enroll-2fa
+// @Produce  json
+// @Success 200 {object} model.TOTPEnrollment
+// @Failure 401 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem "two-factor authentication is already enabled"
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/auth/2fa/enroll [post]
+func (h *Handler) enrollTwoFactor(c *fiber.Ctx) error {
+	userID, err := getUserId(c)
+	if err != nil {
+		return err
+	}
+
+	enrollment, err := h.services.TwoFactor.Enroll(c.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(enrollment)
+}
+
+// @Summary Enable two-factor authentication
+// @Security ApiKeyAuth
+// @Tags auth
+// @Description Enable the enrolled TOTP secret with a code of the authenticator app, the recovery codes are returned once
+// @ID enable-2fa
+// @Accept  json
+// @Produce  json
+// @Param input body twoFactorCodeInput true "code of the authenticator app"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,401 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem "two-factor authentication is not enrolled or already enabled"
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/auth/2fa/enable [post]
+func (h *Handler) enableTwoFactor(c *fiber.Ctx) error {
+	l := logging.LoggerFromContext(c.UserContext())
+	l.Info("enabling two-factor authentication...")
+
+	userID, err := getUserId(c)
+	if err != nil {
+		return err
+	}
+
+	var input twoFactorCodeInput
+	if err := c.BodyParser(&input); err != nil {
+		return apperror.ErrBodyParsed
+	}
+
+	if err := validate(c, &input); err != nil {
+		return err
+	}
+
+	codes, err := h.services.TwoFactor.Enable(c.UserContext(), userID, input.Code)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":        "Two-factor authentication enabled",
+		"recovery_codes": codes,
+	})
+}
+
+// @Summary Disable two-factor authentication
+// @Security ApiKeyAuth
+// @Tags auth
+// @Description Disable two-factor authentication with a code of the authenticator app or a recovery code
+// @ID disable-2fa
+// @Accept  json
+// @Produce  json
+// @Param input body twoFactorCodeInput true "code of the authenticator app or a recovery code"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem "two-factor authentication is required for a role of the user"
+// @Failure 409 {object} apperror.Problem "two-factor authentication is not enabled"
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/auth/2fa/disable [post]
+func (h *Handler) disableTwoFactor(c *fiber.Ctx) error {
+	l := logging.LoggerFromContext(c.UserContext())
+	l.Info("disabling two-factor authentication...")
+
+	userID, err := getUserId(c)
+	if err != nil {
+		return err
+	}
+
+	var input twoFactorCodeInput
+	if err := c.BodyParser(&input); err != nil {
+		return apperror.ErrBodyParsed
+	}
+
+	if err := validate(c, &input); err != nil {
+		return err
+	}
+
+	err = h.services.TwoFactor.Disable(c.UserContext(), userID, input.Code)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+// @Summary Regenerate the recovery codes
+// @Security ApiKeyAuth
+// @Tags auth
+// @Description Replace the recovery codes of the user after checking a code of the authenticator app
+// @ID regenerate-recovery-codes
+// @Accept  json
+// @Produce  json
+// @Param input body twoFactorCodeInput true "code of the authenticator app"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,401 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem "two-factor authentication is not enabled"
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
+// @Router /api/v1/auth/2fa/recovery-codes [post]
+func (h *Handler) regenerateRecoveryCodes(c *fiber.Ctx) error {
+	userID, err := getUserId(c)
+	if err != nil {
+		return err
+	}
+
+	var input twoFactorCodeInput
+	if err := c.BodyParser(&input); err != nil {
+		return apperror.ErrBodyParsed
+	}
+
+	if err := validate(c, &input); err != nil {
+		return err
+	}
+
+	codes, err := h.services.TwoFactor.RegenerateRecoveryCodes(c.UserContext(), userID, input.Code)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"recovery_codes": codes,
+	})
+}
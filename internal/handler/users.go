@@ -1,10 +1,9 @@
 package handler
 
 import (
-	"net/http"
-
 	"github.com/gofiber/fiber/v2"
 
+	"acsp/internal/apperror"
 	"acsp/internal/dto"
 )
 
@@ -16,35 +15,24 @@ import (
 // @Accept  json
 // @Produce  json
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/users/profile [get]
 func (h *Handler) getUserProfile(c *fiber.Ctx) error {
 	userId, err := getUserId(c)
 	if userId == "" {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": "user id is incorrect",
-		})
+		return apperror.New(apperror.KindValidation, "user id is incorrect")
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	user, err := h.services.Authorization.GetUserByID(c.UserContext(), userId)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":  false,
-		"message": nil,
 		"user_id": c.GetRespHeader(userCtx, ""),
 		"user":    user,
 	})
@@ -59,45 +47,27 @@ func (h *Handler) getUserProfile(c *fiber.Ctx) error {
 // @Produce  json
 // @Param file formData file true "user image"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/users/image [post]
 func (h *Handler) uploadUserImage(c *fiber.Ctx) error {
 	userID, err := getUserId(c)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	file, err := c.FormFile("file")
 	if err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
-	}
-
-	err = h.services.S3BucketService.UploadFile(c.UserContext(), userID, file)
-	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return apperror.WithKind(err, apperror.KindValidation)
 	}
 
-	err = h.services.Users.UpdateUserImageURL(c.UserContext(), userID)
+	err = h.services.Users.UploadUserImage(c.UserContext(), userID, file)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":  false,
 		"message": "Successfully uploaded image",
 	})
 }
@@ -111,42 +81,33 @@ func (h *Handler) uploadUserImage(c *fiber.Ctx) error {
 // @Produce  json
 // @Param input body dto.UpdateUser true "user information"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400,404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Failure default {object} map[string]interface{}
+// @Failure 400,404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Failure default {object} apperror.Problem
 // @Router /api/v1/users/profile [put]
 func (h *Handler) updateUserProfile(c *fiber.Ctx) error {
 	userId, err := getUserId(c)
 	if userId == "" {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": "user id is incorrect",
-		})
+		return apperror.New(apperror.KindValidation, "user id is incorrect")
 	} else if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	var input dto.UpdateUser
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"errors":  true,
-			"message": "invalid input body",
-		})
+		return apperror.New(apperror.KindValidation, "invalid input body")
+	}
+
+	if err := validate(c, &input); err != nil {
+		return err
 	}
 
 	err = h.services.Users.UpdateUser(c.UserContext(), userId, input)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"errors":  true,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"errors":  false,
 		"message": "Successfully updated user information",
 	})
 }
@@ -0,0 +1,13 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"acsp/internal/validation"
+)
+
+// validate validates the input of the request, the messages of the invalid fields are in the language
+// of its Accept-Language
+func validate(c *fiber.Ctx, input interface{}) error {
+	return validation.Struct(input, validation.Language(c.Get(fiber.HeaderAcceptLanguage)))
+}
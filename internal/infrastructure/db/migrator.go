@@ -0,0 +1,441 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"acsp/internal/constants"
+	"acsp/internal/logging"
+)
+
+// migrationsLockID is the key of the advisory lock preventing concurrent migrations
+const migrationsLockID = 7_236_001
+
+var migrationFileRegexp = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration is a versioned schema change read from a pair of NNNNNN_name.up.sql and NNNNNN_name.down.sql files.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus is a migration together with the time it was applied at, nil if it is pending.
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Migrator applies migrations and records the applied versions in the schema_migrations table.
+type Migrator struct {
+	db         *sqlx.DB
+	migrations []Migration
+}
+
+// NewMigrator creates a Migrator of the migrations found in fsys.
+func NewMigrator(db *sqlx.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// LoadMigrations reads the migrations of the root of fsys ordered by version.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, errors.Wrap(err, "error when reading migrations")
+	}
+
+	byVersion := map[int64]*Migration{}
+
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".sql" {
+			continue
+		}
+
+		match := migrationFileRegexp.FindStringSubmatch(e.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", e.Name())
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid migration version: %s", e.Name())
+		}
+
+		body, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, errors.Wrapf(err, "error when reading migration %s", e.Name())
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migrations %s and %s have the same version", m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if strings.TrimSpace(m.Up) == "" {
+			return nil, fmt.Errorf("migration %06d_%s has no up script", m.Version, m.Name)
+		}
+
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Up applies all pending migrations in the order of their versions and returns the applied ones.
+// Every migration runs in its own transaction. The schema created before the migrations is baselined
+// at the first migration, see Baseline.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+
+	err := m.withLock(ctx, func(conn *sqlx.Conn, applied map[int64]time.Time) error {
+		if len(applied) == 0 && len(m.migrations) > 0 {
+			exists, err := tableExists(ctx, conn, constants.UsersTable)
+			if err != nil {
+				return err
+			}
+
+			// The tables of the first migration were created by hand, so it is recorded without running it
+			if exists {
+				first := m.migrations[0]
+
+				logging.LoggerFromContext(ctx).Warn("The database has a schema created before the migrations, "+
+					"the first migration is recorded as applied", zap.Int64("version", first.Version),
+					zap.String("name", first.Name))
+
+				err = m.record(ctx, conn, first, applied)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, mg := range m.migrations {
+			if _, ok := applied[mg.Version]; ok {
+				continue
+			}
+
+			err := m.apply(ctx, conn, mg, mg.Up,
+				fmt.Sprintf(`INSERT INTO %s (version, name) VALUES ($1, $2)`, constants.SchemaMigrationsTable),
+				mg.Version, mg.Name)
+			if err != nil {
+				return err
+			}
+
+			done = append(done, mg)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Down reverts the given number of the latest applied migrations and returns the reverted ones.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+
+	err := m.withLock(ctx, func(conn *sqlx.Conn, applied map[int64]time.Time) error {
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			mg := m.migrations[i]
+			if _, ok := applied[mg.Version]; !ok {
+				continue
+			}
+
+			if strings.TrimSpace(mg.Down) == "" {
+				return fmt.Errorf("migration %06d_%s has no down script", mg.Version, mg.Name)
+			}
+
+			err := m.apply(ctx, conn, mg, mg.Down,
+				fmt.Sprintf(`DELETE FROM %s WHERE version = $1`, constants.SchemaMigrationsTable),
+				mg.Version)
+			if err != nil {
+				return err
+			}
+
+			done = append(done, mg)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Baseline records the migrations up to the version as applied without running them and returns the recorded
+// ones. It upgrades a database whose schema was created or changed by hand before the migrations.
+func (m *Migrator) Baseline(ctx context.Context, version int64) ([]Migration, error) {
+	var done []Migration
+
+	err := m.withLock(ctx, func(conn *sqlx.Conn, applied map[int64]time.Time) error {
+		found := false
+		for _, mg := range m.migrations {
+			if mg.Version > version {
+				break
+			}
+
+			found = found || mg.Version == version
+		}
+
+		if !found {
+			return fmt.Errorf("migration %06d not found", version)
+		}
+
+		for _, mg := range m.migrations {
+			if mg.Version > version {
+				break
+			}
+
+			if _, ok := applied[mg.Version]; ok {
+				continue
+			}
+
+			err := m.record(ctx, conn, mg, applied)
+			if err != nil {
+				return err
+			}
+
+			done = append(done, mg)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Status returns every known migration with the time it was applied at.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+
+	err := m.withLock(ctx, func(conn *sqlx.Conn, applied map[int64]time.Time) error {
+		for _, mg := range m.migrations {
+			s := MigrationStatus{Migration: mg}
+			if at, ok := applied[mg.Version]; ok {
+				at := at
+				s.AppliedAt = &at
+			}
+
+			statuses = append(statuses, s)
+		}
+
+		return nil
+	})
+
+	return statuses, err
+}
+
+// apply runs a migration script and updates the schema_migrations table in one transaction.
+func (m *Migrator) apply(
+	ctx context.Context, conn *sqlx.Conn, mg Migration, script, record string, args ...interface{}) error {
+	l := logging.LoggerFromContext(ctx).With(zap.Int64("version", mg.Version), zap.String("name", mg.Name))
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "error when beginning the transaction")
+	}
+
+	_, err = tx.ExecContext(ctx, script)
+	if err == nil {
+		_, err = tx.ExecContext(ctx, record, args...)
+	}
+
+	if err != nil {
+		l.Error("Error when applying migration", zap.Error(err))
+
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			l.Error("Error when rolling back migration", zap.Error(rollbackErr))
+		}
+
+		return errors.Wrapf(err, "migration %06d_%s failed", mg.Version, mg.Name)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrapf(err, "error when committing migration %06d_%s", mg.Version, mg.Name)
+	}
+
+	l.Info("Migration applied")
+
+	return nil
+}
+
+// record records the migration as applied without running its script.
+func (m *Migrator) record(ctx context.Context, conn *sqlx.Conn, mg Migration, applied map[int64]time.Time) error {
+	var appliedAt time.Time
+
+	err := conn.QueryRowxContext(ctx,
+		fmt.Sprintf(`INSERT INTO %s (version, name) VALUES ($1, $2) RETURNING applied_at`,
+			constants.SchemaMigrationsTable), mg.Version, mg.Name).Scan(&appliedAt)
+	if err != nil {
+		return errors.Wrapf(err, "error when recording migration %06d_%s", mg.Version, mg.Name)
+	}
+
+	applied[mg.Version] = appliedAt
+
+	logging.LoggerFromContext(ctx).Info("Migration recorded as applied",
+		zap.Int64("version", mg.Version), zap.String("name", mg.Name))
+
+	return nil
+}
+
+// tableExists reports whether the table exists in the search path.
+func tableExists(ctx context.Context, conn *sqlx.Conn, table string) (bool, error) {
+	var exists bool
+
+	err := conn.QueryRowxContext(ctx, `SELECT to_regclass($1) IS NOT NULL`, table).Scan(&exists)
+	if err != nil {
+		return false, errors.Wrapf(err, "error when checking the table %s", table)
+	}
+
+	return exists, nil
+}
+
+// withLock runs fn on a single connection holding the migrations advisory lock,
+// so that several instances starting at once don't apply the same migration twice.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sqlx.Conn, applied map[int64]time.Time) error) error {
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return errors.Wrap(err, "error when getting a connection")
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationsLockID)
+	if err != nil {
+		return errors.Wrap(err, "error when acquiring the migrations lock")
+	}
+
+	defer func() {
+		// The lock is released with the session anyway, so a failed unlock only delays other instances
+		_, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationsLockID)
+		if err != nil {
+			logging.LoggerFromContext(ctx).Error("Error when releasing the migrations lock", zap.Error(err))
+		}
+	}()
+
+	_, err = conn.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s
+		(
+			version    BIGINT      NOT NULL PRIMARY KEY,
+			name       VARCHAR     NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT (now())
+		)`, constants.SchemaMigrationsTable))
+	if err != nil {
+		return errors.Wrap(err, "error when creating the migrations table")
+	}
+
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf(`SELECT version, applied_at FROM %s`,
+		constants.SchemaMigrationsTable))
+	if err != nil {
+		return errors.Wrap(err, "error when getting applied migrations")
+	}
+	defer func(rows *sql.Rows) {
+		_ = rows.Close()
+	}(rows)
+
+	applied := map[int64]time.Time{}
+	for rows.Next() {
+		var (
+			version   int64
+			appliedAt time.Time
+		)
+
+		err := rows.Scan(&version, &appliedAt)
+		if err != nil {
+			return errors.Wrap(err, "error when scanning applied migration")
+		}
+
+		applied[version] = appliedAt
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return errors.Wrap(err, "error when getting applied migrations")
+	}
+
+	return fn(conn, applied)
+}
+
+// CreateMigration writes the templates of the up and down scripts of a new migration with the next version into dir
+// and returns their paths.
+func CreateMigration(dir, name string) (string, string, error) {
+	name = strings.ToLower(strings.Join(strings.Fields(name), "_"))
+	if !migrationFileRegexp.MatchString("1_" + name + ".up.sql") {
+		return "", "", fmt.Errorf("invalid migration name: %q", name)
+	}
+
+	migrations, err := LoadMigrations(os.DirFS(dir))
+	if err != nil {
+		return "", "", err
+	}
+
+	var version int64 = 1
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
+	}
+
+	base := filepath.Join(dir, fmt.Sprintf("%06d_%s", version, name))
+	up, down := base+".up.sql", base+".down.sql"
+
+	files := map[string]string{
+		up:   "-- Write the schema change here\n",
+		down: "-- Revert the schema change here\n",
+	}
+
+	for path, body := range files {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return "", "", errors.Wrap(err, "error when creating migration file")
+		}
+
+		_, err = f.WriteString(body)
+		if err != nil {
+			f.Close()
+
+			return "", "", errors.Wrap(err, "error when writing migration file")
+		}
+
+		err = f.Close()
+		if err != nil {
+			return "", "", errors.Wrap(err, "error when closing migration file")
+		}
+	}
+
+	return up, down, nil
+}
@@ -0,0 +1,226 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"acsp/internal/constants"
+	"acsp/internal/repository"
+	"acsp/migrations"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		want    []Migration
+		wantErr bool
+	}{
+		{
+			name: "Ordered by version",
+			fsys: fstest.MapFS{
+				"000002_second.up.sql":   {Data: []byte("up 2")},
+				"000002_second.down.sql": {Data: []byte("down 2")},
+				"000001_first.up.sql":    {Data: []byte("up 1")},
+				"migrations.go":          {Data: []byte("package migrations")},
+			},
+			want: []Migration{
+				{Version: 1, Name: "first", Up: "up 1"},
+				{Version: 2, Name: "second", Up: "up 2", Down: "down 2"},
+			},
+		},
+		{
+			name: "Duplicate version",
+			fsys: fstest.MapFS{
+				"000001_first.up.sql":  {Data: []byte("up")},
+				"000001_second.up.sql": {Data: []byte("up")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Missing up script",
+			fsys: fstest.MapFS{
+				"000001_first.down.sql": {Data: []byte("down")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid file name",
+			fsys: fstest.MapFS{
+				"init.sql": {Data: []byte("up")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := LoadMigrations(testCase.fsys)
+			if testCase.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
+
+func TestCreateMigration(t *testing.T) {
+	dir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, "000003_outbox_events.up.sql"), []byte("up"), 0o644)
+	assert.NoError(t, err)
+
+	up, down, err := CreateMigration(dir, "Add Users Index")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "000004_add_users_index.up.sql"), up)
+	assert.Equal(t, filepath.Join(dir, "000004_add_users_index.down.sql"), down)
+
+	_, _, err = CreateMigration(dir, "bad-name")
+	assert.Error(t, err)
+}
+
+// testDB connects to the database of TEST_DATABASE_URL (postgres://...) with a new empty schema, the schema is
+// dropped after the test. The test is skipped without the database.
+func testDB(t *testing.T) *sqlx.DB {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	admin, err := sqlx.Connect(constants.DatabaseName, dsn)
+	require.NoError(t, err)
+
+	schema := fmt.Sprintf("migrator_test_%d", time.Now().UnixNano())
+	_, err = admin.Exec(`CREATE SCHEMA ` + schema)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_, err := admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`)
+		assert.NoError(t, err)
+		assert.NoError(t, admin.Close())
+	})
+
+	// The tables are created in the schema, it is the only one of the search path
+	u, err := url.Parse(dsn)
+	require.NoError(t, err)
+
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+
+	db, err := sqlx.Connect(constants.DatabaseName, u.String())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, db.Close())
+	})
+
+	return db
+}
+
+func TestMigrator_UpDown(t *testing.T) {
+	ctx := context.Background()
+	db := testDB(t)
+
+	migrator, err := NewMigrator(db, migrations.FS)
+	require.NoError(t, err)
+
+	all, err := LoadMigrations(migrations.FS)
+	require.NoError(t, err)
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, all, applied)
+	assert.NoError(t, CheckSchema(ctx, db, repository.Schema))
+
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	// Every down script reverts its up script, so the migrations are applied again
+	reverted, err := migrator.Down(ctx, len(all))
+	require.NoError(t, err)
+	assert.Len(t, reverted, len(all))
+
+	var tables int
+	require.NoError(t, db.Get(&tables, `SELECT count(*) FROM information_schema.tables
+										WHERE table_schema = current_schema() AND table_name <> $1`,
+		constants.SchemaMigrationsTable))
+	assert.Zero(t, tables)
+
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, len(all))
+}
+
+func TestMigrator_Baseline(t *testing.T) {
+	ctx := context.Background()
+	db := testDB(t)
+
+	migrator, err := NewMigrator(db, migrations.FS)
+	require.NoError(t, err)
+
+	all, err := LoadMigrations(migrations.FS)
+	require.NoError(t, err)
+
+	// The schema of the first migration was created by hand before the migrations
+	_, err = db.Exec(all[0].Up)
+	require.NoError(t, err)
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, all[1:], applied)
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	for _, s := range statuses {
+		assert.NotNil(t, s.AppliedAt, s.Name)
+	}
+
+	// The recorded migrations aren't recorded again
+	recorded, err := migrator.Baseline(ctx, all[len(all)-1].Version)
+	require.NoError(t, err)
+	assert.Empty(t, recorded)
+
+	_, err = migrator.Baseline(ctx, 999999)
+	assert.EqualError(t, err, "migration 999999 not found")
+}
+
+func TestMigrator_BaselineVersion(t *testing.T) {
+	ctx := context.Background()
+	db := testDB(t)
+
+	migrator, err := NewMigrator(db, migrations.FS)
+	require.NoError(t, err)
+
+	all, err := LoadMigrations(migrations.FS)
+	require.NoError(t, err)
+
+	// The schema was changed by hand up to the second migration
+	for _, m := range all[:2] {
+		_, err = db.Exec(m.Up)
+		require.NoError(t, err)
+	}
+
+	recorded, err := migrator.Baseline(ctx, all[1].Version)
+	require.NoError(t, err)
+	assert.Equal(t, all[:2], recorded)
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, all[2:], applied)
+}
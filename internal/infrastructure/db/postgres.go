@@ -12,17 +12,31 @@ type PostgresConfig struct {
 	Port     string `yaml:"port" env:"PSQL_PORT"`
 	Database string `yaml:"database" env:"PSQL_DATABASE"`
 	SSL      string `yaml:"ssl" env:"PSQL_SSLMODE"`
+	// AutoMigrate applies the pending migrations on start-up
+	AutoMigrate bool `yaml:"auto_migrate" env:"PSQL_AUTO_MIGRATE"`
+	// SchemaCheck verifies on start-up that the tables and columns used by the code exist
+	SchemaCheck bool `yaml:"schema_check" env:"PSQL_SCHEMA_CHECK"`
 }
 
 // LoadPostgresConfig loads the postgres configuration
 func LoadPostgresConfig(p config.Provider) (*PostgresConfig, error) {
+	l := config.NewLoader(p)
+
 	c := &PostgresConfig{
-		Username: p.Get("PSQL_USERNAME", ""),
-		Password: p.Get("PSQL_PASSWORD", ""),
-		Host:     p.Get("PSQL_HOST", ""),
-		Port:     p.Get("PSQL_PORT", ""),
-		Database: p.Get("PSQL_DATABASE", ""),
-		SSL:      p.Get("PSQL_SSLMODE", ""),
+		Username: l.Required("PSQL_USERNAME"),
+		Password: l.String("PSQL_PASSWORD", ""),
+		Host:     l.Required("PSQL_HOST"),
+		Port:     l.String("PSQL_PORT", "5432"),
+		Database: l.Required("PSQL_DATABASE"),
+		SSL:      l.String("PSQL_SSLMODE", ""),
+
+		AutoMigrate: l.Bool("PSQL_AUTO_MIGRATE", false),
+		SchemaCheck: l.Bool("PSQL_SCHEMA_CHECK", true),
+	}
+
+	err := l.Err()
+	if err != nil {
+		return nil, err
 	}
 
 	return c, nil
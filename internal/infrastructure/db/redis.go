@@ -13,10 +13,17 @@ type RedisConfig struct {
 
 // LoadRedisConfig loads the redis configuration
 func LoadRedisConfig(p config.Provider) (*RedisConfig, error) {
+	l := config.NewLoader(p)
+
 	c := &RedisConfig{
-		Addr:     p.Get("REDIS_ADDRESS", ""),
-		Password: p.Get("REDIS_PASSWORD", ""),
-		Database: config.GetInt(p, "REDIS_DATABASE", 0),
+		Addr:     l.String("REDIS_ADDRESS", "localhost:6379"),
+		Password: l.String("REDIS_PASSWORD", ""),
+		Database: l.Int("REDIS_DATABASE", 0),
+	}
+
+	err := l.Err()
+	if err != nil {
+		return nil, err
 	}
 
 	return c, nil
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// SchemaMismatchError lists the tables and columns expected by the code but missing in the database.
+type SchemaMismatchError struct {
+	MissingTables  []string
+	MissingColumns map[string][]string
+}
+
+func (e *SchemaMismatchError) Error() string {
+	var b strings.Builder
+
+	b.WriteString("database schema doesn't match the code:")
+
+	for _, t := range e.MissingTables {
+		fmt.Fprintf(&b, "\n  - table %s", t)
+	}
+
+	tables := make([]string, 0, len(e.MissingColumns))
+	for t := range e.MissingColumns {
+		tables = append(tables, t)
+	}
+
+	sort.Strings(tables)
+
+	for _, t := range tables {
+		for _, c := range e.MissingColumns[t] {
+			fmt.Fprintf(&b, "\n  - column %s.%s", t, c)
+		}
+	}
+
+	return b.String()
+}
+
+// CheckSchema compares the expected tables and columns with the ones of the current schema of the database
+// and returns a *SchemaMismatchError listing the missing ones.
+func CheckSchema(ctx context.Context, db *sqlx.DB, expected map[string][]string) error {
+	rows, err := db.QueryContext(ctx, `SELECT table_name, column_name
+										FROM information_schema.columns
+										WHERE table_schema = current_schema()`)
+	if err != nil {
+		return errors.Wrap(err, "error when reading the database schema")
+	}
+	defer rows.Close()
+
+	actual := map[string]map[string]bool{}
+	for rows.Next() {
+		var table, column string
+
+		err := rows.Scan(&table, &column)
+		if err != nil {
+			return errors.Wrap(err, "error when scanning the database schema")
+		}
+
+		if actual[table] == nil {
+			actual[table] = map[string]bool{}
+		}
+
+		actual[table][column] = true
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return errors.Wrap(err, "error when reading the database schema")
+	}
+
+	return diffSchema(expected, actual)
+}
+
+// diffSchema returns the tables and columns of expected missing in actual, nil if there are none
+func diffSchema(expected map[string][]string, actual map[string]map[string]bool) error {
+	mismatch := &SchemaMismatchError{MissingColumns: map[string][]string{}}
+
+	for table, columns := range expected {
+		existing, ok := actual[table]
+		if !ok {
+			mismatch.MissingTables = append(mismatch.MissingTables, table)
+
+			continue
+		}
+
+		for _, c := range columns {
+			if !existing[c] {
+				mismatch.MissingColumns[table] = append(mismatch.MissingColumns[table], c)
+			}
+		}
+	}
+
+	if len(mismatch.MissingTables) == 0 && len(mismatch.MissingColumns) == 0 {
+		return nil
+	}
+
+	sort.Strings(mismatch.MissingTables)
+
+	for _, columns := range mismatch.MissingColumns {
+		sort.Strings(columns)
+	}
+
+	return mismatch
+}
@@ -0,0 +1,456 @@
+package db
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"acsp/internal/repository"
+	"acsp/migrations"
+)
+
+func TestDiffSchema(t *testing.T) {
+	actual := map[string]map[string]bool{
+		"users":            {"id": true, "email": true},
+		"scholar_comments": {"id": true, "upvote": true},
+	}
+
+	err := diffSchema(map[string][]string{
+		"users":                          {"id", "email"},
+		"scholar_comments":               {"id", "upvotes", "articleid"},
+		"course_lesson_comments_answers": {"id"},
+	}, actual)
+
+	var mismatch *SchemaMismatchError
+	assert.ErrorAs(t, err, &mismatch)
+	assert.Equal(t, []string{"course_lesson_comments_answers"}, mismatch.MissingTables)
+	assert.Equal(t, map[string][]string{"scholar_comments": {"articleid", "upvotes"}}, mismatch.MissingColumns)
+	assert.Equal(t, `database schema doesn't match the code:
+  - table course_lesson_comments_answers
+  - column scholar_comments.articleid
+  - column scholar_comments.upvotes`, err.Error())
+
+	assert.NoError(t, diffSchema(map[string][]string{"users": {"email"}}, actual))
+}
+
+var (
+	createTableRegexp = regexp.MustCompile(`(?is)^CREATE\s+TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?(\w+)\s*\((.*)\)$`)
+	alterTableRegexp  = regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?(\w+)\s+(.*)$`)
+	dropTableRegexp   = regexp.MustCompile(`(?is)^DROP\s+TABLE\s+(?:IF\s+EXISTS\s+)?(.*?)(?:\s+(?:CASCADE|RESTRICT))?$`)
+	addColumnRegexp   = regexp.MustCompile(`(?is)^ADD\s+(?:COLUMN\s+)?(?:IF\s+NOT\s+EXISTS\s+)?(\w+)`)
+	dropColumnRegexp  = regexp.MustCompile(`(?is)^DROP\s+(?:COLUMN\s+)?(?:IF\s+EXISTS\s+)?(\w+)`)
+	renameRegexp      = regexp.MustCompile(`(?is)^RENAME\s+(?:COLUMN\s+)?(\w+)\s+TO\s+(\w+)$`)
+	renameTableRegexp = regexp.MustCompile(`(?is)^RENAME\s+TO\s+(\w+)$`)
+	commentRegexp     = regexp.MustCompile(`--[^\n]*`)
+)
+
+// constraintKeywords start the table constraints of CREATE TABLE and ALTER TABLE ADD, they aren't columns
+var constraintKeywords = map[string]bool{
+	"CONSTRAINT": true, "PRIMARY": true, "FOREIGN": true, "UNIQUE": true, "CHECK": true, "EXCLUDE": true,
+}
+
+// migratedSchema returns the tables and columns the up scripts of the migrations create, following
+// CREATE TABLE, ALTER TABLE ADD, DROP and RENAME and DROP TABLE. The other statements don't change the columns.
+func migratedSchema(migrations []Migration) (map[string]map[string]bool, error) {
+	schema := map[string]map[string]bool{}
+
+	for _, m := range migrations {
+		for _, stmt := range strings.Split(commentRegexp.ReplaceAllString(m.Up, ""), ";") {
+			stmt = strings.TrimSpace(stmt)
+
+			if match := createTableRegexp.FindStringSubmatch(stmt); match != nil {
+				columns := map[string]bool{}
+				for _, def := range splitTopLevel(match[2]) {
+					if name := firstWord(def); name != "" && !constraintKeywords[strings.ToUpper(name)] {
+						columns[strings.ToLower(name)] = true
+					}
+				}
+
+				schema[strings.ToLower(match[1])] = columns
+
+				continue
+			}
+
+			if match := dropTableRegexp.FindStringSubmatch(stmt); match != nil {
+				for _, table := range strings.Split(match[1], ",") {
+					delete(schema, strings.ToLower(strings.TrimSpace(table)))
+				}
+
+				continue
+			}
+
+			match := alterTableRegexp.FindStringSubmatch(stmt)
+			if match == nil {
+				continue
+			}
+
+			table := strings.ToLower(match[1])
+			columns, ok := schema[table]
+			if !ok {
+				return nil, fmt.Errorf("migration %06d_%s alters the unknown table %s", m.Version, m.Name, table)
+			}
+
+			for _, action := range splitTopLevel(match[2]) {
+				if add := addColumnRegexp.FindStringSubmatch(action); add != nil {
+					if !constraintKeywords[strings.ToUpper(add[1])] {
+						columns[strings.ToLower(add[1])] = true
+					}
+				} else if drop := dropColumnRegexp.FindStringSubmatch(action); drop != nil {
+					if !strings.EqualFold(drop[1], "CONSTRAINT") {
+						delete(columns, strings.ToLower(drop[1]))
+					}
+				} else if rename := renameTableRegexp.FindStringSubmatch(action); rename != nil {
+					delete(schema, table)
+					schema[strings.ToLower(rename[1])] = columns
+				} else if rename := renameRegexp.FindStringSubmatch(action); rename != nil {
+					delete(columns, strings.ToLower(rename[1]))
+					columns[strings.ToLower(rename[2])] = true
+				}
+			}
+		}
+	}
+
+	return schema, nil
+}
+
+// splitTopLevel splits the list by the commas outside of the parentheses
+func splitTopLevel(list string) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+
+	for i, r := range list {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(list[start:i]))
+				start = i + 1
+			}
+		}
+	}
+
+	return append(parts, strings.TrimSpace(list[start:]))
+}
+
+// firstWord returns the first word of the definition without the quotes
+func firstWord(def string) string {
+	fields := strings.Fields(def)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return strings.Trim(fields[0], `"`)
+}
+
+func TestMigratedSchema(t *testing.T) {
+	schema, err := migratedSchema([]Migration{
+		{Version: 1, Name: "init", Up: `
+			CREATE TABLE users
+			(
+				id    BIGSERIAL PRIMARY KEY,
+				email VARCHAR NOT NULL, -- the login, unique
+				roles VARCHAR[] DEFAULT ARRAY ['user'],
+				UNIQUE (email)
+			);
+			CREATE TABLE drafts (id BIGINT);
+			CREATE INDEX users_email_idx ON users (email);`},
+		{Version: 2, Name: "change", Up: `
+			ALTER TABLE users ADD COLUMN IF NOT EXISTS name VARCHAR NOT NULL DEFAULT '', DROP COLUMN roles,
+				ADD CONSTRAINT users_name_check CHECK (name <> 'admin');
+			ALTER TABLE users RENAME COLUMN email TO login;
+			ALTER TABLE users RENAME TO accounts;
+			DROP TABLE IF EXISTS drafts;`},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]bool{
+		"accounts": {"id": true, "login": true, "name": true},
+	}, schema)
+
+	_, err = migratedSchema([]Migration{{Version: 1, Name: "alter", Up: `ALTER TABLE users ADD COLUMN name VARCHAR`}})
+	assert.EqualError(t, err, "migration 000001_alter alters the unknown table users")
+}
+
+// TestSchemaOfMigrations fails when repository.Schema lists a table or a column the migrations don't create,
+// the check of the schema on start-up would refuse the migrated database
+func TestSchemaOfMigrations(t *testing.T) {
+	all, err := LoadMigrations(migrations.FS)
+	require.NoError(t, err)
+
+	schema, err := migratedSchema(all)
+	require.NoError(t, err)
+
+	assert.NoError(t, diffSchema(repository.Schema, schema))
+}
+
+var (
+	sqlStatementRegexp = regexp.MustCompile(`(?i)^\s*(SELECT|INSERT|UPDATE|DELETE|WITH)\b`)
+	sqlLiteralRegexp   = regexp.MustCompile(`'[^']*'|"[^"]*"`)
+	sqlTableRegexp     = regexp.MustCompile(`(?i)\b(?:FROM|JOIN|INTO|UPDATE)\s+(\w+)(?:\s+(?:AS\s+)?(\w+))?`)
+	sqlAliasRegexp     = regexp.MustCompile(`(?i)\bAS\s+(\w+)`)
+	sqlWordRegexp      = regexp.MustCompile(`\$?\w+(?:\.(?:\w+|\*))?`)
+)
+
+// sqlWords are the keywords, functions and types of the queries, the other words are columns
+var sqlWords = map[string]bool{}
+
+func init() {
+	for _, word := range strings.Fields(`select distinct from where and or not null is in as on inner left join
+		insert into values returning update set delete order by asc desc limit offset exists conflict do nothing
+		default true false interval for skip locked now count coalesce unnest text`) {
+		sqlWords[word] = true
+	}
+}
+
+// repositoryQuery is a query of the repositories with the constants of its format put in
+type repositoryQuery struct {
+	pos string
+	sql string
+}
+
+// stringConstants returns the string constants of the Go files of the directory by name, qualified by the
+// package name unless it's the package of the queries
+func stringConstants(fset *token.FileSet, dir, qualifier string, values map[string]string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return err
+	}
+
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			return err
+		}
+
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+
+			for _, spec := range gen.Specs {
+				spec := spec.(*ast.ValueSpec)
+				for i, value := range spec.Values {
+					if lit, ok := value.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+						values[qualifier+spec.Names[i].Name], _ = strconv.Unquote(lit.Value)
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// repositoryQueries returns the statements formatted by fmt.Sprintf in the repositories. A statement with
+// an argument that isn't a constant, e.g. a table of a loop, can't be checked and is left out.
+func repositoryQueries() ([]repositoryQuery, error) {
+	fset := token.NewFileSet()
+	values := map[string]string{}
+
+	err := stringConstants(fset, "../../constants", "constants.", values)
+	if err != nil {
+		return nil, err
+	}
+
+	err = stringConstants(fset, "../../repository", "", values)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := filepath.Glob("../../repository/*.go")
+	if err != nil {
+		return nil, err
+	}
+
+	var queries []repositoryQuery
+
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+
+			if fn, ok := call.Fun.(*ast.SelectorExpr); !ok || fn.Sel.Name != "Sprintf" {
+				return true
+			}
+
+			lit, ok := call.Args[0].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+
+			format, _ := strconv.Unquote(lit.Value)
+			if !sqlStatementRegexp.MatchString(format) {
+				return true
+			}
+
+			args := make([]interface{}, 0, len(call.Args)-1)
+			for _, arg := range call.Args[1:] {
+				var name string
+
+				switch arg := arg.(type) {
+				case *ast.Ident:
+					name = arg.Name
+				case *ast.SelectorExpr:
+					if pkg, ok := arg.X.(*ast.Ident); ok {
+						name = pkg.Name + "." + arg.Sel.Name
+					}
+				}
+
+				value, ok := values[name]
+				if !ok {
+					return true
+				}
+
+				args = append(args, value)
+			}
+
+			queries = append(queries, repositoryQuery{
+				pos: fset.Position(call.Pos()).String(),
+				sql: fmt.Sprintf(format, args...),
+			})
+
+			return true
+		})
+	}
+
+	return queries, nil
+}
+
+// queryColumns returns the tables and the columns of the query missing from the schema. The columns are
+// the words of the query that aren't keywords, tables or aliases, the qualified ones are looked up in the
+// table of their qualifier.
+func queryColumns(query string, schema map[string][]string) []string {
+	query = sqlLiteralRegexp.ReplaceAllString(query, "")
+
+	columns := map[string]map[string]bool{}
+	for table, names := range schema {
+		columns[table] = map[string]bool{}
+		for _, name := range names {
+			columns[table][name] = true
+		}
+	}
+
+	var missing []string
+
+	tables := map[string]string{}
+	for _, match := range sqlTableRegexp.FindAllStringSubmatch(query, -1) {
+		// DO UPDATE SET and FOR UPDATE SKIP LOCKED don't name a table
+		table := strings.ToLower(match[1])
+		if sqlWords[table] {
+			continue
+		}
+
+		if _, ok := columns[table]; !ok {
+			missing = append(missing, "table "+table)
+
+			continue
+		}
+
+		tables[table] = table
+		if alias := strings.ToLower(match[2]); alias != "" && !sqlWords[alias] {
+			tables[alias] = table
+		}
+
+		// The row proposed for insertion of ON CONFLICT DO UPDATE
+		if strings.EqualFold(strings.Fields(match[0])[0], "INTO") {
+			tables["excluded"] = table
+		}
+	}
+
+	aliases := map[string]bool{}
+	for _, match := range sqlAliasRegexp.FindAllStringSubmatch(query, -1) {
+		aliases[strings.ToLower(match[1])] = true
+	}
+
+	for _, word := range sqlWordRegexp.FindAllString(query, -1) {
+		word = strings.ToLower(word)
+
+		if qualifier, column, ok := strings.Cut(word, "."); ok {
+			table, ok := tables[qualifier]
+			if ok && column != "*" && !columns[table][column] {
+				missing = append(missing, "column "+table+"."+column)
+			}
+
+			continue
+		}
+
+		if word[0] == '$' || (word[0] >= '0' && word[0] <= '9') || sqlWords[word] || aliases[word] {
+			continue
+		}
+
+		if _, ok := tables[word]; ok {
+			continue
+		}
+
+		found := false
+		for _, table := range tables {
+			found = found || columns[table][word]
+		}
+
+		if !found && len(tables) > 0 {
+			missing = append(missing, "column "+word)
+		}
+	}
+
+	return missing
+}
+
+func TestQueryColumns(t *testing.T) {
+	schema := map[string][]string{
+		"users":      {"id", "email", "name"},
+		"user_roles": {"id", "user_id", "role_id"},
+	}
+
+	assert.Empty(t, queryColumns(`SELECT u.*, r.role_id AS "role.id" FROM users u
+		INNER JOIN user_roles r ON r.user_id = u.id WHERE email = $1 AND name <> 'admin' LIMIT 1`, schema))
+	assert.Empty(t, queryColumns(`INSERT INTO users (email, name) VALUES ($1, $2)
+		ON CONFLICT (email) DO UPDATE SET name = excluded.name RETURNING id`, schema))
+
+	assert.Equal(t, []string{"column users.login", "column role"},
+		queryColumns(`SELECT u.login FROM users u INNER JOIN user_roles r ON r.id = role`, schema))
+	assert.Equal(t, []string{"table roles"}, queryColumns(`DELETE FROM roles WHERE id = $1`, schema))
+}
+
+// TestSchemaOfRepositories fails when a query of the repositories uses a table or a column that isn't listed
+// in repository.Schema, the check of the schema on start-up wouldn't notice it is missing
+func TestSchemaOfRepositories(t *testing.T) {
+	queries, err := repositoryQueries()
+	require.NoError(t, err)
+
+	// The queries are found, the parsing isn't broken silently
+	require.Greater(t, len(queries), 100)
+
+	for _, query := range queries {
+		assert.Empty(t, queryColumns(query.sql, repository.Schema), "%s:\n%s", query.pos, query.sql)
+	}
+}
@@ -0,0 +1,280 @@
+// Package jwks holds the keys signing and verifying the tokens: the key of the configured id signs the new tokens,
+// all the keys verify them, so that a key is rotated without signing out the users, and the public keys are
+// published as a JSON Web Key Set.
+package jwks
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+)
+
+const (
+	// RS256 is RSASSA-PKCS1-v1_5 with SHA-256 of the RSA keys
+	RS256 = "RS256"
+	// EdDSA is Ed25519 of the Ed25519 keys
+	EdDSA = "EdDSA"
+
+	minRSABits = 2048
+)
+
+var errUnknownKey = errors.New("unknown key id")
+
+// Key is a key of the set, the private key is nil when the key only verifies the tokens.
+type Key struct {
+	ID        string
+	Algorithm string
+	Private   crypto.Signer
+	Public    crypto.PublicKey
+}
+
+// KeySet signs the tokens with the signing key and verifies them with any key of the set.
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+}
+
+// NewKeySet creates a set of the keys, the key of the signingKeyID must have the private key.
+func NewKeySet(signingKeyID string, keys ...*Key) (*KeySet, error) {
+	s := &KeySet{keys: make(map[string]*Key, len(keys))}
+	for _, k := range keys {
+		if _, ok := s.keys[k.ID]; ok {
+			return nil, fmt.Errorf("duplicate key id %q", k.ID)
+		}
+
+		s.keys[k.ID] = k
+	}
+
+	signing, ok := s.keys[signingKeyID]
+	if !ok {
+		return nil, fmt.Errorf("signing key %q not found", signingKeyID)
+	}
+
+	if signing.Private == nil {
+		return nil, fmt.Errorf("signing key %q has no private key", signingKeyID)
+	}
+
+	s.signing = signing
+
+	return s, nil
+}
+
+// LoadDir loads the keys of the PEM files of the directory, the id of a key is its file name without the
+// .pem extension. The files of the previous keys may contain only the public keys.
+func LoadDir(dir, signingKeyID string) (*KeySet, error) {
+	return Load(dir, signingKeyID, nil)
+}
+
+// Load loads the keys of the directory like LoadDir and the signing key encoded in PEM when it is given,
+// e.g. by a secret of the deployment. The directory then only holds the previous keys, it may be missing.
+func Load(dir, signingKeyID string, signingKey []byte) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*Key, 0, len(paths)+1)
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		k, err := ParseKey(strings.TrimSuffix(filepath.Base(path), ".pem"), b)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error when loading the key %s", path)
+		}
+
+		keys = append(keys, k)
+	}
+
+	if len(signingKey) > 0 {
+		k, err := ParseKey(signingKeyID, signingKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "error when loading the signing key")
+		}
+
+		keys = append(keys, k)
+	}
+
+	return NewKeySet(signingKeyID, keys...)
+}
+
+// ParseKey parses a PKCS #8 or PKCS #1 private key or a PKIX public key of RSA or Ed25519 encoded in PEM.
+func ParseKey(id string, b []byte) (*Key, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var (
+		key interface{}
+		err error
+	)
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	k := &Key{ID: id}
+	if signer, ok := key.(crypto.Signer); ok {
+		k.Private = signer
+		k.Public = signer.Public()
+	} else {
+		k.Public = key
+	}
+
+	switch pub := k.Public.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("RSA key of %d bits, at least %d are required", pub.N.BitLen(), minRSABits)
+		}
+
+		k.Algorithm = RS256
+	case ed25519.PublicKey:
+		k.Algorithm = EdDSA
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", k.Public)
+	}
+
+	return k, nil
+}
+
+// Generate generates a private key of the algorithm encoded in PEM.
+func Generate(algorithm string) ([]byte, error) {
+	var (
+		key interface{}
+		err error
+	)
+
+	switch algorithm {
+	case RS256:
+		key, err = rsa.GenerateKey(rand.Reader, minRSABits)
+	case EdDSA:
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", algorithm)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// NewKeyID returns an id of a new key, the ids are ordered by the date of the key.
+func NewKeyID() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s-%x", time.Now().UTC().Format("20060102"), b), nil
+}
+
+// SigningKeyID returns the id of the key signing the tokens.
+func (s *KeySet) SigningKeyID() string {
+	return s.signing.ID
+}
+
+// Sign signs the claims with the signing key, the kid header is the id of the key.
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(s.signing.Algorithm), claims)
+	token.Header["kid"] = s.signing.ID
+
+	return token.SignedString(s.signing.Private)
+}
+
+// Keyfunc returns the public key of the kid header of the token, see jwt.Keyfunc.
+func (s *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	k, ok := s.keys[kid]
+	if !ok {
+		return nil, errUnknownKey
+	}
+
+	// The algorithm of the header must be the one of the key, see CVE-2015-9235
+	if token.Method.Alg() != k.Algorithm {
+		return nil, jwt.ErrTokenSignatureInvalid
+	}
+
+	return k.Public, nil
+}
+
+// JWK is a public key of the set in the JSON Web Key format, see RFC 7517.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	// N and E are the modulus and the exponent of an RSA key
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Curve and X are the curve and the public key of an Ed25519 key, see RFC 8037
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+// JWKSet is the JSON Web Key Set of the public keys.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys verifying the tokens ordered by the id.
+func (s *KeySet) JWKS() JWKSet {
+	set := JWKSet{Keys: make([]JWK, 0, len(s.keys))}
+	for _, k := range s.keys {
+		jwk := JWK{KeyID: k.ID, Use: "sig", Algorithm: k.Algorithm}
+
+		switch pub := k.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].KeyID < set.Keys[j].KeyID
+	})
+
+	return set
+}
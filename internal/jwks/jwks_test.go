@@ -0,0 +1,165 @@
+package jwks
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeKey generates a key of the algorithm into the directory
+func writeKey(t *testing.T, dir, id, algorithm string) {
+	b, err := Generate(algorithm)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, id+".pem"), b, 0o600))
+}
+
+func sign(t *testing.T, s *KeySet) string {
+	token, err := s.Sign(jwt.RegisteredClaims{
+		Subject:   "1",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	})
+	require.NoError(t, err)
+
+	return token
+}
+
+func verify(s *KeySet, token string) error {
+	_, err := jwt.Parse(token, s.Keyfunc, jwt.WithValidMethods([]string{RS256, EdDSA}))
+
+	return err
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	writeKey(t, dir, "2023-01", RS256)
+
+	old, err := LoadDir(dir, "2023-01")
+	require.NoError(t, err)
+
+	token := sign(t, old)
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &jwt.RegisteredClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "2023-01", parsed.Header["kid"])
+	assert.Equal(t, RS256, parsed.Header["alg"])
+
+	// The new key signs, the tokens of the old one are still valid
+	writeKey(t, dir, "2023-02", EdDSA)
+
+	rotated, err := LoadDir(dir, "2023-02")
+	require.NoError(t, err)
+	assert.NoError(t, verify(rotated, token))
+
+	newToken := sign(t, rotated)
+	assert.NoError(t, verify(rotated, newToken))
+
+	// The tokens of the new key aren't valid for the instances not rotated yet
+	assert.ErrorIs(t, verify(old, newToken), errUnknownKey)
+
+	// The old key is removed when its tokens are expired
+	require.NoError(t, os.Remove(filepath.Join(dir, "2023-01.pem")))
+
+	rotated, err = LoadDir(dir, "2023-02")
+	require.NoError(t, err)
+	assert.ErrorIs(t, verify(rotated, token), errUnknownKey)
+}
+
+func TestKeySet_PublicKeys(t *testing.T) {
+	dir := t.TempDir()
+	writeKey(t, dir, "signing", EdDSA)
+	writeKey(t, dir, "previous", RS256)
+
+	// The previous key may be kept without the private key
+	k, err := ParseKey("previous", mustRead(t, filepath.Join(dir, "previous.pem")))
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(k.Public)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "previous.pem"),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	s, err := LoadDir(dir, "signing")
+	require.NoError(t, err)
+
+	jwks := s.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, JWK{KeyType: "RSA", KeyID: "previous", Use: "sig", Algorithm: RS256,
+		N: jwks.Keys[0].N, E: "AQAB"}, jwks.Keys[0])
+	assert.Equal(t, "OKP", jwks.Keys[1].KeyType)
+	assert.Equal(t, "Ed25519", jwks.Keys[1].Curve)
+	assert.Len(t, jwks.Keys[1].X, 43)
+
+	_, err = LoadDir(dir, "previous")
+	assert.EqualError(t, err, `signing key "previous" has no private key`)
+
+	_, err = LoadDir(dir, "unknown")
+	assert.EqualError(t, err, `signing key "unknown" not found`)
+}
+
+func TestKeySet_RejectsForgedTokens(t *testing.T) {
+	dir := t.TempDir()
+	writeKey(t, dir, "rsa", RS256)
+
+	s, err := LoadDir(dir, "rsa")
+	require.NoError(t, err)
+
+	// The public key used as an HMAC secret
+	claims := jwt.RegisteredClaims{Subject: "1"}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = "rsa"
+	forged, err := token.SignedString(mustRead(t, filepath.Join(dir, "rsa.pem")))
+	require.NoError(t, err)
+	assert.Error(t, verify(s, forged))
+
+	// The token of a key of another set with the same id
+	other := t.TempDir()
+	writeKey(t, other, "rsa", RS256)
+
+	o, err := LoadDir(other, "rsa")
+	require.NoError(t, err)
+	assert.ErrorIs(t, verify(s, sign(t, o)), jwt.ErrTokenSignatureInvalid)
+}
+
+func mustRead(t *testing.T, path string) []byte {
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	return b
+}
+
+func TestLoad_SigningKey(t *testing.T) {
+	dir := t.TempDir()
+	writeKey(t, dir, "2023-01", RS256)
+
+	old, err := LoadDir(dir, "2023-01")
+	require.NoError(t, err)
+
+	token := sign(t, old)
+
+	// The signing key is given by a secret, the directory keeps the previous keys
+	key, err := Generate(EdDSA)
+	require.NoError(t, err)
+
+	s, err := Load(dir, "2023-02", key)
+	require.NoError(t, err)
+	assert.Equal(t, "2023-02", s.SigningKeyID())
+	assert.NoError(t, verify(s, token))
+	assert.NoError(t, verify(s, sign(t, s)))
+
+	// The directory isn't needed
+	s, err = Load(filepath.Join(dir, "missing"), "2023-02", key)
+	require.NoError(t, err)
+	assert.ErrorIs(t, verify(s, token), errUnknownKey)
+
+	_, err = Load(dir, "2023-02", []byte("not a key"))
+	assert.EqualError(t, err, "error when loading the signing key: no PEM block found")
+
+	_, err = Load(dir, "2023-01", key)
+	assert.EqualError(t, err, `duplicate key id "2023-01"`)
+}
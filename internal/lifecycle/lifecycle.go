@@ -0,0 +1,124 @@
+// Package lifecycle stops the components of the app in the reverse order of their start.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"acsp/internal/logging"
+)
+
+// StopFunc stops a component, it should return once ctx is done.
+type StopFunc func(ctx context.Context) error
+
+type component struct {
+	name string
+	stop StopFunc
+}
+
+// Manager stops the registered components within a deadline.
+type Manager struct {
+	timeout time.Duration
+
+	mu         sync.Mutex
+	components []component
+}
+
+// NewManager creates a Manager, Shutdown takes at most timeout.
+func NewManager(timeout time.Duration) *Manager {
+	return &Manager{timeout: timeout}
+}
+
+// Register adds a started component, the components are stopped in the reverse order of registration,
+// so a component is registered after the components it uses.
+func (m *Manager) Register(name string, stop StopFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.components = append(m.components, component{name: name, stop: stop})
+}
+
+// ComponentError is a component that failed to stop or didn't stop in time.
+type ComponentError struct {
+	Name string
+	Err  error
+}
+
+// ShutdownError reports the components that failed to stop.
+type ShutdownError struct {
+	Components []ComponentError
+}
+
+func (e *ShutdownError) Error() string {
+	parts := make([]string, 0, len(e.Components))
+	for _, c := range e.Components {
+		parts = append(parts, fmt.Sprintf("%s: %v", c.Name, c.Err))
+	}
+
+	return "couldn't stop " + strings.Join(parts, "; ")
+}
+
+// Shutdown stops the components one by one in the reverse order of registration. A component that doesn't stop
+// before the deadline is left behind, and so are the components after it, the process is about to exit anyway.
+// The components that failed or were left behind are reported in a *ShutdownError.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	l := logging.LoggerFromContext(ctx)
+
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	m.mu.Lock()
+	components := m.components
+	m.components = nil
+	m.mu.Unlock()
+
+	var failed []ComponentError
+
+	for i := len(components) - 1; i >= 0; i-- {
+		c := components[i]
+		start := time.Now()
+
+		err := stop(ctx, c.stop)
+		if err != nil {
+			l.Error("Couldn't stop component", zap.String("component", c.name), zap.Error(err))
+
+			failed = append(failed, ComponentError{Name: c.name, Err: err})
+
+			continue
+		}
+
+		l.Info("Component stopped", zap.String("component", c.name), zap.Duration("took", time.Since(start)))
+	}
+
+	if len(failed) > 0 {
+		return &ShutdownError{Components: failed}
+	}
+
+	return nil
+}
+
+// stop runs fn and waits for it until ctx is done
+func stop(ctx context.Context, fn StopFunc) error {
+	// The deadline may have passed while the previous components were stopping
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("not stopped in time: %w", err)
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("not stopped in time: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,61 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_Shutdown(t *testing.T) {
+	m := NewManager(time.Second)
+
+	var stopped []string
+
+	for _, name := range []string{"postgres", "workers", "http server"} {
+		name := name
+		m.Register(name, func(ctx context.Context) error {
+			stopped = append(stopped, name)
+
+			return nil
+		})
+	}
+
+	assert.NoError(t, m.Shutdown(context.Background()))
+	assert.Equal(t, []string{"http server", "workers", "postgres"}, stopped)
+}
+
+func TestManager_ShutdownFailures(t *testing.T) {
+	m := NewManager(50 * time.Millisecond)
+
+	m.Register("postgres", func(ctx context.Context) error {
+		return nil
+	})
+	m.Register("redis", func(ctx context.Context) error {
+		return errors.New("connection reset")
+	})
+	// A component ignoring the deadline
+	m.Register("workers", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+
+		return nil
+	})
+
+	start := time.Now()
+	err := m.Shutdown(context.Background())
+	assert.Less(t, time.Since(start), time.Second)
+
+	var shutdownErr *ShutdownError
+	if assert.ErrorAs(t, err, &shutdownErr) {
+		names := make([]string, 0, len(shutdownErr.Components))
+		for _, c := range shutdownErr.Components {
+			names = append(names, c.Name)
+		}
+
+		// The components after the one that didn't stop in time are left behind as well
+		assert.Equal(t, []string{"workers", "redis", "postgres"}, names)
+		assert.ErrorIs(t, shutdownErr.Components[0].Err, context.DeadlineExceeded)
+	}
+}
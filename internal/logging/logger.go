@@ -30,6 +30,7 @@ type Builder struct {
 	hostConfig     *config.HostConfig
 	loggerConfig   *config.LoggerConfig
 	fallbackLogger *log.Logger
+	level          *zap.AtomicLevel
 }
 
 // NewBuilder creates a Builder.
@@ -58,10 +59,22 @@ func (b *Builder) WithFallbackLogger(l *log.Logger) *Builder {
 	return b
 }
 
+// WithLevel adds a zap.AtomicLevel, so that the level can be changed while the logger is running.
+func (b *Builder) WithLevel(l zap.AtomicLevel) *Builder {
+	b.level = &l
+
+	return b
+}
+
 // NewLogger creates a zap.Logger.
 func (b *Builder) NewLogger() (*zap.Logger, error) {
+	level := zap.NewAtomicLevelAt(b.loggerConfig.Level)
+	if b.level != nil {
+		level = *b.level
+	}
+
 	loggerConfig := zap.Config{
-		Level:             zap.NewAtomicLevelAt(b.loggerConfig.Level),
+		Level:             level,
 		Development:       false,
 		DisableCaller:     false,
 		DisableStacktrace: false,
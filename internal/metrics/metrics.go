@@ -0,0 +1,102 @@
+// Package metrics holds the Prometheus metrics of the app, they are served by the /metrics endpoint.
+package metrics
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "acsp"
+
+// Registry is the registry of the app metrics, together with the Go runtime and process metrics.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	// HTTPRequests counts the handled requests by method, route template and status.
+	HTTPRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of the handled HTTP requests.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration observes the latency of the requests by method, route template and status.
+	HTTPRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the handled HTTP requests.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// RedisCommandDuration observes the latency of the redis commands by command and result.
+	RedisCommandDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "redis",
+		Name:      "command_duration_seconds",
+		Help:      "Latency of the redis commands, a pipeline is observed as one command.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"command", "result"})
+
+	// S3UploadDuration observes the duration of the uploads to the bucket by result.
+	S3UploadDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "s3",
+		Name:      "upload_duration_seconds",
+		Help:      "Duration of the uploads to the S3 bucket.",
+		Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"result"})
+
+	// SignUps counts the created users.
+	SignUps = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sign_ups_total",
+		Help:      "Number of the users signed up.",
+	})
+
+	// ArticlesCreated counts the created articles.
+	ArticlesCreated = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "articles_created_total",
+		Help:      "Number of the articles created.",
+	})
+
+	// InvitationsSent counts the invitations sent to the cards.
+	InvitationsSent = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "invitations_sent_total",
+		Help:      "Number of the invitations sent to the code connection cards.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// RegisterDB reports the connection pool stats of the database, see sql.DB.Stats.
+func RegisterDB(db *sql.DB, name string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// Result returns the result label of an operation.
+func Result(err error) string {
+	if err != nil {
+		return "error"
+	}
+
+	return "ok"
+}
+
+// Since returns the seconds passed since start, the unit of the durations.
+func Since(start time.Time) float64 {
+	return time.Since(start).Seconds()
+}
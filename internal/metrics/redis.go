@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+)
+
+// RedisHook observes the latency of the redis commands, see redis.Client.AddHook.
+type RedisHook struct{}
+
+func (RedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (RedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+
+		err := next(ctx, cmd)
+
+		RedisCommandDuration.WithLabelValues(cmd.Name(), redisResult(err)).Observe(Since(start))
+
+		return err
+	}
+}
+
+func (RedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+
+		err := next(ctx, cmds)
+
+		RedisCommandDuration.WithLabelValues("pipeline", redisResult(err)).Observe(Since(start))
+
+		return err
+	}
+}
+
+// redisResult doesn't count a missing key as an error
+func redisResult(err error) string {
+	if errors.Is(err, redis.Nil) {
+		return "ok"
+	}
+
+	return Result(err)
+}
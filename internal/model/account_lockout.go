@@ -0,0 +1,18 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// AccountLockout is an entry of the audit trail of the accounts and IPs locked after failed sign-ins.
+type AccountLockout struct {
+	ID int `json:"id" db:"id"`
+	// UserID is null when the email doesn't belong to a user
+	UserID      sql.NullInt64 `json:"user_id" db:"user_id"`
+	Email       string        `json:"email" db:"email"`
+	IP          string        `json:"ip" db:"ip"`
+	Failures    int           `json:"failures" db:"failures"`
+	LockedUntil time.Time     `json:"locked_until" db:"locked_until"`
+	CreatedAt   string        `json:"created_at" db:"created_at"`
+}
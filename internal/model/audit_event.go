@@ -0,0 +1,55 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEvent is an administrative or security-sensitive action, the changes are the fields that differ
+// between the target before and after the action.
+type AuditEvent struct {
+	ID           int64           `json:"id" db:"id"`
+	ActorID      *int            `json:"actor_id" db:"actor_id"`
+	ActorTokenID *int            `json:"actor_token_id,omitempty" db:"actor_token_id"`
+	Action       string          `json:"action" db:"action"`
+	TargetType   string          `json:"target_type" db:"target_type"`
+	TargetID     string          `json:"target_id" db:"target_id"`
+	Changes      json.RawMessage `json:"changes" db:"changes"`
+	IP           string          `json:"ip" db:"ip"`
+	RequestID    string          `json:"request_id" db:"request_id"`
+	CreatedAt    time.Time       `json:"created_at" db:"created_at"`
+}
+
+// AuditChange is a changed field of the target of an audit event.
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditActor is who made the request, the audit events are recorded on behalf of the actor.
+type AuditActor struct {
+	UserID    string
+	TokenID   int
+	IP        string
+	RequestID string
+}
+
+// AuditEventsFilter narrows the audit events, the zero fields match every event.
+type AuditEventsFilter struct {
+	ActorID    *int
+	Action     string
+	TargetType string
+	TargetID   string
+	From       *time.Time
+	To         *time.Time
+	// Before is the cursor of the page, only the events older than the event with this ID are returned
+	Before int64
+	Limit  int
+}
+
+// AuditEventsPage is a page of the audit events from the newest to the oldest.
+type AuditEventsPage struct {
+	Events []AuditEvent `json:"events"`
+	// NextBefore is the cursor of the next page, it is nil on the last page
+	NextBefore *int64 `json:"next_before"`
+}
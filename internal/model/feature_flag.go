@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// FeatureFlag gates a feature. A disabled flag is off for everyone, an enabled one is on for the listed users,
+// the users with one of the roles and the given percentage of the other users.
+type FeatureFlag struct {
+	Key         string         `json:"key" db:"key"`
+	Description string         `json:"description" db:"description"`
+	Enabled     bool           `json:"enabled" db:"enabled"`
+	Roles       pq.StringArray `json:"roles" db:"roles"`
+	UserIDs     pq.Int64Array  `json:"user_ids" db:"user_ids"`
+	Percentage  int            `json:"percentage" db:"percentage"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,15 @@
+package model
+
+// HealthReport is the readiness of the app, it is up when every dependency is up.
+type HealthReport struct {
+	Status string                 `json:"status"`
+	Checks map[string]HealthCheck `json:"checks"`
+}
+
+// HealthCheck is the result of checking a dependency. The report is public, so only the status is sent,
+// the latency and the error are logged.
+type HealthCheck struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"-"`
+	Error     string  `json:"-"`
+}
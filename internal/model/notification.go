@@ -0,0 +1,18 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Notification tells a user about something done to their data by others, e.g. an invitation to their card.
+type Notification struct {
+	ID     int    `json:"id" db:"id"`
+	UserID int    `json:"user_id" db:"user_id"`
+	Kind   string `json:"kind" db:"kind"`
+	// Payload depends on the kind, e.g. the ids of the card and of the invitation
+	Payload        json.RawMessage `json:"payload" db:"payload"`
+	IdempotencyKey string          `json:"-" db:"idempotency_key"`
+	ReadAt         *time.Time      `json:"read_at" db:"read_at"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+}
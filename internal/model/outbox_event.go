@@ -0,0 +1,17 @@
+package model
+
+import "encoding/json"
+
+// OutboxEvent is a side effect written in the same transaction as the data it belongs to
+// and performed later by the outbox worker.
+type OutboxEvent struct {
+	ID             int             `json:"id" db:"id"`
+	EventType      string          `json:"event_type" db:"event_type"`
+	IdempotencyKey string          `json:"idempotency_key" db:"idempotency_key"`
+	Payload        json.RawMessage `json:"payload" db:"payload"`
+	Status         string          `json:"status" db:"status"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+	LastError      string          `json:"last_error" db:"last_error"`
+	NextAttemptAt  string          `json:"next_attempt_at" db:"next_attempt_at"`
+	CreatedAt      string          `json:"created_at" db:"created_at"`
+}
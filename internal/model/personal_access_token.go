@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// PersonalAccessToken authenticates the scripts of a user, only the hash of the token is stored.
+type PersonalAccessToken struct {
+	ID        int    `json:"id" db:"id"`
+	UserID    int    `json:"-" db:"user_id"`
+	Name      string `json:"name" db:"name"`
+	TokenHash string `json:"-" db:"token_hash"`
+	// Hint is the end of the token, the users tell their tokens apart by it
+	Hint       string         `json:"hint" db:"hint"`
+	Scopes     pq.StringArray `json:"scopes" db:"scopes"`
+	ExpiresAt  time.Time      `json:"expires_at" db:"expires_at"`
+	LastUsedAt *time.Time     `json:"last_used_at" db:"last_used_at"`
+	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
+}
+
+// NewPersonalAccessToken is a created token, the token is returned once.
+type NewPersonalAccessToken struct {
+	PersonalAccessToken
+	Token string `json:"token"`
+}
@@ -0,0 +1,40 @@
+package model
+
+// Principal is the authenticated user of a request, it is read from the claims of the access token so that
+// the roles are checked without querying the database.
+type Principal struct {
+	UserID string
+	Roles  []string
+	// TwoFactor is true when the user had two-factor authentication enabled when the token was issued
+	TwoFactor bool
+	// TokenID is the id of the personal access token, it is zero for the access tokens of the sessions
+	TokenID int
+	// Scopes limit the personal access tokens, see constants.ScopeRead
+	Scopes []string
+}
+
+// HasRole returns whether the principal has one of the roles.
+func (p Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasScope returns whether the principal may use the scope, the access tokens of the sessions have every scope.
+func (p Principal) HasScope(scope string) bool {
+	if p.TokenID == 0 {
+		return true
+	}
+
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// RateLimitResult is the decision of the rate limiter on a request.
+type RateLimitResult struct {
+	Allowed bool
+	// Limit is the number of requests allowed per window, zero when the requests aren't limited
+	Limit     int
+	Remaining int
+	// Window is the length of the sliding window
+	Window time.Duration
+	// Reset is the time until a request of the window expires, the request is allowed again then
+	Reset time.Duration
+}
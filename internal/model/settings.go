@@ -0,0 +1,31 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// RuntimeSettings are applied by every instance without a restart, see the settings service.
+type RuntimeSettings struct {
+	LogLevel    string            `json:"log_level"`
+	CORSOrigins []string          `json:"cors_origins"`
+	RateLimit   RateLimitSettings `json:"rate_limit"`
+	// Version is incremented on every change, zero for the settings of the config that were never changed
+	Version   int64     `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RateLimitSettings allows Max requests of a client per window.
+type RateLimitSettings struct {
+	Max           int `json:"max"`
+	WindowSeconds int `json:"window_seconds"`
+}
+
+// SettingsChange is an entry of the audit trail of the runtime settings.
+type SettingsChange struct {
+	ID        int             `json:"id" db:"id"`
+	UserID    int             `json:"user_id" db:"user_id"`
+	Before    json.RawMessage `json:"before" db:"before"`
+	After     json.RawMessage `json:"after" db:"after"`
+	CreatedAt string          `json:"created_at" db:"created_at"`
+}
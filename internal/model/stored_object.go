@@ -0,0 +1,19 @@
+package model
+
+// StoredObject is an object of the object storage registered together with the row that owns it.
+// OwnerType is the folder of the object, e.g. "user-avatars" for the avatars of users.
+type StoredObject struct {
+	ID        int    `json:"id" db:"id"`
+	Key       string `json:"key" db:"object_key"`
+	OwnerType string `json:"owner_type" db:"owner_type"`
+	OwnerID   int    `json:"owner_id" db:"owner_id"`
+	CreatedAt string `json:"created_at" db:"created_at"`
+}
+
+// StorageCleanupReport describes a single run of the storage janitor.
+type StorageCleanupReport struct {
+	DryRun  bool           `json:"dry_run"`
+	Orphans []StoredObject `json:"orphans"`
+	Deleted int            `json:"deleted"`
+	Failed  int            `json:"failed"`
+}
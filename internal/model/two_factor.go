@@ -0,0 +1,26 @@
+package model
+
+import "database/sql"
+
+// TOTP is the authenticator of a user, it is enrolled until the user enables it with a valid code.
+type TOTP struct {
+	UserID    int          `json:"user_id" db:"user_id"`
+	Secret    string       `json:"-" db:"secret"`
+	Enabled   bool         `json:"enabled" db:"enabled"`
+	CreatedAt string       `json:"created_at" db:"created_at"`
+	EnabledAt sql.NullTime `json:"enabled_at" db:"enabled_at"`
+}
+
+// TOTPEnrollment is added to an authenticator app from the provisioning URI or the secret.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// MFAChallenge is the second step of a sign-in, the token is exchanged for a token pair with a valid code.
+type MFAChallenge struct {
+	MFARequired bool   `json:"mfa_required"`
+	Token       string `json:"mfa_token"`
+	// ExpiresIn is the time to enter the code in seconds
+	ExpiresIn int `json:"expires_in"`
+}
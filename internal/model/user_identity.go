@@ -0,0 +1,17 @@
+package model
+
+// UserIdentity links a user to the subject of an OpenID Connect provider.
+type UserIdentity struct {
+	ID        int    `json:"id" db:"id"`
+	UserID    int    `json:"user_id" db:"user_id"`
+	Issuer    string `json:"issuer" db:"issuer"`
+	Subject   string `json:"subject" db:"subject"`
+	Email     string `json:"email" db:"email"`
+	CreatedAt string `json:"created_at" db:"created_at"`
+}
+
+// OIDCState is kept between the redirect to the provider and the callback.
+type OIDCState struct {
+	Verifier string `json:"verifier"`
+	Nonce    string `json:"nonce"`
+}
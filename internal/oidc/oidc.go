@@ -0,0 +1,309 @@
+// Package oidc is a client of an OpenID Connect provider for the authorization code flow with PKCE:
+// the discovery, the authorization URL, the code exchange and the verification of the ID tokens
+// with the keys of the provider.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+)
+
+// keysRefreshInterval limits the refreshes of the keys on the tokens signed by unknown keys
+const keysRefreshInterval = time.Minute
+
+// Config of the client registered at the provider.
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	// GroupsClaim is the claim of the ID token listing the groups of the user
+	GroupsClaim string
+}
+
+// Claims of an ID token used to sign in a user.
+type Claims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Groups        []string
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider is a client of an OpenID Connect provider. The discovery document and the keys are fetched
+// on the first use, so that the app starts while the provider is down.
+type Provider struct {
+	config Config
+	client *http.Client
+
+	mu            sync.Mutex
+	discovery     *discovery
+	keys          map[string]interface{}
+	keysFetchedAt time.Time
+}
+
+// NewProvider creates a client of the provider of the config.
+func NewProvider(c Config, client *http.Client) *Provider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &Provider{
+		config: c,
+		client: client,
+	}
+}
+
+// AuthCodeURL returns the URL of the provider the user signs in at. The state and the nonce bind the callback
+// and the ID token to the sign-in, the challenge of the verifier binds the code exchange to it.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", p.config.ClientID)
+	v.Set("redirect_uri", p.config.RedirectURL)
+	v.Set("scope", strings.Join(p.config.Scopes, " "))
+	v.Set("state", state)
+	v.Set("nonce", nonce)
+	v.Set("code_challenge", ChallengeS256(verifier))
+	v.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+
+	return d.AuthorizationEndpoint + sep + v.Encode(), nil
+}
+
+// Exchange exchanges the code of the callback for the ID token.
+func (p *Provider) Exchange(ctx context.Context, code, verifier string) (string, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	v := url.Values{}
+	v.Set("grant_type", "authorization_code")
+	v.Set("code", code)
+	v.Set("redirect_uri", p.config.RedirectURL)
+	v.Set("client_id", p.config.ClientID)
+	v.Set("code_verifier", verifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(v.Encode()))
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "error when exchanging the code")
+	}
+	defer resp.Body.Close()
+
+	var token struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&token)
+	if err != nil {
+		return "", errors.Wrapf(err, "error when decoding the token response of status %d", resp.StatusCode)
+	}
+
+	if resp.StatusCode != http.StatusOK || token.Error != "" {
+		return "", errors.Errorf("the code is refused: %s %s", token.Error, token.ErrorDescription)
+	}
+
+	if token.IDToken == "" {
+		return "", errors.New("the token response has no ID token")
+	}
+
+	return token.IDToken, nil
+}
+
+// Verify checks the signature, the issuer, the audience, the expiry and the nonce of the ID token.
+func (p *Provider) Verify(ctx context.Context, rawIDToken, nonce string) (Claims, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	claims := jwt.MapClaims{}
+
+	_, err = jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+
+		return p.key(ctx, d, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(d.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return Claims{}, errors.Wrap(err, "invalid ID token")
+	}
+
+	if _, ok := claims["exp"]; !ok {
+		return Claims{}, errors.New("invalid ID token: the token has no expiry")
+	}
+
+	got, _ := claims["nonce"].(string)
+	if subtle.ConstantTimeCompare([]byte(got), []byte(nonce)) != 1 {
+		return Claims{}, errors.New("invalid ID token: the nonce doesn't match")
+	}
+
+	c := Claims{}
+	c.Subject, _ = claims["sub"].(string)
+	c.Email, _ = claims["email"].(string)
+	c.Name, _ = claims["name"].(string)
+
+	// Some providers send the booleans as strings
+	switch v := claims["email_verified"].(type) {
+	case bool:
+		c.EmailVerified = v
+	case string:
+		c.EmailVerified = v == "true"
+	}
+
+	if groups, ok := claims[p.config.GroupsClaim].([]interface{}); ok {
+		for _, g := range groups {
+			if s, ok := g.(string); ok {
+				c.Groups = append(c.Groups, s)
+			}
+		}
+	}
+
+	if c.Subject == "" {
+		return Claims{}, errors.New("invalid ID token: the token has no subject")
+	}
+
+	return c, nil
+}
+
+// getDiscovery returns the discovery document of the issuer, it is fetched once.
+func (p *Provider) getDiscovery(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var d discovery
+
+	err := p.getJSON(ctx, strings.TrimSuffix(p.config.Issuer, "/")+"/.well-known/openid-configuration", &d)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when discovering the provider")
+	}
+
+	if d.Issuer != p.config.Issuer {
+		return nil, errors.Errorf("the provider has the issuer %q instead of %q", d.Issuer, p.config.Issuer)
+	}
+
+	p.discovery = &d
+
+	return p.discovery, nil
+}
+
+// key returns the key of the kid, the keys are refreshed when the provider rotated them.
+func (p *Provider) key(ctx context.Context, d *discovery, kid string) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+
+	if time.Since(p.keysFetchedAt) < keysRefreshInterval {
+		return nil, errors.Errorf("unknown key %q", kid)
+	}
+
+	var set jwks
+
+	err := p.getJSON(ctx, d.JWKSURI, &set)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when getting the keys of the provider")
+	}
+
+	p.keys = set.publicKeys()
+	p.keysFetchedAt = time.Now()
+
+	key, ok := p.keys[kid]
+	if !ok {
+		return nil, errors.Errorf("unknown key %q", kid)
+	}
+
+	return key, nil
+}
+
+func (p *Provider) getJSON(ctx context.Context, u string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d of %s", resp.StatusCode, u)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// RandomToken returns a random URL-safe token, e.g. a state, a nonce or a PKCE verifier.
+func RandomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// ChallengeS256 returns the S256 PKCE challenge of the verifier.
+func ChallengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,118 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"acsp/internal/oidc/oidctest"
+)
+
+var testUser = oidctest.User{
+	Subject:       "42",
+	Email:         "student@astanait.edu.kz",
+	EmailVerified: true,
+	Name:          "Student",
+	Groups:        []string{"students"},
+}
+
+// authorize follows the authorization URL and returns the query of the redirect to the client
+func authorize(t *testing.T, authURL string) url.Values {
+	client := &http.Client{CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+
+	resp, err := client.Get(authURL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, "/callback", location.Path)
+
+	return location.Query()
+}
+
+func newTestProvider(t *testing.T) (*Provider, *oidctest.Server) {
+	idp, err := oidctest.NewServer("acsp", "secret", testUser)
+	require.NoError(t, err)
+	t.Cleanup(idp.Close)
+
+	return NewProvider(Config{
+		Issuer:       idp.URL,
+		ClientID:     "acsp",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:8080/callback",
+		Scopes:       []string{"openid", "email", "profile"},
+		GroupsClaim:  "groups",
+	}, nil), idp
+}
+
+func TestProvider_CodeFlow(t *testing.T) {
+	ctx := context.Background()
+	p, _ := newTestProvider(t)
+
+	verifier, err := RandomToken()
+	require.NoError(t, err)
+
+	authURL, err := p.AuthCodeURL(ctx, "state-1", "nonce-1", verifier)
+	require.NoError(t, err)
+
+	q := authorize(t, authURL)
+	assert.Equal(t, "state-1", q.Get("state"))
+
+	idToken, err := p.Exchange(ctx, q.Get("code"), verifier)
+	require.NoError(t, err)
+
+	claims, err := p.Verify(ctx, idToken, "nonce-1")
+	require.NoError(t, err)
+	assert.Equal(t, Claims{
+		Subject:       "42",
+		Email:         "student@astanait.edu.kz",
+		EmailVerified: true,
+		Name:          "Student",
+		Groups:        []string{"students"},
+	}, claims)
+
+	_, err = p.Verify(ctx, idToken, "nonce-2")
+	assert.Error(t, err)
+
+	// A code is exchanged once
+	_, err = p.Exchange(ctx, q.Get("code"), verifier)
+	assert.Error(t, err)
+}
+
+func TestProvider_ExchangeWrongVerifier(t *testing.T) {
+	ctx := context.Background()
+	p, _ := newTestProvider(t)
+
+	authURL, err := p.AuthCodeURL(ctx, "state-1", "nonce-1", "verifier-1")
+	require.NoError(t, err)
+
+	q := authorize(t, authURL)
+
+	_, err = p.Exchange(ctx, q.Get("code"), "verifier-2")
+	assert.Error(t, err)
+}
+
+func TestProvider_VerifyForeignToken(t *testing.T) {
+	ctx := context.Background()
+	p, _ := newTestProvider(t)
+
+	// A token of another provider isn't signed by the keys of the provider
+	other, _ := newTestProvider(t)
+
+	authURL, err := other.AuthCodeURL(ctx, "state-1", "nonce-1", "verifier-1")
+	require.NoError(t, err)
+
+	idToken, err := other.Exchange(ctx, authorize(t, authURL).Get("code"), "verifier-1")
+	require.NoError(t, err)
+
+	_, err = p.Verify(ctx, idToken, "nonce-1")
+	assert.Error(t, err)
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"strconv"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -27,7 +26,7 @@ func NewArticlesRepository(db *sqlx.DB) *ArticlesDatabase {
 	}
 }
 
-func (a *ArticlesDatabase) Create(ctx context.Context, tx *sqlx.Tx, article *model.Article) error {
+func (a *ArticlesDatabase) Create(ctx context.Context, article *model.Article) error {
 	l := logging.LoggerFromContext(ctx).With(zap.Int("articleID", article.ID))
 
 	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
@@ -36,7 +35,7 @@ func (a *ArticlesDatabase) Create(ctx context.Context, tx *sqlx.Tx, article *mod
 	query := fmt.Sprintf(`INSERT INTO %s (user_id, topic, description) VALUES ($1, $2, $3) RETURNING id`,
 		constants.ArticlesTable)
 
-	stmt, err := tx.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, a.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
@@ -53,7 +52,7 @@ func (a *ArticlesDatabase) Create(ctx context.Context, tx *sqlx.Tx, article *mod
 	if err != nil {
 		l.Error("error when creating the article in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when executing query")
+		return dbError(err, "error when executing query")
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -78,7 +77,7 @@ func (a *ArticlesDatabase) Update(ctx context.Context, article model.Article) er
 	query := fmt.Sprintf(`UPDATE %s SET topic = $1, description = $2, updated_at = now() WHERE id = $3 AND user_id = $4`,
 		constants.ArticlesTable)
 
-	stmt, err := a.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, a.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
@@ -95,12 +94,12 @@ func (a *ArticlesDatabase) Update(ctx context.Context, article model.Article) er
 	if err != nil {
 		l.Error("Error when update the article in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when executing query")
+		return dbError(err, "error when executing query")
 	}
 
 	r, err := res.RowsAffected()
 	if err != nil {
-		return errors.Wrap(err, "error when get rows affected")
+		return dbError(err, "error when get rows affected")
 	}
 
 	if r == 0 {
@@ -111,7 +110,7 @@ func (a *ArticlesDatabase) Update(ctx context.Context, article model.Article) er
 }
 
 // UpdateImageURL updates the image url of the article
-func (a *ArticlesDatabase) UpdateImageURL(ctx context.Context, tx *sqlx.Tx, articleID int) error {
+func (a *ArticlesDatabase) UpdateImageURL(ctx context.Context, articleID int, imageURL string) error {
 	l := logging.LoggerFromContext(ctx).With(zap.Int("articleID", articleID))
 
 	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
@@ -120,7 +119,7 @@ func (a *ArticlesDatabase) UpdateImageURL(ctx context.Context, tx *sqlx.Tx, arti
 	query := fmt.Sprintf(`UPDATE %s SET image_url = $1, updated_at = now() WHERE id = $2`,
 		constants.ArticlesTable)
 
-	stmt, err := tx.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, a.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
@@ -133,16 +132,16 @@ func (a *ArticlesDatabase) UpdateImageURL(ctx context.Context, tx *sqlx.Tx, arti
 		}
 	}(stmt)
 
-	res, err := stmt.Exec("/"+strconv.Itoa(articleID), articleID)
+	res, err := stmt.Exec(imageURL, articleID)
 	if err != nil {
 		l.Error("Error when update the article in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when executing query")
+		return dbError(err, "error when executing query")
 	}
 
 	r, err := res.RowsAffected()
 	if err != nil {
-		return errors.Wrap(err, "error when get rows affected")
+		return dbError(err, "error when get rows affected")
 	}
 
 	if r == 0 {
@@ -160,11 +159,11 @@ func (a *ArticlesDatabase) Delete(ctx context.Context, userID int, articleID int
 	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
 	defer cancel()
 
-	stmt, err := a.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, a.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
-		return errors.Wrap(err, "error when preparing the query")
+		return dbError(err, "error when preparing the query")
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -177,12 +176,12 @@ func (a *ArticlesDatabase) Delete(ctx context.Context, userID int, articleID int
 	if err != nil {
 		l.Error("Error when delete the article in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when executing query")
+		return dbError(err, "error when executing query")
 	}
 
 	r, err := res.RowsAffected()
 	if err != nil {
-		return errors.Wrap(err, "error when get rows affected")
+		return dbError(err, "error when get rows affected")
 	}
 
 	if r == 0 {
@@ -211,11 +210,11 @@ func (a *ArticlesDatabase) GetAll(ctx context.Context) ([]model.Article, error)
 								a.image_url
 								FROM %s a`, constants.ArticlesTable)
 
-	err := a.db.SelectContext(ctx, &articles, query)
+	err := executor(ctx, a.db).SelectContext(ctx, &articles, query)
 	if err != nil {
 		l.Error("Error when get all articles", zap.Error(err))
 
-		return nil, errors.Wrap(err, "error when executing query")
+		return nil, dbError(err, "error when executing query")
 	}
 
 	return articles, nil
@@ -230,13 +229,9 @@ func (a *ArticlesDatabase) GetArticleByID(ctx context.Context, articleID int) (m
 	query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1",
 		constants.ArticlesTable)
 
-	err := a.db.GetContext(ctx, &article, query, articleID)
+	err := executor(ctx, a.db).GetContext(ctx, &article, query, articleID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return model.Article{}, nil
-		}
-
-		return model.Article{}, errors.Wrap(err, "error when get article by id")
+		return model.Article{}, dbError(err, "error when get article by id")
 	}
 
 	return article, nil
@@ -251,13 +246,9 @@ func (a *ArticlesDatabase) GetArticleByIDAndUserID(ctx context.Context, articleI
 	query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1 AND user_id = $2",
 		constants.ArticlesTable)
 
-	err := a.db.GetContext(ctx, &article, query, articleID, userID)
+	err := executor(ctx, a.db).GetContext(ctx, &article, query, articleID, userID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return model.Article{}, nil
-		}
-
-		return model.Article{}, errors.Wrap(err, "error when get article by id and user id")
+		return model.Article{}, dbError(err, "error when get article by id and user id")
 	}
 
 	return article, nil
@@ -272,11 +263,11 @@ func (a *ArticlesDatabase) GetAllByUserID(ctx context.Context, userID int) ([]mo
 
 	query := fmt.Sprintf("SELECT * FROM %s WHERE user_id = $1", constants.ArticlesTable)
 
-	err := a.db.SelectContext(ctx, &articles, query, userID)
+	err := executor(ctx, a.db).SelectContext(ctx, &articles, query, userID)
 	if err != nil {
 		l.Error("Error when get all articles by user id", zap.Error(err))
 
-		return nil, errors.Wrap(err, "error when executing query")
+		return nil, dbError(err, "error when executing query")
 	}
 
 	return articles, nil
@@ -291,7 +282,7 @@ func (a *ArticlesDatabase) CreateComment(ctx context.Context, articleID, userID
 	query := fmt.Sprintf("INSERT INTO %s (user_id, article_id, text) VALUES ($1, $2, $3) RETURNING id",
 		constants.ArticlesCommentsTable)
 
-	stmt, err := a.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, a.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
@@ -307,7 +298,7 @@ func (a *ArticlesDatabase) CreateComment(ctx context.Context, articleID, userID
 
 	r, err := res.RowsAffected()
 	if err != nil {
-		return errors.Wrap(err, "error when get rows affected")
+		return dbError(err, "error when get rows affected")
 	}
 
 	if r == 0 {
@@ -332,18 +323,18 @@ func (a *ArticlesDatabase) GetCommentsByArticleID(ctx context.Context, articleID
 		constants.ArticlesCommentsTable,
 		constants.UsersTable)
 
-	stmt, err := a.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, a.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
-		return []model.Comment{}, errors.Wrap(err, "error when preparing the query")
+		return []model.Comment{}, dbError(err, "error when preparing the query")
 	}
 
 	rows, err := stmt.QueryContext(ctx, articleID)
 	if err != nil {
 		l.Error("Error when executing query", zap.Error(err))
 
-		return []model.Comment{}, errors.Wrap(err, "error when executing query")
+		return []model.Comment{}, dbError(err, "error when executing query")
 	}
 
 	for rows.Next() {
@@ -365,7 +356,7 @@ func (a *ArticlesDatabase) GetCommentsByArticleID(ctx context.Context, articleID
 			&user.Name,
 			&user.Roles)
 		if err != nil {
-			return []model.Comment{}, errors.Wrap(err, "error when scanning row")
+			return []model.Comment{}, dbError(err, "error when scanning row")
 		}
 
 		comment.Author = user
@@ -390,7 +381,7 @@ func (a *ArticlesDatabase) ReplyToComment(ctx context.Context, articleID, userID
 	query := fmt.Sprintf("INSERT INTO %s (user_id, article_id, parent_id, text) VALUES ($1, $2, $3, $4) RETURNING id",
 		constants.ArticlesCommentsTable)
 
-	stmt, err := a.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, a.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
@@ -407,12 +398,12 @@ func (a *ArticlesDatabase) ReplyToComment(ctx context.Context, articleID, userID
 	if err != nil {
 		l.Error("Error when executing the query", zap.Error(err))
 
-		return errors.Wrap(err, "error occurred when executing the query")
+		return dbError(err, "error occurred when executing the query")
 	}
 
 	r, err := res.RowsAffected()
 	if err != nil {
-		return errors.Wrap(err, "error when get rows affected")
+		return dbError(err, "error when get rows affected")
 	}
 
 	if r == 0 {
@@ -453,9 +444,9 @@ func (a *ArticlesDatabase) GetRepliesByArticleIDAndCommentID(
 		constants.UsersTable,
 		constants.ArticlesTable)
 
-	stmt, err := a.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, a.db).PrepareContext(ctx, query)
 	if err != nil {
-		return []model.Comment{}, errors.Wrap(err, "error when preparing the query")
+		return []model.Comment{}, dbError(err, "error when preparing the query")
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -466,7 +457,7 @@ func (a *ArticlesDatabase) GetRepliesByArticleIDAndCommentID(
 
 	rows, err := stmt.QueryContext(ctx, articleID, parentCommentID)
 	if err != nil {
-		return nil, errors.Wrap(err, "error when executing query")
+		return nil, dbError(err, "error when executing query")
 	}
 	defer func(rows *sql.Rows) {
 		err := rows.Close()
@@ -494,7 +485,7 @@ func (a *ArticlesDatabase) GetRepliesByArticleIDAndCommentID(
 			&user.Name,
 			&user.Roles)
 		if err != nil {
-			return nil, errors.Wrap(err, "error when scanning row")
+			return nil, dbError(err, "error when scanning row")
 		}
 
 		comment.Author = user
@@ -505,6 +496,8 @@ func (a *ArticlesDatabase) GetRepliesByArticleIDAndCommentID(
 	return comments, nil
 }
 
+// UpvoteCommentByArticleIDAndCommentID updates the votes of the comment and saves the vote of the user,
+// it must be called within a transaction, see Transactional.
 func (a *ArticlesDatabase) UpvoteCommentByArticleIDAndCommentID(ctx context.Context, articleID, userID, commentID int) error {
 	l := logging.LoggerFromContext(ctx).With(
 		zap.Int("articleID", articleID),
@@ -512,100 +505,55 @@ func (a *ArticlesDatabase) UpvoteCommentByArticleIDAndCommentID(ctx context.Cont
 		zap.Int("commentID", commentID),
 	)
 
-	tx, err := a.db.Begin()
-	if err != nil {
-		l.Error("Error when beginning the transaction", zap.Error(err))
-
-		return errors.Wrap(err, "error occurred when beginning the transaction")
-	}
-
-	query := fmt.Sprintf(`UPDATE %s SET upvotes = upvotes + 1 WHERE id = $1 AND article_id = $2 AND user_id = $3;`,
+	query := fmt.Sprintf(`UPDATE %s SET upvote = upvote + 1 WHERE id = $1 AND article_id = $2 AND user_id = $3;`,
 		constants.ArticlesCommentsTable)
 
-	res, err := tx.Exec(query, commentID, articleID, userID)
+	res, err := executor(ctx, a.db).ExecContext(ctx, query, commentID, articleID, userID)
 	if err != nil {
 		l.Error("Error when executing the query", zap.Error(err))
 
-		err := tx.Rollback()
-		if err != nil {
-			return errors.Wrap(apperror.ErrRollback, "Error when rolling back")
-		}
-
-		return errors.Wrap(err, "error occurred when executing the query")
+		return dbError(err, "error occurred when executing the query")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting rows affected", zap.Error(err))
 
-		err := tx.Rollback()
-		if err != nil {
-			return errors.Wrap(apperror.ErrRollback, "Error when rolling back")
-		}
-
-		return errors.Wrap(err, "error occurred when getting rows affected")
+		return dbError(err, "error occurred when getting rows affected")
 	}
 
 	if rowsAffected < 1 {
-		err := tx.Rollback()
-		if err != nil {
-			return errors.Wrap(apperror.ErrRollback, "Error when rolling back")
-		}
-
 		return errors.Wrap(apperror.ErrUpvoteComment, "error occurred when up-voting the comment in database")
 	}
 
-	querySecond := fmt.Sprintf(`INSERT INTO %s (articleID, comment_id, user_id, vote_type) 
-														VALUES ($1, $2, $3, $4) 
+	querySecond := fmt.Sprintf(`INSERT INTO %s (comment_id, user_id, vote_type) 
+														VALUES ($1, $2, $3) 
 														RETURNING id`,
 		constants.ArticleCommentVotesTable)
 
-	res, err = tx.Exec(querySecond, articleID, commentID, userID, constants.UpvoteType)
+	res, err = executor(ctx, a.db).ExecContext(ctx, querySecond, commentID, userID, constants.UpvoteType)
 	if err != nil {
 		l.Error("Error when executing the query", zap.Error(err))
 
-		err := tx.Rollback()
-		if err != nil {
-			return errors.Wrap(apperror.ErrRollback, "Error when rolling back")
-		}
-
-		return errors.Wrap(err, "error occurred when executing the query")
+		return dbError(err, "error occurred when executing the query")
 	}
 
 	rowsAffected, err = res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting rows affected", zap.Error(err))
 
-		err := tx.Rollback()
-		if err != nil {
-			return errors.Wrap(apperror.ErrRollback, "Error when rolling back")
-		}
-
-		return errors.Wrap(err, "error occurred when getting rows affected")
+		return dbError(err, "error occurred when getting rows affected")
 	}
 
 	if rowsAffected < 1 {
-		err := tx.Rollback()
-		if err != nil {
-			return errors.Wrap(apperror.ErrRollback, "Error when rolling back")
-		}
-
 		return errors.Wrap(apperror.ErrUpvoteComment, "error occurred when inserting upvote of the comment in database")
 	}
 
-	err = tx.Commit()
-	if err != nil {
-		err := tx.Rollback()
-		if err != nil {
-			return errors.Wrap(apperror.ErrRollback, "Error when rolling back")
-		}
-
-		return errors.Wrap(err, "Error when committing the transaction")
-	}
-
 	return nil
 }
 
+// DownvoteCommentByArticleIDAndCommentID updates the votes of the comment and saves the vote of the user,
+// it must be called within a transaction, see Transactional.
 func (a *ArticlesDatabase) DownvoteCommentByArticleIDAndCommentID(ctx context.Context, articleID, userID, commentID int) error {
 	l := logging.LoggerFromContext(ctx).With(
 		zap.Int("articleID", articleID),
@@ -613,95 +561,48 @@ func (a *ArticlesDatabase) DownvoteCommentByArticleIDAndCommentID(ctx context.Co
 		zap.Int("commentID", commentID),
 	)
 
-	tx, err := a.db.Begin()
-	if err != nil {
-		l.Error("Error when beginning the transaction", zap.Error(err))
-
-		return errors.Wrap(err, "error occurred when beginning the transaction")
-	}
-
-	query := fmt.Sprintf(`UPDATE %s SET upvotes = upvotes - 1 WHERE id = $1 AND article_id = $2 AND user_id = $3;`,
+	query := fmt.Sprintf(`UPDATE %s SET downvote = downvote + 1 WHERE id = $1 AND article_id = $2 AND user_id = $3;`,
 		constants.ArticlesCommentsTable)
 
-	res, err := tx.Exec(query, commentID, articleID, userID)
+	res, err := executor(ctx, a.db).ExecContext(ctx, query, commentID, articleID, userID)
 	if err != nil {
 		l.Error("Error when executing the query", zap.Error(err))
 
-		err := tx.Rollback()
-		if err != nil {
-			return errors.Wrap(apperror.ErrRollback, "Error when rolling back")
-		}
-
-		return errors.Wrap(err, "error occurred when executing the query")
+		return dbError(err, "error occurred when executing the query")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting rows affected", zap.Error(err))
 
-		err := tx.Rollback()
-		if err != nil {
-			return errors.Wrap(apperror.ErrRollback, "Error when rolling back")
-		}
-
-		return errors.Wrap(err, "error occurred when getting rows affected")
+		return dbError(err, "error occurred when getting rows affected")
 	}
 
 	if rowsAffected < 1 {
-		err := tx.Rollback()
-		if err != nil {
-			return errors.Wrap(apperror.ErrRollback, "Error when rolling back")
-		}
-
 		return errors.Wrap(apperror.ErrDownvoteComment, "error occurred when down-voting the comment in database")
 	}
 
-	querySecond := fmt.Sprintf(`INSERT INTO %s (articleID, comment_id, user_id, vote_type) 
-														VALUES ($1, $2, $3, $4) 
+	querySecond := fmt.Sprintf(`INSERT INTO %s (comment_id, user_id, vote_type) 
+														VALUES ($1, $2, $3) 
 														RETURNING id`,
 		constants.ArticleCommentVotesTable)
 
-	res, err = tx.Exec(querySecond, articleID, commentID, userID, constants.DownvoteType)
+	res, err = executor(ctx, a.db).ExecContext(ctx, querySecond, commentID, userID, constants.DownvoteType)
 	if err != nil {
 		l.Error("Error when executing the query", zap.Error(err))
 
-		err := tx.Rollback()
-		if err != nil {
-			return errors.Wrap(apperror.ErrRollback, "Error when rolling back")
-		}
-
-		return errors.Wrap(err, "error occurred when executing the query")
+		return dbError(err, "error occurred when executing the query")
 	}
 
 	rowsAffected, err = res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting rows affected", zap.Error(err))
 
-		err := tx.Rollback()
-		if err != nil {
-			return errors.Wrap(apperror.ErrRollback, "Error when rolling back")
-		}
-
-		return errors.Wrap(err, "error occurred when getting rows affected")
+		return dbError(err, "error occurred when getting rows affected")
 	}
 
 	if rowsAffected < 1 {
-		err := tx.Rollback()
-		if err != nil {
-			return errors.Wrap(apperror.ErrRollback, "Error when rolling back")
-		}
-
-		return errors.Wrap(apperror.ErrUpvoteComment, "error occurred when inserting downvote of the comment in database")
-	}
-
-	err = tx.Commit()
-	if err != nil {
-		err := tx.Rollback()
-		if err != nil {
-			return errors.Wrap(apperror.ErrRollback, "Error when rolling back")
-		}
-
-		return errors.Wrap(err, "Error when committing the transaction")
+		return errors.Wrap(apperror.ErrDownvoteComment, "error occurred when inserting downvote of the comment in database")
 	}
 
 	return nil
@@ -716,9 +617,9 @@ func (a *ArticlesDatabase) GetVotesByArticleIDAndCommentID(ctx context.Context,
 	query := fmt.Sprintf("SELECT upvote, downvote FROM %s WHERE id = $1 AND article_id = $2",
 		constants.ArticlesCommentsTable)
 
-	err := a.db.QueryRowContext(ctx, query, commentID, articleID).Scan(&upvote, &downvote)
+	err := executor(ctx, a.db).QueryRowContext(ctx, query, commentID, articleID).Scan(&upvote, &downvote)
 	if err != nil {
-		return 0, errors.Wrap(err, "error occurred when executing the query")
+		return 0, dbError(err, "error occurred when executing the query")
 	}
 
 	return upvote - downvote, nil
@@ -733,9 +634,9 @@ func (a *ArticlesDatabase) HasUserVotedForComment(ctx context.Context, userID, c
 	query := fmt.Sprintf("SELECT id FROM %s WHERE user_id = $1 AND comment_id = $2",
 		constants.ArticleCommentVotesTable)
 
-	err := a.db.QueryRowContext(ctx, query, userID, commentID).Scan(&id)
+	err := executor(ctx, a.db).QueryRowContext(ctx, query, userID, commentID).Scan(&id)
 	if err != nil {
-		return false, errors.Wrap(err, "error occurred when executing the query")
+		return false, dbError(err, "error occurred when executing the query")
 	}
 
 	return id > 0, nil
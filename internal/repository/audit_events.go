@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	"acsp/internal/constants"
+	"acsp/internal/model"
+)
+
+const auditEventColumns = `id, actor_id, actor_token_id, action, target_type, target_id, changes, ip, request_id,
+	created_at`
+
+type AuditEventsDatabase struct {
+	db *sqlx.DB
+}
+
+func NewAuditEventsRepository(db *sqlx.DB) *AuditEventsDatabase {
+	return &AuditEventsDatabase{
+		db: db,
+	}
+}
+
+// Add records the event, it joins the transaction of the context if there is one.
+func (a *AuditEventsDatabase) Add(ctx context.Context, event model.AuditEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`INSERT INTO %s
+								(actor_id, actor_token_id, action, target_type, target_id, changes, ip, request_id)
+								VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		constants.AuditEventsTable)
+
+	_, err := executor(ctx, a.db).ExecContext(ctx, query,
+		event.ActorID, event.ActorTokenID, event.Action, event.TargetType, event.TargetID,
+		[]byte(event.Changes), event.IP, event.RequestID)
+	if err != nil {
+		return errors.Wrap(err, "error when executing query")
+	}
+
+	return nil
+}
+
+// Get returns the events matching the filter from the newest to the oldest, at most filter.Limit of them.
+func (a *AuditEventsDatabase) Get(ctx context.Context, filter model.AuditEventsFilter) ([]model.AuditEvent, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	var (
+		conditions []string
+		args       []interface{}
+	)
+
+	where := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.ActorID != nil {
+		where("actor_id = $%d", *filter.ActorID)
+	}
+
+	if filter.Action != "" {
+		where("action = $%d", filter.Action)
+	}
+
+	if filter.TargetType != "" {
+		where("target_type = $%d", filter.TargetType)
+	}
+
+	if filter.TargetID != "" {
+		where("target_id = $%d", filter.TargetID)
+	}
+
+	if filter.From != nil {
+		where("created_at >= $%d", *filter.From)
+	}
+
+	if filter.To != nil {
+		where("created_at < $%d", *filter.To)
+	}
+
+	if filter.Before > 0 {
+		where("id < $%d", filter.Before)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM %s`, auditEventColumns, constants.AuditEventsTable)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+
+	var events []model.AuditEvent
+
+	err := executor(ctx, a.db).SelectContext(ctx, &events, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when executing query")
+	}
+
+	return events, nil
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"strconv"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -42,8 +41,8 @@ func (r *UsersRepository) GetUserDetailsByUserId(ctx context.Context, id int) (*
 								FROM %s u WHERE u.user_id = $1`,
 		constants.UserDetailsTable)
 
-	err := r.db.
-		QueryRow(query, id).
+	err := executor(ctx, r.db).
+		QueryRowContext(ctx, query, id).
 		Scan(&userDetails.ID,
 			&userDetails.UserID,
 			&userDetails.FirstName,
@@ -55,23 +54,20 @@ func (r *UsersRepository) GetUserDetailsByUserId(ctx context.Context, id int) (*
 	if err != nil {
 		l.Error("Error getting user details by id from database", zap.Error(err))
 
-		return nil, apperror.ErrUserNotFound
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.ErrUserNotFound
+		}
+
+		return nil, dbError(err, "error when getting user details")
 	}
 
 	return &userDetails, nil
 }
 
+// CreateUser creates a user with the default role, it must be called within a transaction, see Transactional.
 func (r *UsersRepository) CreateUser(ctx context.Context, user model.User) error {
 	l := logging.LoggerFromContext(ctx).With(zap.String("email", user.Email))
 
-	// Begin a transaction to create a user and a role for the user
-	tx, err := r.db.Begin()
-	if err != nil {
-		l.Error("Error when beginning the transaction", zap.Error(err))
-
-		return errors.Wrap(err, "Error when beginning the transaction")
-	}
-
 	var userID int
 	query := fmt.Sprintf(
 		`INSERT INTO %s (name, email, password) 
@@ -79,81 +75,36 @@ func (r *UsersRepository) CreateUser(ctx context.Context, user model.User) error
   				RETURNING id`,
 		constants.UsersTable)
 
-	// Create a user
-	row := tx.QueryRow(query, user.Name, user.Email, user.Password)
-
-	// Get the user id
-	err = row.Scan(&userID)
+	// Create a user and get the user id
+	err := executor(ctx, r.db).QueryRowContext(ctx, query, user.Name, user.Email, user.Password).Scan(&userID)
 	if err != nil {
 		l.Error("Error when creating user in database", zap.Error(err))
 
-		return errors.Wrap(err, "Error when creating user in database")
-	}
-
-	// Rollback the transaction if the user id is less than 1
-	if userID < 1 {
-		err := tx.Rollback()
-		if err != nil {
-			return errors.Wrap(apperror.ErrRollback, "Error when rolling back")
-		}
+		return dbError(err, "Error when creating user in database")
 	}
 
 	querySecond := fmt.Sprintf(`INSERT INTO %s (user_id, role_id) 
 										VALUES ($1, $2)`, constants.UserRolesTable)
 
 	// Create a role for the user
-	res, err := tx.Exec(querySecond, userID, constants.DefaultUserRoleID)
+	res, err := executor(ctx, r.db).ExecContext(ctx, querySecond, userID, constants.DefaultUserRoleID)
 	if err != nil {
 		l.Error("Error when executing the query", zap.Error(err))
 
-		// Rollback the transaction if the user role id is less than 1
-		err := tx.Rollback()
-		if err != nil {
-			return errors.Wrap(apperror.ErrRollback, "Error when rolling back the transaction")
-		}
-
-		// Return the error
-		return errors.Wrap(err, "Error when executing the query")
+		return dbError(err, "Error when executing the query")
 	}
 
 	affected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting the affected rows", zap.Error(err))
 
-		// Rollback the transaction if the affected rows is less than 1
-		err := tx.Rollback()
-		if err != nil {
-			return errors.Wrap(apperror.ErrRollback, "Error when rolling back the transaction")
-		}
-
-		// Return the error
-		return errors.Wrap(err, "Error when getting the affected rows")
+		return dbError(err, "Error when getting the affected rows")
 	}
 
-	// Rollback the transaction if the affected rows is less than 1
 	if affected < 1 {
-		err := tx.Rollback()
-		if err != nil {
-			return errors.Wrap(apperror.ErrRollback, "Error when rolling back the transaction")
-		}
-
 		return errors.Wrap(apperror.ErrNoAffectedRows, "Error when creating user")
 	}
 
-	// Commit the transaction
-	err = tx.Commit()
-	if err != nil {
-		// Rollback the transaction if the commit fails
-		err := tx.Rollback()
-		if err != nil {
-			return errors.Wrap(apperror.ErrRollback, "Error when rolling back")
-		}
-
-		// Return the error
-		return errors.Wrap(err, "Error when committing the transaction")
-	}
-
-	// Return nil if the transaction is successful
 	return nil
 }
 
@@ -172,7 +123,7 @@ func (r *UsersRepository) UpdateDetails(ctx context.Context, userID int, u model
 								  WHERE user_id = $5`,
 		constants.UserDetailsTable)
 
-	stmt, err := r.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, r.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
@@ -220,11 +171,15 @@ func (r *UsersRepository) GetUser(ctx context.Context, email, password string) (
 	query := fmt.Sprintf(`SELECT * FROM %s WHERE email=$1 LIMIT 1`,
 		constants.UsersTable)
 
-	err := r.db.Get(&user, query, email)
+	err := executor(ctx, r.db).GetContext(ctx, &user, query, email)
 	if err != nil {
 		l.Error("Error when getting the user from database", zap.Error(err))
 
-		return &model.User{}, apperror.ErrUserNotFound
+		if errors.Is(err, sql.ErrNoRows) {
+			return &model.User{}, apperror.ErrUserNotFound
+		}
+
+		return &model.User{}, dbError(err, "error when getting the user")
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
@@ -259,17 +214,17 @@ func (r *UsersRepository) GetByID(ctx context.Context, id int) (model.User, erro
 									u.is_admin,
 									u.roles AS roles,
 									u.image_url,
-									ud.id,
-									ud.user_id,
-									ud.first_name,
-									ud.last_name,
-									ud.phone_number,
-									ud.specialization
-								FROM %s u INNER JOIN %s ud ON ud.user_id = u.id WHERE u.id=$1`,
+									COALESCE(ud.id::text, ''),
+									COALESCE(ud.user_id::text, ''),
+									COALESCE(ud.first_name, ''),
+									COALESCE(ud.last_name, ''),
+									COALESCE(ud.phone_number, ''),
+									COALESCE(ud.specialization, '')
+								FROM %s u LEFT JOIN %s ud ON ud.user_id = u.id WHERE u.id=$1`,
 		constants.UsersTable, constants.UserDetailsTable)
 
-	err := r.db.
-		QueryRow(query, id).
+	err := executor(ctx, r.db).
+		QueryRowContext(ctx, query, id).
 		Scan(&user.ID,
 			&user.Email,
 			&user.Name,
@@ -288,7 +243,11 @@ func (r *UsersRepository) GetByID(ctx context.Context, id int) (model.User, erro
 	if err != nil {
 		l.Error("Error getting user by id from database", zap.Error(err))
 
-		return model.User{}, apperror.ErrUserNotFound
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.User{}, apperror.ErrUserNotFound
+		}
+
+		return model.User{}, dbError(err, "error when getting the user")
 	}
 
 	user.UserInfo = &userDetails
@@ -307,11 +266,11 @@ func (r *UsersRepository) GetByEmail(ctx context.Context, email string) (*model.
 									u.created_at,	
 									u.updated_at,
 									u.is_admin,
-									ARRAY_AGG(r.name) AS roles,
+									u.roles AS roles,
 									u.image_url
 								FROM %s u WHERE email=$1`,
 		constants.UsersTable)
-	row := r.db.QueryRow(query, email)
+	row := executor(ctx, r.db).QueryRowContext(ctx, query, email)
 
 	err := row.Scan(&user.ID,
 		&user.Email,
@@ -322,10 +281,14 @@ func (r *UsersRepository) GetByEmail(ctx context.Context, email string) (*model.
 		&user.IsAdmin,
 		&user.Roles,
 		&user.ImageURL)
-	if err != nil {
+	if errors.Is(err, sql.ErrNoRows) {
 		return nil, errors.Wrap(apperror.ErrEmailNotFound, "email not found")
 	}
 
+	if err != nil {
+		return nil, dbError(err, "error when getting the user by email")
+	}
+
 	return &user, nil
 }
 
@@ -353,11 +316,11 @@ func (r *UsersRepository) GetAll(ctx context.Context) ([]model.User, error) {
 		constants.UsersTable,
 		constants.UserDetailsTable)
 
-	err := r.db.Select(&users, query)
+	err := executor(ctx, r.db).SelectContext(ctx, &users, query)
 	if err != nil {
 		l.Error("Error when getting users from database", zap.Error(err))
 
-		return nil, errors.Wrap(apperror.ErrUserNotFound, "user not found")
+		return nil, dbError(err, "error when getting the users")
 	}
 
 	return users, err
@@ -370,7 +333,7 @@ func (r *UsersRepository) ExistsUserByID(ctx context.Context, id int) (bool, err
 
 	query := fmt.Sprintf(`SELECT EXISTS(SELECT 1 FROM %s WHERE id=$1)`, constants.UsersTable)
 
-	row := r.db.QueryRow(query, id)
+	row := executor(ctx, r.db).QueryRowContext(ctx, query, id)
 
 	err := row.Scan(&isExists)
 	if err != nil {
@@ -389,7 +352,7 @@ func (r *UsersRepository) ExistsUserByEmail(ctx context.Context, email string) (
 
 	query := fmt.Sprintf(`SELECT EXISTS(SELECT 1 FROM %s WHERE email=$1)`, constants.UsersTable)
 
-	row := r.db.QueryRow(query, email)
+	row := executor(ctx, r.db).QueryRowContext(ctx, query, email)
 
 	err := row.Scan(&isExists)
 	if err != nil {
@@ -401,7 +364,7 @@ func (r *UsersRepository) ExistsUserByEmail(ctx context.Context, email string) (
 	return isExists, nil
 }
 
-func (r *UsersRepository) UpdateImageURL(ctx context.Context, userID int) error {
+func (r *UsersRepository) UpdateImageURL(ctx context.Context, userID int, imageURL string) error {
 	l := logging.LoggerFromContext(ctx).With(zap.Int("userID", userID))
 
 	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
@@ -410,7 +373,7 @@ func (r *UsersRepository) UpdateImageURL(ctx context.Context, userID int) error
 	query := fmt.Sprintf(`UPDATE %s SET image_url = $1, updated_at = now() WHERE id = $2`,
 		constants.UsersTable)
 
-	stmt, err := r.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, r.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
@@ -423,16 +386,16 @@ func (r *UsersRepository) UpdateImageURL(ctx context.Context, userID int) error
 		}
 	}(stmt)
 
-	res, err := stmt.Exec("/"+strconv.Itoa(userID), userID)
+	res, err := stmt.Exec(imageURL, userID)
 	if err != nil {
 		l.Error("Error when update the user's image url in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when executing query")
+		return dbError(err, "error when executing query")
 	}
 
 	rows, err := res.RowsAffected()
 	if err != nil {
-		return errors.Wrap(err, "error when get rows affected")
+		return dbError(err, "error when get rows affected")
 	}
 
 	if rows == 0 {
@@ -441,3 +404,30 @@ func (r *UsersRepository) UpdateImageURL(ctx context.Context, userID int) error
 
 	return nil
 }
+
+func (r *UsersRepository) Delete(ctx context.Context, userID int) error {
+	l := logging.LoggerFromContext(ctx).With(zap.Int("userID", userID))
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, constants.UsersTable)
+
+	res, err := executor(ctx, r.db).ExecContext(ctx, query, userID)
+	if err != nil {
+		l.Error("Error when deleting the user from database", zap.Error(err))
+
+		return dbError(err, "error when executing query")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return dbError(err, "error when get rows affected")
+	}
+
+	if rows == 0 {
+		return errors.Wrap(apperror.ErrUserNotFound, "error when deleting a user")
+	}
+
+	return nil
+}
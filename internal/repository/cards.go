@@ -8,7 +8,6 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
-	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
 	"acsp/internal/apperror"
@@ -39,7 +38,7 @@ func (c *CardsDatabase) Create(ctx context.Context, card model.Card) error {
 								 VALUES ($1, $2, $3, $4) RETURNING id`,
 		constants.CardsTable)
 
-	stmt, err := c.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, c.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
@@ -86,7 +85,7 @@ func (c *CardsDatabase) Update(ctx context.Context, card model.Card) error {
 								  WHERE id = $4 AND user_id = $5`,
 		constants.CardsTable)
 
-	stmt, err := c.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, c.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
@@ -131,7 +130,7 @@ func (c *CardsDatabase) Delete(ctx context.Context, userID int, cardID int) erro
 
 	query := fmt.Sprintf(`DELETE FROM %s WHERE id = $1 AND user_id = $2`, constants.CardsTable)
 
-	stmt, err := c.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, c.db).PrepareContext(ctx, query)
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
 		if err != nil {
@@ -171,7 +170,7 @@ func (c *CardsDatabase) GetByID(ctx context.Context, cardID int) (*model.Card, e
 
 	query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1", constants.CardsTable)
 
-	err := c.db.QueryRowContext(ctx, query, cardID).Scan(
+	err := executor(ctx, c.db).QueryRowContext(ctx, query, cardID).Scan(
 		&card.ID,
 		&card.UserID,
 		&card.Position,
@@ -180,18 +179,13 @@ func (c *CardsDatabase) GetByID(ctx context.Context, cardID int) (*model.Card, e
 		&card.CreatedAt,
 		&card.UpdatedAt)
 
-	switch {
-	case err == sql.ErrNoRows:
-		l.Error("Error when getting card by id", zap.Error(err))
-
-		return &model.Card{}, errors.Wrap(err, "Error when getting card by id")
-	case err != nil:
+	if err != nil {
 		l.Error("Error when getting card by id", zap.Error(err))
 
-		return &model.Card{}, errors.Wrap(err, "Error when getting card by id")
-	default:
-		return &card, nil
+		return &model.Card{}, dbError(err, "Error when getting card by id")
 	}
+
+	return &card, nil
 }
 
 func (c *CardsDatabase) GetByIdAndUserID(ctx context.Context, userID, cardID int) (model.Card, error) {
@@ -213,7 +207,7 @@ func (c *CardsDatabase) GetByIdAndUserID(ctx context.Context, userID, cardID int
 								FROM %s c WHERE id = $1 AND user_id = $2`,
 		constants.CardsTable)
 
-	err := c.db.QueryRowContext(ctx, query, cardID, userID).Scan(
+	err := executor(ctx, c.db).QueryRowContext(ctx, query, cardID, userID).Scan(
 		&card.ID,
 		&card.UserID,
 		&card.Position,
@@ -222,18 +216,13 @@ func (c *CardsDatabase) GetByIdAndUserID(ctx context.Context, userID, cardID int
 		&card.CreatedAt,
 		&card.UpdatedAt)
 
-	switch {
-	case err == sql.ErrNoRows:
-		l.Error("Error when getting card by id", zap.Error(err))
-
-		return model.Card{}, err
-	case err != nil:
+	if err != nil {
 		l.Error("Error when getting card by id", zap.Error(err))
 
-		return model.Card{}, errors.Wrap(err, "Error when getting card by id")
-	default:
-		return card, nil
+		return model.Card{}, dbError(err, "Error when getting card by id")
 	}
+
+	return card, nil
 }
 
 // GetAllByUserID gets all cards by user id.
@@ -247,11 +236,11 @@ func (c *CardsDatabase) GetAllByUserID(ctx context.Context, userID int) (*[]mode
 
 	query := fmt.Sprintf("SELECT * FROM %s WHERE user_id = $1", constants.CardsTable)
 
-	err := c.db.SelectContext(ctx, &cards, query, userID)
+	err := executor(ctx, c.db).SelectContext(ctx, &cards, query, userID)
 	if err != nil {
 		l.Error("Error when getting all cards by user id", zap.Error(err))
 
-		return nil, errors.Wrap(err, "Error when getting all cards by user id")
+		return nil, dbError(err, "Error when getting all cards by user id")
 	}
 
 	return &cards, nil
@@ -269,7 +258,7 @@ func (c *CardsDatabase) GetAll(ctx context.Context) ([]model.Card, error) {
 	query := fmt.Sprintf(`SELECT c.*, u.id, u.email, u.name, u.image_url FROM %s c INNER JOIN %s u ON c.user_id = u.id`,
 		constants.CardsTable, constants.UsersTable)
 
-	rows, err := c.db.QueryContext(ctx, query)
+	rows, err := executor(ctx, c.db).QueryContext(ctx, query)
 	if err != nil {
 		l.Error("Error when querying get all applicants in database", zap.Error(err))
 
@@ -308,8 +297,8 @@ func (c *CardsDatabase) GetAll(ctx context.Context) ([]model.Card, error) {
 	return cards, nil
 }
 
-// CreateInvitation creates an invitation in the database.
-func (c *CardsDatabase) CreateInvitation(ctx context.Context, inviterID int, card model.Card) error {
+// CreateInvitation creates an invitation in the database and returns its id.
+func (c *CardsDatabase) CreateInvitation(ctx context.Context, inviterID int, card model.Card) (int, error) {
 	l := logging.LoggerFromContext(ctx).With(zap.Int("inviterID", inviterID))
 
 	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
@@ -318,11 +307,11 @@ func (c *CardsDatabase) CreateInvitation(ctx context.Context, inviterID int, car
 	query := fmt.Sprintf(`INSERT INTO %s(card_id, inviter_id) VALUES ($1, $2) RETURNING id;`,
 		constants.CardInvitationsTable)
 
-	stmt, err := c.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, c.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
-		return err
+		return 0, err
 	}
 
 	defer func(stmt *sql.Stmt) {
@@ -332,23 +321,16 @@ func (c *CardsDatabase) CreateInvitation(ctx context.Context, inviterID int, car
 		}
 	}(stmt)
 
-	res, err := stmt.Exec(card.ID, inviterID)
-	if err != nil {
-		l.Error("Error when creating the card in database", zap.Error(err))
+	var id int
 
-		return err
-	}
-
-	rowsAffected, err := res.RowsAffected()
+	err = stmt.QueryRow(card.ID, inviterID).Scan(&id)
 	if err != nil {
-		return errors.Wrap(err, "Error when getting the number of rows affected")
-	}
+		l.Error("Error when creating the invitation in database", zap.Error(err))
 
-	if rowsAffected == 0 {
-		return errors.Wrap(apperror.ErrRowsAffected, "Error when creating the invitation")
+		return 0, dbError(err, "error when executing query")
 	}
 
-	return nil
+	return id, nil
 }
 
 // GetInvitationsByUserID gets all invitations by user id.
@@ -377,11 +359,11 @@ func (c *CardsDatabase) GetInvitationsByUserID(ctx context.Context, userID int)
 		constants.CardsTable,
 		constants.CardInvitationsTable)
 
-	rows, err := c.db.QueryContext(ctx, query, userID)
+	rows, err := executor(ctx, c.db).QueryContext(ctx, query, userID)
 	if err != nil {
 		l.Error("Error when querying get all applicants in database", zap.Error(err))
 
-		return nil, errors.Wrap(err, "Error when querying get all applicants in database")
+		return nil, dbError(err, "Error when querying get all applicants in database")
 	}
 
 	defer func(rows *sql.Rows) {
@@ -414,7 +396,7 @@ func (c *CardsDatabase) GetInvitationsByUserID(ctx context.Context, userID int)
 		if err != nil {
 			l.Error("Error when scanning the card in database", zap.Error(err))
 
-			return nil, errors.Wrap(err, "Error when scanning the card in database")
+			return nil, dbError(err, "Error when scanning the card in database")
 		}
 
 		invitationCard.Card = &card
@@ -447,7 +429,7 @@ func (c *CardsDatabase) GetInvitationsByCardID(ctx context.Context, cardID int)
 		constants.CardsTable,
 		constants.CardInvitationsTable)
 
-	rows, err := c.db.QueryContext(ctx, query, cardID)
+	rows, err := executor(ctx, c.db).QueryContext(ctx, query, cardID)
 	defer func(rows *sql.Rows) {
 		err := rows.Close()
 		if err != nil {
@@ -470,7 +452,7 @@ func (c *CardsDatabase) GetInvitationsByCardID(ctx context.Context, cardID int)
 			&invitationCard.Feedback,
 		)
 		if err != nil {
-			return nil, errors.Wrap(err, "Error when scanning the card in database")
+			return nil, dbError(err, "Error when scanning the card in database")
 		}
 
 		invitationCard.Card = &card
@@ -506,7 +488,7 @@ func (c *CardsDatabase) GetInvitationByID(ctx context.Context, userID, cardID, i
 		constants.CardsTable,
 		constants.CardInvitationsTable)
 
-	row := c.db.QueryRowContext(ctx, query, userID, cardID, invitationID)
+	row := executor(ctx, c.db).QueryRowContext(ctx, query, userID, cardID, invitationID)
 
 	err := row.Scan(
 		&card.ID,
@@ -545,7 +527,7 @@ func (c *CardsDatabase) AcceptCardInvitation(ctx context.Context, userID, cardID
 								  WHERE c.user_id = $3 AND c.id = $4 AND ci.id = $5`,
 		constants.CardInvitationsTable, constants.CardsTable)
 
-	stmt, err := c.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, c.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
@@ -597,7 +579,7 @@ func (c *CardsDatabase) DeclineCardInvitation(ctx context.Context, userID, cardI
 								  WHERE c.user_id = $3 AND c.id = $4 AND ci.id = $5`,
 		constants.CardInvitationsTable, constants.CardsTable)
 
-	stmt, err := c.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, c.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
@@ -655,7 +637,7 @@ func (c *CardsDatabase) GetResponsesByUserID(ctx context.Context, userID int) ([
 		constants.CardsTable,
 		constants.CardInvitationsTable)
 
-	rows, err := c.db.QueryContext(ctx, query, userID)
+	rows, err := executor(ctx, c.db).QueryContext(ctx, query, userID)
 	defer func(rows *sql.Rows) {
 		err := rows.Close()
 		if err != nil {
@@ -684,7 +666,7 @@ func (c *CardsDatabase) GetResponsesByUserID(ctx context.Context, userID int) ([
 			&invitationCard.Feedback,
 		)
 		if err != nil {
-			return nil, errors.Wrap(err, "Error when scanning the card in database")
+			return nil, dbError(err, "Error when scanning the card in database")
 		}
 
 		invitationCard.Card = &card
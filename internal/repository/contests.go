@@ -7,10 +7,8 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
-	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
-	"acsp/internal/apperror"
 	"acsp/internal/constants"
 	"acsp/internal/logging"
 	"acsp/internal/model"
@@ -26,7 +24,7 @@ func NewContestsRepository(db *sqlx.DB) *ContestsDatabase {
 	}
 }
 
-func (c *ContestsDatabase) Create(ctx context.Context, contest model.Contest) error {
+func (c *ContestsDatabase) Create(ctx context.Context, contest model.Contest) (int, error) {
 	l := logging.LoggerFromContext(ctx).With(zap.String("contestName", contest.Name))
 
 	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
@@ -34,14 +32,14 @@ func (c *ContestsDatabase) Create(ctx context.Context, contest model.Contest) er
 
 	query := fmt.Sprintf(`INSERT INTO %s 
 								(contest_name, description, link, start_date, end_date)
-								 VALUES ($1, $2, $3, $4) RETURNING rowsAffected`,
+								 VALUES ($1, $2, $3, $4, $5) RETURNING id`,
 		constants.ContestsTable)
 
-	stmt, err := c.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, c.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
-		return err
+		return 0, err
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -50,34 +48,22 @@ func (c *ContestsDatabase) Create(ctx context.Context, contest model.Contest) er
 		}
 	}(stmt)
 
-	res, err := stmt.Exec(
+	var id int
+
+	err = stmt.QueryRow(
 		contest.Name,
 		contest.Description,
 		contest.Link,
 		contest.StartDate,
 		contest.EndDate,
-	)
+	).Scan(&id)
 	if err != nil {
 		l.Error("Error when executing the contest creating statement", zap.Error(err))
 
-		return err
-	}
-
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
-		l.Error("Error when getting the rowsAffected of the creating contest query", zap.Error(err))
-
-		return err
-	}
-
-	if rowsAffected == 0 {
-		l.Error("Error when getting the rows affected of the creating contest query",
-			zap.Error(apperror.ErrCreatingContest))
-
-		return apperror.ErrCreatingContest
+		return 0, dbError(err, "error when executing the contest creating statement")
 	}
 
-	return nil
+	return id, nil
 }
 
 func (c *ContestsDatabase) Update(ctx context.Context, contest model.Contest) error {
@@ -89,17 +75,16 @@ func (c *ContestsDatabase) Update(ctx context.Context, contest model.Contest) er
 	query := fmt.Sprintf(`UPDATE %s SET contest_name = $1, 
 												description = $2, 
 												link = $3,
-												start_date = $4
-												end_date = $5, 
-												updated_at = now() 
+												start_date = $4,
+												end_date = $5
 											WHERE id = $6`,
-		constants.CodingLabProjectsTable)
+		constants.ContestsTable)
 
-	stmt, err := c.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, c.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
-		return errors.Wrap(err, "error when preparing the query")
+		return dbError(err, "error when preparing the query")
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -108,7 +93,7 @@ func (c *ContestsDatabase) Update(ctx context.Context, contest model.Contest) er
 		}
 	}(stmt)
 
-	res, err := stmt.ExecContext(ctx, query,
+	res, err := stmt.ExecContext(ctx,
 		contest.Name,
 		contest.Description,
 		contest.Link,
@@ -119,20 +104,20 @@ func (c *ContestsDatabase) Update(ctx context.Context, contest model.Contest) er
 	if err != nil {
 		l.Error("Error when update the contest in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when executing the query")
+		return dbError(err, "error when executing the query")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting the rows affected", zap.Error(err))
 
-		return errors.Wrap(err, "error when getting the rows affected")
+		return dbError(err, "error when getting the rows affected")
 	}
 
 	if rowsAffected < 1 {
 		l.Error("Error when updating the contest in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when updating the contest in database")
+		return dbError(err, "error when updating the contest in database")
 	}
 
 	return nil
@@ -146,9 +131,9 @@ func (c *ContestsDatabase) Delete(ctx context.Context, contestID int) error {
 
 	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", constants.ContestsTable)
 
-	stmt, err := c.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, c.db).PrepareContext(ctx, query)
 	if err != nil {
-		return errors.Wrap(err, "error when preparing the query")
+		return dbError(err, "error when preparing the query")
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -157,22 +142,22 @@ func (c *ContestsDatabase) Delete(ctx context.Context, contestID int) error {
 		}
 	}(stmt)
 
-	res, err := stmt.ExecContext(ctx, query, contestID)
+	res, err := stmt.ExecContext(ctx, contestID)
 	if err != nil {
-		return errors.Wrap(err, "error when executing the query")
+		return dbError(err, "error when executing the query")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting the rows affected", zap.Error(err))
 
-		return errors.Wrap(err, "error when getting the rows affected")
+		return dbError(err, "error when getting the rows affected")
 	}
 
 	if rowsAffected < 1 {
 		l.Error("Error when deleting the project in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when deleting the project in database")
+		return dbError(err, "error when deleting the project in database")
 	}
 
 	return nil
@@ -184,9 +169,9 @@ func (c *ContestsDatabase) GetByID(ctx context.Context, contestID int) (model.Co
 	query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1",
 		constants.ContestsTable)
 
-	err := c.db.Get(&contest, query, contestID)
+	err := executor(ctx, c.db).GetContext(ctx, &contest, query, contestID)
 	if err != nil {
-		return model.Contest{}, errors.Wrap(err, "error when executing the query")
+		return model.Contest{}, dbError(err, "error when executing the query")
 	}
 
 	return contest, nil
@@ -198,9 +183,9 @@ func (c *ContestsDatabase) GetAll(ctx context.Context) ([]model.Contest, error)
 	query := fmt.Sprintf("SELECT * FROM %s",
 		constants.ContestsTable)
 
-	err := c.db.Select(&contests, query)
+	err := executor(ctx, c.db).SelectContext(ctx, &contests, query)
 	if err != nil {
-		return nil, errors.Wrap(err, "error when getting the contest")
+		return nil, dbError(err, "error when getting the contest")
 	}
 
 	return contests, nil
@@ -7,10 +7,8 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
-	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
-	"acsp/internal/apperror"
 	"acsp/internal/constants"
 	"acsp/internal/logging"
 	"acsp/internal/model"
@@ -26,7 +24,7 @@ func NewCoursesRepository(db *sqlx.DB) *CoursesDatabase {
 	}
 }
 
-func (c *CoursesDatabase) Create(ctx context.Context, course model.Course) error {
+func (c *CoursesDatabase) Create(ctx context.Context, course model.Course) (int, error) {
 	l := logging.LoggerFromContext(ctx).With(zap.String("courseTitle", course.Title))
 
 	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
@@ -37,11 +35,11 @@ func (c *CoursesDatabase) Create(ctx context.Context, course model.Course) error
 								 VALUES ($1, $2, $3, $4) RETURNING id`,
 		constants.CoursesTable)
 
-	stmt, err := c.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, c.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
-		return err
+		return 0, err
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -50,32 +48,21 @@ func (c *CoursesDatabase) Create(ctx context.Context, course model.Course) error
 		}
 	}(stmt)
 
-	res, err := stmt.Exec(
+	var id int
+
+	err = stmt.QueryRow(
 		course.AuthorID,
 		course.Title,
 		course.Description,
 		course.Rating,
-	)
+	).Scan(&id)
 	if err != nil {
 		l.Error("Error when executing the course creating statement", zap.Error(err))
 
-		return errors.Wrap(err, "error when executing the course creating statement")
-	}
-
-	id, err := res.RowsAffected()
-	if err != nil {
-		l.Error("Error when getting the id of the course", zap.Error(err))
-
-		return errors.Wrap(err, "error when getting the id of the course")
+		return 0, dbError(err, "error when executing the course creating statement")
 	}
 
-	if id == 0 {
-		l.Error("Error when getting the id of the course", zap.Error(apperror.ErrCreatingCourse))
-
-		return apperror.ErrCreatingCourse
-	}
-
-	return nil
+	return id, nil
 }
 
 func (c *CoursesDatabase) Update(ctx context.Context, course model.Course) error {
@@ -88,11 +75,11 @@ func (c *CoursesDatabase) Update(ctx context.Context, course model.Course) error
 											WHERE id = $4`,
 		constants.CoursesTable)
 
-	stmt, err := c.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, c.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
-		return errors.Wrap(err, "error when preparing the query")
+		return dbError(err, "error when preparing the query")
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -101,7 +88,7 @@ func (c *CoursesDatabase) Update(ctx context.Context, course model.Course) error
 		}
 	}(stmt)
 
-	res, err := stmt.ExecContext(ctx, query,
+	res, err := stmt.ExecContext(ctx,
 		course.Title,
 		course.Description,
 		course.Rating,
@@ -110,20 +97,20 @@ func (c *CoursesDatabase) Update(ctx context.Context, course model.Course) error
 	if err != nil {
 		l.Error("Error when update the course in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when executing the query to update the course")
+		return dbError(err, "error when executing the query to update the course")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting the rows affected", zap.Error(err))
 
-		return errors.Wrap(err, "error when getting the rows affected")
+		return dbError(err, "error when getting the rows affected")
 	}
 
 	if rowsAffected < 1 {
 		l.Error("Error when updating the project in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when updating the course in database")
+		return dbError(err, "error when updating the course in database")
 	}
 
 	return nil
@@ -137,9 +124,9 @@ func (c *CoursesDatabase) Delete(ctx context.Context, courseID int) error {
 
 	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", constants.CoursesTable)
 
-	stmt, err := c.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, c.db).PrepareContext(ctx, query)
 	if err != nil {
-		return errors.Wrap(err, "error when preparing the query")
+		return dbError(err, "error when preparing the query")
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -148,22 +135,22 @@ func (c *CoursesDatabase) Delete(ctx context.Context, courseID int) error {
 		}
 	}(stmt)
 
-	res, err := stmt.ExecContext(ctx, query, courseID)
+	res, err := stmt.ExecContext(ctx, courseID)
 	if err != nil {
-		return errors.Wrap(err, "error when executing the query")
+		return dbError(err, "error when executing the query")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting the rows affected", zap.Error(err))
 
-		return errors.Wrap(err, "error when getting the rows affected")
+		return dbError(err, "error when getting the rows affected")
 	}
 
 	if rowsAffected < 1 {
 		l.Error("Error when deleting the project in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when deleting the course in database")
+		return dbError(err, "error when deleting the course in database")
 	}
 
 	return nil
@@ -178,9 +165,9 @@ func (c *CoursesDatabase) GetAll(ctx context.Context) ([]model.Course, error) {
 	query := fmt.Sprintf("SELECT * FROM %s",
 		constants.CoursesTable)
 
-	err := c.db.Select(&courses, query)
+	err := executor(ctx, c.db).SelectContext(ctx, &courses, query)
 	if err != nil {
-		return nil, errors.Wrap(err, "error when getting the courses")
+		return nil, dbError(err, "error when getting the courses")
 	}
 
 	return courses, nil
@@ -197,11 +184,11 @@ func (c *CoursesDatabase) GetByID(ctx context.Context, courseID int) (model.Cour
 	query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1",
 		constants.CodingLabProjectsTable)
 
-	err := c.db.GetContext(ctx, &course, query, courseID)
+	err := executor(ctx, c.db).GetContext(ctx, &course, query, courseID)
 	if err != nil {
 		l.Error("Error when getting the course", zap.Error(err))
 
-		return model.Course{}, errors.Wrap(err, "error when executing the query to get the course")
+		return model.Course{}, dbError(err, "error when executing the query to get the course")
 	}
 
 	return course, nil
@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
-	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
 	"acsp/internal/apperror"
@@ -39,7 +38,7 @@ func (c *CourseModuleLessonCommentsDatabase) Create(ctx context.Context, comment
 								 VALUES ($1, $2, $3) RETURNING id`,
 		constants.CourseLessonCommentsTable)
 
-	stmt, err := c.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, c.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
@@ -56,14 +55,14 @@ func (c *CourseModuleLessonCommentsDatabase) Create(ctx context.Context, comment
 	if err != nil {
 		l.Error("Error when executing the lesson comment creating statement", zap.Error(err))
 
-		return errors.Wrap(err, "error when executing the lesson comment creating statement")
+		return dbError(err, "error when executing the lesson comment creating statement")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting the rows affected of the lesson comment", zap.Error(err))
 
-		return errors.Wrap(err, "error when getting the affected of the lesson comment")
+		return dbError(err, "error when getting the affected of the lesson comment")
 	}
 
 	if rowsAffected == 0 {
@@ -86,11 +85,11 @@ func (c *CourseModuleLessonCommentsDatabase) Update(ctx context.Context, comment
 	query := fmt.Sprintf(`UPDATE %s SET text = $1, updated_at = NOW() WHERE id = $2`,
 		constants.CourseModuleLessonsTable)
 
-	stmt, err := c.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, c.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
-		return errors.Wrap(err, "error when preparing the query")
+		return dbError(err, "error when preparing the query")
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -103,20 +102,20 @@ func (c *CourseModuleLessonCommentsDatabase) Update(ctx context.Context, comment
 	if err != nil {
 		l.Error("Error when update the lesson comment in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when executing the query")
+		return dbError(err, "error when executing the query")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting the rows affected", zap.Error(err))
 
-		return errors.Wrap(err, "error when getting the rows affected")
+		return dbError(err, "error when getting the rows affected")
 	}
 
 	if rowsAffected < 1 {
 		l.Error("Error when updating the lesson comment in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when updating the lesson comment in database")
+		return dbError(err, "error when updating the lesson comment in database")
 	}
 
 	return nil
@@ -132,9 +131,9 @@ func (c *CourseModuleLessonCommentsDatabase) Delete(ctx context.Context, lessonI
 
 	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND lesson_id = $2", constants.CourseLessonCommentsTable)
 
-	stmt, err := c.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, c.db).PrepareContext(ctx, query)
 	if err != nil {
-		return errors.Wrap(err, "error when preparing the query")
+		return dbError(err, "error when preparing the query")
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -145,20 +144,20 @@ func (c *CourseModuleLessonCommentsDatabase) Delete(ctx context.Context, lessonI
 
 	res, err := stmt.ExecContext(ctx, query, commentID, lessonID)
 	if err != nil {
-		return errors.Wrap(err, "error when executing the query")
+		return dbError(err, "error when executing the query")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting the rows affected", zap.Error(err))
 
-		return errors.Wrap(err, "error when getting the rows affected")
+		return dbError(err, "error when getting the rows affected")
 	}
 
 	if rowsAffected < 1 {
 		l.Error("Error when deleting the module lesson in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when deleting the lesson module in database")
+		return dbError(err, "error when deleting the lesson module in database")
 	}
 
 	return nil
@@ -172,11 +171,11 @@ func (c *CourseModuleLessonCommentsDatabase) GetAllByLessonId(ctx context.Contex
 	query := fmt.Sprintf("SELECT * FROM %s WHERE lesson_id = $1",
 		constants.CourseLessonCommentsTable)
 
-	err := c.db.Select(&comments, query, lessonID)
+	err := executor(ctx, c.db).SelectContext(ctx, &comments, query, lessonID)
 	if err != nil {
 		l.Error("Error when getting the lesson's comments", zap.Error(err))
 
-		return nil, errors.Wrap(err, "error when getting the lesson comments")
+		return nil, dbError(err, "error when getting the lesson comments")
 	}
 
 	return comments, nil
@@ -193,11 +192,11 @@ func (c *CourseModuleLessonCommentsDatabase) GetByID(ctx context.Context, commen
 	query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1",
 		constants.CourseModuleLessonsTable)
 
-	err := c.db.GetContext(ctx, &comment, query, commentID)
+	err := executor(ctx, c.db).GetContext(ctx, &comment, query, commentID)
 	if err != nil {
 		l.Error("Error when getting the comment", zap.Error(err))
 
-		return model.CourseModuleLessonComment{}, errors.Wrap(err, "error when executing the query")
+		return model.CourseModuleLessonComment{}, dbError(err, "error when executing the query")
 	}
 
 	return comment, nil
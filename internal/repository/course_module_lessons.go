@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
-	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
 	"acsp/internal/apperror"
@@ -39,7 +38,7 @@ func (c *CourseModuleLessonsDatabase) Create(ctx context.Context, lesson model.C
 								 VALUES ($1, $2, $3, $4) RETURNING id`,
 		constants.CourseModuleLessonsTable)
 
-	stmt, err := c.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, c.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
@@ -56,14 +55,14 @@ func (c *CourseModuleLessonsDatabase) Create(ctx context.Context, lesson model.C
 	if err != nil {
 		l.Error("Error when executing the module lesson creating statement", zap.Error(err))
 
-		return errors.Wrap(err, "error when executing the module lesson creating statement")
+		return dbError(err, "error when executing the module lesson creating statement")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting the rows affected of the module lesson", zap.Error(err))
 
-		return errors.Wrap(err, "error when getting the affected of the module lesson")
+		return dbError(err, "error when getting the affected of the module lesson")
 	}
 
 	if rowsAffected == 0 {
@@ -87,11 +86,11 @@ func (c *CourseModuleLessonsDatabase) Update(ctx context.Context, lesson model.C
 								 updated_at = NOW() WHERE id = $4`,
 		constants.CourseModuleLessonsTable)
 
-	stmt, err := c.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, c.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
-		return errors.Wrap(err, "error when preparing the query")
+		return dbError(err, "error when preparing the query")
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -104,20 +103,20 @@ func (c *CourseModuleLessonsDatabase) Update(ctx context.Context, lesson model.C
 	if err != nil {
 		l.Error("Error when update the module lesson in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when executing the query")
+		return dbError(err, "error when executing the query")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting the rows affected", zap.Error(err))
 
-		return errors.Wrap(err, "error when getting the rows affected")
+		return dbError(err, "error when getting the rows affected")
 	}
 
 	if rowsAffected < 1 {
 		l.Error("Error when updating the module lesson in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when updating the module lesson in database")
+		return dbError(err, "error when updating the module lesson in database")
 	}
 
 	return nil
@@ -133,9 +132,9 @@ func (c *CourseModuleLessonsDatabase) Delete(ctx context.Context, lessonID, modu
 
 	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND module_Id = $2", constants.CourseModuleLessonsTable)
 
-	stmt, err := c.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, c.db).PrepareContext(ctx, query)
 	if err != nil {
-		return errors.Wrap(err, "error when preparing the query")
+		return dbError(err, "error when preparing the query")
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -146,20 +145,20 @@ func (c *CourseModuleLessonsDatabase) Delete(ctx context.Context, lessonID, modu
 
 	res, err := stmt.ExecContext(ctx, query, lessonID, moduleID)
 	if err != nil {
-		return errors.Wrap(err, "error when executing the query")
+		return dbError(err, "error when executing the query")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting the rows affected", zap.Error(err))
 
-		return errors.Wrap(err, "error when getting the rows affected")
+		return dbError(err, "error when getting the rows affected")
 	}
 
 	if rowsAffected < 1 {
 		l.Error("Error when deleting the module lesson in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when deleting the lesson module in database")
+		return dbError(err, "error when deleting the lesson module in database")
 	}
 
 	return nil
@@ -173,11 +172,11 @@ func (c *CourseModuleLessonsDatabase) GetAllByModuleId(ctx context.Context, modu
 	query := fmt.Sprintf("SELECT * FROM %s WHERE module_id = $1",
 		constants.CourseModuleLessonsTable)
 
-	err := c.db.Select(&modules, query, moduleID)
+	err := executor(ctx, c.db).SelectContext(ctx, &modules, query, moduleID)
 	if err != nil {
 		l.Error("Error when getting the module's lessons", zap.Error(err))
 
-		return nil, errors.Wrap(err, "error when getting the module lessons")
+		return nil, dbError(err, "error when getting the module lessons")
 	}
 
 	return modules, nil
@@ -194,11 +193,11 @@ func (c *CourseModuleLessonsDatabase) GetByID(ctx context.Context, lessonID int)
 	query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1",
 		constants.CourseModuleLessonsTable)
 
-	err := c.db.GetContext(ctx, &lesson, query, lessonID)
+	err := executor(ctx, c.db).GetContext(ctx, &lesson, query, lessonID)
 	if err != nil {
 		l.Error("Error when getting the module lesson", zap.Error(err))
 
-		return model.CourseModuleLesson{}, errors.Wrap(err, "error when executing the query")
+		return model.CourseModuleLesson{}, dbError(err, "error when executing the query")
 	}
 
 	return lesson, nil
@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
-	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
 	"acsp/internal/apperror"
@@ -39,7 +38,7 @@ func (c *CourseModulesDatabase) Create(ctx context.Context, module model.CourseM
 								 VALUES ($1, $2, $3) RETURNING id`,
 		constants.CourseModulesTable)
 
-	stmt, err := c.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, c.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
@@ -56,14 +55,14 @@ func (c *CourseModulesDatabase) Create(ctx context.Context, module model.CourseM
 	if err != nil {
 		l.Error("Error when executing the course module creating statement", zap.Error(err))
 
-		return errors.Wrap(err, "error when executing the course module creating statement")
+		return dbError(err, "error when executing the course module creating statement")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting the rows affected of the course module", zap.Error(err))
 
-		return errors.Wrap(err, "error when getting the affected of the course module")
+		return dbError(err, "error when getting the affected of the course module")
 	}
 
 	if rowsAffected == 0 {
@@ -85,11 +84,11 @@ func (c *CourseModulesDatabase) Update(ctx context.Context, module model.CourseM
 											WHERE id = $3`,
 		constants.CourseModulesTable)
 
-	stmt, err := c.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, c.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
-		return errors.Wrap(err, "error when preparing the query")
+		return dbError(err, "error when preparing the query")
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -102,20 +101,20 @@ func (c *CourseModulesDatabase) Update(ctx context.Context, module model.CourseM
 	if err != nil {
 		l.Error("Error when update the course module in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when executing the query")
+		return dbError(err, "error when executing the query")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting the rows affected", zap.Error(err))
 
-		return errors.Wrap(err, "error when getting the rows affected")
+		return dbError(err, "error when getting the rows affected")
 	}
 
 	if rowsAffected < 1 {
 		l.Error("Error when updating the course module in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when updating the course module in database")
+		return dbError(err, "error when updating the course module in database")
 	}
 
 	return nil
@@ -131,9 +130,9 @@ func (c *CourseModulesDatabase) Delete(ctx context.Context, courseID, moduleID i
 
 	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND course_id = $2", constants.CourseModulesTable)
 
-	stmt, err := c.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, c.db).PrepareContext(ctx, query)
 	if err != nil {
-		return errors.Wrap(err, "error when preparing the query")
+		return dbError(err, "error when preparing the query")
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -144,20 +143,20 @@ func (c *CourseModulesDatabase) Delete(ctx context.Context, courseID, moduleID i
 
 	res, err := stmt.ExecContext(ctx, query, moduleID, courseID)
 	if err != nil {
-		return errors.Wrap(err, "error when executing the query")
+		return dbError(err, "error when executing the query")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting the rows affected", zap.Error(err))
 
-		return errors.Wrap(err, "error when getting the rows affected")
+		return dbError(err, "error when getting the rows affected")
 	}
 
 	if rowsAffected < 1 {
 		l.Error("Error when deleting the course module in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when deleting the course module in database")
+		return dbError(err, "error when deleting the course module in database")
 	}
 
 	return nil
@@ -168,14 +167,14 @@ func (c *CourseModulesDatabase) GetAllByCourseID(ctx context.Context, courseID i
 
 	var modules []model.CourseModule
 
-	query := fmt.Sprintf("SELECT * FROM %s WHERE project_id = $1",
+	query := fmt.Sprintf("SELECT * FROM %s WHERE course_id = $1",
 		constants.CourseModulesTable)
 
-	err := c.db.Select(&modules, query, courseID)
+	err := executor(ctx, c.db).SelectContext(ctx, &modules, query, courseID)
 	if err != nil {
 		l.Error("Error when getting the course's modules", zap.Error(err))
 
-		return nil, errors.Wrap(err, "error when getting the course modules")
+		return nil, dbError(err, "error when getting the course modules")
 	}
 
 	return modules, nil
@@ -192,11 +191,11 @@ func (c *CourseModulesDatabase) GetByID(ctx context.Context, moduleID int) (mode
 	query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1",
 		constants.CourseModulesTable)
 
-	err := c.db.GetContext(ctx, &module, query, moduleID)
+	err := executor(ctx, c.db).GetContext(ctx, &module, query, moduleID)
 	if err != nil {
 		l.Error("Error when getting the course module", zap.Error(err))
 
-		return model.CourseModule{}, errors.Wrap(err, "error when executing the query")
+		return model.CourseModule{}, dbError(err, "error when executing the query")
 	}
 
 	return module, nil
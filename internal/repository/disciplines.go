@@ -7,10 +7,8 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
-	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
-	"acsp/internal/apperror"
 	"acsp/internal/constants"
 	"acsp/internal/logging"
 	"acsp/internal/model"
@@ -26,7 +24,7 @@ func NewDisciplinesRepository(db *sqlx.DB) *DisciplinesDatabase {
 	}
 }
 
-func (d *DisciplinesDatabase) Create(ctx context.Context, discipline model.Discipline) error {
+func (d *DisciplinesDatabase) Create(ctx context.Context, discipline model.Discipline) (int, error) {
 	l := logging.LoggerFromContext(ctx).With(zap.String(
 		"disciplineTitle", discipline.Title),
 	)
@@ -36,14 +34,14 @@ func (d *DisciplinesDatabase) Create(ctx context.Context, discipline model.Disci
 
 	query := fmt.Sprintf(`INSERT INTO %s 
 								(title, description)
-								 VALUES ($1, $2)`,
+								 VALUES ($1, $2) RETURNING id`,
 		constants.CodingLabDisciplinesTable)
 
-	stmt, err := d.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, d.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
-		return err
+		return 0, err
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -52,27 +50,16 @@ func (d *DisciplinesDatabase) Create(ctx context.Context, discipline model.Disci
 		}
 	}(stmt)
 
-	res, err := stmt.Exec(discipline.Title, discipline.Description)
-	if err != nil {
-		l.Error("Error when executing the discipline creating statement", zap.Error(err))
+	var id int
 
-		return err
-	}
-
-	rowsAffected, err := res.RowsAffected()
+	err = stmt.QueryRow(discipline.Title, discipline.Description).Scan(&id)
 	if err != nil {
-		l.Error("Error when getting the rowsAffected of the discipline", zap.Error(err))
-
-		return err
-	}
-
-	if rowsAffected == 0 {
-		l.Error("Error when getting the rowsAffected of the project", zap.Error(apperror.ErrCreatingDiscipline))
+		l.Error("Error when executing the discipline creating statement", zap.Error(err))
 
-		return apperror.ErrCreatingDiscipline
+		return 0, dbError(err, "error when executing the discipline creating statement")
 	}
 
-	return nil
+	return id, nil
 }
 
 func (d *DisciplinesDatabase) Update(ctx context.Context, discipline model.Discipline) error {
@@ -85,11 +72,11 @@ func (d *DisciplinesDatabase) Update(ctx context.Context, discipline model.Disci
 											WHERE id = $4`,
 		constants.CodingLabDisciplinesTable)
 
-	stmt, err := d.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, d.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
-		return errors.Wrap(err, "error when preparing the query")
+		return dbError(err, "error when preparing the query")
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -98,7 +85,7 @@ func (d *DisciplinesDatabase) Update(ctx context.Context, discipline model.Disci
 		}
 	}(stmt)
 
-	res, err := stmt.ExecContext(ctx, query,
+	res, err := stmt.ExecContext(ctx,
 		discipline.Title,
 		discipline.Description,
 		discipline.ImageURL,
@@ -107,20 +94,20 @@ func (d *DisciplinesDatabase) Update(ctx context.Context, discipline model.Disci
 	if err != nil {
 		l.Error("Error when update the discipline in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when executing the query")
+		return dbError(err, "error when executing the query")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting the rows affected", zap.Error(err))
 
-		return errors.Wrap(err, "error when getting the rows affected")
+		return dbError(err, "error when getting the rows affected")
 	}
 
 	if rowsAffected < 1 {
 		l.Error("Error when updating the discipline in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when updating the discipline in database")
+		return dbError(err, "error when updating the discipline in database")
 	}
 
 	return nil
@@ -134,9 +121,9 @@ func (d *DisciplinesDatabase) Delete(ctx context.Context, disciplineID int) erro
 
 	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", constants.CodingLabDisciplinesTable)
 
-	stmt, err := d.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, d.db).PrepareContext(ctx, query)
 	if err != nil {
-		return errors.Wrap(err, "error when preparing the query")
+		return dbError(err, "error when preparing the query")
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -145,22 +132,22 @@ func (d *DisciplinesDatabase) Delete(ctx context.Context, disciplineID int) erro
 		}
 	}(stmt)
 
-	res, err := stmt.ExecContext(ctx, query, disciplineID)
+	res, err := stmt.ExecContext(ctx, disciplineID)
 	if err != nil {
-		return errors.Wrap(err, "error when executing the query")
+		return dbError(err, "error when executing the query")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting the rows affected", zap.Error(err))
 
-		return errors.Wrap(err, "error when getting the rows affected")
+		return dbError(err, "error when getting the rows affected")
 	}
 
 	if rowsAffected < 1 {
 		l.Error("Error when deleting the project module in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when deleting the project module in database")
+		return dbError(err, "error when deleting the project module in database")
 	}
 
 	return nil
@@ -174,11 +161,11 @@ func (d *DisciplinesDatabase) GetByID(ctx context.Context, disciplineID int) (mo
 
 	query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1", constants.CodingLabDisciplinesTable)
 
-	stmt, err := d.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, d.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
-		return model.Discipline{}, errors.Wrap(err, "error when preparing the query")
+		return model.Discipline{}, dbError(err, "error when preparing the query")
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -199,7 +186,7 @@ func (d *DisciplinesDatabase) GetByID(ctx context.Context, disciplineID int) (mo
 	if err != nil {
 		l.Error("Error when scanning the discipline", zap.Error(err))
 
-		return model.Discipline{}, errors.Wrap(err, "error when scanning the discipline")
+		return model.Discipline{}, dbError(err, "error when scanning the discipline")
 	}
 
 	return discipline, nil
@@ -213,11 +200,11 @@ func (d *DisciplinesDatabase) GetAll(ctx context.Context) ([]model.Discipline, e
 	query := fmt.Sprintf("SELECT * FROM %s",
 		constants.CodingLabDisciplinesTable)
 
-	err := d.db.Select(&disciplines, query)
+	err := executor(ctx, d.db).SelectContext(ctx, &disciplines, query)
 	if err != nil {
 		l.Error("Error when getting the disciplines", zap.Error(err))
 
-		return nil, errors.Wrap(err, "error when getting the disciplines")
+		return nil, dbError(err, "error when getting the disciplines")
 	}
 
 	return disciplines, nil
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"acsp/internal/apperror"
+)
+
+// uniqueViolation is the code of the postgres error raised on a duplicate key
+const uniqueViolation = "23505"
+
+// dbError wraps the error of a query with the message. No rows are translated into a not found error
+// and duplicate keys into a conflict, so that the clients get 404 and 409 rather than 500.
+func dbError(err error, message string) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return apperror.WithKind(errors.Wrap(err, message), apperror.KindNotFound)
+	case isUniqueViolation(err):
+		return apperror.WithKind(errors.Wrap(err, message), apperror.KindConflict)
+	default:
+		return errors.Wrap(err, message)
+	}
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
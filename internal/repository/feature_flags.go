@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"acsp/internal/apperror"
+	"acsp/internal/constants"
+	"acsp/internal/logging"
+	"acsp/internal/model"
+)
+
+type FeatureFlagsDatabase struct {
+	db *sqlx.DB
+}
+
+func NewFeatureFlagsRepository(db *sqlx.DB) *FeatureFlagsDatabase {
+	return &FeatureFlagsDatabase{
+		db: db,
+	}
+}
+
+// GetAll returns all feature flags ordered by key.
+func (f *FeatureFlagsDatabase) GetAll(ctx context.Context) ([]model.FeatureFlag, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`SELECT key, description, enabled, roles, user_ids, percentage, created_at, updated_at 
+								FROM %s ORDER BY key`,
+		constants.FeatureFlagsTable)
+
+	flags := []model.FeatureFlag{}
+
+	err := executor(ctx, f.db).SelectContext(ctx, &flags, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when executing query")
+	}
+
+	return flags, nil
+}
+
+// GetByKey returns the feature flag, apperror.ErrFeatureFlagNotFound when there is no such flag.
+func (f *FeatureFlagsDatabase) GetByKey(ctx context.Context, key string) (model.FeatureFlag, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`SELECT key, description, enabled, roles, user_ids, percentage, created_at, updated_at 
+								FROM %s WHERE key = $1`,
+		constants.FeatureFlagsTable)
+
+	var flag model.FeatureFlag
+
+	err := executor(ctx, f.db).GetContext(ctx, &flag, query, key)
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return model.FeatureFlag{}, apperror.ErrFeatureFlagNotFound
+	case err != nil:
+		return model.FeatureFlag{}, errors.Wrap(err, "error when executing query")
+	default:
+		return flag, nil
+	}
+}
+
+// Create creates the feature flag, apperror.ErrFeatureFlagExists when the key is taken.
+func (f *FeatureFlagsDatabase) Create(ctx context.Context, flag model.FeatureFlag) error {
+	l := logging.LoggerFromContext(ctx).With(zap.String("key", flag.Key))
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`INSERT INTO %s (key, description, enabled, roles, user_ids, percentage) 
+								VALUES ($1, $2, $3, $4, $5, $6)`,
+		constants.FeatureFlagsTable)
+
+	_, err := executor(ctx, f.db).ExecContext(ctx, query,
+		flag.Key, flag.Description, flag.Enabled, pq.Array(flag.Roles), pq.Array(flag.UserIDs), flag.Percentage)
+
+	if isUniqueViolation(err) {
+		return apperror.ErrFeatureFlagExists
+	}
+
+	if err != nil {
+		l.Error("Error when creating the feature flag", zap.Error(err))
+
+		return errors.Wrap(err, "error when executing query")
+	}
+
+	return nil
+}
+
+// Update replaces the rules of the feature flag, apperror.ErrFeatureFlagNotFound when there is no such flag.
+func (f *FeatureFlagsDatabase) Update(ctx context.Context, flag model.FeatureFlag) error {
+	l := logging.LoggerFromContext(ctx).With(zap.String("key", flag.Key))
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`UPDATE %s 
+								SET description = $2, enabled = $3, roles = $4, user_ids = $5, percentage = $6, 
+								    updated_at = now() 
+								WHERE key = $1`,
+		constants.FeatureFlagsTable)
+
+	res, err := executor(ctx, f.db).ExecContext(ctx, query,
+		flag.Key, flag.Description, flag.Enabled, pq.Array(flag.Roles), pq.Array(flag.UserIDs), flag.Percentage)
+	if err != nil {
+		l.Error("Error when updating the feature flag", zap.Error(err))
+
+		return errors.Wrap(err, "error when executing query")
+	}
+
+	return flagAffected(res)
+}
+
+// Delete deletes the feature flag, apperror.ErrFeatureFlagNotFound when there is no such flag.
+func (f *FeatureFlagsDatabase) Delete(ctx context.Context, key string) error {
+	l := logging.LoggerFromContext(ctx).With(zap.String("key", key))
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE key = $1`, constants.FeatureFlagsTable)
+
+	res, err := executor(ctx, f.db).ExecContext(ctx, query, key)
+	if err != nil {
+		l.Error("Error when deleting the feature flag", zap.Error(err))
+
+		return errors.Wrap(err, "error when executing query")
+	}
+
+	return flagAffected(res)
+}
+
+func flagAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "error when getting affected rows")
+	}
+
+	if n == 0 {
+		return apperror.ErrFeatureFlagNotFound
+	}
+
+	return nil
+}
+
+type FeatureFlagsCacheRedis struct {
+	redis *redis.Client
+}
+
+func NewFeatureFlagsCacheRepository(r *redis.Client) *FeatureFlagsCacheRedis {
+	return &FeatureFlagsCacheRedis{
+		redis: r,
+	}
+}
+
+// Get returns the cached feature flags, found is false when they aren't cached.
+func (f *FeatureFlagsCacheRedis) Get(ctx context.Context) ([]model.FeatureFlag, bool, error) {
+	b, err := f.redis.Get(ctx, constants.FeatureFlagsKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, errors.Wrap(err, "error when getting the cached feature flags")
+	}
+
+	var flags []model.FeatureFlag
+
+	err = json.Unmarshal(b, &flags)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "error when decoding the cached feature flags")
+	}
+
+	return flags, true, nil
+}
+
+// Set caches the feature flags for ttl.
+func (f *FeatureFlagsCacheRedis) Set(ctx context.Context, flags []model.FeatureFlag, ttl time.Duration) error {
+	b, err := json.Marshal(flags)
+	if err != nil {
+		return errors.Wrap(err, "error when encoding the feature flags")
+	}
+
+	err = f.redis.Set(ctx, constants.FeatureFlagsKey, b, ttl).Err()
+	if err != nil {
+		return errors.Wrap(err, "error when caching the feature flags")
+	}
+
+	return nil
+}
+
+// Invalidate removes the cached feature flags, so that the next read loads them from the database.
+func (f *FeatureFlagsCacheRedis) Invalidate(ctx context.Context) error {
+	err := f.redis.Del(ctx, constants.FeatureFlagsKey).Err()
+	if err != nil {
+		return errors.Wrap(err, "error when invalidating the cached feature flags")
+	}
+
+	return nil
+}
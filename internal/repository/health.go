@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v9"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+type HealthRepository struct {
+	db    *sqlx.DB
+	redis *redis.Client
+}
+
+func NewHealthRepository(db *sqlx.DB, r *redis.Client) *HealthRepository {
+	return &HealthRepository{
+		db:    db,
+		redis: r,
+	}
+}
+
+// PingDatabase checks the connection to postgres.
+func (h *HealthRepository) PingDatabase(ctx context.Context) error {
+	err := h.db.PingContext(ctx)
+	if err != nil {
+		return errors.Wrap(err, "error when pinging postgres")
+	}
+
+	return nil
+}
+
+// PingCache checks the connection to redis.
+func (h *HealthRepository) PingCache(ctx context.Context) error {
+	err := h.redis.Ping(ctx).Err()
+	if err != nil {
+		return errors.Wrap(err, "error when pinging redis")
+	}
+
+	return nil
+}
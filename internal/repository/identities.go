@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"acsp/internal/constants"
+	"acsp/internal/logging"
+	"acsp/internal/model"
+)
+
+type IdentitiesDatabase struct {
+	db *sqlx.DB
+}
+
+func NewIdentitiesRepository(db *sqlx.DB) *IdentitiesDatabase {
+	return &IdentitiesDatabase{
+		db: db,
+	}
+}
+
+// GetUserID returns the user linked to the subject of the issuer.
+func (i *IdentitiesDatabase) GetUserID(ctx context.Context, issuer, subject string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`SELECT user_id FROM %s WHERE issuer = $1 AND subject = $2`,
+		constants.UserIdentitiesTable)
+
+	var userID int
+
+	err := executor(ctx, i.db).GetContext(ctx, &userID, query, issuer, subject)
+	if err != nil {
+		return 0, dbError(err, "error when getting the identity")
+	}
+
+	return userID, nil
+}
+
+// Link links the user to the subject of the issuer.
+func (i *IdentitiesDatabase) Link(ctx context.Context, identity model.UserIdentity) error {
+	l := logging.LoggerFromContext(ctx).With(zap.Int("userID", identity.UserID))
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`INSERT INTO %s (user_id, issuer, subject, email) VALUES ($1, $2, $3, $4)`,
+		constants.UserIdentitiesTable)
+
+	_, err := executor(ctx, i.db).ExecContext(ctx, query,
+		identity.UserID, identity.Issuer, identity.Subject, identity.Email)
+	if err != nil {
+		l.Error("Error when linking the identity", zap.Error(err))
+
+		return dbError(err, "error when executing query")
+	}
+
+	return nil
+}
+
+type OIDCStatesRedis struct {
+	redis *redis.Client
+}
+
+func NewOIDCStatesRepository(r *redis.Client) *OIDCStatesRedis {
+	return &OIDCStatesRedis{
+		redis: r,
+	}
+}
+
+// Save keeps the state of a sign-in at the provider for ttl.
+func (o *OIDCStatesRedis) Save(ctx context.Context, state string, s model.OIDCState, ttl time.Duration) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return errors.Wrap(err, "error when encoding the state")
+	}
+
+	err = o.redis.Set(ctx, constants.OIDCStateKeyPrefix+state, b, ttl).Err()
+	if err != nil {
+		return errors.Wrap(err, "error when saving the state")
+	}
+
+	return nil
+}
+
+// Take returns and deletes the state, so that a callback is handled once. Found is false when the state
+// expired or was taken.
+func (o *OIDCStatesRedis) Take(ctx context.Context, state string) (model.OIDCState, bool, error) {
+	var s model.OIDCState
+
+	b, err := o.redis.GetDel(ctx, constants.OIDCStateKeyPrefix+state).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return s, false, nil
+	}
+
+	if err != nil {
+		return s, false, errors.Wrap(err, "error when taking the state")
+	}
+
+	err = json.Unmarshal(b, &s)
+	if err != nil {
+		return s, false, errors.Wrap(err, "error when decoding the state")
+	}
+
+	return s, true, nil
+}
+
+// SaveCode keeps the one-time code of a completed sign-in for ttl, the code is exchanged for the user.
+func (o *OIDCStatesRedis) SaveCode(ctx context.Context, code string, userID int, ttl time.Duration) error {
+	err := o.redis.Set(ctx, constants.OIDCCodeKeyPrefix+code, userID, ttl).Err()
+	if err != nil {
+		return errors.Wrap(err, "error when saving the code")
+	}
+
+	return nil
+}
+
+// TakeCode returns the user of the code and deletes it, so that a code is exchanged once. Found is false
+// when the code expired or was taken.
+func (o *OIDCStatesRedis) TakeCode(ctx context.Context, code string) (int, bool, error) {
+	userID, err := o.redis.GetDel(ctx, constants.OIDCCodeKeyPrefix+code).Int()
+	if errors.Is(err, redis.Nil) {
+		return 0, false, nil
+	}
+
+	if err != nil {
+		return 0, false, errors.Wrap(err, "error when taking the code")
+	}
+
+	return userID, true, nil
+}
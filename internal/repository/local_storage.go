@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"acsp/internal/apperror"
+)
+
+// LocalStorageRepository implements the ObjectStorage interface on top of a local directory.
+// It is meant for development and tests, objects are served by the static route of the app.
+type LocalStorageRepository struct {
+	root string
+}
+
+// NewLocalStorageRepository creates a new instance of LocalStorageRepository.
+func NewLocalStorageRepository(root string) *LocalStorageRepository {
+	return &LocalStorageRepository{root: root}
+}
+
+// PutObject writes an object to the local directory, replacing the previous one with the same key.
+func (r *LocalStorageRepository) PutObject(ctx context.Context, key string, fileBytes []byte) error {
+	path, err := r.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return errors.Wrap(err, "Error occurred when creating storage directory")
+	}
+
+	// Write to a temporary file first so that readers never see a partially written object
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return errors.Wrap(err, "Error occurred when creating temporary file")
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(fileBytes)
+	if err != nil {
+		tmp.Close()
+
+		return errors.Wrap(err, "Error occurred when writing file")
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return errors.Wrap(err, "Error occurred when closing file")
+	}
+
+	err = os.Chmod(tmp.Name(), 0o644)
+	if err != nil {
+		return errors.Wrap(err, "Error occurred when changing file mode")
+	}
+
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return errors.Wrap(err, "Error occurred when moving file to storage")
+	}
+
+	return nil
+}
+
+// CopyObject copies an object inside the local directory, replacing the destination object.
+func (r *LocalStorageRepository) CopyObject(ctx context.Context, srcKey, dstKey string) error {
+	path, err := r.path(srcKey)
+	if err != nil {
+		return err
+	}
+
+	fileBytes, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return errors.Wrapf(apperror.ErrObjectNotFound, "key %q", srcKey)
+	}
+	if err != nil {
+		return errors.Wrap(err, "Error occurred when reading file from storage")
+	}
+
+	return r.PutObject(ctx, dstKey, fileBytes)
+}
+
+// HeadObject checks that an object exists in the local directory.
+func (r *LocalStorageRepository) HeadObject(ctx context.Context, key string) error {
+	path, err := r.path(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return errors.Wrapf(apperror.ErrObjectNotFound, "key %q", key)
+	}
+	if err != nil {
+		return errors.Wrap(err, "Error occurred when checking file in storage")
+	}
+
+	return nil
+}
+
+// DeleteObject removes an object from the local directory, deleting a missing object is not an error.
+func (r *LocalStorageRepository) DeleteObject(ctx context.Context, key string) error {
+	path, err := r.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return errors.Wrap(err, "Error occurred when deleting file from storage")
+	}
+
+	return nil
+}
+
+// path resolves the key inside the root directory and rejects keys escaping it.
+func (r *LocalStorageRepository) path(key string) (string, error) {
+	cleaned := filepath.Clean("/" + key)
+	if cleaned == "/" || strings.HasPrefix(key, "/") {
+		return "", errors.Wrapf(apperror.ErrInvalidObjectKey, "key %q", key)
+	}
+
+	return filepath.Join(r.root, filepath.FromSlash(cleaned)), nil
+}
+
+// Ping checks that the root directory exists or can be created.
+func (r *LocalStorageRepository) Ping(ctx context.Context) error {
+	err := os.MkdirAll(r.root, 0o755)
+	if err != nil {
+		return errors.Wrap(err, "Error occurred when checking storage directory")
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"acsp/internal/constants"
+	"acsp/internal/logging"
+	"acsp/internal/model"
+)
+
+// countFailure increments the failures of a subject, the window starts with the first failure.
+var countFailure = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+
+return count
+`)
+
+type LoginAttemptsRedis struct {
+	redis *redis.Client
+}
+
+func NewLoginAttemptsRepository(r *redis.Client) *LoginAttemptsRedis {
+	return &LoginAttemptsRedis{
+		redis: r,
+	}
+}
+
+// GetLock returns the reason and the remaining time of the lock of the subject, found is false when
+// the subject isn't locked.
+func (l *LoginAttemptsRedis) GetLock(ctx context.Context, subject string) (string, time.Duration, bool, error) {
+	key := constants.LoginLockKeyPrefix + subject
+
+	pipe := l.redis.Pipeline()
+	get := pipe.Get(ctx, key)
+	ttl := pipe.PTTL(ctx, key)
+
+	_, err := pipe.Exec(ctx)
+	if errors.Is(err, redis.Nil) {
+		return "", 0, false, nil
+	}
+
+	if err != nil {
+		return "", 0, false, errors.Wrap(err, "error when getting the lock")
+	}
+
+	return get.Val(), ttl.Val(), true, nil
+}
+
+// AddFailure counts a failed sign-in of the subject and returns the failures of the window.
+func (l *LoginAttemptsRedis) AddFailure(ctx context.Context, subject string, window time.Duration) (int, error) {
+	count, err := countFailure.Run(ctx, l.redis, []string{constants.LoginFailuresKeyPrefix + subject},
+		window.Milliseconds()).Int()
+	if err != nil {
+		return 0, errors.Wrap(err, "error when counting the failure")
+	}
+
+	return count, nil
+}
+
+// Lock locks the subject for d, the reason tells the delays after failures apart from the lockouts.
+func (l *LoginAttemptsRedis) Lock(ctx context.Context, subject, reason string, d time.Duration) error {
+	err := l.redis.Set(ctx, constants.LoginLockKeyPrefix+subject, reason, d).Err()
+	if err != nil {
+		return errors.Wrap(err, "error when locking")
+	}
+
+	return nil
+}
+
+// Reset forgets the failures and the lock of the subject.
+func (l *LoginAttemptsRedis) Reset(ctx context.Context, subject string) error {
+	err := l.redis.Del(ctx, constants.LoginFailuresKeyPrefix+subject, constants.LoginLockKeyPrefix+subject).Err()
+	if err != nil {
+		return errors.Wrap(err, "error when resetting the failures")
+	}
+
+	return nil
+}
+
+type AccountLockoutsDatabase struct {
+	db *sqlx.DB
+}
+
+func NewAccountLockoutsRepository(db *sqlx.DB) *AccountLockoutsDatabase {
+	return &AccountLockoutsDatabase{
+		db: db,
+	}
+}
+
+// Add records a lockout, the user is looked up by the email of the lockout.
+func (a *AccountLockoutsDatabase) Add(ctx context.Context, lockout model.AccountLockout) error {
+	l := logging.LoggerFromContext(ctx).With(zap.String("email", lockout.Email), zap.String("ip", lockout.IP))
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`INSERT INTO %s (user_id, email, ip, failures, locked_until)
+								VALUES ((SELECT id FROM %s WHERE email = $1 LIMIT 1), $1, $2, $3, $4)`,
+		constants.AccountLockoutsTable, constants.UsersTable)
+
+	_, err := executor(ctx, a.db).ExecContext(ctx, query,
+		lockout.Email, lockout.IP, lockout.Failures, lockout.LockedUntil)
+	if err != nil {
+		l.Error("Error when recording the lockout", zap.Error(err))
+
+		return dbError(err, "error when executing query")
+	}
+
+	return nil
+}
+
+// GetLockedIPs returns the IPs of the lockouts of the email that aren't over yet.
+func (a *AccountLockoutsDatabase) GetLockedIPs(ctx context.Context, email string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`SELECT DISTINCT ip FROM %s WHERE email = $1 AND locked_until > now()`,
+		constants.AccountLockoutsTable)
+
+	var ips []string
+
+	err := executor(ctx, a.db).SelectContext(ctx, &ips, query, email)
+	if err != nil {
+		return nil, dbError(err, "error when executing query")
+	}
+
+	return ips, nil
+}
@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
-	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
 	"acsp/internal/constants"
@@ -34,11 +33,11 @@ func (m *MaterialsDatabase) Create(ctx context.Context, material model.Material)
 	query := fmt.Sprintf("INSERT INTO %s (user_id, topic, description) VALUES ($1, $2, $3) RETURNING id",
 		constants.MaterialsTable)
 
-	stmt, err := m.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, m.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
-		return errors.Wrap(err, "error when preparing the query")
+		return dbError(err, "error when preparing the query")
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -51,20 +50,20 @@ func (m *MaterialsDatabase) Create(ctx context.Context, material model.Material)
 	if err != nil {
 		l.Error("Error when creating the material in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when executing the query")
+		return dbError(err, "error when executing the query")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting the rows affected", zap.Error(err))
 
-		return errors.Wrap(err, "error when getting the rows affected")
+		return dbError(err, "error when getting the rows affected")
 	}
 
 	if rowsAffected < 1 {
 		l.Error("Error when creating the material in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when creating the material in database")
+		return dbError(err, "error when creating the material in database")
 	}
 
 	return nil
@@ -79,11 +78,11 @@ func (m *MaterialsDatabase) Update(ctx context.Context, material model.Material)
 	query := fmt.Sprintf("UPDATE %s SET topic = $1, description = $2, updated_at = now() WHERE id = $3 AND user_id = $4",
 		constants.MaterialsTable)
 
-	stmt, err := m.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, m.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
-		return errors.Wrap(err, "error when preparing the query")
+		return dbError(err, "error when preparing the query")
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -96,20 +95,20 @@ func (m *MaterialsDatabase) Update(ctx context.Context, material model.Material)
 	if err != nil {
 		l.Error("Error when update the article in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when executing the query")
+		return dbError(err, "error when executing the query")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting the rows affected", zap.Error(err))
 
-		return errors.Wrap(err, "error when getting the rows affected")
+		return dbError(err, "error when getting the rows affected")
 	}
 
 	if rowsAffected < 1 {
 		l.Error("Error when creating the material in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when creating the material in database")
+		return dbError(err, "error when creating the material in database")
 	}
 
 	return nil
@@ -123,9 +122,9 @@ func (m *MaterialsDatabase) Delete(ctx context.Context, userID int, materialID i
 
 	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND user_id = $2", constants.MaterialsTable)
 
-	stmt, err := m.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, m.db).PrepareContext(ctx, query)
 	if err != nil {
-		return errors.Wrap(err, "error when preparing the query")
+		return dbError(err, "error when preparing the query")
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -136,20 +135,20 @@ func (m *MaterialsDatabase) Delete(ctx context.Context, userID int, materialID i
 
 	res, err := stmt.ExecContext(ctx, query, materialID, userID)
 	if err != nil {
-		return errors.Wrap(err, "error when executing the query")
+		return dbError(err, "error when executing the query")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting the rows affected", zap.Error(err))
 
-		return errors.Wrap(err, "error when getting the rows affected")
+		return dbError(err, "error when getting the rows affected")
 	}
 
 	if rowsAffected < 1 {
 		l.Error("Error when creating the material in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when creating the material in database")
+		return dbError(err, "error when creating the material in database")
 	}
 
 	return nil
@@ -166,11 +165,11 @@ func (m *MaterialsDatabase) GetByID(ctx context.Context, materialID int) (model.
 	query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1",
 		constants.MaterialsTable)
 
-	err := m.db.GetContext(ctx, &material, query, materialID)
+	err := executor(ctx, m.db).GetContext(ctx, &material, query, materialID)
 	if err != nil {
 		l.Error("Error when getting the material", zap.Error(err))
 
-		return model.Material{}, errors.Wrap(err, "error when executing the query")
+		return model.Material{}, dbError(err, "error when executing the query")
 	}
 
 	return material, nil
@@ -182,9 +181,9 @@ func (m *MaterialsDatabase) GetAllByUserID(ctx context.Context, userID int) ([]m
 	query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1",
 		constants.MaterialsTable)
 
-	err := m.db.Select(&materials, query, userID)
+	err := executor(ctx, m.db).SelectContext(ctx, &materials, query, userID)
 	if err != nil {
-		return []model.Material{}, errors.Wrap(err, "error when getting the materials")
+		return []model.Material{}, dbError(err, "error when getting the materials")
 	}
 
 	return materials, nil
@@ -196,9 +195,9 @@ func (m *MaterialsDatabase) GetAll(ctx context.Context) ([]model.Material, error
 	query := fmt.Sprintf("SELECT * FROM %s",
 		constants.MaterialsTable)
 
-	err := m.db.Select(&materials, query)
+	err := executor(ctx, m.db).SelectContext(ctx, &materials, query)
 	if err != nil {
-		return nil, errors.Wrap(err, "error when getting the materials")
+		return nil, dbError(err, "error when getting the materials")
 	}
 
 	return materials, nil
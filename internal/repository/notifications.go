@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+
+	"acsp/internal/apperror"
+	"acsp/internal/constants"
+	"acsp/internal/logging"
+	"acsp/internal/model"
+)
+
+const notificationColumns = `id, user_id, kind, payload, idempotency_key, read_at, created_at`
+
+type NotificationsDatabase struct {
+	db *sqlx.DB
+}
+
+func NewNotificationsRepository(db *sqlx.DB) *NotificationsDatabase {
+	return &NotificationsDatabase{
+		db: db,
+	}
+}
+
+// Add adds a notification, adding one with the same idempotency key again is not an error.
+func (n *NotificationsDatabase) Add(ctx context.Context, notification model.Notification) error {
+	l := logging.LoggerFromContext(ctx).With(zap.Int("userID", notification.UserID),
+		zap.String("kind", notification.Kind))
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`INSERT INTO %s (user_id, kind, payload, idempotency_key)
+								VALUES ($1, $2, $3, $4)
+								ON CONFLICT (idempotency_key) DO NOTHING`,
+		constants.NotificationsTable)
+
+	_, err := executor(ctx, n.db).ExecContext(ctx, query,
+		notification.UserID, notification.Kind, []byte(notification.Payload), notification.IdempotencyKey)
+	if err != nil {
+		l.Error("Error when adding the notification", zap.Error(err))
+
+		return dbError(err, "error when executing query")
+	}
+
+	return nil
+}
+
+// GetByUserID returns the latest notifications of the user, the latest first.
+func (n *NotificationsDatabase) GetByUserID(ctx context.Context, userID, limit int) ([]model.Notification, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE user_id = $1 ORDER BY id DESC LIMIT $2`,
+		notificationColumns, constants.NotificationsTable)
+
+	notifications := make([]model.Notification, 0)
+
+	err := executor(ctx, n.db).SelectContext(ctx, &notifications, query, userID, limit)
+	if err != nil {
+		return nil, dbError(err, "error when getting the notifications")
+	}
+
+	return notifications, nil
+}
+
+// MarkRead marks the notification of the user as read, marking a read one again keeps the first time.
+func (n *NotificationsDatabase) MarkRead(ctx context.Context, userID, notificationID int) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`UPDATE %s SET read_at = COALESCE(read_at, now()) WHERE id = $1 AND user_id = $2`,
+		constants.NotificationsTable)
+
+	res, err := executor(ctx, n.db).ExecContext(ctx, query, notificationID, userID)
+	if err != nil {
+		return dbError(err, "error when executing query")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return dbError(err, "error when getting affected rows")
+	}
+
+	if affected == 0 {
+		return apperror.ErrNotificationNotFound
+	}
+
+	return nil
+}
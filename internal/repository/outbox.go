@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"acsp/internal/constants"
+	"acsp/internal/logging"
+	"acsp/internal/model"
+)
+
+type OutboxDatabase struct {
+	db *sqlx.DB
+}
+
+func NewOutboxRepository(db *sqlx.DB) *OutboxDatabase {
+	return &OutboxDatabase{
+		db: db,
+	}
+}
+
+// Enqueue writes an event, it must be called within the transaction of the data the event belongs to.
+// An event with an already enqueued idempotency key is ignored.
+func (o *OutboxDatabase) Enqueue(ctx context.Context, event model.OutboxEvent) error {
+	l := logging.LoggerFromContext(ctx).With(
+		zap.String("eventType", event.EventType),
+		zap.String("idempotencyKey", event.IdempotencyKey),
+	)
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`INSERT INTO %s (event_type, idempotency_key, payload)
+								VALUES ($1, $2, $3)
+								ON CONFLICT (idempotency_key) DO NOTHING`,
+		constants.OutboxEventsTable)
+
+	_, err := executor(ctx, o.db).ExecContext(ctx, query, event.EventType, event.IdempotencyKey, []byte(event.Payload))
+	if err != nil {
+		l.Error("Error when enqueuing the outbox event", zap.Error(err))
+
+		return errors.Wrap(err, "error when executing query")
+	}
+
+	return nil
+}
+
+// Claim takes the due pending events and hides them from other workers for the lease time.
+// The attempts of the claimed events are incremented.
+func (o *OutboxDatabase) Claim(ctx context.Context, limit int, lease time.Duration) ([]model.OutboxEvent, error) {
+	l := logging.LoggerFromContext(ctx)
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`UPDATE %[1]s SET
+									attempts = attempts + 1,
+									next_attempt_at = now() + $2 * INTERVAL '1 millisecond'
+								WHERE id IN (
+									SELECT id FROM %[1]s
+									WHERE status = $3 AND next_attempt_at <= now()
+									ORDER BY id
+									LIMIT $1
+									FOR UPDATE SKIP LOCKED)
+								RETURNING
+									id,
+									event_type,
+									idempotency_key,
+									payload,
+									status,
+									attempts,
+									last_error,
+									next_attempt_at,
+									created_at`,
+		constants.OutboxEventsTable)
+
+	var events []model.OutboxEvent
+
+	err := executor(ctx, o.db).SelectContext(ctx, &events, query, limit, lease.Milliseconds(), constants.OutboxPendingStatus)
+	if err != nil {
+		l.Error("Error when claiming outbox events", zap.Error(err))
+
+		return nil, errors.Wrap(err, "error when executing query")
+	}
+
+	return events, nil
+}
+
+// MarkDone marks an event as performed.
+func (o *OutboxDatabase) MarkDone(ctx context.Context, eventID int) error {
+	query := fmt.Sprintf(`UPDATE %s SET status = $1, last_error = '', processed_at = now() WHERE id = $2`,
+		constants.OutboxEventsTable)
+
+	return o.exec(ctx, eventID, query, constants.OutboxDoneStatus, eventID)
+}
+
+// Retry schedules the next attempt of a failed event.
+func (o *OutboxDatabase) Retry(ctx context.Context, eventID int, nextAttemptAt time.Time, lastError string) error {
+	query := fmt.Sprintf(`UPDATE %s SET next_attempt_at = $1, last_error = $2 WHERE id = $3`,
+		constants.OutboxEventsTable)
+
+	return o.exec(ctx, eventID, query, nextAttemptAt, lastError, eventID)
+}
+
+// MarkFailed gives up an event, it stays in the table for inspection.
+func (o *OutboxDatabase) MarkFailed(ctx context.Context, eventID int, lastError string) error {
+	query := fmt.Sprintf(`UPDATE %s SET status = $1, last_error = $2, processed_at = now() WHERE id = $3`,
+		constants.OutboxEventsTable)
+
+	return o.exec(ctx, eventID, query, constants.OutboxFailedStatus, lastError, eventID)
+}
+
+func (o *OutboxDatabase) exec(ctx context.Context, eventID int, query string, args ...interface{}) error {
+	l := logging.LoggerFromContext(ctx).With(zap.Int("eventID", eventID))
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	_, err := executor(ctx, o.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		l.Error("Error when updating the outbox event", zap.Error(err))
+
+		return errors.Wrap(err, "error when executing query")
+	}
+
+	return nil
+}
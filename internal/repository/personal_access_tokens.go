@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"acsp/internal/apperror"
+	"acsp/internal/constants"
+	"acsp/internal/logging"
+	"acsp/internal/model"
+)
+
+const personalAccessTokenColumns = `id, user_id, name, token_hash, hint, scopes, expires_at, last_used_at, created_at`
+
+type PersonalAccessTokensDatabase struct {
+	db *sqlx.DB
+}
+
+func NewPersonalAccessTokensRepository(db *sqlx.DB) *PersonalAccessTokensDatabase {
+	return &PersonalAccessTokensDatabase{
+		db: db,
+	}
+}
+
+// Create creates the token and returns it with the id.
+func (p *PersonalAccessTokensDatabase) Create(
+	ctx context.Context,
+	token model.PersonalAccessToken,
+) (model.PersonalAccessToken, error) {
+	l := logging.LoggerFromContext(ctx).With(zap.Int("userID", token.UserID))
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`INSERT INTO %s (user_id, name, token_hash, hint, scopes, expires_at)
+								VALUES ($1, $2, $3, $4, $5, $6)
+								RETURNING id, created_at`,
+		constants.PersonalAccessTokensTable)
+
+	err := executor(ctx, p.db).QueryRowxContext(ctx, query,
+		token.UserID, token.Name, token.TokenHash, token.Hint, token.Scopes, token.ExpiresAt).
+		Scan(&token.ID, &token.CreatedAt)
+	if err != nil {
+		l.Error("Error when creating the personal access token", zap.Error(err))
+
+		return model.PersonalAccessToken{}, dbError(err, "error when executing query")
+	}
+
+	return token, nil
+}
+
+// CountByUser returns the number of the tokens of the user that aren't expired.
+func (p *PersonalAccessTokensDatabase) CountByUser(ctx context.Context, userID int) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`SELECT count(*) FROM %s WHERE user_id = $1 AND expires_at > now()`,
+		constants.PersonalAccessTokensTable)
+
+	var count int
+
+	err := executor(ctx, p.db).GetContext(ctx, &count, query, userID)
+	if err != nil {
+		return 0, dbError(err, "error when counting the personal access tokens")
+	}
+
+	return count, nil
+}
+
+// GetByUser returns the tokens of the user, the latest first.
+func (p *PersonalAccessTokensDatabase) GetByUser(ctx context.Context, userID int) ([]model.PersonalAccessToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE user_id = $1 ORDER BY created_at DESC, id DESC`,
+		personalAccessTokenColumns, constants.PersonalAccessTokensTable)
+
+	tokens := make([]model.PersonalAccessToken, 0)
+
+	err := executor(ctx, p.db).SelectContext(ctx, &tokens, query, userID)
+	if err != nil {
+		return nil, dbError(err, "error when getting the personal access tokens")
+	}
+
+	return tokens, nil
+}
+
+// GetByHash returns the token of the hash.
+func (p *PersonalAccessTokensDatabase) GetByHash(ctx context.Context, hash string) (model.PersonalAccessToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE token_hash = $1`,
+		personalAccessTokenColumns, constants.PersonalAccessTokensTable)
+
+	var token model.PersonalAccessToken
+
+	err := executor(ctx, p.db).GetContext(ctx, &token, query, hash)
+	if err != nil {
+		return model.PersonalAccessToken{}, dbError(err, "error when getting the personal access token")
+	}
+
+	return token, nil
+}
+
+// Delete revokes the token of the user.
+func (p *PersonalAccessTokensDatabase) Delete(ctx context.Context, userID, tokenID int) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE id = $1 AND user_id = $2`, constants.PersonalAccessTokensTable)
+
+	res, err := executor(ctx, p.db).ExecContext(ctx, query, tokenID, userID)
+	if err != nil {
+		return dbError(err, "error when executing query")
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "error when getting affected rows")
+	}
+
+	if n == 0 {
+		return apperror.ErrTokenNotFound
+	}
+
+	return nil
+}
+
+// Touch records the use of the token.
+func (p *PersonalAccessTokensDatabase) Touch(ctx context.Context, tokenID int) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`UPDATE %s SET last_used_at = now() WHERE id = $1`, constants.PersonalAccessTokensTable)
+
+	_, err := executor(ctx, p.db).ExecContext(ctx, query, tokenID)
+	if err != nil {
+		return dbError(err, "error when executing query")
+	}
+
+	return nil
+}
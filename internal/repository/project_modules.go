@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
-	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
 	"acsp/internal/apperror"
@@ -39,7 +38,7 @@ func (p *ProjectModulesDatabase) Create(ctx context.Context, module model.Projec
 								 VALUES ($1, $2) RETURNING id`,
 		constants.CodingLabProjectModulesTable)
 
-	stmt, err := p.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, p.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
@@ -85,11 +84,11 @@ func (p *ProjectModulesDatabase) Update(ctx context.Context, module model.Projec
 											WHERE id = $2`,
 		constants.CodingLabProjectsTable)
 
-	stmt, err := p.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, p.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
-		return errors.Wrap(err, "error when preparing the query")
+		return dbError(err, "error when preparing the query")
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -102,20 +101,20 @@ func (p *ProjectModulesDatabase) Update(ctx context.Context, module model.Projec
 	if err != nil {
 		l.Error("Error when update the project module in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when executing the query")
+		return dbError(err, "error when executing the query")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting the rows affected", zap.Error(err))
 
-		return errors.Wrap(err, "error when getting the rows affected")
+		return dbError(err, "error when getting the rows affected")
 	}
 
 	if rowsAffected < 1 {
 		l.Error("Error when updating the project module in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when updating the project module in database")
+		return dbError(err, "error when updating the project module in database")
 	}
 
 	return nil
@@ -129,9 +128,9 @@ func (p *ProjectModulesDatabase) Delete(ctx context.Context, projectID, moduleID
 
 	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND project_id = $2", constants.CodingLabProjectModulesTable)
 
-	stmt, err := p.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, p.db).PrepareContext(ctx, query)
 	if err != nil {
-		return errors.Wrap(err, "error when preparing the query")
+		return dbError(err, "error when preparing the query")
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -142,20 +141,20 @@ func (p *ProjectModulesDatabase) Delete(ctx context.Context, projectID, moduleID
 
 	res, err := stmt.ExecContext(ctx, query, moduleID, projectID)
 	if err != nil {
-		return errors.Wrap(err, "error when executing the query")
+		return dbError(err, "error when executing the query")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting the rows affected", zap.Error(err))
 
-		return errors.Wrap(err, "error when getting the rows affected")
+		return dbError(err, "error when getting the rows affected")
 	}
 
 	if rowsAffected < 1 {
 		l.Error("Error when deleting the project module in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when deleting the project module in database")
+		return dbError(err, "error when deleting the project module in database")
 	}
 
 	return nil
@@ -169,11 +168,11 @@ func (p *ProjectModulesDatabase) GetAllByProjectID(ctx context.Context, projectI
 	query := fmt.Sprintf("SELECT * FROM %s WHERE project_id = $1",
 		constants.CodingLabProjectModulesTable)
 
-	err := p.db.Select(&modules, query, projectID)
+	err := executor(ctx, p.db).SelectContext(ctx, &modules, query, projectID)
 	if err != nil {
 		l.Error("Error when getting the project's modules", zap.Error(err))
 
-		return nil, errors.Wrap(err, "error when getting the projects")
+		return nil, dbError(err, "error when getting the projects")
 	}
 
 	return modules, nil
@@ -190,11 +189,11 @@ func (p *ProjectModulesDatabase) GetByID(ctx context.Context, moduleID int) (mod
 	query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1",
 		constants.CodingLabProjectsTable)
 
-	err := p.db.GetContext(ctx, &module, query, moduleID)
+	err := executor(ctx, p.db).GetContext(ctx, &module, query, moduleID)
 	if err != nil {
 		l.Error("Error when getting the project module", zap.Error(err))
 
-		return model.ProjectModule{}, errors.Wrap(err, "error when executing the query")
+		return model.ProjectModule{}, dbError(err, "error when executing the query")
 	}
 
 	return module, nil
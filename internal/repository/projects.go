@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
-	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
 	"acsp/internal/apperror"
@@ -37,7 +36,7 @@ func (p *ProjectsDatabase) Create(ctx context.Context, disciplineID int, project
 								 VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
 		constants.CodingLabProjectsTable)
 
-	stmt, err := p.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, p.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
@@ -90,11 +89,11 @@ func (p *ProjectsDatabase) Update(ctx context.Context, project model.Project) er
 											WHERE id = $5 AND discipline_id = $6`,
 		constants.CodingLabProjectsTable)
 
-	stmt, err := p.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, p.db).PrepareContext(ctx, query)
 	if err != nil {
 		l.Error("Error when preparing the query", zap.Error(err))
 
-		return errors.Wrap(err, "error when preparing the query")
+		return dbError(err, "error when preparing the query")
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -114,20 +113,20 @@ func (p *ProjectsDatabase) Update(ctx context.Context, project model.Project) er
 	if err != nil {
 		l.Error("Error when update the project in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when executing the query")
+		return dbError(err, "error when executing the query")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting the rows affected", zap.Error(err))
 
-		return errors.Wrap(err, "error when getting the rows affected")
+		return dbError(err, "error when getting the rows affected")
 	}
 
 	if rowsAffected < 1 {
 		l.Error("Error when updating the project in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when updating the project in database")
+		return dbError(err, "error when updating the project in database")
 	}
 
 	return nil
@@ -141,9 +140,9 @@ func (p *ProjectsDatabase) Delete(ctx context.Context, projectID int) error {
 
 	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", constants.CodingLabProjectsTable)
 
-	stmt, err := p.db.PrepareContext(ctx, query)
+	stmt, err := executor(ctx, p.db).PrepareContext(ctx, query)
 	if err != nil {
-		return errors.Wrap(err, "error when preparing the query")
+		return dbError(err, "error when preparing the query")
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -154,20 +153,20 @@ func (p *ProjectsDatabase) Delete(ctx context.Context, projectID int) error {
 
 	res, err := stmt.ExecContext(ctx, query, projectID)
 	if err != nil {
-		return errors.Wrap(err, "error when executing the query")
+		return dbError(err, "error when executing the query")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		l.Error("Error when getting the rows affected", zap.Error(err))
 
-		return errors.Wrap(err, "error when getting the rows affected")
+		return dbError(err, "error when getting the rows affected")
 	}
 
 	if rowsAffected < 1 {
 		l.Error("Error when deleting the project in database", zap.Error(err))
 
-		return errors.Wrap(err, "error when deleting the project in database")
+		return dbError(err, "error when deleting the project in database")
 	}
 
 	return nil
@@ -179,9 +178,9 @@ func (p *ProjectsDatabase) GetAll(ctx context.Context) ([]model.Project, error)
 	query := fmt.Sprintf("SELECT * FROM %s",
 		constants.CodingLabProjectsTable)
 
-	err := p.db.Select(&projects, query)
+	err := executor(ctx, p.db).SelectContext(ctx, &projects, query)
 	if err != nil {
-		return nil, errors.Wrap(err, "error when getting the projects")
+		return nil, dbError(err, "error when getting the projects")
 	}
 
 	return projects, nil
@@ -198,11 +197,11 @@ func (p *ProjectsDatabase) GetByID(ctx context.Context, projectID int) (model.Pr
 	query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1",
 		constants.CodingLabProjectsTable)
 
-	err := p.db.GetContext(ctx, &project, query, projectID)
+	err := executor(ctx, p.db).GetContext(ctx, &project, query, projectID)
 	if err != nil {
 		l.Error("Error when getting the project", zap.Error(err))
 
-		return model.Project{}, errors.Wrap(err, "error when executing the query")
+		return model.Project{}, dbError(err, "error when executing the query")
 	}
 
 	return project, nil
@@ -219,11 +218,11 @@ func (p *ProjectsDatabase) GetAllByDisciplineID(ctx context.Context, disciplineI
 	query := fmt.Sprintf("SELECT * FROM %s WHERE discipline_id = $1",
 		constants.CodingLabProjectsTable)
 
-	err := p.db.SelectContext(ctx, &projects, query, disciplineID)
+	err := executor(ctx, p.db).SelectContext(ctx, &projects, query, disciplineID)
 	if err != nil {
 		l.Error("Error when getting the projects", zap.Error(err))
 
-		return nil, errors.Wrap(err, "error when executing the query")
+		return nil, dbError(err, "error when executing the query")
 	}
 
 	return projects, nil
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+	"github.com/pkg/errors"
+
+	"acsp/internal/constants"
+	"acsp/internal/model"
+)
+
+// slidingWindow counts the requests of the last window in a sorted set scored by the time of the requests.
+// The count and the insert are atomic, so that concurrent requests of several instances are counted once.
+// It returns whether the request is allowed, the remaining requests and the milliseconds until the oldest
+// request of the window expires.
+var slidingWindow = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+
+local count = redis.call('ZCARD', key)
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', key, now, ARGV[4])
+	redis.call('PEXPIRE', key, window)
+	count = count + 1
+	allowed = 1
+end
+
+local reset = window
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+
+return {allowed, limit - count, reset}
+`)
+
+type RateLimiterRedis struct {
+	redis *redis.Client
+}
+
+func NewRateLimiterRepository(r *redis.Client) *RateLimiterRedis {
+	return &RateLimiterRedis{
+		redis: r,
+	}
+}
+
+// Allow counts the request of the client of the policy, unless max requests were counted within the window.
+func (r *RateLimiterRedis) Allow(
+	ctx context.Context,
+	policy, client string,
+	max int,
+	window time.Duration,
+) (model.RateLimitResult, error) {
+	now := time.Now()
+	key := constants.RateLimitKeyPrefix + policy + ":" + client
+
+	// The member only has to be unique, the requests of the same millisecond are counted apart
+	member := fmt.Sprintf("%d-%d", now.UnixNano(), rand.Int63())
+
+	res, err := slidingWindow.Run(ctx, r.redis, []string{key},
+		now.UnixMilli(), window.Milliseconds(), max, member).Int64Slice()
+	if err != nil {
+		return model.RateLimitResult{}, errors.Wrap(err, "error when counting the request")
+	}
+
+	return model.RateLimitResult{
+		Allowed:   res[0] == 1,
+		Limit:     max,
+		Remaining: int(res[1]),
+		Window:    window,
+		Reset:     time.Duration(res[2]) * time.Millisecond,
+	}, nil
+}
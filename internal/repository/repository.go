@@ -2,9 +2,9 @@ package repository
 
 import (
 	"context"
-	"database/sql"
+	"time"
 
-	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/go-redis/redis/v9"
 	"github.com/jmoiron/sqlx"
 
 	"acsp/internal/dto"
@@ -26,7 +26,24 @@ type Repository struct {
 	CourseLessons
 	CourseLessonComments
 	Transactional
-	S3Bucket
+	ObjectStorage
+	StoredObjects
+	Outbox
+	Settings
+	SettingsChanges
+	FeatureFlags
+	FeatureFlagsCache
+	RateLimiter
+	LoginAttempts
+	AccountLockouts
+	TwoFactor
+	MFAChallenges
+	Identities
+	OIDCStates
+	PersonalAccessTokens
+	AuditEvents
+	Notifications
+	Health
 }
 
 // Users interface provides methods for working with users
@@ -40,7 +57,8 @@ type Users interface {
 	UpdateDetails(ctx context.Context, userID int, userDetails model.UserDetails) error
 	ExistsUserByID(ctx context.Context, id int) (bool, error)
 	ExistsUserByEmail(ctx context.Context, email string) (bool, error)
-	UpdateImageURL(ctx context.Context, userID int) error
+	UpdateImageURL(ctx context.Context, userID int, imageURL string) error
+	Delete(ctx context.Context, userID int) error
 }
 
 // Roles interface provides methods for working with roles
@@ -51,12 +69,13 @@ type Roles interface {
 	SaveUserRole(ctx context.Context, userID, roleID int) error
 	DeleteUserRole(ctx context.Context, userID, roleID int) error
 	GetUserRoles(ctx context.Context, userID int) ([]model.Role, error)
+	GetRoleByName(ctx context.Context, name string) (model.Role, error)
 }
 
 type Articles interface {
-	Create(ctx context.Context, tx *sqlx.Tx, article *model.Article) error
+	Create(ctx context.Context, article *model.Article) error
 	Update(ctx context.Context, article model.Article) error
-	UpdateImageURL(ctx context.Context, tx *sqlx.Tx, articleID int) error
+	UpdateImageURL(ctx context.Context, articleID int, imageURL string) error
 	Delete(ctx context.Context, userID int, articleID int) error
 	GetAll(ctx context.Context) ([]model.Article, error)
 	GetAllByUserID(ctx context.Context, userID int) ([]model.Article, error)
@@ -91,7 +110,7 @@ type Cards interface {
 	GetByIdAndUserID(ctx context.Context, userID, cardID int) (model.Card, error)
 	GetAllByUserID(ctx context.Context, userID int) (*[]model.Card, error)
 	GetAll(ctx context.Context) ([]model.Card, error)
-	CreateInvitation(ctx context.Context, inviterID int, card model.Card) error
+	CreateInvitation(ctx context.Context, inviterID int, card model.Card) (int, error)
 	GetInvitationsByUserID(ctx context.Context, userID int) ([]model.InvitationCard, error)
 	GetInvitationByID(ctx context.Context, userID, cardID, invitationID int) (model.InvitationCard, error)
 	GetInvitationsByCardID(ctx context.Context, cardID int) ([]model.InvitationCard, error)
@@ -101,7 +120,7 @@ type Cards interface {
 }
 
 type Contests interface {
-	Create(ctx context.Context, contest model.Contest) error
+	Create(ctx context.Context, contest model.Contest) (int, error)
 	Update(ctx context.Context, contest model.Contest) error
 	Delete(ctx context.Context, contestID int) error
 	GetByID(ctx context.Context, contestID int) (model.Contest, error)
@@ -109,7 +128,7 @@ type Contests interface {
 }
 
 type Disciplines interface {
-	Create(ctx context.Context, discipline model.Discipline) error
+	Create(ctx context.Context, discipline model.Discipline) (int, error)
 	Update(ctx context.Context, discipline model.Discipline) error
 	Delete(ctx context.Context, disciplineID int) error
 	GetByID(ctx context.Context, disciplineID int) (model.Discipline, error)
@@ -134,7 +153,7 @@ type ProjectModules interface {
 }
 
 type Courses interface {
-	Create(ctx context.Context, course model.Course) error
+	Create(ctx context.Context, course model.Course) (int, error)
 	Update(ctx context.Context, course model.Course) error
 	Delete(ctx context.Context, courseID int) error
 	GetAll(ctx context.Context) ([]model.Course, error)
@@ -165,18 +184,158 @@ type CourseLessonComments interface {
 	GetByID(ctx context.Context, commentID int) (model.CourseModuleLessonComment, error)
 }
 
-// S3Bucket interface provides methods for storing and retrieving objects from an S3 bucket.
-type S3Bucket interface {
-	PutObject(bucketName string, objectName string, fileBytes []byte) error
+// ObjectStorage interface provides methods for storing and retrieving objects from an object storage
+// (an S3 bucket or a local directory).
+type ObjectStorage interface {
+	PutObject(ctx context.Context, key string, fileBytes []byte) error
+	CopyObject(ctx context.Context, srcKey, dstKey string) error
+	// HeadObject returns apperror.ErrObjectNotFound when the object doesn't exist
+	HeadObject(ctx context.Context, key string) error
+	DeleteObject(ctx context.Context, key string) error
+	// Ping checks that the storage is reachable
+	Ping(ctx context.Context) error
 }
 
+// StoredObjects interface provides methods for working with the registry of stored objects.
+type StoredObjects interface {
+	Track(ctx context.Context, object model.StoredObject) error
+	GetOrphans(ctx context.Context, createdBefore time.Time, limit int) ([]model.StoredObject, error)
+	Delete(ctx context.Context, key string) error
+	ResetOwnerImage(ctx context.Context, object model.StoredObject) error
+}
+
+// Outbox interface provides methods for working with the side effects waiting to be performed.
+type Outbox interface {
+	Enqueue(ctx context.Context, event model.OutboxEvent) error
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]model.OutboxEvent, error)
+	MarkDone(ctx context.Context, eventID int) error
+	Retry(ctx context.Context, eventID int, nextAttemptAt time.Time, lastError string) error
+	MarkFailed(ctx context.Context, eventID int, lastError string) error
+}
+
+// Settings interface provides methods for working with the runtime settings shared by all instances.
+type Settings interface {
+	Get(ctx context.Context) (model.RuntimeSettings, bool, error)
+	Update(
+		ctx context.Context,
+		fn func(current model.RuntimeSettings, found bool) (model.RuntimeSettings, error),
+	) (model.RuntimeSettings, error)
+}
+
+// SettingsChanges interface provides methods for working with the audit trail of the runtime settings.
+type SettingsChanges interface {
+	Add(ctx context.Context, change model.SettingsChange) error
+	GetLatest(ctx context.Context, limit int) ([]model.SettingsChange, error)
+}
+
+// FeatureFlags interface provides methods for working with the feature flags.
+type FeatureFlags interface {
+	GetAll(ctx context.Context) ([]model.FeatureFlag, error)
+	GetByKey(ctx context.Context, key string) (model.FeatureFlag, error)
+	Create(ctx context.Context, flag model.FeatureFlag) error
+	Update(ctx context.Context, flag model.FeatureFlag) error
+	Delete(ctx context.Context, key string) error
+}
+
+// FeatureFlagsCache interface provides methods for working with the feature flags cached for all instances.
+type FeatureFlagsCache interface {
+	Get(ctx context.Context) ([]model.FeatureFlag, bool, error)
+	Set(ctx context.Context, flags []model.FeatureFlag, ttl time.Duration) error
+	Invalidate(ctx context.Context) error
+}
+
+// RateLimiter interface provides methods for counting the requests of the clients shared by all instances.
+type RateLimiter interface {
+	Allow(ctx context.Context, policy, client string, max int, window time.Duration) (model.RateLimitResult, error)
+}
+
+// LoginAttempts interface provides methods for counting the failed sign-ins of the accounts and the IPs
+// and locking them, shared by all instances. The subjects are the prefixed emails and IPs.
+type LoginAttempts interface {
+	GetLock(ctx context.Context, subject string) (string, time.Duration, bool, error)
+	AddFailure(ctx context.Context, subject string, window time.Duration) (int, error)
+	Lock(ctx context.Context, subject, reason string, d time.Duration) error
+	Reset(ctx context.Context, subject string) error
+}
+
+// AccountLockouts interface provides methods for working with the audit trail of the lockouts.
+type AccountLockouts interface {
+	Add(ctx context.Context, lockout model.AccountLockout) error
+	GetLockedIPs(ctx context.Context, email string) ([]string, error)
+}
+
+// TwoFactor interface provides methods for working with the authenticators and the recovery codes of the users.
+type TwoFactor interface {
+	GetTOTP(ctx context.Context, userID int) (model.TOTP, error)
+	SaveTOTP(ctx context.Context, userID int, secret string) error
+	EnableTOTP(ctx context.Context, userID int) error
+	DeleteTOTP(ctx context.Context, userID int) error
+	ReplaceRecoveryCodes(ctx context.Context, userID int, hashes []string) error
+	UseRecoveryCode(ctx context.Context, userID int, hash string) (bool, error)
+}
+
+// MFAChallenges interface provides methods for working with the sign-ins waiting for the second step and the used
+// TOTP codes, shared by all instances.
+type MFAChallenges interface {
+	Create(ctx context.Context, token string, userID int, ttl time.Duration) error
+	Get(ctx context.Context, token string) (int, bool, error)
+	Complete(ctx context.Context, token string) (bool, error)
+	UseCode(ctx context.Context, userID int, step int64, ttl time.Duration) (bool, error)
+}
+
+// Identities interface provides methods for working with the links of the users to the subjects of the
+// OpenID Connect providers.
+type Identities interface {
+	GetUserID(ctx context.Context, issuer, subject string) (int, error)
+	Link(ctx context.Context, identity model.UserIdentity) error
+}
+
+// OIDCStates interface provides methods for working with the sign-ins waiting for the callback of the
+// OpenID Connect provider and the one-time codes of the completed ones, shared by all instances.
+type OIDCStates interface {
+	Save(ctx context.Context, state string, s model.OIDCState, ttl time.Duration) error
+	Take(ctx context.Context, state string) (model.OIDCState, bool, error)
+	SaveCode(ctx context.Context, code string, userID int, ttl time.Duration) error
+	TakeCode(ctx context.Context, code string) (int, bool, error)
+}
+
+// PersonalAccessTokens interface provides methods for working with the personal access tokens of the users,
+// they are found by the hash of the token.
+type PersonalAccessTokens interface {
+	Create(ctx context.Context, token model.PersonalAccessToken) (model.PersonalAccessToken, error)
+	CountByUser(ctx context.Context, userID int) (int, error)
+	GetByUser(ctx context.Context, userID int) ([]model.PersonalAccessToken, error)
+	GetByHash(ctx context.Context, hash string) (model.PersonalAccessToken, error)
+	Delete(ctx context.Context, userID, tokenID int) error
+	Touch(ctx context.Context, tokenID int) error
+}
+
+// AuditEvents interface provides methods for working with the audit log of the administrative and
+// security-sensitive actions.
+type AuditEvents interface {
+	Add(ctx context.Context, event model.AuditEvent) error
+	Get(ctx context.Context, filter model.AuditEventsFilter) ([]model.AuditEvent, error)
+}
+
+// Notifications interface provides methods for working with the notifications of the users.
+type Notifications interface {
+	Add(ctx context.Context, notification model.Notification) error
+	GetByUserID(ctx context.Context, userID, limit int) ([]model.Notification, error)
+	MarkRead(ctx context.Context, userID, notificationID int) error
+}
+
+// Health interface provides methods for checking the connections to the databases.
+type Health interface {
+	PingDatabase(ctx context.Context) error
+	PingCache(ctx context.Context) error
+}
+
+// Transactional interface provides a unit of work spanning several repositories.
 type Transactional interface {
-	Begin(ctx context.Context, o *sql.TxOptions) (*sqlx.Tx, error)
-	Commit(ctx context.Context, tx *sqlx.Tx) error
-	Rollback(ctx context.Context, tx *sqlx.Tx) error
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
-func NewRepository(db *sqlx.DB, sess *session.Session) *Repository {
+func NewRepository(db *sqlx.DB, r *redis.Client, storage ObjectStorage) *Repository {
 	return &Repository{
 		Users:                NewUsersRepository(db),
 		Roles:                NewRolesRepository(db),
@@ -191,7 +350,24 @@ func NewRepository(db *sqlx.DB, sess *session.Session) *Repository {
 		CourseModules:        NewCourseModulesRepository(db),
 		CourseLessons:        NewCourseModuleLessonsRepository(db),
 		CourseLessonComments: NewCourseModuleLessonCommentsRepository(db),
-		S3Bucket:             NewS3BucketRepository(sess),
+		ObjectStorage:        storage,
 		Transactional:        NewTransactionManager(db),
+		StoredObjects:        NewStoredObjectsRepository(db),
+		Outbox:               NewOutboxRepository(db),
+		Settings:             NewSettingsRepository(r),
+		SettingsChanges:      NewSettingsChangesRepository(db),
+		FeatureFlags:         NewFeatureFlagsRepository(db),
+		FeatureFlagsCache:    NewFeatureFlagsCacheRepository(r),
+		RateLimiter:          NewRateLimiterRepository(r),
+		LoginAttempts:        NewLoginAttemptsRepository(r),
+		AccountLockouts:      NewAccountLockoutsRepository(db),
+		TwoFactor:            NewTwoFactorRepository(db),
+		MFAChallenges:        NewMFAChallengesRepository(r),
+		Identities:           NewIdentitiesRepository(db),
+		OIDCStates:           NewOIDCStatesRepository(r),
+		PersonalAccessTokens: NewPersonalAccessTokensRepository(db),
+		AuditEvents:          NewAuditEventsRepository(db),
+		Notifications:        NewNotificationsRepository(db),
+		Health:               NewHealthRepository(db, r),
 	}
 }
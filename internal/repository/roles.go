@@ -5,10 +5,8 @@ import (
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
-	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
-	"acsp/internal/apperror"
 	"acsp/internal/constants"
 	"acsp/internal/logging"
 	"acsp/internal/model"
@@ -32,7 +30,7 @@ func (r RolesDatabase) CreateRole(ctx context.Context, name string) error {
                RETURNING id`,
 		constants.RolesTable)
 
-	_, err := r.db.Exec(query, name)
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, name)
 	if err != nil {
 		l.Error("Error when creating new role in database", zap.Error(err))
 
@@ -51,7 +49,7 @@ func (r RolesDatabase) UpdateRole(ctx context.Context, roleID int, newName strin
 				WHERE id = $2`,
 		constants.RolesTable)
 
-	_, err := r.db.Exec(query, constants.RolesTable, roleID, newName)
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, constants.RolesTable, roleID, newName)
 	if err != nil {
 		l.Error("Error when updating role in database", zap.Error(err))
 
@@ -69,7 +67,7 @@ func (r RolesDatabase) DeleteRole(ctx context.Context, roleID int) error {
 				WHERE id = $1`,
 		constants.RolesTable)
 
-	_, err := r.db.Exec(query, roleID)
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, roleID)
 	if err != nil {
 		l.Error("Error when deleting role from database", zap.Error(err))
 
@@ -87,7 +85,7 @@ func (r RolesDatabase) SaveUserRole(ctx context.Context, userID, roleID int) err
 				VALUES ($1, $2) RETURNING id`,
 		constants.UserRolesTable)
 
-	_, err := r.db.Exec(query, userID, roleID)
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, userID, roleID)
 	if err != nil {
 		l.Error("Error when saving user role in database", zap.Error(err))
 
@@ -105,7 +103,7 @@ func (r RolesDatabase) DeleteUserRole(ctx context.Context, userID, roleID int) e
 				WHERE user_id = $1 AND role_id = $2`,
 		constants.UserRolesTable)
 
-	_, err := r.db.Exec(query, constants.UserRolesTable, userID, roleID)
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, constants.UserRolesTable, userID, roleID)
 	if err != nil {
 		l.Error("Error when deleting user role from database", zap.Error(err))
 
@@ -115,6 +113,19 @@ func (r RolesDatabase) DeleteUserRole(ctx context.Context, userID, roleID int) e
 	return nil
 }
 
+func (r RolesDatabase) GetRoleByName(ctx context.Context, name string) (model.Role, error) {
+	var role model.Role
+
+	query := fmt.Sprintf(`SELECT id, name FROM %s WHERE name = $1`, constants.RolesTable)
+
+	err := executor(ctx, r.db).GetContext(ctx, &role, query, name)
+	if err != nil {
+		return model.Role{}, dbError(err, "error when getting the role")
+	}
+
+	return role, nil
+}
+
 func (r RolesDatabase) GetUserRoles(ctx context.Context, userID int) ([]model.Role, error) {
 	l := logging.LoggerFromContext(ctx).With(zap.Int("userID", userID))
 
@@ -125,11 +136,11 @@ func (r RolesDatabase) GetUserRoles(ctx context.Context, userID int) ([]model.Ro
 				WHERE user_id = $1`,
 		constants.UserRolesTable, constants.RolesTable)
 
-	err := r.db.Select(&roles, query, userID)
+	err := executor(ctx, r.db).SelectContext(ctx, &roles, query, userID)
 	if err != nil {
 		l.Error("Error when getting user roles from database", zap.Error(err))
 
-		return nil, errors.Wrap(apperror.ErrUserNotFound, "User not found")
+		return nil, dbError(err, "error when getting the user roles")
 	}
 
 	return roles, err
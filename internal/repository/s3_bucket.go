@@ -2,38 +2,165 @@ package repository
 
 import (
 	"bytes"
+	"context"
 	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"acsp/internal/apperror"
+	"acsp/internal/metrics"
+	"acsp/internal/tracing"
+)
+
+const (
+	s3BucketAttribute = attribute.Key("aws.s3.bucket")
+	s3KeyAttribute    = attribute.Key("aws.s3.key")
 )
 
-// S3Repository implements the ObjectRepository interface using the AWS SDK for Go.
+// S3Repository implements the ObjectStorage interface using the AWS SDK for Go.
 type S3Repository struct {
-	sess *session.Session
+	sess   *session.Session
+	bucket string
 }
 
 // NewS3BucketRepository creates a new instance of S3Repository.
-func NewS3BucketRepository(sess *session.Session) *S3Repository {
-	return &S3Repository{sess: sess}
+func NewS3BucketRepository(sess *session.Session, bucket string) *S3Repository {
+	return &S3Repository{sess: sess, bucket: bucket}
 }
 
-// PutObject adds an object to an S3 bucket.
-func (r *S3Repository) PutObject(bucketName string, objectName string, fileBytes []byte) error {
+// PutObject adds a private object to an S3 bucket. The uploads are staged, the object is made public by
+// CopyObject to its final key once its owner is committed.
+func (r *S3Repository) PutObject(ctx context.Context, key string, fileBytes []byte) error {
 	svc := s3.New(r.sess)
 
-	_, err := svc.PutObject(&s3.PutObjectInput{
-		Bucket:      aws.String(bucketName),
-		Key:         aws.String(objectName),
-		ACL:         aws.String("public-read"),
+	ctx, span := r.startSpan(ctx, "PutObject", key)
+	start := time.Now()
+
+	_, err := svc.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(r.bucket),
+		Key:         aws.String(key),
 		Body:        bytes.NewReader(fileBytes),
 		ContentType: aws.String(http.DetectContentType(fileBytes)),
 	})
+
+	metrics.S3UploadDuration.WithLabelValues(metrics.Result(err)).Observe(metrics.Since(start))
+	tracing.End(span, err)
+
 	if err != nil {
 		return errors.Wrap(err, "Error occurred when uploading file to S3")
 	}
 
 	return nil
 }
+
+// CopyObject copies an object inside an S3 bucket, replacing the destination object. The copy is public.
+func (r *S3Repository) CopyObject(ctx context.Context, srcKey, dstKey string) error {
+	svc := s3.New(r.sess)
+
+	ctx, span := r.startSpan(ctx, "CopyObject", dstKey)
+
+	_, err := svc.CopyObjectWithContext(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(r.bucket),
+		CopySource: aws.String(url.PathEscape(r.bucket + "/" + srcKey)),
+		Key:        aws.String(dstKey),
+		ACL:        aws.String("public-read"),
+	})
+
+	tracing.End(span, err)
+
+	if err != nil {
+		var aerr awserr.Error
+		if errors.As(err, &aerr) && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return errors.Wrapf(apperror.ErrObjectNotFound, "key %q", srcKey)
+		}
+
+		return errors.Wrap(err, "Error occurred when copying file in S3")
+	}
+
+	return nil
+}
+
+// HeadObject checks that an object exists in an S3 bucket.
+func (r *S3Repository) HeadObject(ctx context.Context, key string) error {
+	svc := s3.New(r.sess)
+
+	ctx, span := r.startSpan(ctx, "HeadObject", key)
+
+	_, err := svc.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(r.bucket),
+		Key:    aws.String(key),
+	})
+
+	tracing.End(span, err)
+
+	if err != nil {
+		// HEAD responses have no body, so a missing object is reported as NotFound rather than NoSuchKey
+		var aerr awserr.Error
+		if errors.As(err, &aerr) && (aerr.Code() == "NotFound" || aerr.Code() == s3.ErrCodeNoSuchKey) {
+			return errors.Wrapf(apperror.ErrObjectNotFound, "key %q", key)
+		}
+
+		return errors.Wrap(err, "Error occurred when checking file in S3")
+	}
+
+	return nil
+}
+
+// DeleteObject removes an object from an S3 bucket, deleting a missing object is not an error.
+func (r *S3Repository) DeleteObject(ctx context.Context, key string) error {
+	svc := s3.New(r.sess)
+
+	ctx, span := r.startSpan(ctx, "DeleteObject", key)
+
+	_, err := svc.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(r.bucket),
+		Key:    aws.String(key),
+	})
+
+	tracing.End(span, err)
+
+	if err != nil {
+		return errors.Wrap(err, "Error occurred when deleting file from S3")
+	}
+
+	return nil
+}
+
+// Ping checks that the bucket exists and the credentials have access to it.
+func (r *S3Repository) Ping(ctx context.Context) error {
+	svc := s3.New(r.sess)
+
+	ctx, span := r.startSpan(ctx, "HeadBucket", "")
+
+	_, err := svc.HeadBucketWithContext(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(r.bucket),
+	})
+
+	tracing.End(span, err)
+
+	if err != nil {
+		return errors.Wrap(err, "Error occurred when checking S3 bucket")
+	}
+
+	return nil
+}
+
+// startSpan starts the span of a call of the S3 API
+func (r *S3Repository) startSpan(ctx context.Context, operation, key string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "s3 "+operation, trace.SpanKindClient,
+		semconv.RPCSystemKey.String("aws-api"),
+		semconv.RPCService("S3"),
+		semconv.RPCMethod(operation),
+		s3BucketAttribute.String(r.bucket),
+		s3KeyAttribute.String(key),
+	)
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"acsp/internal/constants"
+)
+
+// Schema lists the tables and columns referenced by the queries of the repositories.
+// It is checked against the database on start-up. The tests of infrastructure/db check that the queries use
+// only these and that the migrations create all of them.
+var Schema = map[string][]string{
+	constants.UsersTable: {
+		"id", "email", "name", "password", "created_at", "updated_at", "is_admin", "roles", "image_url",
+	},
+	constants.UserDetailsTable: {
+		"id", "user_id", "first_name", "last_name", "phone_number", "specialization", "updated_at",
+	},
+	constants.RolesTable:     {"id", "name"},
+	constants.UserRolesTable: {"id", "user_id", "role_id"},
+	constants.ArticlesTable: {
+		"id", "user_id", "topic", "description", "upvote", "downvote", "image_url", "created_at", "updated_at",
+	},
+	constants.MaterialsTable: {
+		"id", "user_id", "topic", "description", "upvote", "downvote", "created_at", "updated_at",
+	},
+	constants.ArticlesCommentsTable: {
+		"id", "user_id", "article_id", "parent_id", "text", "upvote", "downvote", "created_at", "updated_at",
+	},
+	constants.ArticleCommentVotesTable: {"id", "user_id", "comment_id", "vote_type"},
+	constants.CardsTable: {
+		"id", "user_id", "position", "skills", "description", "created_at", "updated_at",
+	},
+	constants.CardInvitationsTable: {
+		"id", "card_id", "inviter_id", "status", "feedback", "created_at", "updated_at",
+	},
+	constants.ContestsTable: {
+		"id", "contest_name", "description", "link", "start_date", "end_date", "created_at",
+	},
+	constants.CodingLabDisciplinesTable: {
+		"id", "title", "description", "image_url", "created_at", "updated_at",
+	},
+	constants.CodingLabProjectsTable: {
+		"id", "discipline_id", "title", "description", "level", "image_url", "work_hours", "created_at", "updated_at",
+	},
+	constants.CodingLabProjectModulesTable: {
+		"id", "project_id", "title", "description", "reference_url", "created_at", "updated_at",
+	},
+	constants.CoursesTable: {
+		"id", "author_id", "title", "description", "rating", "image_url", "created_at", "updated_at",
+	},
+	constants.CourseModulesTable: {
+		"id", "course_id", "title", "expected_result", "created_at", "updated_at",
+	},
+	constants.CourseModuleLessonsTable: {
+		"id", "module_id", "title", "description", "reference_url", "created_at", "updated_at",
+	},
+	constants.CourseLessonCommentsTable: {
+		"id", "lesson_id", "user_id", "text", "created_at", "updated_at",
+	},
+	constants.CourseLessonCommentsAnswersTable: {
+		"id", "comment_id", "answer", "created_at", "updated_at",
+	},
+	constants.StoredObjectsTable: {
+		"id", "object_key", "owner_type", "owner_id", "created_at",
+	},
+	constants.OutboxEventsTable: {
+		"id", "event_type", "idempotency_key", "payload", "status", "attempts", "last_error", "next_attempt_at",
+		"created_at", "processed_at",
+	},
+	constants.SettingsChangesTable: {"id", "user_id", "before", "after", "created_at"},
+	constants.FeatureFlagsTable: {
+		"key", "description", "enabled", "roles", "user_ids", "percentage", "created_at", "updated_at",
+	},
+	constants.AccountLockoutsTable: {"id", "user_id", "email", "ip", "failures", "locked_until", "created_at"},
+	constants.UserTOTPTable:        {"user_id", "secret", "enabled", "created_at", "enabled_at"},
+	constants.RecoveryCodesTable:   {"id", "user_id", "code_hash", "used_at", "created_at"},
+	constants.UserIdentitiesTable:  {"id", "user_id", "issuer", "subject", "email", "created_at"},
+	constants.PersonalAccessTokensTable: {
+		"id", "user_id", "name", "token_hash", "hint", "scopes", "expires_at", "last_used_at", "created_at",
+	},
+	constants.AuditEventsTable: {
+		"id", "actor_id", "actor_token_id", "action", "target_type", "target_id", "changes", "ip", "request_id",
+		"created_at",
+	},
+	constants.NotificationsTable: {
+		"id", "user_id", "kind", "payload", "idempotency_key", "read_at", "created_at",
+	},
+}
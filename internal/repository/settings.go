@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"acsp/internal/constants"
+	"acsp/internal/logging"
+	"acsp/internal/model"
+)
+
+type SettingsRedis struct {
+	redis *redis.Client
+}
+
+func NewSettingsRepository(r *redis.Client) *SettingsRedis {
+	return &SettingsRedis{
+		redis: r,
+	}
+}
+
+// Get returns the stored runtime settings, found is false when they were never changed.
+func (s *SettingsRedis) Get(ctx context.Context) (model.RuntimeSettings, bool, error) {
+	var settings model.RuntimeSettings
+
+	b, err := s.redis.Get(ctx, constants.RuntimeSettingsKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return settings, false, nil
+	}
+
+	if err != nil {
+		return settings, false, errors.Wrap(err, "error when getting the runtime settings")
+	}
+
+	err = json.Unmarshal(b, &settings)
+	if err != nil {
+		return settings, false, errors.Wrap(err, "error when decoding the runtime settings")
+	}
+
+	return settings, true, nil
+}
+
+// Update replaces the stored runtime settings with the result of fn. The key is watched, so that concurrent
+// updates of several instances don't overwrite each other, and the version of the settings is incremented.
+func (s *SettingsRedis) Update(
+	ctx context.Context,
+	fn func(current model.RuntimeSettings, found bool) (model.RuntimeSettings, error),
+) (model.RuntimeSettings, error) {
+	var updated model.RuntimeSettings
+
+	err := s.redis.Watch(ctx, func(tx *redis.Tx) error {
+		current, found, err := s.Get(ctx)
+		if err != nil {
+			return err
+		}
+
+		updated, err = fn(current, found)
+		if err != nil {
+			return err
+		}
+
+		updated.Version = current.Version + 1
+		updated.UpdatedAt = time.Now().UTC()
+
+		b, err := json.Marshal(updated)
+		if err != nil {
+			return errors.Wrap(err, "error when encoding the runtime settings")
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, constants.RuntimeSettingsKey, b, 0)
+
+			return nil
+		})
+
+		return err
+	}, constants.RuntimeSettingsKey)
+	if err != nil {
+		return updated, errors.Wrap(err, "error when updating the runtime settings")
+	}
+
+	return updated, nil
+}
+
+type SettingsChangesDatabase struct {
+	db *sqlx.DB
+}
+
+func NewSettingsChangesRepository(db *sqlx.DB) *SettingsChangesDatabase {
+	return &SettingsChangesDatabase{
+		db: db,
+	}
+}
+
+// Add records a change of the runtime settings.
+func (s *SettingsChangesDatabase) Add(ctx context.Context, change model.SettingsChange) error {
+	l := logging.LoggerFromContext(ctx).With(zap.Int("userID", change.UserID))
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`INSERT INTO %s (user_id, before, after) VALUES ($1, $2, $3)`,
+		constants.SettingsChangesTable)
+
+	_, err := executor(ctx, s.db).ExecContext(ctx, query, change.UserID, []byte(change.Before), []byte(change.After))
+	if err != nil {
+		l.Error("Error when recording the settings change", zap.Error(err))
+
+		return errors.Wrap(err, "error when executing query")
+	}
+
+	return nil
+}
+
+// GetLatest returns the latest changes of the runtime settings, the newest first.
+func (s *SettingsChangesDatabase) GetLatest(ctx context.Context, limit int) ([]model.SettingsChange, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`SELECT id, user_id, before, after, created_at FROM %s ORDER BY id DESC LIMIT $1`,
+		constants.SettingsChangesTable)
+
+	var changes []model.SettingsChange
+
+	err := executor(ctx, s.db).SelectContext(ctx, &changes, query, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when executing query")
+	}
+
+	return changes, nil
+}
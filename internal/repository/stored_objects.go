@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"acsp/internal/constants"
+	"acsp/internal/logging"
+	"acsp/internal/model"
+)
+
+// ownerTables maps the folder of a stored object to the table of the rows that own objects of this folder.
+// Every owner table keeps the path of its current object in the image_url column.
+var ownerTables = []struct {
+	folder string
+	table  string
+}{
+	{constants.UsersAvatarsFolder, constants.UsersTable},
+	{constants.ArticlesImagesFolder, constants.ArticlesTable},
+	{constants.DisciplinesImagesFolder, constants.CodingLabDisciplinesTable},
+	{constants.ProjectsImagesFolder, constants.CodingLabProjectsTable},
+	{constants.CoursesImagesFolder, constants.CoursesTable},
+}
+
+type StoredObjectsDatabase struct {
+	db *sqlx.DB
+}
+
+func NewStoredObjectsRepository(db *sqlx.DB) *StoredObjectsDatabase {
+	return &StoredObjectsDatabase{
+		db: db,
+	}
+}
+
+// Track registers an object in the registry, registering the same key twice is not an error.
+func (s *StoredObjectsDatabase) Track(ctx context.Context, object model.StoredObject) error {
+	l := logging.LoggerFromContext(ctx).With(zap.String("objectKey", object.Key))
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`INSERT INTO %s (object_key, owner_type, owner_id)
+								VALUES ($1, $2, $3)
+								ON CONFLICT (object_key) DO NOTHING`,
+		constants.StoredObjectsTable)
+
+	_, err := executor(ctx, s.db).ExecContext(ctx, query, object.Key, object.OwnerType, object.OwnerID)
+	if err != nil {
+		l.Error("Error when tracking the stored object in database", zap.Error(err))
+
+		return dbError(err, "error when executing query")
+	}
+
+	return nil
+}
+
+// GetOrphans returns the objects created before the given time, whose owner is deleted
+// or doesn't point to the object anymore (e.g. a replaced avatar).
+func (s *StoredObjectsDatabase) GetOrphans(
+	ctx context.Context, createdBefore time.Time, limit int) ([]model.StoredObject, error) {
+	l := logging.LoggerFromContext(ctx)
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
+	defer cancel()
+
+	conditions := make([]string, 0, len(ownerTables))
+	for _, o := range ownerTables {
+		conditions = append(conditions, fmt.Sprintf(
+			`(so.owner_type = '%[1]s' AND NOT EXISTS(
+				SELECT 1 FROM %[2]s o WHERE o.id = so.owner_id AND '%[1]s' || o.image_url = so.object_key))`,
+			o.folder, o.table))
+	}
+
+	// Staged uploads are moved by the outbox worker, they are orphaned when no pending event is going to move them
+	conditions = append(conditions, fmt.Sprintf(
+		`(so.owner_type = '%s' AND NOT EXISTS(
+			SELECT 1 FROM %s e WHERE e.status = '%s' AND e.payload->>'staging_key' = so.object_key))`,
+		constants.StagingFolder, constants.OutboxEventsTable, constants.OutboxPendingStatus))
+
+	query := fmt.Sprintf(`SELECT
+									so.id,
+									so.object_key,
+									so.owner_type,
+									so.owner_id,
+									so.created_at
+								FROM %s so
+								WHERE so.created_at < $1 AND (%s)
+								ORDER BY so.id
+								LIMIT $2`,
+		constants.StoredObjectsTable, strings.Join(conditions, " OR "))
+
+	var objects []model.StoredObject
+
+	err := executor(ctx, s.db).SelectContext(ctx, &objects, query, createdBefore, limit)
+	if err != nil {
+		l.Error("Error when getting orphaned objects from database", zap.Error(err))
+
+		return nil, dbError(err, "error when executing query")
+	}
+
+	return objects, nil
+}
+
+// Delete removes an object from the registry.
+func (s *StoredObjectsDatabase) Delete(ctx context.Context, key string) error {
+	l := logging.LoggerFromContext(ctx).With(zap.String("objectKey", key))
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE object_key = $1`, constants.StoredObjectsTable)
+
+	_, err := executor(ctx, s.db).ExecContext(ctx, query, key)
+	if err != nil {
+		l.Error("Error when deleting the stored object from database", zap.Error(err))
+
+		return dbError(err, "error when executing query")
+	}
+
+	return nil
+}
+
+// ResetOwnerImage points the owner of the object back to the default image, if it still points to the object.
+func (s *StoredObjectsDatabase) ResetOwnerImage(ctx context.Context, object model.StoredObject) error {
+	l := logging.LoggerFromContext(ctx).With(zap.String("objectKey", object.Key))
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	for _, o := range ownerTables {
+		if o.folder != object.OwnerType {
+			continue
+		}
+
+		query := fmt.Sprintf(`UPDATE %s SET image_url = DEFAULT WHERE id = $1 AND $2 || image_url = $3`, o.table)
+
+		_, err := executor(ctx, s.db).ExecContext(ctx, query, object.OwnerID, o.folder, object.Key)
+		if err != nil {
+			l.Error("Error when resetting the image of the owner", zap.Error(err))
+
+			return dbError(err, "error when executing query")
+		}
+
+		return nil
+	}
+
+	return errors.Errorf("unknown owner type %q", object.OwnerType)
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"acsp/internal/tracing"
+)
+
+// tracedExecutor records a span per query of the executor. The spans of the queries returning rows
+// and of the prepared statements end when the rows or the statement are returned.
+type tracedExecutor struct {
+	QueryExecutor
+}
+
+func (t tracedExecutor) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := startQuery(ctx, query)
+
+	res, err := t.QueryExecutor.ExecContext(ctx, query, args...)
+
+	tracing.End(span, err)
+
+	return res, err
+}
+
+func (t tracedExecutor) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := startQuery(ctx, query)
+
+	rows, err := t.QueryExecutor.QueryContext(ctx, query, args...)
+
+	tracing.End(span, err)
+
+	return rows, err
+}
+
+func (t tracedExecutor) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	ctx, span := startQuery(ctx, query)
+
+	rows, err := t.QueryExecutor.QueryxContext(ctx, query, args...)
+
+	tracing.End(span, err)
+
+	return rows, err
+}
+
+func (t tracedExecutor) QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row {
+	ctx, span := startQuery(ctx, query)
+
+	row := t.QueryExecutor.QueryRowxContext(ctx, query, args...)
+
+	tracing.End(span, row.Err())
+
+	return row
+}
+
+func (t tracedExecutor) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, span := startQuery(ctx, query)
+
+	row := t.QueryExecutor.QueryRowContext(ctx, query, args...)
+
+	tracing.End(span, row.Err())
+
+	return row
+}
+
+func (t tracedExecutor) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	ctx, span := startQuery(ctx, query)
+
+	stmt, err := t.QueryExecutor.PrepareContext(ctx, query)
+
+	tracing.End(span, err)
+
+	return stmt, err
+}
+
+func (t tracedExecutor) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	ctx, span := startQuery(ctx, query)
+
+	err := t.QueryExecutor.GetContext(ctx, dest, query, args...)
+
+	// No rows is an answer of the query rather than its failure
+	if err == sql.ErrNoRows {
+		tracing.End(span, nil)
+	} else {
+		tracing.End(span, err)
+	}
+
+	return err
+}
+
+func (t tracedExecutor) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	ctx, span := startQuery(ctx, query)
+
+	err := t.QueryExecutor.SelectContext(ctx, dest, query, args...)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+// startQuery starts the span of a query, the statement is recorded without its arguments
+func startQuery(ctx context.Context, query string) (context.Context, trace.Span) {
+	statement := strings.Join(strings.Fields(query), " ")
+
+	operation := statement
+	if i := strings.IndexByte(statement, ' '); i > 0 {
+		operation = statement[:i]
+	}
+
+	operation = strings.ToUpper(operation)
+
+	return tracing.Start(ctx, "db "+operation, trace.SpanKindClient,
+		semconv.DBSystemPostgreSQL,
+		semconv.DBOperation(operation),
+		semconv.DBStatement(statement),
+	)
+}
@@ -6,8 +6,39 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
+
+	"acsp/internal/apperror"
+)
+
+// QueryExecutor is implemented by both *sqlx.DB and *sqlx.Tx.
+// Repositories run their queries through the executor of the context, see executor.
+type QueryExecutor interface {
+	sqlx.ExtContext
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+var (
+	_ QueryExecutor = (*sqlx.DB)(nil)
+	_ QueryExecutor = (*sqlx.Tx)(nil)
 )
 
+// txKey is the key of the transaction in the context
+type txKey struct{}
+
+// executor returns the transaction of the context, or the database when the context has no transaction.
+// The queries of the executor are traced.
+func executor(ctx context.Context, db *sqlx.DB) QueryExecutor {
+	tx, ok := ctx.Value(txKey{}).(*sqlx.Tx)
+	if ok {
+		return tracedExecutor{QueryExecutor: tx}
+	}
+
+	return tracedExecutor{QueryExecutor: db}
+}
+
 // TransactionManager is a struct that manages transactions
 type TransactionManager struct {
 	db *sqlx.DB
@@ -18,23 +49,40 @@ func NewTransactionManager(db *sqlx.DB) *TransactionManager {
 	return &TransactionManager{db: db}
 }
 
-func (t *TransactionManager) Begin(ctx context.Context, o *sql.TxOptions) (*sqlx.Tx, error) {
-	tx, err := t.db.BeginTxx(ctx, o)
+// WithinTransaction runs fn in a transaction put into its context, so that every repository called with
+// this context takes part in the transaction. The transaction is committed when fn returns nil and rolled back
+// otherwise. Nested calls join the transaction of the outer call.
+func (t *TransactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return nil, errors.Wrap(err, "Error when beginning the transaction")
+		return errors.Wrap(err, "Error when beginning the transaction")
 	}
 
-	return tx, nil
-}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
 
-func (t *TransactionManager) Commit(ctx context.Context, tx *sqlx.Tx) error {
-	return tx.Commit()
-}
+			panic(p)
+		}
+	}()
+
+	err = fn(context.WithValue(ctx, txKey{}, tx))
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return errors.Wrapf(apperror.ErrRollback, "%v: %v", err, rollbackErr)
+		}
+
+		return err
+	}
 
-func (t *TransactionManager) Rollback(ctx context.Context, tx *sqlx.Tx) error {
-	err := tx.Rollback()
+	err = tx.Commit()
 	if err != nil {
-		return errors.Wrap(err, "Error when rolling back the transaction")
+		return errors.Wrap(err, "Error when committing the transaction")
 	}
 
 	return nil
@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"acsp/internal/constants"
+	"acsp/internal/logging"
+	"acsp/internal/model"
+)
+
+type TwoFactorDatabase struct {
+	db *sqlx.DB
+}
+
+func NewTwoFactorRepository(db *sqlx.DB) *TwoFactorDatabase {
+	return &TwoFactorDatabase{
+		db: db,
+	}
+}
+
+// GetTOTP returns the authenticator of the user.
+func (t *TwoFactorDatabase) GetTOTP(ctx context.Context, userID int) (model.TOTP, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`SELECT user_id, secret, enabled, created_at, enabled_at FROM %s WHERE user_id = $1`,
+		constants.UserTOTPTable)
+
+	var totp model.TOTP
+
+	err := executor(ctx, t.db).GetContext(ctx, &totp, query, userID)
+	if err != nil {
+		return model.TOTP{}, dbError(err, "error when getting the authenticator")
+	}
+
+	return totp, nil
+}
+
+// SaveTOTP enrolls a new secret of the user, it replaces the secret of an authenticator that isn't enabled.
+func (t *TwoFactorDatabase) SaveTOTP(ctx context.Context, userID int, secret string) error {
+	l := logging.LoggerFromContext(ctx).With(zap.Int("userID", userID))
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`INSERT INTO %s (user_id, secret) VALUES ($1, $2)
+								ON CONFLICT (user_id) DO UPDATE SET secret = excluded.secret, created_at = now()
+								WHERE NOT %[1]s.enabled`,
+		constants.UserTOTPTable)
+
+	_, err := executor(ctx, t.db).ExecContext(ctx, query, userID, secret)
+	if err != nil {
+		l.Error("Error when enrolling the authenticator", zap.Error(err))
+
+		return dbError(err, "error when executing query")
+	}
+
+	return nil
+}
+
+// EnableTOTP enables the authenticator of the user.
+func (t *TwoFactorDatabase) EnableTOTP(ctx context.Context, userID int) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`UPDATE %s SET enabled = true, enabled_at = now() WHERE user_id = $1`,
+		constants.UserTOTPTable)
+
+	_, err := executor(ctx, t.db).ExecContext(ctx, query, userID)
+	if err != nil {
+		return dbError(err, "error when executing query")
+	}
+
+	return nil
+}
+
+// DeleteTOTP removes the authenticator and the recovery codes of the user.
+func (t *TwoFactorDatabase) DeleteTOTP(ctx context.Context, userID int) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	for _, table := range []string{constants.RecoveryCodesTable, constants.UserTOTPTable} {
+		query := fmt.Sprintf(`DELETE FROM %s WHERE user_id = $1`, table)
+
+		_, err := executor(ctx, t.db).ExecContext(ctx, query, userID)
+		if err != nil {
+			return dbError(err, "error when executing query")
+		}
+	}
+
+	return nil
+}
+
+// ReplaceRecoveryCodes replaces the recovery codes of the user with the hashes.
+func (t *TwoFactorDatabase) ReplaceRecoveryCodes(ctx context.Context, userID int, hashes []string) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE user_id = $1`, constants.RecoveryCodesTable)
+
+	_, err := executor(ctx, t.db).ExecContext(ctx, query, userID)
+	if err != nil {
+		return dbError(err, "error when executing query")
+	}
+
+	query = fmt.Sprintf(`INSERT INTO %s (user_id, code_hash) SELECT $1, unnest($2::text[])`,
+		constants.RecoveryCodesTable)
+
+	_, err = executor(ctx, t.db).ExecContext(ctx, query, userID, pq.Array(hashes))
+	if err != nil {
+		return dbError(err, "error when executing query")
+	}
+
+	return nil
+}
+
+// UseRecoveryCode marks the unused recovery code of the hash as used, it returns false when there is none.
+func (t *TwoFactorDatabase) UseRecoveryCode(ctx context.Context, userID int, hash string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	query := fmt.Sprintf(`UPDATE %s SET used_at = now() WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`,
+		constants.RecoveryCodesTable)
+
+	res, err := executor(ctx, t.db).ExecContext(ctx, query, userID, hash)
+	if err != nil {
+		return false, dbError(err, "error when executing query")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, dbError(err, "error when getting the affected rows")
+	}
+
+	return affected > 0, nil
+}
+
+type MFAChallengesRedis struct {
+	redis *redis.Client
+}
+
+func NewMFAChallengesRepository(r *redis.Client) *MFAChallengesRedis {
+	return &MFAChallengesRedis{
+		redis: r,
+	}
+}
+
+// Create stores the challenge of the user for ttl.
+func (m *MFAChallengesRedis) Create(ctx context.Context, token string, userID int, ttl time.Duration) error {
+	err := m.redis.Set(ctx, constants.MFAChallengeKeyPrefix+token, userID, ttl).Err()
+	if err != nil {
+		return errors.Wrap(err, "error when creating the challenge")
+	}
+
+	return nil
+}
+
+// Get returns the user of the challenge, found is false when the challenge expired or was completed.
+func (m *MFAChallengesRedis) Get(ctx context.Context, token string) (int, bool, error) {
+	v, err := m.redis.Get(ctx, constants.MFAChallengeKeyPrefix+token).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, false, nil
+	}
+
+	if err != nil {
+		return 0, false, errors.Wrap(err, "error when getting the challenge")
+	}
+
+	userID, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, false, errors.Wrap(err, "error when decoding the challenge")
+	}
+
+	return userID, true, nil
+}
+
+// Complete deletes the challenge, it returns false when the challenge was completed by another request.
+func (m *MFAChallengesRedis) Complete(ctx context.Context, token string) (bool, error) {
+	n, err := m.redis.Del(ctx, constants.MFAChallengeKeyPrefix+token).Result()
+	if err != nil {
+		return false, errors.Wrap(err, "error when completing the challenge")
+	}
+
+	return n > 0, nil
+}
+
+// UseCode marks the TOTP code of the step as used by the user for ttl, it returns false when it was used.
+func (m *MFAChallengesRedis) UseCode(ctx context.Context, userID int, step int64, ttl time.Duration) (bool, error) {
+	key := fmt.Sprintf("%s%d:%d", constants.TOTPUsedKeyPrefix, userID, step)
+
+	ok, err := m.redis.SetNX(ctx, key, 1, ttl).Result()
+	if err != nil {
+		return false, errors.Wrap(err, "error when using the code")
+	}
+
+	return ok, nil
+}
@@ -0,0 +1,171 @@
+// Package seed loads fixtures of local development data into the database, see the seed command.
+package seed
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Fixtures is the data loaded by the seed command. Users are referenced by their emails.
+type Fixtures struct {
+	Users    []UserFixture    `yaml:"users" json:"users"`
+	Courses  []CourseFixture  `yaml:"courses" json:"courses"`
+	Articles []ArticleFixture `yaml:"articles" json:"articles"`
+	Cards    []CardFixture    `yaml:"cards" json:"cards"`
+}
+
+type UserFixture struct {
+	Email    string `yaml:"email" json:"email"`
+	Name     string `yaml:"name" json:"name"`
+	Password string `yaml:"password" json:"password"` // plain text, hashed when the user is created
+	// Roles are names of the roles table, the user role is given to everyone
+	Roles   []string            `yaml:"roles" json:"roles"`
+	Details *UserDetailsFixture `yaml:"details" json:"details"`
+}
+
+type UserDetailsFixture struct {
+	FirstName      string `yaml:"first_name" json:"first_name"`
+	LastName       string `yaml:"last_name" json:"last_name"`
+	PhoneNumber    string `yaml:"phone_number" json:"phone_number"`
+	Specialization string `yaml:"specialization" json:"specialization"`
+}
+
+type CourseFixture struct {
+	Author      string          `yaml:"author" json:"author"`
+	Title       string          `yaml:"title" json:"title"`
+	Description string          `yaml:"description" json:"description"`
+	Rating      int             `yaml:"rating" json:"rating"`
+	Modules     []ModuleFixture `yaml:"modules" json:"modules"`
+}
+
+type ModuleFixture struct {
+	Title          string          `yaml:"title" json:"title"`
+	ExpectedResult string          `yaml:"expected_result" json:"expected_result"`
+	Lessons        []LessonFixture `yaml:"lessons" json:"lessons"`
+}
+
+type LessonFixture struct {
+	Title        string `yaml:"title" json:"title"`
+	Description  string `yaml:"description" json:"description"`
+	ReferenceURL string `yaml:"reference_url" json:"reference_url"`
+}
+
+type ArticleFixture struct {
+	Author      string           `yaml:"author" json:"author"`
+	Topic       string           `yaml:"topic" json:"topic"`
+	Description string           `yaml:"description" json:"description"`
+	Comments    []CommentFixture `yaml:"comments" json:"comments"`
+}
+
+type CommentFixture struct {
+	Author  string           `yaml:"author" json:"author"`
+	Text    string           `yaml:"text" json:"text"`
+	Replies []CommentFixture `yaml:"replies" json:"replies"`
+}
+
+type CardFixture struct {
+	Author      string              `yaml:"author" json:"author"`
+	Position    string              `yaml:"position" json:"position"`
+	Skills      []string            `yaml:"skills" json:"skills"`
+	Description string              `yaml:"description" json:"description"`
+	Invitations []InvitationFixture `yaml:"invitations" json:"invitations"`
+}
+
+type InvitationFixture struct {
+	Inviter  string `yaml:"inviter" json:"inviter"`
+	Status   string `yaml:"status" json:"status"`
+	Feedback string `yaml:"feedback" json:"feedback"`
+}
+
+// emailDomain is the domain of the emails the users sign up with, see dto.CreateUser
+const emailDomain = "@astanait.edu.kz"
+
+// Load reads and merges the .yml, .yaml and .json fixtures of the root of fsys in the order of their names.
+func Load(fsys fs.FS) (Fixtures, error) {
+	var all Fixtures
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return all, errors.Wrap(err, "error when reading fixtures")
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+
+		var unmarshal func([]byte, interface{}) error
+
+		switch path.Ext(e.Name()) {
+		case ".yml", ".yaml":
+			unmarshal = yaml.Unmarshal
+		case ".json":
+			unmarshal = json.Unmarshal
+		default:
+			continue
+		}
+
+		body, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return all, errors.Wrapf(err, "error when reading fixture %s", e.Name())
+		}
+
+		var f Fixtures
+
+		err = unmarshal(body, &f)
+		if err != nil {
+			return all, errors.Wrapf(err, "error when parsing fixture %s", e.Name())
+		}
+
+		all.Users = append(all.Users, f.Users...)
+		all.Courses = append(all.Courses, f.Courses...)
+		all.Articles = append(all.Articles, f.Articles...)
+		all.Cards = append(all.Cards, f.Cards...)
+	}
+
+	return all, all.validate()
+}
+
+// validate checks the required fields, so that a typo doesn't end up as an empty row
+func (f Fixtures) validate() error {
+	for _, u := range f.Users {
+		if u.Email == "" || u.Name == "" || u.Password == "" {
+			return fmt.Errorf("user %q must have an email, a name and a password", u.Email)
+		}
+
+		if !strings.HasSuffix(u.Email, emailDomain) {
+			return fmt.Errorf("user %q must have an email of %s", u.Email, emailDomain)
+		}
+	}
+
+	for _, c := range f.Courses {
+		if c.Author == "" || c.Title == "" {
+			return fmt.Errorf("course %q must have an author and a title", c.Title)
+		}
+	}
+
+	for _, a := range f.Articles {
+		if a.Author == "" || a.Topic == "" {
+			return fmt.Errorf("article %q must have an author and a topic", a.Topic)
+		}
+	}
+
+	for _, c := range f.Cards {
+		if c.Author == "" || c.Position == "" {
+			return fmt.Errorf("card %q must have an author and a position", c.Position)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package seed
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+
+	"acsp/fixtures"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"01_users.yml": {Data: []byte(`
+users:
+  - email: a@astanait.edu.kz
+    name: A
+    password: secret
+    roles: [admin]
+`)},
+		"02_cards.json": {Data: []byte(`{"cards": [{"author": "a@astanait.edu.kz", "position": "Backend", "skills": ["Go"],
+			"invitations": [{"inviter": "a@astanait.edu.kz"}]}]}`)},
+		"README.md": {Data: []byte("not a fixture")},
+	}
+
+	f, err := Load(fsys)
+	assert.NoError(t, err)
+	assert.Equal(t, []UserFixture{{Email: "a@astanait.edu.kz", Name: "A", Password: "secret", Roles: []string{"admin"}}},
+		f.Users)
+	assert.Equal(t, []CardFixture{{
+		Author:      "a@astanait.edu.kz",
+		Position:    "Backend",
+		Skills:      []string{"Go"},
+		Invitations: []InvitationFixture{{Inviter: "a@astanait.edu.kz"}},
+	}}, f.Cards)
+
+	_, err = Load(fstest.MapFS{"users.yml": {Data: []byte("users:\n  - email: a@astanait.edu.kz\n")}})
+	assert.Error(t, err)
+
+	// The users couldn't sign up with another domain
+	_, err = Load(fstest.MapFS{"users.yml": {Data: []byte(`users: [{email: a@acsp.dev, name: A, password: secret}]`)}})
+	assert.EqualError(t, err, `user "a@acsp.dev" must have an email of @astanait.edu.kz`)
+}
+
+func TestLoad_EmbeddedFixtures(t *testing.T) {
+	f, err := Load(fixtures.FS)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, f.Users)
+	assert.NotEmpty(t, f.Courses)
+	assert.NotEmpty(t, f.Articles)
+	assert.NotEmpty(t, f.Cards)
+}
@@ -0,0 +1,376 @@
+package seed
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
+
+	"acsp/internal/constants"
+)
+
+// Report counts the rows created by a seed, rows that already existed are not counted
+type Report struct {
+	Users       int
+	Courses     int
+	Modules     int
+	Lessons     int
+	Articles    int
+	Comments    int
+	Cards       int
+	Invitations int
+}
+
+// Seeder writes fixtures into the database. Every row is looked up by its natural key first
+// (e.g. the email of a user or the title of a course of an author), so seeding twice changes nothing.
+type Seeder struct {
+	db *sqlx.DB
+}
+
+func NewSeeder(db *sqlx.DB) *Seeder {
+	return &Seeder{db: db}
+}
+
+// Seed writes the fixtures in one transaction.
+func (s *Seeder) Seed(ctx context.Context, f Fixtures) (report Report, err error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return report, errors.Wrap(err, "error when beginning the transaction")
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+
+			return
+		}
+
+		err = errors.Wrap(tx.Commit(), "error when committing the transaction")
+	}()
+
+	r := &run{ctx: ctx, tx: tx, users: map[string]int{}, report: &report}
+
+	for _, u := range f.Users {
+		err = r.user(u)
+		if err != nil {
+			return report, errors.Wrapf(err, "error when seeding user %s", u.Email)
+		}
+	}
+
+	for _, c := range f.Courses {
+		err = r.course(c)
+		if err != nil {
+			return report, errors.Wrapf(err, "error when seeding course %q", c.Title)
+		}
+	}
+
+	for _, a := range f.Articles {
+		err = r.article(a)
+		if err != nil {
+			return report, errors.Wrapf(err, "error when seeding article %q", a.Topic)
+		}
+	}
+
+	for _, c := range f.Cards {
+		err = r.card(c)
+		if err != nil {
+			return report, errors.Wrapf(err, "error when seeding card %q", c.Position)
+		}
+	}
+
+	return report, nil
+}
+
+// run is the state of a single seed
+type run struct {
+	ctx    context.Context
+	tx     *sqlx.Tx
+	users  map[string]int // user ids by emails
+	report *Report
+}
+
+// ensure returns the id of the row found by the find query, the row is inserted by the insert query
+// returning its id when there is none
+func (r *run) ensure(find string, findArgs []interface{}, insert string, insertArgs ...interface{}) (int, bool, error) {
+	var id int
+
+	err := r.tx.GetContext(r.ctx, &id, find, findArgs...)
+	if err == nil {
+		return id, false, nil
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, false, errors.Wrap(err, "error when executing query")
+	}
+
+	err = r.tx.GetContext(r.ctx, &id, insert, insertArgs...)
+	if err != nil {
+		return 0, false, errors.Wrap(err, "error when executing query")
+	}
+
+	return id, true, nil
+}
+
+// userID returns the id of the user with the email, users missing in the fixtures are looked up in the database
+func (r *run) userID(email string) (int, error) {
+	if id, ok := r.users[email]; ok {
+		return id, nil
+	}
+
+	var id int
+
+	err := r.tx.GetContext(r.ctx, &id, fmt.Sprintf(`SELECT id FROM %s WHERE email = $1`, constants.UsersTable), email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("unknown user %s", email)
+	}
+
+	if err != nil {
+		return 0, errors.Wrap(err, "error when executing query")
+	}
+
+	r.users[email] = id
+
+	return id, nil
+}
+
+func (r *run) user(u UserFixture) error {
+	roles := []string{"user"}
+	isAdmin := false
+
+	for _, role := range u.Roles {
+		if role != "user" {
+			roles = append(roles, role)
+		}
+
+		isAdmin = isAdmin || role == "admin"
+	}
+
+	var id int
+
+	err := r.tx.GetContext(r.ctx, &id, fmt.Sprintf(`SELECT id FROM %s WHERE email = $1`, constants.UsersTable), u.Email)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		hash, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return errors.Wrap(err, "error when hashing the password")
+		}
+
+		err = r.tx.GetContext(r.ctx, &id,
+			fmt.Sprintf(`INSERT INTO %s (name, email, password, is_admin, roles)
+								VALUES ($1, $2, $3, $4, $5)
+								RETURNING id`, constants.UsersTable),
+			u.Name, u.Email, string(hash), isAdmin, pq.StringArray(roles))
+		if err != nil {
+			return errors.Wrap(err, "error when executing query")
+		}
+
+		r.report.Users++
+	case err != nil:
+		return errors.Wrap(err, "error when executing query")
+	}
+
+	r.users[u.Email] = id
+
+	for _, role := range roles {
+		var roleID int
+
+		err := r.tx.GetContext(r.ctx, &roleID,
+			fmt.Sprintf(`SELECT id FROM %s WHERE name = $1`, constants.RolesTable), role)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("unknown role %s", role)
+		}
+
+		if err != nil {
+			return errors.Wrap(err, "error when executing query")
+		}
+
+		_, err = r.tx.ExecContext(r.ctx,
+			fmt.Sprintf(`INSERT INTO %[1]s (user_id, role_id)
+								SELECT $1, $2
+								WHERE NOT EXISTS(SELECT 1 FROM %[1]s WHERE user_id = $1 AND role_id = $2)`,
+				constants.UserRolesTable),
+			id, roleID)
+		if err != nil {
+			return errors.Wrap(err, "error when executing query")
+		}
+	}
+
+	if u.Details != nil {
+		_, err := r.tx.ExecContext(r.ctx,
+			fmt.Sprintf(`INSERT INTO %[1]s (user_id, first_name, last_name, email, phone_number, specialization)
+								SELECT $1, $2, $3, $4, $5, $6
+								WHERE NOT EXISTS(SELECT 1 FROM %[1]s WHERE user_id = $1)`,
+				constants.UserDetailsTable),
+			id, u.Details.FirstName, u.Details.LastName, u.Email, u.Details.PhoneNumber, u.Details.Specialization)
+		if err != nil {
+			return errors.Wrap(err, "error when executing query")
+		}
+	}
+
+	return nil
+}
+
+func (r *run) course(c CourseFixture) error {
+	authorID, err := r.userID(c.Author)
+	if err != nil {
+		return err
+	}
+
+	courseID, created, err := r.ensure(
+		fmt.Sprintf(`SELECT id FROM %s WHERE author_id = $1 AND title = $2`, constants.CoursesTable),
+		[]interface{}{authorID, c.Title},
+		fmt.Sprintf(`INSERT INTO %s (author_id, title, description, rating) VALUES ($1, $2, $3, $4) RETURNING id`,
+			constants.CoursesTable),
+		authorID, c.Title, c.Description, c.Rating)
+	if err != nil {
+		return err
+	}
+
+	if created {
+		r.report.Courses++
+	}
+
+	for _, m := range c.Modules {
+		moduleID, created, err := r.ensure(
+			fmt.Sprintf(`SELECT id FROM %s WHERE course_id = $1 AND title = $2`, constants.CourseModulesTable),
+			[]interface{}{courseID, m.Title},
+			fmt.Sprintf(`INSERT INTO %s (course_id, title, expected_result) VALUES ($1, $2, $3) RETURNING id`,
+				constants.CourseModulesTable),
+			courseID, m.Title, m.ExpectedResult)
+		if err != nil {
+			return err
+		}
+
+		if created {
+			r.report.Modules++
+		}
+
+		for _, l := range m.Lessons {
+			_, created, err := r.ensure(
+				fmt.Sprintf(`SELECT id FROM %s WHERE module_id = $1 AND title = $2`, constants.CourseModuleLessonsTable),
+				[]interface{}{moduleID, l.Title},
+				fmt.Sprintf(`INSERT INTO %s (module_id, title, description, reference_url)
+									VALUES ($1, $2, $3, $4) RETURNING id`,
+					constants.CourseModuleLessonsTable),
+				moduleID, l.Title, l.Description, l.ReferenceURL)
+			if err != nil {
+				return err
+			}
+
+			if created {
+				r.report.Lessons++
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *run) article(a ArticleFixture) error {
+	authorID, err := r.userID(a.Author)
+	if err != nil {
+		return err
+	}
+
+	articleID, created, err := r.ensure(
+		fmt.Sprintf(`SELECT id FROM %s WHERE user_id = $1 AND topic = $2`, constants.ArticlesTable),
+		[]interface{}{authorID, a.Topic},
+		fmt.Sprintf(`INSERT INTO %s (user_id, topic, description) VALUES ($1, $2, $3) RETURNING id`,
+			constants.ArticlesTable),
+		authorID, a.Topic, a.Description)
+	if err != nil {
+		return err
+	}
+
+	if created {
+		r.report.Articles++
+	}
+
+	return r.comments(articleID, nil, a.Comments)
+}
+
+// comments seeds a thread of comments, parentID is nil for the top level comments of the article
+func (r *run) comments(articleID int, parentID *int, comments []CommentFixture) error {
+	for _, c := range comments {
+		authorID, err := r.userID(c.Author)
+		if err != nil {
+			return err
+		}
+
+		commentID, created, err := r.ensure(
+			fmt.Sprintf(`SELECT id FROM %s
+								WHERE article_id = $1 AND user_id = $2 AND parent_id IS NOT DISTINCT FROM $3 AND text = $4`,
+				constants.ArticlesCommentsTable),
+			[]interface{}{articleID, authorID, parentID, c.Text},
+			fmt.Sprintf(`INSERT INTO %s (article_id, user_id, parent_id, text) VALUES ($1, $2, $3, $4) RETURNING id`,
+				constants.ArticlesCommentsTable),
+			articleID, authorID, parentID, c.Text)
+		if err != nil {
+			return err
+		}
+
+		if created {
+			r.report.Comments++
+		}
+
+		err = r.comments(articleID, &commentID, c.Replies)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *run) card(c CardFixture) error {
+	authorID, err := r.userID(c.Author)
+	if err != nil {
+		return err
+	}
+
+	cardID, created, err := r.ensure(
+		fmt.Sprintf(`SELECT id FROM %s WHERE user_id = $1 AND position = $2`, constants.CardsTable),
+		[]interface{}{authorID, c.Position},
+		fmt.Sprintf(`INSERT INTO %s (user_id, position, skills, description) VALUES ($1, $2, $3, $4) RETURNING id`,
+			constants.CardsTable),
+		authorID, c.Position, pq.StringArray(c.Skills), c.Description)
+	if err != nil {
+		return err
+	}
+
+	if created {
+		r.report.Cards++
+	}
+
+	for _, i := range c.Invitations {
+		inviterID, err := r.userID(i.Inviter)
+		if err != nil {
+			return err
+		}
+
+		status := i.Status
+		if status == "" {
+			status = "NOT ANSWERED"
+		}
+
+		_, created, err := r.ensure(
+			fmt.Sprintf(`SELECT id FROM %s WHERE card_id = $1 AND inviter_id = $2`, constants.CardInvitationsTable),
+			[]interface{}{cardID, inviterID},
+			fmt.Sprintf(`INSERT INTO %s (card_id, inviter_id, status, feedback) VALUES ($1, $2, $3, $4) RETURNING id`,
+				constants.CardInvitationsTable),
+			cardID, inviterID, status, i.Feedback)
+		if err != nil {
+			return err
+		}
+
+		if created {
+			r.report.Invitations++
+		}
+	}
+
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"acsp/internal/constants"
 	"acsp/internal/dto"
 	"acsp/internal/logging"
+	"acsp/internal/metrics"
 	"acsp/internal/model"
 	"acsp/internal/repository"
 )
@@ -17,19 +18,35 @@ import (
 type ArticlesService struct {
 	repo      repository.Articles
 	usersRepo repository.Users
+	objects   repository.StoredObjects
+	outbox    repository.Outbox
 	txManager repository.Transactional
-	s3Bucket  S3Bucket
+	storage   ObjectStorage
+	urls      URLBuilder
 }
 
-func NewArticlesService(r repository.Articles, a repository.Users, s S3Bucket, t repository.Transactional) *ArticlesService {
+func NewArticlesService(
+	r repository.Articles,
+	a repository.Users,
+	o repository.StoredObjects,
+	ob repository.Outbox,
+	s ObjectStorage,
+	t repository.Transactional,
+	u URLBuilder,
+) *ArticlesService {
 	return &ArticlesService{
 		repo:      r,
 		usersRepo: a,
-		s3Bucket:  s,
+		objects:   o,
+		outbox:    ob,
+		storage:   s,
 		txManager: t,
+		urls:      u,
 	}
 }
 
+// Create creates an article. Its image is staged before the transaction and moved to the final key
+// by the outbox worker, so that the image and the article are either both kept or both cleaned up.
 func (s *ArticlesService) Create(ctx context.Context, userID string, dto dto.CreateArticle) error {
 	l := logging.LoggerFromContext(ctx).With(zap.String("topic", dto.Topic))
 
@@ -53,52 +70,59 @@ func (s *ArticlesService) Create(ctx context.Context, userID string, dto dto.Cre
 		Author:      &user,
 	}
 
-	tx, err := s.txManager.Begin(ctx, nil)
-	if err != nil {
-		l.Error("Error occurred when starting transaction", zap.Error(err))
-
-		return err
-	}
-
-	defer func() {
+	var stagingKey string
+	if dto.Image != nil {
+		stagingKey, err = stageUpload(ctx, s.objects, s.storage, userId, dto.Image)
 		if err != nil {
-			l.Error("Error occurred when rolling back transaction", zap.Error(err))
+			l.Info("Error occurred when uploading file to object storage", zap.Error(err))
 
-			err = s.txManager.Rollback(ctx, tx)
-			return
+			return err
 		}
+	}
 
-		err = s.txManager.Commit(ctx, tx)
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.repo.Create(ctx, &article)
 		if err != nil {
-			l.Error("Error occurred when committing transaction", zap.Error(err))
+			l.Error("Error occurred when creating article", zap.Error(err))
 
-			return
+			return errors.Wrap(err, "Error occurred when creating article")
 		}
-	}()
 
-	err = s.repo.Create(ctx, tx, &article)
-	if err != nil {
-		l.Error("Error occurred when creating article", zap.Error(err))
+		if stagingKey == "" {
+			return nil
+		}
 
-		return errors.Wrap(err, "Error occurred when creating article")
-	}
+		key, imagePath := s.urls.VersionedKey(constants.ArticlesImagesFolder, article.ID)
 
-	if dto.Image != nil {
-		err = s.s3Bucket.UploadFile(ctx, constants.ArticlesImagesFolder+"/"+strconv.Itoa(article.ID), dto.Image)
+		err = s.repo.UpdateImageURL(ctx, article.ID, imagePath)
 		if err != nil {
-			l.Info("Error occurred when uploading file to s3 bucket", zap.Error(err))
+			l.Info("Error occurred when updating image URL", zap.Error(err))
 
 			return err
 		}
 
-		err = s.repo.UpdateImageURL(ctx, tx, article.ID)
+		event, err := newFinalizeUploadEvent(stagingKey, key, constants.ArticlesImagesFolder, article.ID)
 		if err != nil {
-			l.Info("Error occurred when updating image URL", zap.Error(err))
+			return err
+		}
+
+		err = s.outbox.Enqueue(ctx, event)
+		if err != nil {
+			l.Info("Error occurred when enqueuing image finalization", zap.Error(err))
 
 			return err
 		}
+
+		return nil
+	})
+	if err != nil {
+		l.Error("Error occurred when saving article", zap.Error(err))
+
+		return err
 	}
 
+	metrics.ArticlesCreated.Inc()
+
 	return nil
 }
 
@@ -301,16 +325,19 @@ func (s *ArticlesService) UpvoteCommentByArticleIDAndCommentID(userContext conte
 		return errors.Wrap(err, "failed to convert comment id")
 	}
 
-	voted, err := s.repo.HasUserVotedForComment(userContext, userId, commentId)
-	if err != nil {
-		return errors.Wrap(err, "failed to check if user has voted for comment")
-	}
+	// The vote is checked and saved in one transaction together with the votes of the comment
+	return s.txManager.WithinTransaction(userContext, func(ctx context.Context) error {
+		voted, err := s.repo.HasUserVotedForComment(ctx, userId, commentId)
+		if err != nil {
+			return errors.Wrap(err, "failed to check if user has voted for comment")
+		}
 
-	if voted {
-		return errors.New("user has already voted for this comment")
-	}
+		if voted {
+			return errors.New("user has already voted for this comment")
+		}
 
-	return s.repo.UpvoteCommentByArticleIDAndCommentID(userContext, articleId, userId, commentId)
+		return s.repo.UpvoteCommentByArticleIDAndCommentID(ctx, articleId, userId, commentId)
+	})
 }
 
 func (s *ArticlesService) DownvoteCommentByArticleIDAndCommentID(userContext context.Context, articleID, commentID, userID string) error {
@@ -329,16 +356,19 @@ func (s *ArticlesService) DownvoteCommentByArticleIDAndCommentID(userContext con
 		return errors.Wrap(err, "failed to convert comment id")
 	}
 
-	voted, err := s.repo.HasUserVotedForComment(userContext, userId, commentId)
-	if err != nil {
-		return errors.Wrap(err, "failed to check if user has voted for comment")
-	}
+	// The vote is checked and saved in one transaction together with the votes of the comment
+	return s.txManager.WithinTransaction(userContext, func(ctx context.Context) error {
+		voted, err := s.repo.HasUserVotedForComment(ctx, userId, commentId)
+		if err != nil {
+			return errors.Wrap(err, "failed to check if user has voted for comment")
+		}
 
-	if voted {
-		return errors.New("user has already voted for this comment")
-	}
+		if voted {
+			return errors.New("user has already voted for this comment")
+		}
 
-	return s.repo.DownvoteCommentByArticleIDAndCommentID(userContext, articleId, userId, commentId)
+		return s.repo.DownvoteCommentByArticleIDAndCommentID(ctx, articleId, userId, commentId)
+	})
 }
 
 func (s *ArticlesService) GetVotesByArticleIDAndCommentID(userContext context.Context, articleID, commentID string) (int, error) {
@@ -358,10 +388,7 @@ func (s *ArticlesService) GetVotesByArticleIDAndCommentID(userContext context.Co
 // getFullURLForArticles function gets a slice of articles and changes every article's image_url to a full url
 func (s *ArticlesService) getFullURLForArticles(articles []model.Article) []model.Article {
 	for i := range articles {
-		articles[i].ImageURL = constants.BucketName + "." +
-			constants.EndPoint + "/" +
-			constants.ArticlesImagesFolder +
-			articles[i].ImageURL
+		articles[i].ImageURL = s.urls.ImageURL(constants.ArticlesImagesFolder, articles[i].ImageURL)
 	}
 
 	return articles
@@ -369,10 +396,7 @@ func (s *ArticlesService) getFullURLForArticles(articles []model.Article) []mode
 
 // getFullURLForArticle function gets an article and changes its image_url to a full url
 func (s *ArticlesService) getFullURLForArticle(article model.Article) model.Article {
-	article.ImageURL = constants.BucketName + "." +
-		constants.EndPoint + "/" +
-		constants.ArticlesImagesFolder +
-		article.ImageURL
+	article.ImageURL = s.urls.ImageURL(constants.ArticlesImagesFolder, article.ImageURL)
 
 	return article
 }
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"acsp/internal/constants"
+	"acsp/internal/dto"
+	"acsp/internal/logging"
+	"acsp/internal/model"
+	"acsp/internal/repository"
+)
+
+type auditActorKey struct{}
+
+// ContextWithActor returns a copy of the context with the actor of the request, the audit events recorded
+// with the context are attributed to the actor.
+func ContextWithActor(ctx context.Context, actor model.AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// actorFromContext returns the actor of the request, the zero actor when the action isn't made by a user.
+func actorFromContext(ctx context.Context) model.AuditActor {
+	actor, _ := ctx.Value(auditActorKey{}).(model.AuditActor)
+
+	return actor
+}
+
+// AuditService implements the Auditor interface.
+type AuditService struct {
+	repo repository.AuditEvents
+}
+
+func NewAuditService(repo repository.AuditEvents) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// Record records the action of the actor of the context on the target, the action is done already,
+// so the errors are logged and not returned.
+func (s *AuditService) Record(ctx context.Context, action, targetType, targetID string, before, after interface{}) {
+	err := s.RecordTx(ctx, action, targetType, targetID, before, after)
+	if err != nil {
+		logging.LoggerFromContext(ctx).Error("Error when recording the audit event", zap.Error(err),
+			zap.String("action", action), zap.String("targetType", targetType), zap.String("targetID", targetID))
+	}
+}
+
+// RecordTx records the action like Record in the transaction of the context and returns the errors,
+// so that the action is rolled back rather than committed without its event.
+func (s *AuditService) RecordTx(
+	ctx context.Context, action, targetType, targetID string, before, after interface{}) error {
+	changes, err := auditChanges(before, after)
+	if err != nil {
+		return errors.Wrap(err, "error when diffing the target of the audit event")
+	}
+
+	actor := actorFromContext(ctx)
+
+	event := model.AuditEvent{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Changes:    changes,
+		IP:         actor.IP,
+		RequestID:  actor.RequestID,
+	}
+
+	if id, err := strconv.Atoi(actor.UserID); err == nil {
+		event.ActorID = &id
+	}
+
+	if actor.TokenID != 0 {
+		event.ActorTokenID = &actor.TokenID
+	}
+
+	return s.repo.Add(ctx, event)
+}
+
+// GetEvents returns a page of the events matching the input from the newest to the oldest.
+func (s *AuditService) GetEvents(ctx context.Context, input dto.GetAuditEvents) (model.AuditEventsPage, error) {
+	filter := model.AuditEventsFilter{
+		Action:     input.Action,
+		TargetType: input.TargetType,
+		TargetID:   input.TargetID,
+		Before:     input.Before,
+		Limit:      input.Limit,
+	}
+
+	if input.ActorID > 0 {
+		filter.ActorID = &input.ActorID
+	}
+
+	if filter.Limit <= 0 || filter.Limit > constants.MaxAuditEventsLimit {
+		filter.Limit = constants.DefaultAuditEventsLimit
+	}
+
+	var err error
+
+	filter.From, err = parseAuditTime(input.From)
+	if err != nil {
+		return model.AuditEventsPage{}, err
+	}
+
+	filter.To, err = parseAuditTime(input.To)
+	if err != nil {
+		return model.AuditEventsPage{}, err
+	}
+
+	limit := filter.Limit
+
+	// One more event tells if there is a next page
+	filter.Limit++
+
+	events, err := s.repo.Get(ctx, filter)
+	if err != nil {
+		return model.AuditEventsPage{}, err
+	}
+
+	page := model.AuditEventsPage{Events: make([]model.AuditEvent, 0, len(events))}
+
+	if len(events) > limit {
+		events = events[:limit]
+		page.NextBefore = &events[limit-1].ID
+	}
+
+	page.Events = append(page.Events, events...)
+
+	return page, nil
+}
+
+// parseAuditTime parses the bound of the time range of the audit events, nil when it isn't given.
+func parseAuditTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when parsing the time")
+	}
+
+	return &t, nil
+}
+
+// auditChanges returns the fields of the JSON of the target that differ before and after the action,
+// the target is nil before it is created and after it is deleted.
+func auditChanges(before, after interface{}) (json.RawMessage, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]model.AuditChange{}
+
+	for name, value := range beforeFields {
+		if !reflect.DeepEqual(value, afterFields[name]) {
+			changes[name] = model.AuditChange{Before: value, After: afterFields[name]}
+		}
+	}
+
+	for name, value := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			changes[name] = model.AuditChange{After: value}
+		}
+	}
+
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when marshaling the changes")
+	}
+
+	return data, nil
+}
+
+// auditFields returns the fields of the JSON of the target, the fields hidden from JSON like the
+// passwords are never recorded.
+func auditFields(target interface{}) (map[string]interface{}, error) {
+	if target == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(target)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when marshaling the target")
+	}
+
+	var fields map[string]interface{}
+
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when unmarshaling the target")
+	}
+
+	return fields, nil
+}
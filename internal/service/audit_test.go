@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"acsp/internal/constants"
+	"acsp/internal/dto"
+	"acsp/internal/model"
+)
+
+// fakeAuditEvents keeps the events in memory, only the filters used by the tests are supported.
+// Adding fails when err is set.
+type fakeAuditEvents struct {
+	events []model.AuditEvent
+	err    error
+}
+
+func (f *fakeAuditEvents) Add(ctx context.Context, event model.AuditEvent) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	event.ID = int64(len(f.events) + 1)
+	f.events = append(f.events, event)
+
+	return nil
+}
+
+func (f *fakeAuditEvents) Get(ctx context.Context, filter model.AuditEventsFilter) ([]model.AuditEvent, error) {
+	var events []model.AuditEvent
+
+	for i := len(f.events) - 1; i >= 0 && len(events) < filter.Limit; i-- {
+		event := f.events[i]
+
+		if filter.Before > 0 && event.ID >= filter.Before {
+			continue
+		}
+
+		if filter.Action != "" && event.Action != filter.Action {
+			continue
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func newTestAuditService() (*AuditService, *fakeAuditEvents) {
+	repo := &fakeAuditEvents{}
+
+	return NewAuditService(repo), repo
+}
+
+func TestAuditChanges(t *testing.T) {
+	before := model.Contest{ID: 1, Name: "ICPC", Description: "Regional", Link: "https://icpc.global"}
+	after := before
+	after.Description = "Semi-final"
+
+	changes, err := auditChanges(before, after)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"description": {"before": "Regional", "after": "Semi-final"}}`, string(changes))
+
+	changes, err = auditChanges(nil, model.Discipline{Title: "Go"})
+	require.NoError(t, err)
+
+	var created map[string]model.AuditChange
+	require.NoError(t, json.Unmarshal(changes, &created))
+	assert.Equal(t, model.AuditChange{After: "Go"}, created["title"])
+
+	changes, err = auditChanges(model.User{ID: "7", Email: "user@example.com", Password: "hash"}, nil)
+	require.NoError(t, err)
+	assert.Contains(t, string(changes), "user@example.com")
+	assert.NotContains(t, string(changes), "hash", "the fields hidden from JSON are never recorded")
+
+	changes, err = auditChanges(nil, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{}`, string(changes))
+}
+
+func TestAuditService_Record(t *testing.T) {
+	s, repo := newTestAuditService()
+
+	ctx := ContextWithActor(context.Background(), model.AuditActor{
+		UserID: "2", TokenID: 5, IP: "10.0.0.1", RequestID: "req-1",
+	})
+	s.Record(ctx, constants.AuditActionDelete, constants.AuditTargetCourse, "3", model.Course{ID: 3}, nil)
+
+	require.Len(t, repo.events, 1)
+	event := repo.events[0]
+	require.NotNil(t, event.ActorID)
+	require.NotNil(t, event.ActorTokenID)
+	assert.Equal(t, 2, *event.ActorID)
+	assert.Equal(t, 5, *event.ActorTokenID)
+	assert.Equal(t, constants.AuditActionDelete, event.Action)
+	assert.Equal(t, constants.AuditTargetCourse, event.TargetType)
+	assert.Equal(t, "3", event.TargetID)
+	assert.Equal(t, "10.0.0.1", event.IP)
+	assert.Equal(t, "req-1", event.RequestID)
+
+	s.Record(context.Background(), constants.AuditActionUnlock, constants.AuditTargetUser, "7", nil, nil)
+
+	require.Len(t, repo.events, 2)
+	assert.Nil(t, repo.events[1].ActorID, "the actions without a user have no actor")
+	assert.Nil(t, repo.events[1].ActorTokenID)
+}
+
+func TestAuditService_RecordTx(t *testing.T) {
+	s, repo := newTestAuditService()
+	repo.err = errors.New("connection reset")
+
+	// The error rolls back the transaction of the action, Record only logs it
+	err := s.RecordTx(context.Background(), constants.AuditActionDelete, constants.AuditTargetUser, "7", nil, nil)
+	assert.ErrorIs(t, err, repo.err)
+
+	s.Record(context.Background(), constants.AuditActionDelete, constants.AuditTargetUser, "7", nil, nil)
+	assert.Empty(t, repo.events)
+}
+
+func TestAuditService_GetEvents(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestAuditService()
+
+	for i := 0; i < 5; i++ {
+		s.Record(ctx, constants.AuditActionUpdate, constants.AuditTargetContest, "1", nil, nil)
+	}
+	s.Record(ctx, constants.AuditActionDelete, constants.AuditTargetContest, "1", nil, nil)
+
+	page, err := s.GetEvents(ctx, dto.GetAuditEvents{Action: constants.AuditActionUpdate, Limit: 3})
+	require.NoError(t, err)
+	require.Len(t, page.Events, 3)
+	assert.Equal(t, int64(5), page.Events[0].ID, "the newest events come first")
+	require.NotNil(t, page.NextBefore)
+	assert.Equal(t, int64(3), *page.NextBefore)
+
+	page, err = s.GetEvents(ctx, dto.GetAuditEvents{Action: constants.AuditActionUpdate, Limit: 3, Before: 3})
+	require.NoError(t, err)
+	require.Len(t, page.Events, 2)
+	assert.Nil(t, page.NextBefore, "the last page has no cursor")
+
+	page, err = s.GetEvents(ctx, dto.GetAuditEvents{Action: constants.AuditActionCreate})
+	require.NoError(t, err)
+	assert.NotNil(t, page.Events, "no events are an empty list")
+	assert.Empty(t, page.Events)
+
+	_, err = s.GetEvents(ctx, dto.GetAuditEvents{From: "yesterday"})
+	assert.Error(t, err)
+}
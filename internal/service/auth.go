@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/go-redis/redis/v9"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 
@@ -13,22 +14,36 @@ import (
 	"acsp/internal/config"
 	"acsp/internal/constants"
 	"acsp/internal/dto"
+	"acsp/internal/jwks"
 	"acsp/internal/logging"
+	"acsp/internal/metrics"
 	"acsp/internal/model"
 	"acsp/internal/repository"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+const (
+	accessTokenType  = "access"
+	refreshTokenType = "refresh"
+)
+
+// accessTokenClaims are verified by the other services with the public keys, see jwks.KeySet.JWKS
 type accessTokenClaims struct {
 	jwt.RegisteredClaims
 	UserId    string `json:"user_id"`
 	UserEmail string `json:"user_email"`
+	// TokenType tells the access tokens from the refresh tokens signed with the same key
+	TokenType string `json:"token_type"`
+	// Roles are the roles of the user when the token was issued, the changes apply to the next token
+	Roles     []string `json:"roles"`
+	TwoFactor bool     `json:"mfa"`
 }
 
 type refreshTokenClaims struct {
 	jwt.RegisteredClaims
-	UserId string `json:"user_id"`
+	UserId    string `json:"user_id"`
+	TokenType string `json:"token_type"`
 }
 
 type TokenDetails struct {
@@ -42,16 +57,33 @@ type TokenDetails struct {
 type AuthService struct {
 	repo        repository.Users
 	roles       repository.Roles
+	twoFactor   repository.TwoFactor
+	txManager   repository.Transactional
 	redisClient *redis.Client
 	authConfig  config.AuthConfig
+	keys        *jwks.KeySet
+	urls        URLBuilder
 }
 
-func NewAuthService(repo repository.Users, rolesRepo repository.Roles, r *redis.Client, a config.AuthConfig) *AuthService {
+func NewAuthService(
+	repo repository.Users,
+	rolesRepo repository.Roles,
+	twoFactorRepo repository.TwoFactor,
+	t repository.Transactional,
+	r *redis.Client,
+	a config.AuthConfig,
+	k *jwks.KeySet,
+	u URLBuilder,
+) *AuthService {
 	return &AuthService{
 		repo:        repo,
 		roles:       rolesRepo,
+		twoFactor:   twoFactorRepo,
+		txManager:   t,
 		redisClient: r,
 		authConfig:  a,
+		keys:        k,
+		urls:        u,
 	}
 }
 
@@ -78,13 +110,18 @@ func (s *AuthService) CreateUser(ctx context.Context, userDto dto.CreateUser) er
 		IsAdmin:  false,
 	}
 
-	err = s.repo.CreateUser(ctx, newUser)
+	// The user and its default role are created together
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		return s.repo.CreateUser(ctx, newUser)
+	})
 	if err != nil {
 		l.Error("Error occurred when creating a user", zap.Error(err))
 
 		return err
 	}
 
+	metrics.SignUps.Inc()
+
 	return nil
 }
 
@@ -104,48 +141,56 @@ func (s *AuthService) GetUserByID(ctx context.Context, userID string) (model.Use
 	return user, nil
 }
 
-func (s *AuthService) GenerateTokenPair(ctx context.Context, email, password string) (*TokenDetails, error) {
+// Authenticate checks the credentials of the user, the tokens are generated after the second step of the users
+// with two-factor authentication enabled.
+func (s *AuthService) Authenticate(ctx context.Context, email, password string) (model.User, error) {
+	// Get the user from the database
+	user, err := s.repo.GetUser(ctx, email, password)
+	if err != nil {
+		logging.LoggerFromContext(ctx).Warn("Error when getting a user", zap.Error(err))
+
+		return model.User{}, err
+	}
+
+	return *user, nil
+}
+
+// GenerateTokenPair generates the tokens of the authenticated user.
+func (s *AuthService) GenerateTokenPair(ctx context.Context, user model.User) (*TokenDetails, error) {
 	l := logging.LoggerFromContext(ctx)
 	l.Info("Generating a token...")
 
 	var tokenDetails TokenDetails
 
-	// Get the user from the database
-	user, err := s.repo.GetUser(ctx, email, password)
+	id, err := strconv.Atoi(user.ID)
 	if err != nil {
-		l.Warn("Error when getting a user", zap.Error(err))
+		return nil, err
+	}
 
-		return &TokenDetails{}, err
+	roles, twoFactor, err := principalClaims(ctx, s.roles, s.twoFactor, id)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create the access token with the user ID as the subject
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256,
-		&accessTokenClaims{
-			jwt.RegisteredClaims{
-				ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.authConfig.JWT.AccessTokenTTL)),
-				IssuedAt:  jwt.NewNumericDate(time.Now()),
-			},
-			user.ID,
-			user.Email,
-		})
-
-	// Create the refresh token with the user ID as the subject
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256,
-		&refreshTokenClaims{
-			jwt.RegisteredClaims{
-				ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.authConfig.JWT.RefreshTokenTTL)),
-				IssuedAt:  jwt.NewNumericDate(time.Now()),
-			},
-			user.ID,
-		})
-
-	// Sign the tokens using the secret key
-	accessTokenJWT, err := accessToken.SignedString([]byte(s.authConfig.JWT.AccessTokenSecret))
+	accessTokenJWT, err := s.keys.Sign(&accessTokenClaims{
+		RegisteredClaims: s.registeredClaims(user.ID, s.authConfig.JWT.AccessTokenTTL),
+		UserId:           user.ID,
+		UserEmail:        user.Email,
+		TokenType:        accessTokenType,
+		Roles:            roles,
+		TwoFactor:        twoFactor,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	refreshTokenJWT, err := refreshToken.SignedString([]byte(s.authConfig.JWT.RefreshTokenSecret))
+	// Create the refresh token with the user ID as the subject
+	refreshTokenJWT, err := s.keys.Sign(&refreshTokenClaims{
+		RegisteredClaims: s.registeredClaims(user.ID, s.authConfig.JWT.RefreshTokenTTL),
+		UserId:           user.ID,
+		TokenType:        refreshTokenType,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -160,33 +205,103 @@ func (s *AuthService) GenerateTokenPair(ctx context.Context, email, password str
 	return &tokenDetails, nil
 }
 
-// ParseToken parses the access token and returns the user ID
-func (s *AuthService) ParseToken(accessToken string) (string, error) {
-	// Parse the token
-	token, err := jwt.ParseWithClaims(accessToken, &accessTokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// Validate the signing method used to sign the token (HMAC)
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, apperror.ErrBadSigningMethod
-		}
+// ParseToken parses the access token and returns the user of the token
+func (s *AuthService) ParseToken(accessToken string) (model.Principal, error) {
+	var claims accessTokenClaims
 
-		// Return the secret key used to sign the token
-		return []byte(s.authConfig.JWT.AccessTokenSecret), nil
-	})
+	err := s.parse(accessToken, &claims)
+	if err != nil {
+		return model.Principal{}, err
+	}
+
+	if claims.TokenType != accessTokenType {
+		return model.Principal{}, apperror.ErrBadClaimsType
+	}
 
-	// Check if the token is valid
+	return model.Principal{UserID: claims.UserId, Roles: claims.Roles, TwoFactor: claims.TwoFactor}, nil
+}
+
+// ParseRefreshToken parses the refresh token and returns the user ID, the token must be the latest refresh token
+// of the user, see SaveRefreshToken
+func (s *AuthService) ParseRefreshToken(ctx context.Context, refreshToken string) (string, error) {
+	var claims refreshTokenClaims
+
+	err := s.parse(refreshToken, &claims)
 	if err != nil {
 		return "", err
 	}
 
-	// Check if the token is expired or not active yet
-	claims, ok := token.Claims.(*accessTokenClaims)
-	if !ok {
+	if claims.TokenType != refreshTokenType {
 		return "", apperror.ErrBadClaimsType
 	}
 
+	saved, err := s.redisClient.Get(ctx, claims.UserId).Result()
+	if errors.Is(err, redis.Nil) || saved != refreshToken {
+		return "", apperror.New(apperror.KindUnauthorized, "unauthorized, your session was ended earlier")
+	}
+
+	if err != nil {
+		return "", err
+	}
+
 	return claims.UserId, nil
 }
 
+// parse verifies the signature, the issuer and the expiration of the token and reads its claims
+func (s *AuthService) parse(token string, claims jwt.Claims) error {
+	_, err := jwt.ParseWithClaims(token, claims, s.keys.Keyfunc,
+		jwt.WithValidMethods([]string{jwks.RS256, jwks.EdDSA}),
+		jwt.WithIssuer(s.authConfig.JWT.Issuer))
+
+	return err
+}
+
+// registeredClaims returns the claims of a token of the user valid for the ttl
+func (s *AuthService) registeredClaims(userID string, ttl time.Duration) jwt.RegisteredClaims {
+	now := time.Now()
+
+	return jwt.RegisteredClaims{
+		Issuer:    s.authConfig.JWT.Issuer,
+		Subject:   userID,
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		IssuedAt:  jwt.NewNumericDate(now),
+	}
+}
+
+// principalClaims returns the roles of the user and whether two-factor authentication is enabled, see model.Principal
+func principalClaims(
+	ctx context.Context,
+	rolesRepo repository.Roles,
+	twoFactorRepo repository.TwoFactor,
+	userID int,
+) ([]string, bool, error) {
+	roles, err := rolesRepo.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	names := make([]string, 0, len(roles))
+	for _, r := range roles {
+		names = append(names, r.Name)
+	}
+
+	authenticator, err := twoFactorRepo.GetTOTP(ctx, userID)
+	if apperror.KindOf(err) == apperror.KindNotFound {
+		return names, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return names, authenticator.Enabled, nil
+}
+
+// PublicKeys returns the keys verifying the tokens
+func (s *AuthService) PublicKeys() jwks.JWKSet {
+	return s.keys.JWKS()
+}
+
 // SaveRefreshToken parses the refresh token and returns the user ID
 func (s *AuthService) SaveRefreshToken(ctx context.Context, userID string, details *TokenDetails) error {
 	// Save the refresh token in the database with the user ID as the key and the refresh token as the value
@@ -230,10 +345,7 @@ func generatePasswordHash(password string) (string, error) {
 
 // getFullURLForUser function gets a user and changes its image_url to a full url
 func (s *AuthService) getFullURLForUser(user *model.User) model.User {
-	user.ImageURL = constants.BucketName + "." +
-		constants.EndPoint + "/" +
-		constants.UsersAvatarsFolder +
-		user.ImageURL
+	user.ImageURL = s.urls.ImageURL(constants.UsersAvatarsFolder, user.ImageURL)
 
 	return *user
 }
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"acsp/internal/config"
+	"acsp/internal/jwks"
+	"acsp/internal/model"
+)
+
+func newTestKey(t *testing.T, id, algorithm string) *jwks.Key {
+	b, err := jwks.Generate(algorithm)
+	require.NoError(t, err)
+
+	k, err := jwks.ParseKey(id, b)
+	require.NoError(t, err)
+
+	return k
+}
+
+func newTestAuthService(t *testing.T, keys *jwks.KeySet) *AuthService {
+	roles := &fakeRoles{roles: map[int][]model.Role{2: {{ID: "1", Name: "user"}, {ID: "2", Name: "admin"}}}}
+	twoFactor := &fakeTwoFactor{authenticators: map[int]model.TOTP{2: {UserID: 2, Enabled: true}}}
+
+	return NewAuthService(&fakeLockoutUsers{}, roles, twoFactor, fakeTransactional{}, nil, config.AuthConfig{
+		JWT: config.JWTConfig{AccessTokenTTL: time.Hour, RefreshTokenTTL: 24 * time.Hour, Issuer: "acsp"},
+	}, keys, nil)
+}
+
+func TestAuthService_TokenClaims(t *testing.T) {
+	ctx := context.Background()
+
+	keys, err := jwks.NewKeySet("ed", newTestKey(t, "ed", jwks.EdDSA))
+	require.NoError(t, err)
+
+	s := newTestAuthService(t, keys)
+
+	pair, err := s.GenerateTokenPair(ctx, model.User{ID: "2", Email: "admin@example.com"})
+	require.NoError(t, err)
+
+	principal, err := s.ParseToken(pair.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, model.Principal{UserID: "2", Roles: []string{"user", "admin"}, TwoFactor: true}, principal)
+
+	pair, err = s.GenerateTokenPair(ctx, model.User{ID: "1", Email: "user@example.com"})
+	require.NoError(t, err)
+
+	principal, err = s.ParseToken(pair.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, model.Principal{UserID: "1", Roles: []string{}}, principal)
+
+	// The refresh token isn't an access token
+	_, err = s.ParseToken(pair.RefreshToken)
+	assert.Error(t, err)
+
+	// The tokens of another issuer aren't accepted
+	other := newTestAuthService(t, keys)
+	other.authConfig.JWT.Issuer = "other"
+
+	_, err = other.ParseToken(pair.AccessToken)
+	assert.Error(t, err)
+}
+
+func TestAuthService_KeyRotation(t *testing.T) {
+	ctx := context.Background()
+	previous, next := newTestKey(t, "2023-01", jwks.RS256), newTestKey(t, "2023-02", jwks.EdDSA)
+
+	oldKeys, err := jwks.NewKeySet("2023-01", previous)
+	require.NoError(t, err)
+
+	pair, err := newTestAuthService(t, oldKeys).GenerateTokenPair(ctx, model.User{ID: "2"})
+	require.NoError(t, err)
+
+	// The token signed before the rotation is still valid
+	newKeys, err := jwks.NewKeySet("2023-02", previous, next)
+	require.NoError(t, err)
+
+	s := newTestAuthService(t, newKeys)
+
+	principal, err := s.ParseToken(pair.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "2", principal.UserID)
+
+	// The key is published until it is removed
+	assert.Len(t, s.PublicKeys().Keys, 2)
+}
@@ -11,6 +11,7 @@ import (
 	"acsp/internal/constants"
 	"acsp/internal/dto"
 	"acsp/internal/logging"
+	"acsp/internal/metrics"
 	"acsp/internal/model"
 	"acsp/internal/repository"
 )
@@ -18,10 +19,19 @@ import (
 type CardsService struct {
 	cardsRepo repository.Cards
 	usersRepo repository.Users
+	outbox    repository.Outbox
+	txManager repository.Transactional
+	urls      URLBuilder
 }
 
-func NewCardsService(cardsRepo repository.Cards, usersRepo repository.Users) *CardsService {
-	return &CardsService{cardsRepo: cardsRepo, usersRepo: usersRepo}
+func NewCardsService(
+	cardsRepo repository.Cards,
+	usersRepo repository.Users,
+	o repository.Outbox,
+	t repository.Transactional,
+	u URLBuilder,
+) *CardsService {
+	return &CardsService{cardsRepo: cardsRepo, usersRepo: usersRepo, outbox: o, txManager: t, urls: u}
 }
 
 func (c *CardsService) Create(ctx context.Context, userID string, dto dto.CreateCard) error {
@@ -118,8 +128,8 @@ func (c *CardsService) GetAllByUserID(ctx context.Context, userID string) (*[]mo
 			return nil, err
 		}
 
-		for _, card := range *cards {
-			card.Author = c.getFullURLForUser(card.Author)
+		for i := range *cards {
+			(*cards)[i].Author = c.getFullURLForUser((*cards)[i].Author)
 		}
 
 		return cards, nil
@@ -136,8 +146,8 @@ func (c *CardsService) GetAll(ctx context.Context) ([]model.Card, error) {
 		return nil, err
 	}
 
-	for _, card := range cards {
-		card.Author = c.getFullURLForUser(card.Author)
+	for i := range cards {
+		cards[i].Author = c.getFullURLForUser(cards[i].Author)
 	}
 
 	return cards, nil
@@ -156,6 +166,13 @@ func (c *CardsService) GetByID(ctx context.Context, cardID int) (*model.Card, er
 	return card, nil
 }
 
+// invitationNotification is the payload of constants.NotificationCardInvitation.
+type invitationNotification struct {
+	InvitationID int `json:"invitation_id"`
+	CardID       int `json:"card_id"`
+	InviterID    int `json:"inviter_id"`
+}
+
 func (c *CardsService) CreateInvitation(ctx context.Context, userID string, cardID int) error {
 	l := logging.LoggerFromContext(ctx)
 
@@ -177,12 +194,32 @@ func (c *CardsService) CreateInvitation(ctx context.Context, userID string, card
 			return err
 		}
 
-		err = c.cardsRepo.CreateInvitation(ctx, userId, *card)
+		// The author of the card is notified by the outbox worker once the invitation is committed
+		err = c.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			invitationID, err := c.cardsRepo.CreateInvitation(ctx, userId, *card)
+			if err != nil {
+				l.Error("Error when creating an invitation in database", zap.Error(err))
+
+				return err
+			}
+
+			event, err := newNotificationEvent(card.UserID, constants.NotificationCardInvitation,
+				strconv.Itoa(invitationID), invitationNotification{
+					InvitationID: invitationID,
+					CardID:       card.ID,
+					InviterID:    userId,
+				})
+			if err != nil {
+				return err
+			}
+
+			return c.outbox.Enqueue(ctx, event)
+		})
 		if err != nil {
-			l.Error("Error when creating an invitation in database", zap.Error(err))
-
 			return err
 		}
+
+		metrics.InvitationsSent.Inc()
 	} else {
 		return errors.Wrap(apperror.ErrUserNotFound, "user not found in database")
 	}
@@ -295,12 +332,9 @@ func (c *CardsService) GetResponsesByUserID(ctx context.Context, userID string)
 	return c.cardsRepo.GetResponsesByUserID(ctx, userId)
 }
 
-// getFullURLForUser function gets an article and changes its image_url to a full url
+// getFullURLForUser function gets a user and changes its image_url to a full url
 func (c *CardsService) getFullURLForUser(user model.User) model.User {
-	user.ImageURL = constants.BucketName + "." +
-		constants.EndPoint + "/" +
-		constants.ArticlesImagesFolder +
-		user.ImageURL
+	user.ImageURL = c.urls.ImageURL(constants.UsersAvatarsFolder, user.ImageURL)
 
 	return user
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	"acsp/internal/constants"
 	"acsp/internal/dto"
 	"acsp/internal/logging"
 	"acsp/internal/model"
@@ -14,12 +15,16 @@ import (
 )
 
 type ContestsService struct {
-	repo repository.Contests
+	repo      repository.Contests
+	txManager repository.Transactional
+	audit     Auditor
 }
 
-func NewContestsService(r repository.Contests) *ContestsService {
+func NewContestsService(r repository.Contests, t repository.Transactional, a Auditor) *ContestsService {
 	return &ContestsService{
-		repo: r,
+		repo:      r,
+		txManager: t,
+		audit:     a,
 	}
 }
 
@@ -34,21 +39,39 @@ func (c *ContestsService) Create(ctx context.Context, contest dto.CreateContest)
 		EndDate:     contest.EndDate,
 	}
 
-	err := c.repo.Create(ctx, contestModel)
-	if err != nil {
-		l.Error("Error when creating the contest", zap.Error(err))
+	return c.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		id, err := c.repo.Create(ctx, contestModel)
+		if err != nil {
+			l.Error("Error when creating the contest", zap.Error(err))
 
-		return errors.Wrap(err, "error when creating the contest")
-	}
+			return errors.Wrap(err, "error when creating the contest")
+		}
+
+		contestModel.ID = id
 
-	return nil
+		return c.audit.RecordTx(ctx, constants.AuditActionCreate, constants.AuditTargetContest, strconv.Itoa(id), nil,
+			contestModel)
+	})
 }
 
 func (c *ContestsService) Update(ctx context.Context, contestID string, contest dto.UpdateContest) error {
 	l := logging.LoggerFromContext(ctx).With(zap.String("contestID", contestID),
 		zap.String("contestName", contest.Name))
 
+	contestId, err := strconv.Atoi(contestID)
+	if err != nil {
+		l.Error("Error when converting id to int", zap.Error(err))
+
+		return errors.Wrap(err, "error when converting id to int")
+	}
+
+	before, err := c.repo.GetByID(ctx, contestId)
+	if err != nil {
+		return errors.Wrap(err, "error when getting the contest")
+	}
+
 	contestModel := model.Contest{
+		ID:          contestId,
 		Name:        contest.Name,
 		Description: contest.Description,
 		Link:        contest.Link,
@@ -56,15 +79,23 @@ func (c *ContestsService) Update(ctx context.Context, contestID string, contest
 		EndDate:     contest.EndDate,
 	}
 
-	err := c.repo.Update(ctx, contestModel)
-	if err != nil {
-		l.Error("Error when updating the contest", zap.Error(err))
+	return c.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := c.repo.Update(ctx, contestModel)
+		if err != nil {
+			l.Error("Error when updating the contest", zap.Error(err))
 
-		return errors.Wrap(err, "error when updating the contest")
-	}
+			return errors.Wrap(err, "error when updating the contest")
+		}
 
-	return nil
+		after, err := c.repo.GetByID(ctx, contestId)
+		if err != nil {
+			return errors.Wrap(err, "error when getting the contest")
+		}
+
+		return c.audit.RecordTx(ctx, constants.AuditActionUpdate, constants.AuditTargetContest, contestID, before, after)
+	})
 }
+
 func (c *ContestsService) Delete(ctx context.Context, contestID string) error {
 	l := logging.LoggerFromContext(ctx).With(zap.String("contestID", contestID))
 
@@ -75,14 +106,21 @@ func (c *ContestsService) Delete(ctx context.Context, contestID string) error {
 		return errors.Wrap(err, "error when converting id to int")
 	}
 
-	err = c.repo.Delete(ctx, contestId)
+	before, err := c.repo.GetByID(ctx, contestId)
 	if err != nil {
-		l.Error("Error when deleting the contest", zap.Error(err))
-
-		return errors.Wrap(err, "error when deleting the contest")
+		return errors.Wrap(err, "error when getting the contest")
 	}
 
-	return nil
+	return c.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := c.repo.Delete(ctx, contestId)
+		if err != nil {
+			l.Error("Error when deleting the contest", zap.Error(err))
+
+			return errors.Wrap(err, "error when deleting the contest")
+		}
+
+		return c.audit.RecordTx(ctx, constants.AuditActionDelete, constants.AuditTargetContest, contestID, before, nil)
+	})
 }
 
 func (c *ContestsService) GetByID(ctx context.Context, contestID string) (model.Contest, error) {
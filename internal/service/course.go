@@ -2,10 +2,12 @@ package service
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	"acsp/internal/constants"
 	"acsp/internal/dto"
 	"acsp/internal/logging"
 	"acsp/internal/model"
@@ -15,10 +17,14 @@ import (
 type CoursesService struct {
 	repo        repository.Courses
 	modulesRepo repository.CourseModules
+	txManager   repository.Transactional
+	urls        URLBuilder
+	audit       Auditor
 }
 
-func NewCoursesService(repo repository.Courses, modulesRepo repository.CourseModules) *CoursesService {
-	return &CoursesService{repo: repo, modulesRepo: modulesRepo}
+func NewCoursesService(repo repository.Courses, modulesRepo repository.CourseModules, t repository.Transactional,
+	u URLBuilder, a Auditor) *CoursesService {
+	return &CoursesService{repo: repo, modulesRepo: modulesRepo, txManager: t, urls: u, audit: a}
 }
 
 func (c *CoursesService) Create(ctx context.Context, input dto.CreateCourse) error {
@@ -31,14 +37,19 @@ func (c *CoursesService) Create(ctx context.Context, input dto.CreateCourse) err
 		Description: input.Description,
 	}
 
-	err := c.repo.Create(ctx, course)
-	if err != nil {
-		l.Error("Error when creating a course", zap.Error(err))
+	return c.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		id, err := c.repo.Create(ctx, course)
+		if err != nil {
+			l.Error("Error when creating a course", zap.Error(err))
 
-		return errors.Wrap(err, "error when creating a course")
-	}
+			return errors.Wrap(err, "error when creating a course")
+		}
+
+		course.ID = id
 
-	return nil
+		return c.audit.RecordTx(ctx, constants.AuditActionCreate, constants.AuditTargetCourse, strconv.Itoa(id), nil,
+			course)
+	})
 }
 
 func (c *CoursesService) Update(ctx context.Context, courseID int, input dto.UpdateCourse) error {
@@ -46,6 +57,11 @@ func (c *CoursesService) Update(ctx context.Context, courseID int, input dto.Upd
 		zap.Int("courseID", courseID),
 	)
 
+	before, err := c.repo.GetByID(ctx, courseID)
+	if err != nil {
+		return errors.Wrap(err, "error when getting a course by ID")
+	}
+
 	project := model.Course{
 		ID:          courseID,
 		Title:       input.Title,
@@ -53,14 +69,22 @@ func (c *CoursesService) Update(ctx context.Context, courseID int, input dto.Upd
 		Rating:      input.Rating,
 	}
 
-	err := c.repo.Update(ctx, project)
-	if err != nil {
-		l.Error("Error when updating a course", zap.Error(err))
+	return c.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := c.repo.Update(ctx, project)
+		if err != nil {
+			l.Error("Error when updating a course", zap.Error(err))
 
-		return errors.Wrap(err, "error when updating a course")
-	}
+			return errors.Wrap(err, "error when updating a course")
+		}
+
+		after, err := c.repo.GetByID(ctx, courseID)
+		if err != nil {
+			return errors.Wrap(err, "error when getting a course by ID")
+		}
 
-	return nil
+		return c.audit.RecordTx(ctx, constants.AuditActionUpdate, constants.AuditTargetCourse, strconv.Itoa(courseID),
+			before, after)
+	})
 }
 
 func (c *CoursesService) Delete(ctx context.Context, courseID int) error {
@@ -68,14 +92,22 @@ func (c *CoursesService) Delete(ctx context.Context, courseID int) error {
 		zap.Int("courseID", courseID),
 	)
 
-	err := c.repo.Delete(ctx, courseID)
+	before, err := c.repo.GetByID(ctx, courseID)
 	if err != nil {
-		l.Error("Error when deleting a course", zap.Error(err))
-
-		return errors.Wrap(err, "error when deleting a course")
+		return errors.Wrap(err, "error when getting a course by ID")
 	}
 
-	return nil
+	return c.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := c.repo.Delete(ctx, courseID)
+		if err != nil {
+			l.Error("Error when deleting a course", zap.Error(err))
+
+			return errors.Wrap(err, "error when deleting a course")
+		}
+
+		return c.audit.RecordTx(ctx, constants.AuditActionDelete, constants.AuditTargetCourse, strconv.Itoa(courseID),
+			before, nil)
+	})
 }
 
 func (c *CoursesService) GetAll(ctx context.Context) ([]model.Course, error) {
@@ -88,6 +120,10 @@ func (c *CoursesService) GetAll(ctx context.Context) ([]model.Course, error) {
 		return nil, errors.Wrap(err, "error when getting all courses")
 	}
 
+	for i := range courses {
+		courses[i] = c.getFullURL(courses[i])
+	}
+
 	return courses, nil
 }
 
@@ -114,5 +150,11 @@ func (c *CoursesService) GetByID(ctx context.Context, courseID int) (model.Cours
 		course.Modules = m
 	}
 
-	return course, nil
+	return c.getFullURL(course), nil
+}
+
+func (c *CoursesService) getFullURL(course model.Course) model.Course {
+	course.ImageURL = c.urls.ImageURL(constants.CoursesImagesFolder, course.ImageURL)
+
+	return course
 }
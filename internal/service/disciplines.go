@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -16,10 +17,14 @@ import (
 type DisciplinesService struct {
 	repo         repository.Disciplines
 	projectsRepo repository.Projects
+	txManager    repository.Transactional
+	urls         URLBuilder
+	audit        Auditor
 }
 
-func NewDisciplinesService(r repository.Disciplines, p repository.Projects) *DisciplinesService {
-	return &DisciplinesService{repo: r, projectsRepo: p}
+func NewDisciplinesService(r repository.Disciplines, p repository.Projects, t repository.Transactional, u URLBuilder,
+	a Auditor) *DisciplinesService {
+	return &DisciplinesService{repo: r, projectsRepo: p, txManager: t, urls: u, audit: a}
 }
 
 func (d DisciplinesService) Create(ctx context.Context, input dto.CreateDiscipline) error {
@@ -32,14 +37,19 @@ func (d DisciplinesService) Create(ctx context.Context, input dto.CreateDiscipli
 		Description: input.Description,
 	}
 
-	err := d.repo.Create(ctx, discipline)
-	if err != nil {
-		l.Error("Error when creating discipline", zap.Error(err))
+	return d.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		id, err := d.repo.Create(ctx, discipline)
+		if err != nil {
+			l.Error("Error when creating discipline", zap.Error(err))
 
-		return errors.Wrap(err, "error when creating discipline")
-	}
+			return errors.Wrap(err, "error when creating discipline")
+		}
 
-	return nil
+		discipline.ID = id
+
+		return d.audit.RecordTx(ctx, constants.AuditActionCreate, constants.AuditTargetDiscipline, strconv.Itoa(id), nil,
+			discipline)
+	})
 }
 
 func (d DisciplinesService) Update(ctx context.Context, disciplineID int, discipline dto.UpdateDiscipline) error {
@@ -47,20 +57,33 @@ func (d DisciplinesService) Update(ctx context.Context, disciplineID int, discip
 		"disciplineTitle", discipline.Title),
 	)
 
+	before, err := d.repo.GetByID(ctx, disciplineID)
+	if err != nil {
+		return errors.Wrap(err, "error when getting discipline")
+	}
+
 	disciplineToUpdate := model.Discipline{
 		ID:          disciplineID,
 		Title:       discipline.Title,
 		Description: discipline.Description,
 	}
 
-	err := d.repo.Update(ctx, disciplineToUpdate)
-	if err != nil {
-		l.Error("Error when updating discipline", zap.Error(err))
+	return d.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := d.repo.Update(ctx, disciplineToUpdate)
+		if err != nil {
+			l.Error("Error when updating discipline", zap.Error(err))
 
-		return errors.Wrap(err, "error when updating discipline")
-	}
+			return errors.Wrap(err, "error when updating discipline")
+		}
+
+		after, err := d.repo.GetByID(ctx, disciplineID)
+		if err != nil {
+			return errors.Wrap(err, "error when getting discipline")
+		}
 
-	return nil
+		return d.audit.RecordTx(ctx, constants.AuditActionUpdate, constants.AuditTargetDiscipline,
+			strconv.Itoa(disciplineID), before, after)
+	})
 }
 
 func (d DisciplinesService) Delete(ctx context.Context, disciplineID int) error {
@@ -68,14 +91,22 @@ func (d DisciplinesService) Delete(ctx context.Context, disciplineID int) error
 		"disciplineID", disciplineID),
 	)
 
-	err := d.repo.Delete(ctx, disciplineID)
+	before, err := d.repo.GetByID(ctx, disciplineID)
 	if err != nil {
-		l.Error("Error when deleting discipline", zap.Error(err))
-
-		return errors.Wrap(err, "error when deleting discipline")
+		return errors.Wrap(err, "error when getting discipline")
 	}
 
-	return nil
+	return d.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := d.repo.Delete(ctx, disciplineID)
+		if err != nil {
+			l.Error("Error when deleting discipline", zap.Error(err))
+
+			return errors.Wrap(err, "error when deleting discipline")
+		}
+
+		return d.audit.RecordTx(ctx, constants.AuditActionDelete, constants.AuditTargetDiscipline,
+			strconv.Itoa(disciplineID), before, nil)
+	})
 }
 
 func (d DisciplinesService) GetAll(ctx context.Context) ([]model.Discipline, error) {
@@ -120,20 +151,18 @@ func (d DisciplinesService) GetByID(ctx context.Context, disciplineID int) (mode
 
 func (d DisciplinesService) getFullURLs(disciplines []model.Discipline) []model.Discipline {
 	for i := range disciplines {
-		disciplines[i].ImageURL = constants.BucketName + "." +
-			constants.EndPoint + "/" +
-			constants.DisciplinesImagesFolder +
-			disciplines[i].ImageURL
+		disciplines[i].ImageURL = d.urls.ImageURL(constants.DisciplinesImagesFolder, disciplines[i].ImageURL)
 	}
 
 	return disciplines
 }
 
 func (d DisciplinesService) getFullURL(discipline model.Discipline) model.Discipline {
-	discipline.ImageURL = constants.BucketName + "." +
-		constants.EndPoint + "/" +
-		constants.DisciplinesImagesFolder +
-		discipline.ImageURL
+	discipline.ImageURL = d.urls.ImageURL(constants.DisciplinesImagesFolder, discipline.ImageURL)
+
+	for i := range discipline.Projects {
+		discipline.Projects[i].ImageURL = d.urls.ImageURL(constants.ProjectsImagesFolder, discipline.Projects[i].ImageURL)
+	}
 
 	return discipline
 }
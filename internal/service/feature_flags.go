@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"hash/fnv"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"acsp/internal/config"
+	"acsp/internal/constants"
+	"acsp/internal/dto"
+	"acsp/internal/logging"
+	"acsp/internal/model"
+	"acsp/internal/repository"
+)
+
+// FeatureFlagsService implements the FeatureFlags interface.
+type FeatureFlagsService struct {
+	repo      repository.FeatureFlags
+	cache     repository.FeatureFlagsCache
+	roles     repository.Roles
+	txManager repository.Transactional
+	audit     Auditor
+	config    config.FeatureFlagsConfig
+}
+
+// NewFeatureFlagsService creates a new instance of FeatureFlagsService.
+func NewFeatureFlagsService(
+	repo repository.FeatureFlags,
+	cache repository.FeatureFlagsCache,
+	roles repository.Roles,
+	t repository.Transactional,
+	a Auditor,
+	c config.FeatureFlagsConfig,
+) *FeatureFlagsService {
+	return &FeatureFlagsService{
+		repo:      repo,
+		cache:     cache,
+		roles:     roles,
+		txManager: t,
+		audit:     a,
+		config:    c,
+	}
+}
+
+// IsEnabled reports whether the feature is on for the user, a missing flag is off. A feature is turned off for
+// everyone at once by disabling its flag.
+func (f *FeatureFlagsService) IsEnabled(ctx context.Context, key, userID string) (bool, error) {
+	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return false, errors.Wrap(err, "invalid user id")
+	}
+
+	flags, err := f.cachedFlags(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	for _, flag := range flags {
+		if flag.Key == key {
+			return f.evaluate(ctx, flag, id)
+		}
+	}
+
+	return false, nil
+}
+
+// GetAll returns all feature flags.
+func (f *FeatureFlagsService) GetAll(ctx context.Context) ([]model.FeatureFlag, error) {
+	return f.repo.GetAll(ctx)
+}
+
+// GetByKey returns the feature flag.
+func (f *FeatureFlagsService) GetByKey(ctx context.Context, key string) (model.FeatureFlag, error) {
+	return f.repo.GetByKey(ctx, key)
+}
+
+// Create creates a feature flag.
+func (f *FeatureFlagsService) Create(ctx context.Context, input dto.CreateFeatureFlag) error {
+	flag := model.FeatureFlag{
+		Key:         input.Key,
+		Description: input.Description,
+		Enabled:     input.Enabled,
+		Roles:       input.Roles,
+		UserIDs:     input.UserIDs,
+		Percentage:  input.Percentage,
+	}
+
+	err := f.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := f.repo.Create(ctx, flag)
+		if err != nil {
+			return err
+		}
+
+		return f.audit.RecordTx(ctx, constants.AuditActionCreate, constants.AuditTargetFeatureFlag, flag.Key, nil, flag)
+	})
+	if err != nil {
+		return err
+	}
+
+	f.invalidate(ctx)
+
+	return nil
+}
+
+// Update replaces the rules of the feature flag.
+func (f *FeatureFlagsService) Update(ctx context.Context, key string, input dto.UpdateFeatureFlag) error {
+	before, err := f.repo.GetByKey(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	flag := model.FeatureFlag{
+		Key:         key,
+		Description: input.Description,
+		Enabled:     input.Enabled,
+		Roles:       input.Roles,
+		UserIDs:     input.UserIDs,
+		Percentage:  input.Percentage,
+	}
+
+	err = f.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := f.repo.Update(ctx, flag)
+		if err != nil {
+			return err
+		}
+
+		after, err := f.repo.GetByKey(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		return f.audit.RecordTx(ctx, constants.AuditActionUpdate, constants.AuditTargetFeatureFlag, key, before, after)
+	})
+	if err != nil {
+		return err
+	}
+
+	f.invalidate(ctx)
+
+	return nil
+}
+
+// Delete deletes the feature flag, the feature is off afterwards.
+func (f *FeatureFlagsService) Delete(ctx context.Context, key string) error {
+	before, err := f.repo.GetByKey(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	err = f.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := f.repo.Delete(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		return f.audit.RecordTx(ctx, constants.AuditActionDelete, constants.AuditTargetFeatureFlag, key, before, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	f.invalidate(ctx)
+
+	return nil
+}
+
+// cachedFlags returns the flags of the cache, they are loaded from the database on a miss.
+// An unavailable cache only makes the flags slower, so its errors are logged.
+func (f *FeatureFlagsService) cachedFlags(ctx context.Context) ([]model.FeatureFlag, error) {
+	l := logging.LoggerFromContext(ctx)
+
+	flags, found, err := f.cache.Get(ctx)
+	if err != nil {
+		l.Error("Error when getting the cached feature flags", zap.Error(err))
+	}
+
+	if found {
+		return flags, nil
+	}
+
+	flags, err = f.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = f.cache.Set(ctx, flags, f.config.CacheTTL)
+	if err != nil {
+		l.Error("Error when caching the feature flags", zap.Error(err))
+	}
+
+	return flags, nil
+}
+
+func (f *FeatureFlagsService) invalidate(ctx context.Context) {
+	err := f.cache.Invalidate(ctx)
+	if err != nil {
+		logging.LoggerFromContext(ctx).Error("Error when invalidating the cached feature flags, "+
+			"the change is applied once the cache expires", zap.Error(err))
+	}
+}
+
+func (f *FeatureFlagsService) evaluate(ctx context.Context, flag model.FeatureFlag, userID int) (bool, error) {
+	if !flag.Enabled {
+		return false, nil
+	}
+
+	for _, id := range flag.UserIDs {
+		if id == int64(userID) {
+			return true, nil
+		}
+	}
+
+	// The roles are only needed by the flags rolled out to roles
+	if len(flag.Roles) > 0 {
+		roles, err := f.roles.GetUserRoles(ctx, userID)
+		if err != nil {
+			return false, err
+		}
+
+		for _, role := range roles {
+			for _, name := range flag.Roles {
+				if role.Name == name {
+					return true, nil
+				}
+			}
+		}
+	}
+
+	return rolloutBucket(flag.Key, userID) < flag.Percentage, nil
+}
+
+// rolloutBucket places the user in one of 100 buckets. The flag key is a part of the hash, so that
+// the same users aren't always the first to get every feature, and a user keeps the feature when
+// the percentage grows.
+func rolloutBucket(key string, userID int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key + ":" + strconv.Itoa(userID)))
+
+	return int(h.Sum32() % 100)
+}
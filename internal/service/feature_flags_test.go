@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"acsp/internal/apperror"
+	"acsp/internal/config"
+	"acsp/internal/constants"
+	"acsp/internal/dto"
+	"acsp/internal/model"
+	"acsp/internal/repository"
+)
+
+type fakeFeatureFlags struct {
+	repository.FeatureFlags
+
+	flags []model.FeatureFlag
+	loads int
+}
+
+func (f *fakeFeatureFlags) GetAll(ctx context.Context) ([]model.FeatureFlag, error) {
+	f.loads++
+
+	return f.flags, nil
+}
+
+func (f *fakeFeatureFlags) GetByKey(ctx context.Context, key string) (model.FeatureFlag, error) {
+	for _, flag := range f.flags {
+		if flag.Key == key {
+			return flag, nil
+		}
+	}
+
+	return model.FeatureFlag{}, apperror.ErrFeatureFlagNotFound
+}
+
+func (f *fakeFeatureFlags) Create(ctx context.Context, flag model.FeatureFlag) error {
+	f.flags = append(f.flags, flag)
+
+	return nil
+}
+
+func (f *fakeFeatureFlags) Update(ctx context.Context, flag model.FeatureFlag) error {
+	for i := range f.flags {
+		if f.flags[i].Key == flag.Key {
+			f.flags[i] = flag
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeFeatureFlags) Delete(ctx context.Context, key string) error {
+	for i := range f.flags {
+		if f.flags[i].Key == key {
+			f.flags = append(f.flags[:i], f.flags[i+1:]...)
+
+			break
+		}
+	}
+
+	return nil
+}
+
+type fakeFeatureFlagsCache struct {
+	flags []model.FeatureFlag
+	found bool
+}
+
+func (f *fakeFeatureFlagsCache) Get(ctx context.Context) ([]model.FeatureFlag, bool, error) {
+	return f.flags, f.found, nil
+}
+
+func (f *fakeFeatureFlagsCache) Set(ctx context.Context, flags []model.FeatureFlag, ttl time.Duration) error {
+	f.flags, f.found = flags, true
+
+	return nil
+}
+
+func (f *fakeFeatureFlagsCache) Invalidate(ctx context.Context) error {
+	f.flags, f.found = nil, false
+
+	return nil
+}
+
+type fakeRoles struct {
+	repository.Roles
+
+	roles map[int][]model.Role
+}
+
+func (f *fakeRoles) GetUserRoles(ctx context.Context, userID int) ([]model.Role, error) {
+	return f.roles[userID], nil
+}
+
+func newTestFeatureFlagsService(flags ...model.FeatureFlag) (*FeatureFlagsService, *fakeFeatureFlags) {
+	repo := &fakeFeatureFlags{flags: flags}
+	roles := &fakeRoles{roles: map[int][]model.Role{2: {{ID: "2", Name: "admin"}}}}
+
+	return NewFeatureFlagsService(repo, &fakeFeatureFlagsCache{}, roles, fakeTransactional{},
+		NewAuditService(&fakeAuditEvents{}), config.FeatureFlagsConfig{CacheTTL: time.Minute}), repo
+}
+
+func TestFeatureFlagsService_IsEnabled(t *testing.T) {
+	f, repo := newTestFeatureFlagsService(
+		model.FeatureFlag{Key: "off", Enabled: false, Percentage: 100, UserIDs: pq.Int64Array{1}},
+		model.FeatureFlag{Key: "everyone", Enabled: true, Percentage: 100},
+		model.FeatureFlag{Key: "users", Enabled: true, UserIDs: pq.Int64Array{1}},
+		model.FeatureFlag{Key: "admins", Enabled: true, Roles: pq.StringArray{"admin"}},
+	)
+
+	tests := []struct {
+		key     string
+		userID  string
+		enabled bool
+	}{
+		{key: "off", userID: "1", enabled: false},
+		{key: "everyone", userID: "3", enabled: true},
+		{key: "users", userID: "1", enabled: true},
+		{key: "users", userID: "2", enabled: false},
+		{key: "admins", userID: "2", enabled: true},
+		{key: "admins", userID: "1", enabled: false},
+		{key: "missing", userID: "1", enabled: false},
+	}
+
+	for _, tt := range tests {
+		enabled, err := f.IsEnabled(context.Background(), tt.key, tt.userID)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.enabled, enabled, "%s for user %s", tt.key, tt.userID)
+	}
+
+	// The flags are loaded once and then read from the cache
+	assert.Equal(t, 1, repo.loads)
+
+	_, err := f.IsEnabled(context.Background(), "everyone", "admin")
+	assert.Error(t, err)
+}
+
+func TestFeatureFlagsService_IsEnabledPercentage(t *testing.T) {
+	f, _ := newTestFeatureFlagsService(model.FeatureFlag{Key: "half", Enabled: true, Percentage: 50})
+
+	enabled := 0
+
+	for id := 1; id <= 1000; id++ {
+		ok, err := f.IsEnabled(context.Background(), "half", strconv.Itoa(id))
+		assert.NoError(t, err)
+
+		if ok {
+			enabled++
+		}
+
+		// The rollout is stable for a user
+		again, _ := f.IsEnabled(context.Background(), "half", strconv.Itoa(id))
+		assert.Equal(t, ok, again)
+	}
+
+	assert.InDelta(t, 500, enabled, 60)
+}
+
+func TestFeatureFlagsService_Audit(t *testing.T) {
+	ctx := context.Background()
+	audit, events := newTestAuditService()
+	f := NewFeatureFlagsService(&fakeFeatureFlags{}, &fakeFeatureFlagsCache{}, &fakeRoles{}, fakeTransactional{},
+		audit, config.FeatureFlagsConfig{CacheTTL: time.Minute})
+
+	assert.NoError(t, f.Create(ctx, dto.CreateFeatureFlag{Key: "contests", Percentage: 10}))
+	assert.NoError(t, f.Update(ctx, "contests", dto.UpdateFeatureFlag{Enabled: true, Percentage: 50}))
+	assert.NoError(t, f.Delete(ctx, "contests"))
+
+	if assert.Len(t, events.events, 3) {
+		for i, action := range []string{
+			constants.AuditActionCreate, constants.AuditActionUpdate, constants.AuditActionDelete,
+		} {
+			assert.Equal(t, action, events.events[i].Action)
+			assert.Equal(t, constants.AuditTargetFeatureFlag, events.events[i].TargetType)
+			assert.Equal(t, "contests", events.events[i].TargetID)
+		}
+
+		assert.JSONEq(t, `{"enabled": {"before": false, "after": true}, "percentage": {"before": 10, "after": 50}}`,
+			string(events.events[1].Changes))
+	}
+}
+
+func TestFeatureFlagsService_AuditFailure(t *testing.T) {
+	cache := &fakeFeatureFlagsCache{flags: []model.FeatureFlag{{Key: "contests"}}, found: true}
+	audit := NewAuditService(&fakeAuditEvents{err: errors.New("connection refused")})
+	f := NewFeatureFlagsService(&fakeFeatureFlags{}, cache, &fakeRoles{}, fakeTransactional{}, audit,
+		config.FeatureFlagsConfig{CacheTTL: time.Minute})
+
+	// The change is rolled back without its event, so the cached flags are still current
+	assert.Error(t, f.Create(context.Background(), dto.CreateFeatureFlag{Key: "rating"}))
+	assert.True(t, cache.found)
+}
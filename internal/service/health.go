@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"acsp/internal/config"
+	"acsp/internal/constants"
+	"acsp/internal/logging"
+	"acsp/internal/model"
+	"acsp/internal/repository"
+)
+
+// HealthService implements the Health interface.
+type HealthService struct {
+	checks  map[string]func(ctx context.Context) error
+	timeout time.Duration
+}
+
+// NewHealthService creates a new instance of HealthService checking postgres, redis and the object storage.
+func NewHealthService(repo repository.Health, storage repository.ObjectStorage, c config.HealthConfig) *HealthService {
+	return &HealthService{
+		checks: map[string]func(ctx context.Context) error{
+			"postgres": repo.PingDatabase,
+			"redis":    repo.PingCache,
+			"storage":  storage.Ping,
+		},
+		timeout: c.CheckTimeout,
+	}
+}
+
+// Check checks the dependencies at once, each of them has the check timeout to answer.
+func (h *HealthService) Check(ctx context.Context) model.HealthReport {
+	report := model.HealthReport{
+		Status: constants.HealthStatusUp,
+		Checks: make(map[string]model.HealthCheck, len(h.checks)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for name, check := range h.checks {
+		wg.Add(1)
+
+		go func(name string, check func(ctx context.Context) error) {
+			defer wg.Done()
+
+			result := h.run(ctx, check)
+			if result.Status != constants.HealthStatusUp {
+				logging.LoggerFromContext(ctx).Error("The dependency is down", zap.String("dependency", name),
+					zap.Float64("latencyMS", result.LatencyMS), zap.String("error", result.Error))
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Checks[name] = result
+			if result.Status != constants.HealthStatusUp {
+				report.Status = constants.HealthStatusDown
+			}
+		}(name, check)
+	}
+
+	wg.Wait()
+
+	return report
+}
+
+func (h *HealthService) run(ctx context.Context, check func(ctx context.Context) error) model.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+
+	err := check(ctx)
+
+	result := model.HealthCheck{
+		Status:    constants.HealthStatusUp,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+
+	if err != nil {
+		result.Status = constants.HealthStatusDown
+		result.Error = err.Error()
+	}
+
+	return result
+}
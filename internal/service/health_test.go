@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"acsp/internal/config"
+	"acsp/internal/constants"
+)
+
+type fakeHealth struct {
+	databaseErr error
+	cacheDelay  time.Duration
+}
+
+func (f *fakeHealth) PingDatabase(ctx context.Context) error {
+	return f.databaseErr
+}
+
+func (f *fakeHealth) PingCache(ctx context.Context) error {
+	select {
+	case <-time.After(f.cacheDelay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestHealthService_Check(t *testing.T) {
+	c := config.HealthConfig{CheckTimeout: 50 * time.Millisecond}
+
+	report := NewHealthService(&fakeHealth{}, &fakeObjectStorage{}, c).Check(context.Background())
+	assert.Equal(t, constants.HealthStatusUp, report.Status)
+	assert.Len(t, report.Checks, 3)
+
+	report = NewHealthService(
+		&fakeHealth{databaseErr: errors.New("connection refused"), cacheDelay: time.Second},
+		&fakeObjectStorage{}, c,
+	).Check(context.Background())
+	assert.Equal(t, constants.HealthStatusDown, report.Status)
+	assert.Equal(t, constants.HealthStatusDown, report.Checks["postgres"].Status)
+	assert.Equal(t, "connection refused", report.Checks["postgres"].Error)
+	// A dependency that doesn't answer in time is down
+	assert.Equal(t, constants.HealthStatusDown, report.Checks["redis"].Status)
+	assert.Less(t, report.Checks["redis"].LatencyMS, float64(time.Second.Milliseconds()))
+	assert.Equal(t, constants.HealthStatusUp, report.Checks["storage"].Status)
+
+	// The report is public, the errors are only logged
+	data, err := json.Marshal(report)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "connection refused")
+}
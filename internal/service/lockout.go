@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"acsp/internal/apperror"
+	"acsp/internal/config"
+	"acsp/internal/constants"
+	"acsp/internal/logging"
+	"acsp/internal/model"
+	"acsp/internal/repository"
+)
+
+const (
+	// lockoutReason locks an account or an IP after the max failures
+	lockoutReason = "lockout"
+	// delayReason slows down the sign-ins of an account after a few failures
+	delayReason = "delay"
+)
+
+// LockoutService implements the Lockout interface.
+type LockoutService struct {
+	repo      repository.LoginAttempts
+	lockouts  repository.AccountLockouts
+	users     repository.Users
+	txManager repository.Transactional
+	audit     Auditor
+	config    config.LockoutConfig
+}
+
+// NewLockoutService creates a new instance of LockoutService.
+func NewLockoutService(
+	repo repository.LoginAttempts,
+	lockouts repository.AccountLockouts,
+	users repository.Users,
+	t repository.Transactional,
+	a Auditor,
+	c config.LockoutConfig,
+) *LockoutService {
+	return &LockoutService{
+		repo:      repo,
+		lockouts:  lockouts,
+		users:     users,
+		txManager: t,
+		audit:     a,
+		config:    c,
+	}
+}
+
+// Check returns an error telling when to retry if the account or the IP may not sign in now.
+func (s *LockoutService) Check(ctx context.Context, email, ip string) error {
+	reason, ttl, found, err := s.repo.GetLock(ctx, accountSubject(email))
+	if err != nil {
+		return err
+	}
+
+	if found {
+		if reason == lockoutReason {
+			return apperror.WithRetryAfter(apperror.ErrAccountLocked, ttl)
+		}
+
+		return apperror.WithRetryAfter(apperror.ErrTooManyAttempts, ttl)
+	}
+
+	_, ttl, found, err = s.repo.GetLock(ctx, ipSubject(ip))
+	if err != nil {
+		return err
+	}
+
+	if found {
+		return apperror.WithRetryAfter(apperror.ErrTooManyAttempts, ttl)
+	}
+
+	return nil
+}
+
+// Fail counts a failed sign-in of the account and of the IP. The account is locked after the max failures,
+// its next sign-ins are delayed after a few failures, the delay doubles with every failure. The IP is locked
+// after the max failures of the IP, so that many accounts aren't guessed from one address.
+func (s *LockoutService) Fail(ctx context.Context, email, ip string) error {
+	l := logging.LoggerFromContext(ctx).With(zap.String("email", email), zap.String("ip", ip))
+
+	account := accountSubject(email)
+
+	failures, err := s.repo.AddFailure(ctx, account, s.config.FailureWindow)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case failures >= s.config.MaxFailures:
+		// The failures are counted anew after the lockout
+		if err := s.repo.Reset(ctx, account); err != nil {
+			return err
+		}
+
+		if err := s.lock(ctx, account, constants.AuditTargetAccount, normalizeEmail(email), email, ip, failures); err != nil {
+			return err
+		}
+
+		l.Warn("The account is locked after failed sign-ins", zap.Int("failures", failures))
+	case failures > s.config.DelayAfter && s.config.Delay > 0:
+		if err := s.repo.Lock(ctx, account, delayReason, s.delay(failures)); err != nil {
+			return err
+		}
+	}
+
+	failures, err = s.repo.AddFailure(ctx, ipSubject(ip), s.config.FailureWindow)
+	if err != nil {
+		return err
+	}
+
+	if failures >= s.config.IPMaxFailures {
+		if err := s.repo.Reset(ctx, ipSubject(ip)); err != nil {
+			return err
+		}
+
+		if err := s.lock(ctx, ipSubject(ip), constants.AuditTargetIP, ip, email, ip, failures); err != nil {
+			return err
+		}
+
+		l.Warn("The IP is locked after failed sign-ins", zap.Int("failures", failures))
+	}
+
+	return nil
+}
+
+// Succeed forgets the failures of the account after a successful sign-in.
+func (s *LockoutService) Succeed(ctx context.Context, email string) error {
+	return s.repo.Reset(ctx, accountSubject(email))
+}
+
+// Unlock lets the user sign in again before the lockout is over. The locks of the IPs the user was locked
+// from are cleared as well, the user may share the address, e.g. of the campus network, with whoever
+// locked it. Only the IPs recorded with the email of the user are cleared, the others stay locked.
+func (s *LockoutService) Unlock(ctx context.Context, userID string) error {
+	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.users.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.Reset(ctx, accountSubject(user.Email))
+	if err != nil {
+		return err
+	}
+
+	ips, err := s.lockouts.GetLockedIPs(ctx, normalizeEmail(user.Email))
+	if err != nil {
+		return err
+	}
+
+	for _, ip := range ips {
+		err = s.repo.Reset(ctx, ipSubject(ip))
+		if err != nil {
+			return err
+		}
+	}
+
+	logging.LoggerFromContext(ctx).Info("The account is unlocked", zap.Int("userID", id), zap.Strings("ips", ips))
+
+	s.audit.Record(ctx, constants.AuditActionUnlock, constants.AuditTargetUser, userID, nil,
+		struct {
+			IPs []string `json:"ips"`
+		}{IPs: ips})
+
+	return nil
+}
+
+// lock locks the subject for the lockout duration and records the lockout, the target is the account or the IP.
+func (s *LockoutService) lock(ctx context.Context, subject, targetType, targetID, email, ip string, failures int) error {
+	err := s.repo.Lock(ctx, subject, lockoutReason, s.config.LockoutDuration)
+	if err != nil {
+		return err
+	}
+
+	lockout := model.AccountLockout{
+		Email:       normalizeEmail(email),
+		IP:          ip,
+		Failures:    failures,
+		LockedUntil: time.Now().Add(s.config.LockoutDuration).UTC(),
+	}
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.lockouts.Add(ctx, lockout)
+		if err != nil {
+			return err
+		}
+
+		return s.audit.RecordTx(ctx, constants.AuditActionLock, targetType, targetID, nil, lockout)
+	})
+}
+
+// delay returns the delay after the failures, it doubles with every failure after DelayAfter up to MaxDelay.
+func (s *LockoutService) delay(failures int) time.Duration {
+	d := s.config.Delay
+	for i := s.config.DelayAfter + 1; i < failures && d < s.config.MaxDelay; i++ {
+		d *= 2
+	}
+
+	if s.config.MaxDelay > 0 && d > s.config.MaxDelay {
+		return s.config.MaxDelay
+	}
+
+	return d
+}
+
+func accountSubject(email string) string {
+	return "account:" + normalizeEmail(email)
+}
+
+func ipSubject(ip string) string {
+	return "ip:" + ip
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
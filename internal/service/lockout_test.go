@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"acsp/internal/apperror"
+	"acsp/internal/config"
+	"acsp/internal/constants"
+	"acsp/internal/model"
+	"acsp/internal/repository"
+)
+
+type loginLock struct {
+	reason string
+	d      time.Duration
+}
+
+// fakeLoginAttempts keeps the failures and the locks in memory, the locks don't expire
+type fakeLoginAttempts struct {
+	failures map[string]int
+	locks    map[string]loginLock
+}
+
+func newFakeLoginAttempts() *fakeLoginAttempts {
+	return &fakeLoginAttempts{failures: map[string]int{}, locks: map[string]loginLock{}}
+}
+
+func (f *fakeLoginAttempts) GetLock(ctx context.Context, subject string) (string, time.Duration, bool, error) {
+	lock, ok := f.locks[subject]
+
+	return lock.reason, lock.d, ok, nil
+}
+
+func (f *fakeLoginAttempts) AddFailure(ctx context.Context, subject string, window time.Duration) (int, error) {
+	f.failures[subject]++
+
+	return f.failures[subject], nil
+}
+
+func (f *fakeLoginAttempts) Lock(ctx context.Context, subject, reason string, d time.Duration) error {
+	f.locks[subject] = loginLock{reason: reason, d: d}
+
+	return nil
+}
+
+func (f *fakeLoginAttempts) Reset(ctx context.Context, subject string) error {
+	delete(f.failures, subject)
+	delete(f.locks, subject)
+
+	return nil
+}
+
+type fakeAccountLockouts struct {
+	lockouts []model.AccountLockout
+}
+
+func (f *fakeAccountLockouts) Add(ctx context.Context, lockout model.AccountLockout) error {
+	f.lockouts = append(f.lockouts, lockout)
+
+	return nil
+}
+
+func (f *fakeAccountLockouts) GetLockedIPs(ctx context.Context, email string) ([]string, error) {
+	var ips []string
+
+	for _, lockout := range f.lockouts {
+		if lockout.Email == email && lockout.LockedUntil.After(time.Now()) {
+			ips = append(ips, lockout.IP)
+		}
+	}
+
+	return ips, nil
+}
+
+// fakeLockoutUsers only implements GetByID
+type fakeLockoutUsers struct {
+	repository.Users
+	users map[int]model.User
+}
+
+func (f *fakeLockoutUsers) GetByID(ctx context.Context, id int) (model.User, error) {
+	user, ok := f.users[id]
+	if !ok {
+		return model.User{}, apperror.ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+var testLockoutConfig = config.LockoutConfig{
+	MaxFailures:     5,
+	IPMaxFailures:   8,
+	FailureWindow:   15 * time.Minute,
+	LockoutDuration: 15 * time.Minute,
+	DelayAfter:      2,
+	Delay:           time.Second,
+	MaxDelay:        3 * time.Second,
+}
+
+func TestLockoutService_Fail(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeLoginAttempts()
+	lockouts := &fakeAccountLockouts{}
+	audit, events := newTestAuditService()
+	s := NewLockoutService(repo, lockouts, &fakeLockoutUsers{}, fakeTransactional{}, audit, testLockoutConfig)
+
+	// The first failures aren't delayed
+	for i := 0; i < 2; i++ {
+		require.NoError(t, s.Fail(ctx, "User@Example.com", "10.0.0.1"))
+	}
+	assert.NoError(t, s.Check(ctx, "user@example.com", "10.0.0.1"))
+
+	// The delay doubles up to the max delay
+	for _, want := range []time.Duration{time.Second, 2 * time.Second} {
+		require.NoError(t, s.Fail(ctx, "user@example.com", "10.0.0.1"))
+
+		err := s.Check(ctx, "user@example.com", "10.0.0.1")
+		assert.ErrorIs(t, err, apperror.ErrTooManyAttempts)
+
+		var retryErr *apperror.RetryError
+		require.ErrorAs(t, err, &retryErr)
+		assert.Equal(t, want, retryErr.RetryAfter)
+	}
+
+	// The account is locked after the max failures
+	require.NoError(t, s.Fail(ctx, "user@example.com", "10.0.0.1"))
+
+	err := s.Check(ctx, "user@example.com", "10.0.0.2")
+	assert.ErrorIs(t, err, apperror.ErrAccountLocked)
+	assert.Equal(t, apperror.KindTooManyRequests, apperror.KindOf(err))
+
+	require.Len(t, lockouts.lockouts, 1)
+	assert.Equal(t, "user@example.com", lockouts.lockouts[0].Email)
+	assert.Equal(t, "10.0.0.1", lockouts.lockouts[0].IP)
+	assert.Equal(t, 5, lockouts.lockouts[0].Failures)
+
+	require.Len(t, events.events, 1)
+	assert.Equal(t, constants.AuditActionLock, events.events[0].Action)
+	assert.Equal(t, constants.AuditTargetAccount, events.events[0].TargetType)
+	assert.Equal(t, "user@example.com", events.events[0].TargetID)
+
+	// The failures of the account are counted anew after the lockout
+	assert.Zero(t, repo.failures["account:user@example.com"])
+}
+
+func TestLockoutService_FailIP(t *testing.T) {
+	ctx := context.Background()
+	lockouts := &fakeAccountLockouts{}
+	audit, events := newTestAuditService()
+	s := NewLockoutService(
+		newFakeLoginAttempts(), lockouts, &fakeLockoutUsers{}, fakeTransactional{}, audit, testLockoutConfig)
+
+	// Guessing many accounts from one IP locks the IP
+	for i := 0; i < testLockoutConfig.IPMaxFailures; i++ {
+		require.NoError(t, s.Fail(ctx, "user"+string(rune('a'+i))+"@example.com", "10.0.0.1"))
+	}
+
+	assert.ErrorIs(t, s.Check(ctx, "other@example.com", "10.0.0.1"), apperror.ErrTooManyAttempts)
+	assert.NoError(t, s.Check(ctx, "other@example.com", "10.0.0.2"))
+	assert.Len(t, lockouts.lockouts, 1)
+
+	require.Len(t, events.events, 1)
+	assert.Equal(t, constants.AuditTargetIP, events.events[0].TargetType)
+	assert.Equal(t, "10.0.0.1", events.events[0].TargetID)
+}
+
+func TestLockoutService_SucceedAndUnlock(t *testing.T) {
+	ctx := context.Background()
+	users := &fakeLockoutUsers{users: map[int]model.User{7: {ID: "7", Email: "User@example.com"}}}
+	audit, events := newTestAuditService()
+	s := NewLockoutService(
+		newFakeLoginAttempts(), &fakeAccountLockouts{}, users, fakeTransactional{}, audit, testLockoutConfig)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, s.Fail(ctx, "user@example.com", "10.0.0.1"))
+	}
+	require.Error(t, s.Check(ctx, "user@example.com", "10.0.0.1"))
+
+	require.NoError(t, s.Succeed(ctx, "user@example.com"))
+	assert.NoError(t, s.Check(ctx, "user@example.com", "10.0.0.1"))
+
+	for i := 0; i < testLockoutConfig.MaxFailures; i++ {
+		require.NoError(t, s.Fail(ctx, "user@example.com", "10.0.0.2"))
+	}
+	require.ErrorIs(t, s.Check(ctx, "user@example.com", "10.0.0.2"), apperror.ErrAccountLocked)
+
+	require.NoError(t, s.Unlock(ctx, "7"))
+	assert.NoError(t, s.Check(ctx, "user@example.com", "10.0.0.2"))
+	require.Len(t, events.events, 2)
+	assert.Equal(t, constants.AuditActionUnlock, events.events[1].Action)
+	assert.Equal(t, "7", events.events[1].TargetID)
+
+	assert.ErrorIs(t, s.Unlock(ctx, "8"), apperror.ErrUserNotFound)
+	assert.Equal(t, apperror.KindValidation, apperror.KindOf(s.Unlock(ctx, "abc")))
+}
+
+func TestLockoutService_UnlockIP(t *testing.T) {
+	ctx := context.Background()
+	users := &fakeLockoutUsers{users: map[int]model.User{7: {ID: "7", Email: "user@example.com"}}}
+	s := NewLockoutService(newFakeLoginAttempts(), &fakeAccountLockouts{}, users, fakeTransactional{},
+		NewAuditService(&fakeAuditEvents{}), testLockoutConfig)
+
+	// The user shares the IP with whoever guesses the accounts, the last failure is the user's
+	for i := 0; i < testLockoutConfig.IPMaxFailures-1; i++ {
+		require.NoError(t, s.Fail(ctx, "user"+string(rune('a'+i))+"@example.com", "10.0.0.1"))
+	}
+	require.NoError(t, s.Fail(ctx, "user@example.com", "10.0.0.1"))
+
+	// Another IP is locked by others only
+	for i := 0; i < testLockoutConfig.IPMaxFailures; i++ {
+		require.NoError(t, s.Fail(ctx, "other"+string(rune('a'+i))+"@example.com", "10.0.0.2"))
+	}
+
+	require.ErrorIs(t, s.Check(ctx, "user@example.com", "10.0.0.1"), apperror.ErrTooManyAttempts)
+
+	require.NoError(t, s.Unlock(ctx, "7"))
+	assert.NoError(t, s.Check(ctx, "user@example.com", "10.0.0.1"))
+	assert.ErrorIs(t, s.Check(ctx, "user@example.com", "10.0.0.2"), apperror.ErrTooManyAttempts)
+}
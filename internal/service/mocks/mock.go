@@ -0,0 +1,2705 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	dto "acsp/internal/dto"
+	jwks "acsp/internal/jwks"
+	model "acsp/internal/model"
+	service "acsp/internal/service"
+	context "context"
+	multipart "mime/multipart"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAuthorization is a mock of Authorization interface.
+type MockAuthorization struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthorizationMockRecorder
+}
+
+// MockAuthorizationMockRecorder is the mock recorder for MockAuthorization.
+type MockAuthorizationMockRecorder struct {
+	mock *MockAuthorization
+}
+
+// NewMockAuthorization creates a new mock instance.
+func NewMockAuthorization(ctrl *gomock.Controller) *MockAuthorization {
+	mock := &MockAuthorization{ctrl: ctrl}
+	mock.recorder = &MockAuthorizationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthorization) EXPECT() *MockAuthorizationMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAuthorization) Authenticate(ctx context.Context, email, password string) (model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, email, password)
+	ret0, _ := ret[0].(model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAuthorizationMockRecorder) Authenticate(ctx, email, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAuthorization)(nil).Authenticate), ctx, email, password)
+}
+
+// CreateUser mocks base method.
+func (m *MockAuthorization) CreateUser(ctx context.Context, dto dto.CreateUser) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockAuthorizationMockRecorder) CreateUser(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockAuthorization)(nil).CreateUser), ctx, dto)
+}
+
+// DeleteRefreshToken mocks base method.
+func (m *MockAuthorization) DeleteRefreshToken(ctx context.Context, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRefreshToken", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRefreshToken indicates an expected call of DeleteRefreshToken.
+func (mr *MockAuthorizationMockRecorder) DeleteRefreshToken(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRefreshToken", reflect.TypeOf((*MockAuthorization)(nil).DeleteRefreshToken), ctx, userID)
+}
+
+// GenerateTokenPair mocks base method.
+func (m *MockAuthorization) GenerateTokenPair(ctx context.Context, user model.User) (*service.TokenDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateTokenPair", ctx, user)
+	ret0, _ := ret[0].(*service.TokenDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateTokenPair indicates an expected call of GenerateTokenPair.
+func (mr *MockAuthorizationMockRecorder) GenerateTokenPair(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateTokenPair", reflect.TypeOf((*MockAuthorization)(nil).GenerateTokenPair), ctx, user)
+}
+
+// GetUserByID mocks base method.
+func (m *MockAuthorization) GetUserByID(ctx context.Context, userID string) (model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", ctx, userID)
+	ret0, _ := ret[0].(model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockAuthorizationMockRecorder) GetUserByID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockAuthorization)(nil).GetUserByID), ctx, userID)
+}
+
+// ParseRefreshToken mocks base method.
+func (m *MockAuthorization) ParseRefreshToken(ctx context.Context, token string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseRefreshToken", ctx, token)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParseRefreshToken indicates an expected call of ParseRefreshToken.
+func (mr *MockAuthorizationMockRecorder) ParseRefreshToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseRefreshToken", reflect.TypeOf((*MockAuthorization)(nil).ParseRefreshToken), ctx, token)
+}
+
+// ParseToken mocks base method.
+func (m *MockAuthorization) ParseToken(token string) (model.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseToken", token)
+	ret0, _ := ret[0].(model.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParseToken indicates an expected call of ParseToken.
+func (mr *MockAuthorizationMockRecorder) ParseToken(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseToken", reflect.TypeOf((*MockAuthorization)(nil).ParseToken), token)
+}
+
+// PublicKeys mocks base method.
+func (m *MockAuthorization) PublicKeys() jwks.JWKSet {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublicKeys")
+	ret0, _ := ret[0].(jwks.JWKSet)
+	return ret0
+}
+
+// PublicKeys indicates an expected call of PublicKeys.
+func (mr *MockAuthorizationMockRecorder) PublicKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublicKeys", reflect.TypeOf((*MockAuthorization)(nil).PublicKeys))
+}
+
+// SaveRefreshToken mocks base method.
+func (m *MockAuthorization) SaveRefreshToken(ctx context.Context, userID string, details *service.TokenDetails) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRefreshToken", ctx, userID, details)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveRefreshToken indicates an expected call of SaveRefreshToken.
+func (mr *MockAuthorizationMockRecorder) SaveRefreshToken(ctx, userID, details interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRefreshToken", reflect.TypeOf((*MockAuthorization)(nil).SaveRefreshToken), ctx, userID, details)
+}
+
+// MockUsers is a mock of Users interface.
+type MockUsers struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsersMockRecorder
+}
+
+// MockUsersMockRecorder is the mock recorder for MockUsers.
+type MockUsersMockRecorder struct {
+	mock *MockUsers
+}
+
+// NewMockUsers creates a new mock instance.
+func NewMockUsers(ctrl *gomock.Controller) *MockUsers {
+	mock := &MockUsers{ctrl: ctrl}
+	mock.recorder = &MockUsersMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsers) EXPECT() *MockUsersMockRecorder {
+	return m.recorder
+}
+
+// CreateUser mocks base method.
+func (m *MockUsers) CreateUser(ctx context.Context, dto dto.CreateUser) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockUsersMockRecorder) CreateUser(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUsers)(nil).CreateUser), ctx, dto)
+}
+
+// DeleteUser mocks base method.
+func (m *MockUsers) DeleteUser(ctx context.Context, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockUsersMockRecorder) DeleteUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUsers)(nil).DeleteUser), ctx, userID)
+}
+
+// GetAllUsers mocks base method.
+func (m *MockUsers) GetAllUsers(userContext context.Context) ([]model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllUsers", userContext)
+	ret0, _ := ret[0].([]model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllUsers indicates an expected call of GetAllUsers.
+func (mr *MockUsersMockRecorder) GetAllUsers(userContext interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUsers", reflect.TypeOf((*MockUsers)(nil).GetAllUsers), userContext)
+}
+
+// GetUserByID mocks base method.
+func (m *MockUsers) GetUserByID(ctx context.Context, userID string) (model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", ctx, userID)
+	ret0, _ := ret[0].(model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockUsersMockRecorder) GetUserByID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUsers)(nil).GetUserByID), ctx, userID)
+}
+
+// UpdateUser mocks base method.
+func (m *MockUsers) UpdateUser(ctx context.Context, userID string, dto dto.UpdateUser) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", ctx, userID, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUser indicates an expected call of UpdateUser.
+func (mr *MockUsersMockRecorder) UpdateUser(ctx, userID, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUsers)(nil).UpdateUser), ctx, userID, dto)
+}
+
+// UploadUserImage mocks base method.
+func (m *MockUsers) UploadUserImage(ctx context.Context, userID string, file *multipart.FileHeader) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadUserImage", ctx, userID, file)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UploadUserImage indicates an expected call of UploadUserImage.
+func (mr *MockUsersMockRecorder) UploadUserImage(ctx, userID, file interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadUserImage", reflect.TypeOf((*MockUsers)(nil).UploadUserImage), ctx, userID, file)
+}
+
+// MockRoles is a mock of Roles interface.
+type MockRoles struct {
+	ctrl     *gomock.Controller
+	recorder *MockRolesMockRecorder
+}
+
+// MockRolesMockRecorder is the mock recorder for MockRoles.
+type MockRolesMockRecorder struct {
+	mock *MockRoles
+}
+
+// NewMockRoles creates a new mock instance.
+func NewMockRoles(ctrl *gomock.Controller) *MockRoles {
+	mock := &MockRoles{ctrl: ctrl}
+	mock.recorder = &MockRolesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoles) EXPECT() *MockRolesMockRecorder {
+	return m.recorder
+}
+
+// CreateRole mocks base method.
+func (m *MockRoles) CreateRole(ctx context.Context, userID, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRole", ctx, userID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRole indicates an expected call of CreateRole.
+func (mr *MockRolesMockRecorder) CreateRole(ctx, userID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRole", reflect.TypeOf((*MockRoles)(nil).CreateRole), ctx, userID, name)
+}
+
+// DeleteRole mocks base method.
+func (m *MockRoles) DeleteRole(ctx context.Context, userID, roleID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRole", ctx, userID, roleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRole indicates an expected call of DeleteRole.
+func (mr *MockRolesMockRecorder) DeleteRole(ctx, userID, roleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRole", reflect.TypeOf((*MockRoles)(nil).DeleteRole), ctx, userID, roleID)
+}
+
+// DeleteUserRole mocks base method.
+func (m *MockRoles) DeleteUserRole(ctx context.Context, userID, roleID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserRole", ctx, userID, roleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserRole indicates an expected call of DeleteUserRole.
+func (mr *MockRolesMockRecorder) DeleteUserRole(ctx, userID, roleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserRole", reflect.TypeOf((*MockRoles)(nil).DeleteUserRole), ctx, userID, roleID)
+}
+
+// GetUserRoles mocks base method.
+func (m *MockRoles) GetUserRoles(ctx context.Context, userID string) ([]model.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRoles", ctx, userID)
+	ret0, _ := ret[0].([]model.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRoles indicates an expected call of GetUserRoles.
+func (mr *MockRolesMockRecorder) GetUserRoles(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRoles", reflect.TypeOf((*MockRoles)(nil).GetUserRoles), ctx, userID)
+}
+
+// SaveUserRole mocks base method.
+func (m *MockRoles) SaveUserRole(ctx context.Context, userID, roleID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveUserRole", ctx, userID, roleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveUserRole indicates an expected call of SaveUserRole.
+func (mr *MockRolesMockRecorder) SaveUserRole(ctx, userID, roleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUserRole", reflect.TypeOf((*MockRoles)(nil).SaveUserRole), ctx, userID, roleID)
+}
+
+// UpdateRole mocks base method.
+func (m *MockRoles) UpdateRole(ctx context.Context, userID, roleID, newName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", ctx, userID, roleID, newName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockRolesMockRecorder) UpdateRole(ctx, userID, roleID, newName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockRoles)(nil).UpdateRole), ctx, userID, roleID, newName)
+}
+
+// MockArticles is a mock of Articles interface.
+type MockArticles struct {
+	ctrl     *gomock.Controller
+	recorder *MockArticlesMockRecorder
+}
+
+// MockArticlesMockRecorder is the mock recorder for MockArticles.
+type MockArticlesMockRecorder struct {
+	mock *MockArticles
+}
+
+// NewMockArticles creates a new mock instance.
+func NewMockArticles(ctrl *gomock.Controller) *MockArticles {
+	mock := &MockArticles{ctrl: ctrl}
+	mock.recorder = &MockArticlesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArticles) EXPECT() *MockArticlesMockRecorder {
+	return m.recorder
+}
+
+// CommentByID mocks base method.
+func (m *MockArticles) CommentByID(ctx context.Context, articleID, userID string, comment dto.CreateComment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommentByID", ctx, articleID, userID, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CommentByID indicates an expected call of CommentByID.
+func (mr *MockArticlesMockRecorder) CommentByID(ctx, articleID, userID, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommentByID", reflect.TypeOf((*MockArticles)(nil).CommentByID), ctx, articleID, userID, comment)
+}
+
+// Create mocks base method.
+func (m *MockArticles) Create(ctx context.Context, userID string, dto dto.CreateArticle) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockArticlesMockRecorder) Create(ctx, userID, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockArticles)(nil).Create), ctx, userID, dto)
+}
+
+// Delete mocks base method.
+func (m *MockArticles) Delete(ctx context.Context, userID, projectId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, projectId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockArticlesMockRecorder) Delete(ctx, userID, projectId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockArticles)(nil).Delete), ctx, userID, projectId)
+}
+
+// DownvoteCommentByArticleIDAndCommentID mocks base method.
+func (m *MockArticles) DownvoteCommentByArticleIDAndCommentID(userContext context.Context, articleID, commentID, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownvoteCommentByArticleIDAndCommentID", userContext, articleID, commentID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DownvoteCommentByArticleIDAndCommentID indicates an expected call of DownvoteCommentByArticleIDAndCommentID.
+func (mr *MockArticlesMockRecorder) DownvoteCommentByArticleIDAndCommentID(userContext, articleID, commentID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownvoteCommentByArticleIDAndCommentID", reflect.TypeOf((*MockArticles)(nil).DownvoteCommentByArticleIDAndCommentID), userContext, articleID, commentID, userID)
+}
+
+// GetAll mocks base method.
+func (m *MockArticles) GetAll(ctx context.Context) ([]model.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]model.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockArticlesMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockArticles)(nil).GetAll), ctx)
+}
+
+// GetAllByUserID mocks base method.
+func (m *MockArticles) GetAllByUserID(ctx context.Context, userID string) ([]model.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByUserID", ctx, userID)
+	ret0, _ := ret[0].([]model.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllByUserID indicates an expected call of GetAllByUserID.
+func (mr *MockArticlesMockRecorder) GetAllByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByUserID", reflect.TypeOf((*MockArticles)(nil).GetAllByUserID), ctx, userID)
+}
+
+// GetByID mocks base method.
+func (m *MockArticles) GetByID(ctx context.Context, articleID string) (model.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, articleID)
+	ret0, _ := ret[0].(model.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockArticlesMockRecorder) GetByID(ctx, articleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockArticles)(nil).GetByID), ctx, articleID)
+}
+
+// GetCommentsByArticleID mocks base method.
+func (m *MockArticles) GetCommentsByArticleID(ctx context.Context, articleID string) ([]model.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommentsByArticleID", ctx, articleID)
+	ret0, _ := ret[0].([]model.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommentsByArticleID indicates an expected call of GetCommentsByArticleID.
+func (mr *MockArticlesMockRecorder) GetCommentsByArticleID(ctx, articleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommentsByArticleID", reflect.TypeOf((*MockArticles)(nil).GetCommentsByArticleID), ctx, articleID)
+}
+
+// GetRepliesByArticleIDAndCommentID mocks base method.
+func (m *MockArticles) GetRepliesByArticleIDAndCommentID(ctx context.Context, articleID, commentID string) ([]model.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRepliesByArticleIDAndCommentID", ctx, articleID, commentID)
+	ret0, _ := ret[0].([]model.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRepliesByArticleIDAndCommentID indicates an expected call of GetRepliesByArticleIDAndCommentID.
+func (mr *MockArticlesMockRecorder) GetRepliesByArticleIDAndCommentID(ctx, articleID, commentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRepliesByArticleIDAndCommentID", reflect.TypeOf((*MockArticles)(nil).GetRepliesByArticleIDAndCommentID), ctx, articleID, commentID)
+}
+
+// GetVotesByArticleIDAndCommentID mocks base method.
+func (m *MockArticles) GetVotesByArticleIDAndCommentID(userContext context.Context, articleID, commentID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVotesByArticleIDAndCommentID", userContext, articleID, commentID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVotesByArticleIDAndCommentID indicates an expected call of GetVotesByArticleIDAndCommentID.
+func (mr *MockArticlesMockRecorder) GetVotesByArticleIDAndCommentID(userContext, articleID, commentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVotesByArticleIDAndCommentID", reflect.TypeOf((*MockArticles)(nil).GetVotesByArticleIDAndCommentID), userContext, articleID, commentID)
+}
+
+// ReplyToCommentByArticleIDAndCommentID mocks base method.
+func (m *MockArticles) ReplyToCommentByArticleIDAndCommentID(ctx context.Context, articleID, userID, parentCommentID string, comment dto.ReplyToComment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplyToCommentByArticleIDAndCommentID", ctx, articleID, userID, parentCommentID, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplyToCommentByArticleIDAndCommentID indicates an expected call of ReplyToCommentByArticleIDAndCommentID.
+func (mr *MockArticlesMockRecorder) ReplyToCommentByArticleIDAndCommentID(ctx, articleID, userID, parentCommentID, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplyToCommentByArticleIDAndCommentID", reflect.TypeOf((*MockArticles)(nil).ReplyToCommentByArticleIDAndCommentID), ctx, articleID, userID, parentCommentID, comment)
+}
+
+// Update mocks base method.
+func (m *MockArticles) Update(ctx context.Context, articleID, userID string, article dto.UpdateArticle) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, articleID, userID, article)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockArticlesMockRecorder) Update(ctx, articleID, userID, article interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockArticles)(nil).Update), ctx, articleID, userID, article)
+}
+
+// UpvoteCommentByArticleIDAndCommentID mocks base method.
+func (m *MockArticles) UpvoteCommentByArticleIDAndCommentID(userContext context.Context, articleID, commentID, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpvoteCommentByArticleIDAndCommentID", userContext, articleID, commentID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpvoteCommentByArticleIDAndCommentID indicates an expected call of UpvoteCommentByArticleIDAndCommentID.
+func (mr *MockArticlesMockRecorder) UpvoteCommentByArticleIDAndCommentID(userContext, articleID, commentID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpvoteCommentByArticleIDAndCommentID", reflect.TypeOf((*MockArticles)(nil).UpvoteCommentByArticleIDAndCommentID), userContext, articleID, commentID, userID)
+}
+
+// MockMaterials is a mock of Materials interface.
+type MockMaterials struct {
+	ctrl     *gomock.Controller
+	recorder *MockMaterialsMockRecorder
+}
+
+// MockMaterialsMockRecorder is the mock recorder for MockMaterials.
+type MockMaterialsMockRecorder struct {
+	mock *MockMaterials
+}
+
+// NewMockMaterials creates a new mock instance.
+func NewMockMaterials(ctrl *gomock.Controller) *MockMaterials {
+	mock := &MockMaterials{ctrl: ctrl}
+	mock.recorder = &MockMaterialsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMaterials) EXPECT() *MockMaterialsMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockMaterials) Create(ctx context.Context, userID string, dto dto.CreateMaterial) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockMaterialsMockRecorder) Create(ctx, userID, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockMaterials)(nil).Create), ctx, userID, dto)
+}
+
+// Delete mocks base method.
+func (m *MockMaterials) Delete(ctx context.Context, userID, materialID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, materialID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockMaterialsMockRecorder) Delete(ctx, userID, materialID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockMaterials)(nil).Delete), ctx, userID, materialID)
+}
+
+// GetAll mocks base method.
+func (m *MockMaterials) GetAll(ctx context.Context) ([]model.Material, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]model.Material)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockMaterialsMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockMaterials)(nil).GetAll), ctx)
+}
+
+// GetAllByUserID mocks base method.
+func (m *MockMaterials) GetAllByUserID(ctx context.Context, userID string) ([]model.Material, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByUserID", ctx, userID)
+	ret0, _ := ret[0].([]model.Material)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllByUserID indicates an expected call of GetAllByUserID.
+func (mr *MockMaterialsMockRecorder) GetAllByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByUserID", reflect.TypeOf((*MockMaterials)(nil).GetAllByUserID), ctx, userID)
+}
+
+// GetByID mocks base method.
+func (m *MockMaterials) GetByID(ctx context.Context, materialID string) (model.Material, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, materialID)
+	ret0, _ := ret[0].(model.Material)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockMaterialsMockRecorder) GetByID(ctx, materialID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockMaterials)(nil).GetByID), ctx, materialID)
+}
+
+// Update mocks base method.
+func (m *MockMaterials) Update(ctx context.Context, materialID, userID string, material dto.UpdateMaterial) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, materialID, userID, material)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockMaterialsMockRecorder) Update(ctx, materialID, userID, material interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockMaterials)(nil).Update), ctx, materialID, userID, material)
+}
+
+// MockCards is a mock of Cards interface.
+type MockCards struct {
+	ctrl     *gomock.Controller
+	recorder *MockCardsMockRecorder
+}
+
+// MockCardsMockRecorder is the mock recorder for MockCards.
+type MockCardsMockRecorder struct {
+	mock *MockCards
+}
+
+// NewMockCards creates a new mock instance.
+func NewMockCards(ctrl *gomock.Controller) *MockCards {
+	mock := &MockCards{ctrl: ctrl}
+	mock.recorder = &MockCardsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCards) EXPECT() *MockCardsMockRecorder {
+	return m.recorder
+}
+
+// AcceptInvitation mocks base method.
+func (m *MockCards) AcceptInvitation(ctx context.Context, userID, cardID, invitationID string, input dto.AnswerInvitation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptInvitation", ctx, userID, cardID, invitationID, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AcceptInvitation indicates an expected call of AcceptInvitation.
+func (mr *MockCardsMockRecorder) AcceptInvitation(ctx, userID, cardID, invitationID, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptInvitation", reflect.TypeOf((*MockCards)(nil).AcceptInvitation), ctx, userID, cardID, invitationID, input)
+}
+
+// Create mocks base method.
+func (m *MockCards) Create(ctx context.Context, userID string, dto dto.CreateCard) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCardsMockRecorder) Create(ctx, userID, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCards)(nil).Create), ctx, userID, dto)
+}
+
+// CreateInvitation mocks base method.
+func (m *MockCards) CreateInvitation(ctx context.Context, userID string, cardID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInvitation", ctx, userID, cardID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateInvitation indicates an expected call of CreateInvitation.
+func (mr *MockCardsMockRecorder) CreateInvitation(ctx, userID, cardID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInvitation", reflect.TypeOf((*MockCards)(nil).CreateInvitation), ctx, userID, cardID)
+}
+
+// DeclineInvitation mocks base method.
+func (m *MockCards) DeclineInvitation(ctx context.Context, userID, cardID, invitationID string, input dto.AnswerInvitation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeclineInvitation", ctx, userID, cardID, invitationID, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeclineInvitation indicates an expected call of DeclineInvitation.
+func (mr *MockCardsMockRecorder) DeclineInvitation(ctx, userID, cardID, invitationID, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclineInvitation", reflect.TypeOf((*MockCards)(nil).DeclineInvitation), ctx, userID, cardID, invitationID, input)
+}
+
+// Delete mocks base method.
+func (m *MockCards) Delete(ctx context.Context, userID string, cardID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, cardID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCardsMockRecorder) Delete(ctx, userID, cardID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCards)(nil).Delete), ctx, userID, cardID)
+}
+
+// GetAll mocks base method.
+func (m *MockCards) GetAll(ctx context.Context) ([]model.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]model.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockCardsMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockCards)(nil).GetAll), ctx)
+}
+
+// GetAllByUserID mocks base method.
+func (m *MockCards) GetAllByUserID(ctx context.Context, userID string) (*[]model.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByUserID", ctx, userID)
+	ret0, _ := ret[0].(*[]model.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllByUserID indicates an expected call of GetAllByUserID.
+func (mr *MockCardsMockRecorder) GetAllByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByUserID", reflect.TypeOf((*MockCards)(nil).GetAllByUserID), ctx, userID)
+}
+
+// GetByID mocks base method.
+func (m *MockCards) GetByID(ctx context.Context, cardID int) (*model.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, cardID)
+	ret0, _ := ret[0].(*model.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockCardsMockRecorder) GetByID(ctx, cardID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCards)(nil).GetByID), ctx, cardID)
+}
+
+// GetInvitationByID mocks base method.
+func (m *MockCards) GetInvitationByID(ctx context.Context, userID, cardID, invitationID string) (model.InvitationCard, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInvitationByID", ctx, userID, cardID, invitationID)
+	ret0, _ := ret[0].(model.InvitationCard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInvitationByID indicates an expected call of GetInvitationByID.
+func (mr *MockCardsMockRecorder) GetInvitationByID(ctx, userID, cardID, invitationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvitationByID", reflect.TypeOf((*MockCards)(nil).GetInvitationByID), ctx, userID, cardID, invitationID)
+}
+
+// GetInvitationsByCardID mocks base method.
+func (m *MockCards) GetInvitationsByCardID(ctx context.Context, userID, cardID string) ([]model.InvitationCard, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInvitationsByCardID", ctx, userID, cardID)
+	ret0, _ := ret[0].([]model.InvitationCard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInvitationsByCardID indicates an expected call of GetInvitationsByCardID.
+func (mr *MockCardsMockRecorder) GetInvitationsByCardID(ctx, userID, cardID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvitationsByCardID", reflect.TypeOf((*MockCards)(nil).GetInvitationsByCardID), ctx, userID, cardID)
+}
+
+// GetInvitationsByUserID mocks base method.
+func (m *MockCards) GetInvitationsByUserID(ctx context.Context, userID string) ([]model.InvitationCard, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInvitationsByUserID", ctx, userID)
+	ret0, _ := ret[0].([]model.InvitationCard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInvitationsByUserID indicates an expected call of GetInvitationsByUserID.
+func (mr *MockCardsMockRecorder) GetInvitationsByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvitationsByUserID", reflect.TypeOf((*MockCards)(nil).GetInvitationsByUserID), ctx, userID)
+}
+
+// GetResponsesByUserID mocks base method.
+func (m *MockCards) GetResponsesByUserID(ctx context.Context, userID string) ([]model.InvitationCard, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResponsesByUserID", ctx, userID)
+	ret0, _ := ret[0].([]model.InvitationCard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResponsesByUserID indicates an expected call of GetResponsesByUserID.
+func (mr *MockCardsMockRecorder) GetResponsesByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResponsesByUserID", reflect.TypeOf((*MockCards)(nil).GetResponsesByUserID), ctx, userID)
+}
+
+// Update mocks base method.
+func (m *MockCards) Update(ctx context.Context, userID string, cardID int, dto dto.UpdateCard) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userID, cardID, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCardsMockRecorder) Update(ctx, userID, cardID, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCards)(nil).Update), ctx, userID, cardID, dto)
+}
+
+// MockContests is a mock of Contests interface.
+type MockContests struct {
+	ctrl     *gomock.Controller
+	recorder *MockContestsMockRecorder
+}
+
+// MockContestsMockRecorder is the mock recorder for MockContests.
+type MockContestsMockRecorder struct {
+	mock *MockContests
+}
+
+// NewMockContests creates a new mock instance.
+func NewMockContests(ctrl *gomock.Controller) *MockContests {
+	mock := &MockContests{ctrl: ctrl}
+	mock.recorder = &MockContestsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContests) EXPECT() *MockContestsMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockContests) Create(ctx context.Context, contest dto.CreateContest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, contest)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockContestsMockRecorder) Create(ctx, contest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockContests)(nil).Create), ctx, contest)
+}
+
+// Delete mocks base method.
+func (m *MockContests) Delete(ctx context.Context, contestID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, contestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockContestsMockRecorder) Delete(ctx, contestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockContests)(nil).Delete), ctx, contestID)
+}
+
+// GetAll mocks base method.
+func (m *MockContests) GetAll(ctx context.Context) ([]model.Contest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]model.Contest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockContestsMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockContests)(nil).GetAll), ctx)
+}
+
+// GetByID mocks base method.
+func (m *MockContests) GetByID(ctx context.Context, contestID string) (model.Contest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, contestID)
+	ret0, _ := ret[0].(model.Contest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockContestsMockRecorder) GetByID(ctx, contestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockContests)(nil).GetByID), ctx, contestID)
+}
+
+// Update mocks base method.
+func (m *MockContests) Update(ctx context.Context, contestID string, contest dto.UpdateContest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, contestID, contest)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockContestsMockRecorder) Update(ctx, contestID, contest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockContests)(nil).Update), ctx, contestID, contest)
+}
+
+// MockDisciplines is a mock of Disciplines interface.
+type MockDisciplines struct {
+	ctrl     *gomock.Controller
+	recorder *MockDisciplinesMockRecorder
+}
+
+// MockDisciplinesMockRecorder is the mock recorder for MockDisciplines.
+type MockDisciplinesMockRecorder struct {
+	mock *MockDisciplines
+}
+
+// NewMockDisciplines creates a new mock instance.
+func NewMockDisciplines(ctrl *gomock.Controller) *MockDisciplines {
+	mock := &MockDisciplines{ctrl: ctrl}
+	mock.recorder = &MockDisciplinesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDisciplines) EXPECT() *MockDisciplinesMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDisciplines) Create(ctx context.Context, discipline dto.CreateDiscipline) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, discipline)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDisciplinesMockRecorder) Create(ctx, discipline interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDisciplines)(nil).Create), ctx, discipline)
+}
+
+// Delete mocks base method.
+func (m *MockDisciplines) Delete(ctx context.Context, disciplineID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, disciplineID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockDisciplinesMockRecorder) Delete(ctx, disciplineID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDisciplines)(nil).Delete), ctx, disciplineID)
+}
+
+// GetAll mocks base method.
+func (m *MockDisciplines) GetAll(ctx context.Context) ([]model.Discipline, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]model.Discipline)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockDisciplinesMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockDisciplines)(nil).GetAll), ctx)
+}
+
+// GetByID mocks base method.
+func (m *MockDisciplines) GetByID(ctx context.Context, disciplineID int) (model.Discipline, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, disciplineID)
+	ret0, _ := ret[0].(model.Discipline)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockDisciplinesMockRecorder) GetByID(ctx, disciplineID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockDisciplines)(nil).GetByID), ctx, disciplineID)
+}
+
+// Update mocks base method.
+func (m *MockDisciplines) Update(ctx context.Context, disciplineID int, discipline dto.UpdateDiscipline) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, disciplineID, discipline)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDisciplinesMockRecorder) Update(ctx, disciplineID, discipline interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDisciplines)(nil).Update), ctx, disciplineID, discipline)
+}
+
+// MockProjects is a mock of Projects interface.
+type MockProjects struct {
+	ctrl     *gomock.Controller
+	recorder *MockProjectsMockRecorder
+}
+
+// MockProjectsMockRecorder is the mock recorder for MockProjects.
+type MockProjectsMockRecorder struct {
+	mock *MockProjects
+}
+
+// NewMockProjects creates a new mock instance.
+func NewMockProjects(ctrl *gomock.Controller) *MockProjects {
+	mock := &MockProjects{ctrl: ctrl}
+	mock.recorder = &MockProjectsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProjects) EXPECT() *MockProjectsMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockProjects) Create(ctx context.Context, disciplineID int, project dto.CreateProject) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, disciplineID, project)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockProjectsMockRecorder) Create(ctx, disciplineID, project interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProjects)(nil).Create), ctx, disciplineID, project)
+}
+
+// Delete mocks base method.
+func (m *MockProjects) Delete(ctx context.Context, disciplineID, projectID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, disciplineID, projectID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockProjectsMockRecorder) Delete(ctx, disciplineID, projectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProjects)(nil).Delete), ctx, disciplineID, projectID)
+}
+
+// GetAll mocks base method.
+func (m *MockProjects) GetAll(ctx context.Context) ([]model.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]model.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockProjectsMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockProjects)(nil).GetAll), ctx)
+}
+
+// GetAllByDisciplineID mocks base method.
+func (m *MockProjects) GetAllByDisciplineID(ctx context.Context, disciplineID int) ([]model.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByDisciplineID", ctx, disciplineID)
+	ret0, _ := ret[0].([]model.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllByDisciplineID indicates an expected call of GetAllByDisciplineID.
+func (mr *MockProjectsMockRecorder) GetAllByDisciplineID(ctx, disciplineID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByDisciplineID", reflect.TypeOf((*MockProjects)(nil).GetAllByDisciplineID), ctx, disciplineID)
+}
+
+// GetByID mocks base method.
+func (m *MockProjects) GetByID(ctx context.Context, disciplineID, projectID int) (model.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, disciplineID, projectID)
+	ret0, _ := ret[0].(model.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockProjectsMockRecorder) GetByID(ctx, disciplineID, projectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockProjects)(nil).GetByID), ctx, disciplineID, projectID)
+}
+
+// Update mocks base method.
+func (m *MockProjects) Update(ctx context.Context, disciplineID, projectID int, project dto.UpdateProject) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, disciplineID, projectID, project)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockProjectsMockRecorder) Update(ctx, disciplineID, projectID, project interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockProjects)(nil).Update), ctx, disciplineID, projectID, project)
+}
+
+// MockProjectModules is a mock of ProjectModules interface.
+type MockProjectModules struct {
+	ctrl     *gomock.Controller
+	recorder *MockProjectModulesMockRecorder
+}
+
+// MockProjectModulesMockRecorder is the mock recorder for MockProjectModules.
+type MockProjectModulesMockRecorder struct {
+	mock *MockProjectModules
+}
+
+// NewMockProjectModules creates a new mock instance.
+func NewMockProjectModules(ctrl *gomock.Controller) *MockProjectModules {
+	mock := &MockProjectModules{ctrl: ctrl}
+	mock.recorder = &MockProjectModulesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProjectModules) EXPECT() *MockProjectModulesMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockProjectModules) Create(ctx context.Context, projectID int, module dto.CreateProjectModule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, projectID, module)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockProjectModulesMockRecorder) Create(ctx, projectID, module interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProjectModules)(nil).Create), ctx, projectID, module)
+}
+
+// Delete mocks base method.
+func (m *MockProjectModules) Delete(ctx context.Context, projectID, moduleID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, projectID, moduleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockProjectModulesMockRecorder) Delete(ctx, projectID, moduleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProjectModules)(nil).Delete), ctx, projectID, moduleID)
+}
+
+// GetAll mocks base method.
+func (m *MockProjectModules) GetAll(ctx context.Context, projectID int) ([]model.ProjectModule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, projectID)
+	ret0, _ := ret[0].([]model.ProjectModule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockProjectModulesMockRecorder) GetAll(ctx, projectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockProjectModules)(nil).GetAll), ctx, projectID)
+}
+
+// GetByID mocks base method.
+func (m *MockProjectModules) GetByID(ctx context.Context, moduleID int) (model.ProjectModule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, moduleID)
+	ret0, _ := ret[0].(model.ProjectModule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockProjectModulesMockRecorder) GetByID(ctx, moduleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockProjectModules)(nil).GetByID), ctx, moduleID)
+}
+
+// Update mocks base method.
+func (m *MockProjectModules) Update(ctx context.Context, projectID, moduleID int, module dto.UpdateProjectModule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, projectID, moduleID, module)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockProjectModulesMockRecorder) Update(ctx, projectID, moduleID, module interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockProjectModules)(nil).Update), ctx, projectID, moduleID, module)
+}
+
+// MockCourses is a mock of Courses interface.
+type MockCourses struct {
+	ctrl     *gomock.Controller
+	recorder *MockCoursesMockRecorder
+}
+
+// MockCoursesMockRecorder is the mock recorder for MockCourses.
+type MockCoursesMockRecorder struct {
+	mock *MockCourses
+}
+
+// NewMockCourses creates a new mock instance.
+func NewMockCourses(ctrl *gomock.Controller) *MockCourses {
+	mock := &MockCourses{ctrl: ctrl}
+	mock.recorder = &MockCoursesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCourses) EXPECT() *MockCoursesMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCourses) Create(ctx context.Context, course dto.CreateCourse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, course)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCoursesMockRecorder) Create(ctx, course interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCourses)(nil).Create), ctx, course)
+}
+
+// Delete mocks base method.
+func (m *MockCourses) Delete(ctx context.Context, courseID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, courseID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCoursesMockRecorder) Delete(ctx, courseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCourses)(nil).Delete), ctx, courseID)
+}
+
+// GetAll mocks base method.
+func (m *MockCourses) GetAll(ctx context.Context) ([]model.Course, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]model.Course)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockCoursesMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockCourses)(nil).GetAll), ctx)
+}
+
+// GetByID mocks base method.
+func (m *MockCourses) GetByID(ctx context.Context, courseID int) (model.Course, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, courseID)
+	ret0, _ := ret[0].(model.Course)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockCoursesMockRecorder) GetByID(ctx, courseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCourses)(nil).GetByID), ctx, courseID)
+}
+
+// Update mocks base method.
+func (m *MockCourses) Update(ctx context.Context, courseID int, course dto.UpdateCourse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, courseID, course)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCoursesMockRecorder) Update(ctx, courseID, course interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCourses)(nil).Update), ctx, courseID, course)
+}
+
+// MockCourseModules is a mock of CourseModules interface.
+type MockCourseModules struct {
+	ctrl     *gomock.Controller
+	recorder *MockCourseModulesMockRecorder
+}
+
+// MockCourseModulesMockRecorder is the mock recorder for MockCourseModules.
+type MockCourseModulesMockRecorder struct {
+	mock *MockCourseModules
+}
+
+// NewMockCourseModules creates a new mock instance.
+func NewMockCourseModules(ctrl *gomock.Controller) *MockCourseModules {
+	mock := &MockCourseModules{ctrl: ctrl}
+	mock.recorder = &MockCourseModulesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCourseModules) EXPECT() *MockCourseModulesMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCourseModules) Create(ctx context.Context, courseID int, module dto.CreateCourseModule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, courseID, module)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCourseModulesMockRecorder) Create(ctx, courseID, module interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCourseModules)(nil).Create), ctx, courseID, module)
+}
+
+// Delete mocks base method.
+func (m *MockCourseModules) Delete(ctx context.Context, courseID, moduleID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, courseID, moduleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCourseModulesMockRecorder) Delete(ctx, courseID, moduleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCourseModules)(nil).Delete), ctx, courseID, moduleID)
+}
+
+// GetAll mocks base method.
+func (m *MockCourseModules) GetAll(ctx context.Context, courseID int) ([]model.CourseModule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, courseID)
+	ret0, _ := ret[0].([]model.CourseModule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockCourseModulesMockRecorder) GetAll(ctx, courseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockCourseModules)(nil).GetAll), ctx, courseID)
+}
+
+// GetByID mocks base method.
+func (m *MockCourseModules) GetByID(ctx context.Context, moduleID int) (model.CourseModule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, moduleID)
+	ret0, _ := ret[0].(model.CourseModule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockCourseModulesMockRecorder) GetByID(ctx, moduleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCourseModules)(nil).GetByID), ctx, moduleID)
+}
+
+// Update mocks base method.
+func (m *MockCourseModules) Update(ctx context.Context, courseID, moduleID int, module dto.UpdateCourseModule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, courseID, moduleID, module)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCourseModulesMockRecorder) Update(ctx, courseID, moduleID, module interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCourseModules)(nil).Update), ctx, courseID, moduleID, module)
+}
+
+// MockModuleLessons is a mock of ModuleLessons interface.
+type MockModuleLessons struct {
+	ctrl     *gomock.Controller
+	recorder *MockModuleLessonsMockRecorder
+}
+
+// MockModuleLessonsMockRecorder is the mock recorder for MockModuleLessons.
+type MockModuleLessonsMockRecorder struct {
+	mock *MockModuleLessons
+}
+
+// NewMockModuleLessons creates a new mock instance.
+func NewMockModuleLessons(ctrl *gomock.Controller) *MockModuleLessons {
+	mock := &MockModuleLessons{ctrl: ctrl}
+	mock.recorder = &MockModuleLessonsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockModuleLessons) EXPECT() *MockModuleLessonsMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockModuleLessons) Create(ctx context.Context, moduleID int, lesson dto.CreateCourseModuleLesson) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, moduleID, lesson)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockModuleLessonsMockRecorder) Create(ctx, moduleID, lesson interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockModuleLessons)(nil).Create), ctx, moduleID, lesson)
+}
+
+// Delete mocks base method.
+func (m *MockModuleLessons) Delete(ctx context.Context, moduleID, lessonID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, moduleID, lessonID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockModuleLessonsMockRecorder) Delete(ctx, moduleID, lessonID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockModuleLessons)(nil).Delete), ctx, moduleID, lessonID)
+}
+
+// GetAll mocks base method.
+func (m *MockModuleLessons) GetAll(ctx context.Context, moduleID int) ([]model.CourseModuleLesson, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, moduleID)
+	ret0, _ := ret[0].([]model.CourseModuleLesson)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockModuleLessonsMockRecorder) GetAll(ctx, moduleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockModuleLessons)(nil).GetAll), ctx, moduleID)
+}
+
+// GetByID mocks base method.
+func (m *MockModuleLessons) GetByID(ctx context.Context, lessonID int) (model.CourseModuleLesson, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, lessonID)
+	ret0, _ := ret[0].(model.CourseModuleLesson)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockModuleLessonsMockRecorder) GetByID(ctx, lessonID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockModuleLessons)(nil).GetByID), ctx, lessonID)
+}
+
+// Update mocks base method.
+func (m *MockModuleLessons) Update(ctx context.Context, moduleID, lessonID int, lesson dto.UpdateCourseModuleLesson) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, moduleID, lessonID, lesson)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockModuleLessonsMockRecorder) Update(ctx, moduleID, lessonID, lesson interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockModuleLessons)(nil).Update), ctx, moduleID, lessonID, lesson)
+}
+
+// MockLessonComments is a mock of LessonComments interface.
+type MockLessonComments struct {
+	ctrl     *gomock.Controller
+	recorder *MockLessonCommentsMockRecorder
+}
+
+// MockLessonCommentsMockRecorder is the mock recorder for MockLessonComments.
+type MockLessonCommentsMockRecorder struct {
+	mock *MockLessonComments
+}
+
+// NewMockLessonComments creates a new mock instance.
+func NewMockLessonComments(ctrl *gomock.Controller) *MockLessonComments {
+	mock := &MockLessonComments{ctrl: ctrl}
+	mock.recorder = &MockLessonCommentsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLessonComments) EXPECT() *MockLessonCommentsMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockLessonComments) Create(ctx context.Context, lessonID int, comment dto.CreateLessonComment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, lessonID, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockLessonCommentsMockRecorder) Create(ctx, lessonID, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockLessonComments)(nil).Create), ctx, lessonID, comment)
+}
+
+// Delete mocks base method.
+func (m *MockLessonComments) Delete(ctx context.Context, lessonID, commentID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, lessonID, commentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockLessonCommentsMockRecorder) Delete(ctx, lessonID, commentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockLessonComments)(nil).Delete), ctx, lessonID, commentID)
+}
+
+// GetAll mocks base method.
+func (m *MockLessonComments) GetAll(ctx context.Context, lessonID int) ([]model.CourseModuleLessonComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, lessonID)
+	ret0, _ := ret[0].([]model.CourseModuleLessonComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockLessonCommentsMockRecorder) GetAll(ctx, lessonID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockLessonComments)(nil).GetAll), ctx, lessonID)
+}
+
+// GetByID mocks base method.
+func (m *MockLessonComments) GetByID(ctx context.Context, commentID int) (model.CourseModuleLessonComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, commentID)
+	ret0, _ := ret[0].(model.CourseModuleLessonComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockLessonCommentsMockRecorder) GetByID(ctx, commentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockLessonComments)(nil).GetByID), ctx, commentID)
+}
+
+// Update mocks base method.
+func (m *MockLessonComments) Update(ctx context.Context, lessonID, commentID int, comment dto.UpdateLessonComment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, lessonID, commentID, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockLessonCommentsMockRecorder) Update(ctx, lessonID, commentID, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockLessonComments)(nil).Update), ctx, lessonID, commentID, comment)
+}
+
+// MockObjectStorage is a mock of ObjectStorage interface.
+type MockObjectStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockObjectStorageMockRecorder
+}
+
+// MockObjectStorageMockRecorder is the mock recorder for MockObjectStorage.
+type MockObjectStorageMockRecorder struct {
+	mock *MockObjectStorage
+}
+
+// NewMockObjectStorage creates a new mock instance.
+func NewMockObjectStorage(ctrl *gomock.Controller) *MockObjectStorage {
+	mock := &MockObjectStorage{ctrl: ctrl}
+	mock.recorder = &MockObjectStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockObjectStorage) EXPECT() *MockObjectStorageMockRecorder {
+	return m.recorder
+}
+
+// UploadFile mocks base method.
+func (m *MockObjectStorage) UploadFile(ctx context.Context, key string, file *multipart.FileHeader) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadFile", ctx, key, file)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UploadFile indicates an expected call of UploadFile.
+func (mr *MockObjectStorageMockRecorder) UploadFile(ctx, key, file interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadFile", reflect.TypeOf((*MockObjectStorage)(nil).UploadFile), ctx, key, file)
+}
+
+// MockStorageJanitor is a mock of StorageJanitor interface.
+type MockStorageJanitor struct {
+	ctrl     *gomock.Controller
+	recorder *MockStorageJanitorMockRecorder
+}
+
+// MockStorageJanitorMockRecorder is the mock recorder for MockStorageJanitor.
+type MockStorageJanitorMockRecorder struct {
+	mock *MockStorageJanitor
+}
+
+// NewMockStorageJanitor creates a new mock instance.
+func NewMockStorageJanitor(ctrl *gomock.Controller) *MockStorageJanitor {
+	mock := &MockStorageJanitor{ctrl: ctrl}
+	mock.recorder = &MockStorageJanitorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStorageJanitor) EXPECT() *MockStorageJanitorMockRecorder {
+	return m.recorder
+}
+
+// Clean mocks base method.
+func (m *MockStorageJanitor) Clean(ctx context.Context) (model.StorageCleanupReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clean", ctx)
+	ret0, _ := ret[0].(model.StorageCleanupReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Clean indicates an expected call of Clean.
+func (mr *MockStorageJanitorMockRecorder) Clean(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clean", reflect.TypeOf((*MockStorageJanitor)(nil).Clean), ctx)
+}
+
+// FindOrphans mocks base method.
+func (m *MockStorageJanitor) FindOrphans(ctx context.Context) ([]model.StoredObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrphans", ctx)
+	ret0, _ := ret[0].([]model.StoredObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrphans indicates an expected call of FindOrphans.
+func (mr *MockStorageJanitorMockRecorder) FindOrphans(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrphans", reflect.TypeOf((*MockStorageJanitor)(nil).FindOrphans), ctx)
+}
+
+// Run mocks base method.
+func (m *MockStorageJanitor) Run(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Run", ctx)
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockStorageJanitorMockRecorder) Run(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockStorageJanitor)(nil).Run), ctx)
+}
+
+// MockOutboxWorker is a mock of OutboxWorker interface.
+type MockOutboxWorker struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxWorkerMockRecorder
+}
+
+// MockOutboxWorkerMockRecorder is the mock recorder for MockOutboxWorker.
+type MockOutboxWorkerMockRecorder struct {
+	mock *MockOutboxWorker
+}
+
+// NewMockOutboxWorker creates a new mock instance.
+func NewMockOutboxWorker(ctrl *gomock.Controller) *MockOutboxWorker {
+	mock := &MockOutboxWorker{ctrl: ctrl}
+	mock.recorder = &MockOutboxWorkerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxWorker) EXPECT() *MockOutboxWorkerMockRecorder {
+	return m.recorder
+}
+
+// ProcessBatch mocks base method.
+func (m *MockOutboxWorker) ProcessBatch(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessBatch", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessBatch indicates an expected call of ProcessBatch.
+func (mr *MockOutboxWorkerMockRecorder) ProcessBatch(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessBatch", reflect.TypeOf((*MockOutboxWorker)(nil).ProcessBatch), ctx)
+}
+
+// Run mocks base method.
+func (m *MockOutboxWorker) Run(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Run", ctx)
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockOutboxWorkerMockRecorder) Run(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockOutboxWorker)(nil).Run), ctx)
+}
+
+// MockLockout is a mock of Lockout interface.
+type MockLockout struct {
+	ctrl     *gomock.Controller
+	recorder *MockLockoutMockRecorder
+}
+
+// MockLockoutMockRecorder is the mock recorder for MockLockout.
+type MockLockoutMockRecorder struct {
+	mock *MockLockout
+}
+
+// NewMockLockout creates a new mock instance.
+func NewMockLockout(ctrl *gomock.Controller) *MockLockout {
+	mock := &MockLockout{ctrl: ctrl}
+	mock.recorder = &MockLockoutMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLockout) EXPECT() *MockLockoutMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockLockout) Check(ctx context.Context, email, ip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, email, ip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockLockoutMockRecorder) Check(ctx, email, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockLockout)(nil).Check), ctx, email, ip)
+}
+
+// Fail mocks base method.
+func (m *MockLockout) Fail(ctx context.Context, email, ip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fail", ctx, email, ip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Fail indicates an expected call of Fail.
+func (mr *MockLockoutMockRecorder) Fail(ctx, email, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fail", reflect.TypeOf((*MockLockout)(nil).Fail), ctx, email, ip)
+}
+
+// Succeed mocks base method.
+func (m *MockLockout) Succeed(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Succeed", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Succeed indicates an expected call of Succeed.
+func (mr *MockLockoutMockRecorder) Succeed(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Succeed", reflect.TypeOf((*MockLockout)(nil).Succeed), ctx, email)
+}
+
+// Unlock mocks base method.
+func (m *MockLockout) Unlock(ctx context.Context, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockLockoutMockRecorder) Unlock(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockLockout)(nil).Unlock), ctx, userID)
+}
+
+// MockTwoFactor is a mock of TwoFactor interface.
+type MockTwoFactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTwoFactorMockRecorder
+}
+
+// MockTwoFactorMockRecorder is the mock recorder for MockTwoFactor.
+type MockTwoFactorMockRecorder struct {
+	mock *MockTwoFactor
+}
+
+// NewMockTwoFactor creates a new mock instance.
+func NewMockTwoFactor(ctrl *gomock.Controller) *MockTwoFactor {
+	mock := &MockTwoFactor{ctrl: ctrl}
+	mock.recorder = &MockTwoFactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTwoFactor) EXPECT() *MockTwoFactorMockRecorder {
+	return m.recorder
+}
+
+// ChallengeUserID mocks base method.
+func (m *MockTwoFactor) ChallengeUserID(ctx context.Context, token string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChallengeUserID", ctx, token)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChallengeUserID indicates an expected call of ChallengeUserID.
+func (mr *MockTwoFactorMockRecorder) ChallengeUserID(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChallengeUserID", reflect.TypeOf((*MockTwoFactor)(nil).ChallengeUserID), ctx, token)
+}
+
+// CompleteChallenge mocks base method.
+func (m *MockTwoFactor) CompleteChallenge(ctx context.Context, token, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteChallenge", ctx, token, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteChallenge indicates an expected call of CompleteChallenge.
+func (mr *MockTwoFactorMockRecorder) CompleteChallenge(ctx, token, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteChallenge", reflect.TypeOf((*MockTwoFactor)(nil).CompleteChallenge), ctx, token, code)
+}
+
+// Disable mocks base method.
+func (m *MockTwoFactor) Disable(ctx context.Context, userID, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Disable", ctx, userID, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Disable indicates an expected call of Disable.
+func (mr *MockTwoFactorMockRecorder) Disable(ctx, userID, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disable", reflect.TypeOf((*MockTwoFactor)(nil).Disable), ctx, userID, code)
+}
+
+// Enable mocks base method.
+func (m *MockTwoFactor) Enable(ctx context.Context, userID, code string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enable", ctx, userID, code)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Enable indicates an expected call of Enable.
+func (mr *MockTwoFactorMockRecorder) Enable(ctx, userID, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enable", reflect.TypeOf((*MockTwoFactor)(nil).Enable), ctx, userID, code)
+}
+
+// Enabled mocks base method.
+func (m *MockTwoFactor) Enabled(ctx context.Context, userID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enabled", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Enabled indicates an expected call of Enabled.
+func (mr *MockTwoFactorMockRecorder) Enabled(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enabled", reflect.TypeOf((*MockTwoFactor)(nil).Enabled), ctx, userID)
+}
+
+// Enroll mocks base method.
+func (m *MockTwoFactor) Enroll(ctx context.Context, userID string) (model.TOTPEnrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enroll", ctx, userID)
+	ret0, _ := ret[0].(model.TOTPEnrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Enroll indicates an expected call of Enroll.
+func (mr *MockTwoFactorMockRecorder) Enroll(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enroll", reflect.TypeOf((*MockTwoFactor)(nil).Enroll), ctx, userID)
+}
+
+// NewChallenge mocks base method.
+func (m *MockTwoFactor) NewChallenge(ctx context.Context, userID string) (model.MFAChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewChallenge", ctx, userID)
+	ret0, _ := ret[0].(model.MFAChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewChallenge indicates an expected call of NewChallenge.
+func (mr *MockTwoFactorMockRecorder) NewChallenge(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewChallenge", reflect.TypeOf((*MockTwoFactor)(nil).NewChallenge), ctx, userID)
+}
+
+// RegenerateRecoveryCodes mocks base method.
+func (m *MockTwoFactor) RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenerateRecoveryCodes", ctx, userID, code)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegenerateRecoveryCodes indicates an expected call of RegenerateRecoveryCodes.
+func (mr *MockTwoFactorMockRecorder) RegenerateRecoveryCodes(ctx, userID, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateRecoveryCodes", reflect.TypeOf((*MockTwoFactor)(nil).RegenerateRecoveryCodes), ctx, userID, code)
+}
+
+// RequireForRole mocks base method.
+func (m *MockTwoFactor) RequireForRole(role string, enabled bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequireForRole", role, enabled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequireForRole indicates an expected call of RequireForRole.
+func (mr *MockTwoFactorMockRecorder) RequireForRole(role, enabled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequireForRole", reflect.TypeOf((*MockTwoFactor)(nil).RequireForRole), role, enabled)
+}
+
+// MockSingleSignOn is a mock of SingleSignOn interface.
+type MockSingleSignOn struct {
+	ctrl     *gomock.Controller
+	recorder *MockSingleSignOnMockRecorder
+}
+
+// MockSingleSignOnMockRecorder is the mock recorder for MockSingleSignOn.
+type MockSingleSignOnMockRecorder struct {
+	mock *MockSingleSignOn
+}
+
+// NewMockSingleSignOn creates a new mock instance.
+func NewMockSingleSignOn(ctrl *gomock.Controller) *MockSingleSignOn {
+	mock := &MockSingleSignOn{ctrl: ctrl}
+	mock.recorder = &MockSingleSignOnMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSingleSignOn) EXPECT() *MockSingleSignOnMockRecorder {
+	return m.recorder
+}
+
+// AuthURL mocks base method.
+func (m *MockSingleSignOn) AuthURL(ctx context.Context) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthURL", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AuthURL indicates an expected call of AuthURL.
+func (mr *MockSingleSignOnMockRecorder) AuthURL(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthURL", reflect.TypeOf((*MockSingleSignOn)(nil).AuthURL), ctx)
+}
+
+// Callback mocks base method.
+func (m *MockSingleSignOn) Callback(ctx context.Context, code, state string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Callback", ctx, code, state)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Callback indicates an expected call of Callback.
+func (mr *MockSingleSignOnMockRecorder) Callback(ctx, code, state interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Callback", reflect.TypeOf((*MockSingleSignOn)(nil).Callback), ctx, code, state)
+}
+
+// Exchange mocks base method.
+func (m *MockSingleSignOn) Exchange(ctx context.Context, code string) (model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exchange", ctx, code)
+	ret0, _ := ret[0].(model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exchange indicates an expected call of Exchange.
+func (mr *MockSingleSignOnMockRecorder) Exchange(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exchange", reflect.TypeOf((*MockSingleSignOn)(nil).Exchange), ctx, code)
+}
+
+// MockNotifications is a mock of Notifications interface.
+type MockNotifications struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationsMockRecorder
+}
+
+// MockNotificationsMockRecorder is the mock recorder for MockNotifications.
+type MockNotificationsMockRecorder struct {
+	mock *MockNotifications
+}
+
+// NewMockNotifications creates a new mock instance.
+func NewMockNotifications(ctrl *gomock.Controller) *MockNotifications {
+	mock := &MockNotifications{ctrl: ctrl}
+	mock.recorder = &MockNotificationsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifications) EXPECT() *MockNotificationsMockRecorder {
+	return m.recorder
+}
+
+// GetByUserID mocks base method.
+func (m *MockNotifications) GetByUserID(ctx context.Context, userID string) ([]model.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserID", ctx, userID)
+	ret0, _ := ret[0].([]model.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserID indicates an expected call of GetByUserID.
+func (mr *MockNotificationsMockRecorder) GetByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockNotifications)(nil).GetByUserID), ctx, userID)
+}
+
+// MarkRead mocks base method.
+func (m *MockNotifications) MarkRead(ctx context.Context, userID, notificationID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", ctx, userID, notificationID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockNotificationsMockRecorder) MarkRead(ctx, userID, notificationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockNotifications)(nil).MarkRead), ctx, userID, notificationID)
+}
+
+// MockPersonalAccessTokens is a mock of PersonalAccessTokens interface.
+type MockPersonalAccessTokens struct {
+	ctrl     *gomock.Controller
+	recorder *MockPersonalAccessTokensMockRecorder
+}
+
+// MockPersonalAccessTokensMockRecorder is the mock recorder for MockPersonalAccessTokens.
+type MockPersonalAccessTokensMockRecorder struct {
+	mock *MockPersonalAccessTokens
+}
+
+// NewMockPersonalAccessTokens creates a new mock instance.
+func NewMockPersonalAccessTokens(ctrl *gomock.Controller) *MockPersonalAccessTokens {
+	mock := &MockPersonalAccessTokens{ctrl: ctrl}
+	mock.recorder = &MockPersonalAccessTokensMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPersonalAccessTokens) EXPECT() *MockPersonalAccessTokensMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockPersonalAccessTokens) Authenticate(ctx context.Context, token string) (model.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, token)
+	ret0, _ := ret[0].(model.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockPersonalAccessTokensMockRecorder) Authenticate(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockPersonalAccessTokens)(nil).Authenticate), ctx, token)
+}
+
+// Create mocks base method.
+func (m *MockPersonalAccessTokens) Create(ctx context.Context, userID string, input dto.CreatePersonalAccessToken) (model.NewPersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, input)
+	ret0, _ := ret[0].(model.NewPersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPersonalAccessTokensMockRecorder) Create(ctx, userID, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPersonalAccessTokens)(nil).Create), ctx, userID, input)
+}
+
+// GetAll mocks base method.
+func (m *MockPersonalAccessTokens) GetAll(ctx context.Context, userID string) ([]model.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, userID)
+	ret0, _ := ret[0].([]model.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockPersonalAccessTokensMockRecorder) GetAll(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockPersonalAccessTokens)(nil).GetAll), ctx, userID)
+}
+
+// Revoke mocks base method.
+func (m *MockPersonalAccessTokens) Revoke(ctx context.Context, userID, tokenID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, userID, tokenID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockPersonalAccessTokensMockRecorder) Revoke(ctx, userID, tokenID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockPersonalAccessTokens)(nil).Revoke), ctx, userID, tokenID)
+}
+
+// MockAuditor is a mock of Auditor interface.
+type MockAuditor struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditorMockRecorder
+}
+
+// MockAuditorMockRecorder is the mock recorder for MockAuditor.
+type MockAuditorMockRecorder struct {
+	mock *MockAuditor
+}
+
+// NewMockAuditor creates a new mock instance.
+func NewMockAuditor(ctrl *gomock.Controller) *MockAuditor {
+	mock := &MockAuditor{ctrl: ctrl}
+	mock.recorder = &MockAuditorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditor) EXPECT() *MockAuditorMockRecorder {
+	return m.recorder
+}
+
+// GetEvents mocks base method.
+func (m *MockAuditor) GetEvents(ctx context.Context, input dto.GetAuditEvents) (model.AuditEventsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvents", ctx, input)
+	ret0, _ := ret[0].(model.AuditEventsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEvents indicates an expected call of GetEvents.
+func (mr *MockAuditorMockRecorder) GetEvents(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvents", reflect.TypeOf((*MockAuditor)(nil).GetEvents), ctx, input)
+}
+
+// Record mocks base method.
+func (m *MockAuditor) Record(ctx context.Context, action, targetType, targetID string, before, after interface{}) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Record", ctx, action, targetType, targetID, before, after)
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockAuditorMockRecorder) Record(ctx, action, targetType, targetID, before, after interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockAuditor)(nil).Record), ctx, action, targetType, targetID, before, after)
+}
+
+// RecordTx mocks base method.
+func (m *MockAuditor) RecordTx(ctx context.Context, action, targetType, targetID string, before, after interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordTx", ctx, action, targetType, targetID, before, after)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordTx indicates an expected call of RecordTx.
+func (mr *MockAuditorMockRecorder) RecordTx(ctx, action, targetType, targetID, before, after interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordTx", reflect.TypeOf((*MockAuditor)(nil).RecordTx), ctx, action, targetType, targetID, before, after)
+}
+
+// MockSettings is a mock of Settings interface.
+type MockSettings struct {
+	ctrl     *gomock.Controller
+	recorder *MockSettingsMockRecorder
+}
+
+// MockSettingsMockRecorder is the mock recorder for MockSettings.
+type MockSettingsMockRecorder struct {
+	mock *MockSettings
+}
+
+// NewMockSettings creates a new mock instance.
+func NewMockSettings(ctrl *gomock.Controller) *MockSettings {
+	mock := &MockSettings{ctrl: ctrl}
+	mock.recorder = &MockSettingsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSettings) EXPECT() *MockSettingsMockRecorder {
+	return m.recorder
+}
+
+// Current mocks base method.
+func (m *MockSettings) Current() model.RuntimeSettings {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Current")
+	ret0, _ := ret[0].(model.RuntimeSettings)
+	return ret0
+}
+
+// Current indicates an expected call of Current.
+func (mr *MockSettingsMockRecorder) Current() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Current", reflect.TypeOf((*MockSettings)(nil).Current))
+}
+
+// GetChanges mocks base method.
+func (m *MockSettings) GetChanges(ctx context.Context) ([]model.SettingsChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChanges", ctx)
+	ret0, _ := ret[0].([]model.SettingsChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChanges indicates an expected call of GetChanges.
+func (mr *MockSettingsMockRecorder) GetChanges(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChanges", reflect.TypeOf((*MockSettings)(nil).GetChanges), ctx)
+}
+
+// OnChange mocks base method.
+func (m *MockSettings) OnChange(fn func(model.RuntimeSettings)) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnChange", fn)
+}
+
+// OnChange indicates an expected call of OnChange.
+func (mr *MockSettingsMockRecorder) OnChange(fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnChange", reflect.TypeOf((*MockSettings)(nil).OnChange), fn)
+}
+
+// Patch mocks base method.
+func (m *MockSettings) Patch(ctx context.Context, userID string, patch dto.PatchSettings) (model.RuntimeSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", ctx, userID, patch)
+	ret0, _ := ret[0].(model.RuntimeSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockSettingsMockRecorder) Patch(ctx, userID, patch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockSettings)(nil).Patch), ctx, userID, patch)
+}
+
+// Reload mocks base method.
+func (m *MockSettings) Reload(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reload", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reload indicates an expected call of Reload.
+func (mr *MockSettingsMockRecorder) Reload(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reload", reflect.TypeOf((*MockSettings)(nil).Reload), ctx)
+}
+
+// Run mocks base method.
+func (m *MockSettings) Run(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Run", ctx)
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockSettingsMockRecorder) Run(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockSettings)(nil).Run), ctx)
+}
+
+// MockFeatureFlags is a mock of FeatureFlags interface.
+type MockFeatureFlags struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeatureFlagsMockRecorder
+}
+
+// MockFeatureFlagsMockRecorder is the mock recorder for MockFeatureFlags.
+type MockFeatureFlagsMockRecorder struct {
+	mock *MockFeatureFlags
+}
+
+// NewMockFeatureFlags creates a new mock instance.
+func NewMockFeatureFlags(ctrl *gomock.Controller) *MockFeatureFlags {
+	mock := &MockFeatureFlags{ctrl: ctrl}
+	mock.recorder = &MockFeatureFlagsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeatureFlags) EXPECT() *MockFeatureFlagsMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockFeatureFlags) Create(ctx context.Context, input dto.CreateFeatureFlag) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockFeatureFlagsMockRecorder) Create(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockFeatureFlags)(nil).Create), ctx, input)
+}
+
+// Delete mocks base method.
+func (m *MockFeatureFlags) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockFeatureFlagsMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFeatureFlags)(nil).Delete), ctx, key)
+}
+
+// GetAll mocks base method.
+func (m *MockFeatureFlags) GetAll(ctx context.Context) ([]model.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]model.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockFeatureFlagsMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockFeatureFlags)(nil).GetAll), ctx)
+}
+
+// GetByKey mocks base method.
+func (m *MockFeatureFlags) GetByKey(ctx context.Context, key string) (model.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByKey", ctx, key)
+	ret0, _ := ret[0].(model.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByKey indicates an expected call of GetByKey.
+func (mr *MockFeatureFlagsMockRecorder) GetByKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByKey", reflect.TypeOf((*MockFeatureFlags)(nil).GetByKey), ctx, key)
+}
+
+// IsEnabled mocks base method.
+func (m *MockFeatureFlags) IsEnabled(ctx context.Context, key, userID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsEnabled", ctx, key, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsEnabled indicates an expected call of IsEnabled.
+func (mr *MockFeatureFlagsMockRecorder) IsEnabled(ctx, key, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEnabled", reflect.TypeOf((*MockFeatureFlags)(nil).IsEnabled), ctx, key, userID)
+}
+
+// Update mocks base method.
+func (m *MockFeatureFlags) Update(ctx context.Context, key string, input dto.UpdateFeatureFlag) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, key, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockFeatureFlagsMockRecorder) Update(ctx, key, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockFeatureFlags)(nil).Update), ctx, key, input)
+}
+
+// MockRateLimiter is a mock of RateLimiter interface.
+type MockRateLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateLimiterMockRecorder
+}
+
+// MockRateLimiterMockRecorder is the mock recorder for MockRateLimiter.
+type MockRateLimiterMockRecorder struct {
+	mock *MockRateLimiter
+}
+
+// NewMockRateLimiter creates a new mock instance.
+func NewMockRateLimiter(ctrl *gomock.Controller) *MockRateLimiter {
+	mock := &MockRateLimiter{ctrl: ctrl}
+	mock.recorder = &MockRateLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateLimiter) EXPECT() *MockRateLimiterMockRecorder {
+	return m.recorder
+}
+
+// Allow mocks base method.
+func (m *MockRateLimiter) Allow(ctx context.Context, policy, ip, userID string) (model.RateLimitResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Allow", ctx, policy, ip, userID)
+	ret0, _ := ret[0].(model.RateLimitResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Allow indicates an expected call of Allow.
+func (mr *MockRateLimiterMockRecorder) Allow(ctx, policy, ip, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Allow", reflect.TypeOf((*MockRateLimiter)(nil).Allow), ctx, policy, ip, userID)
+}
+
+// MockHealth is a mock of Health interface.
+type MockHealth struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthMockRecorder
+}
+
+// MockHealthMockRecorder is the mock recorder for MockHealth.
+type MockHealthMockRecorder struct {
+	mock *MockHealth
+}
+
+// NewMockHealth creates a new mock instance.
+func NewMockHealth(ctrl *gomock.Controller) *MockHealth {
+	mock := &MockHealth{ctrl: ctrl}
+	mock.recorder = &MockHealthMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealth) EXPECT() *MockHealthMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockHealth) Check(ctx context.Context) model.HealthReport {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx)
+	ret0, _ := ret[0].(model.HealthReport)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockHealthMockRecorder) Check(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockHealth)(nil).Check), ctx)
+}
+
+// MockURLBuilder is a mock of URLBuilder interface.
+type MockURLBuilder struct {
+	ctrl     *gomock.Controller
+	recorder *MockURLBuilderMockRecorder
+}
+
+// MockURLBuilderMockRecorder is the mock recorder for MockURLBuilder.
+type MockURLBuilderMockRecorder struct {
+	mock *MockURLBuilder
+}
+
+// NewMockURLBuilder creates a new mock instance.
+func NewMockURLBuilder(ctrl *gomock.Controller) *MockURLBuilder {
+	mock := &MockURLBuilder{ctrl: ctrl}
+	mock.recorder = &MockURLBuilderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockURLBuilder) EXPECT() *MockURLBuilderMockRecorder {
+	return m.recorder
+}
+
+// ImageURL mocks base method.
+func (m *MockURLBuilder) ImageURL(folder, imagePath string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImageURL", folder, imagePath)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ImageURL indicates an expected call of ImageURL.
+func (mr *MockURLBuilderMockRecorder) ImageURL(folder, imagePath interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImageURL", reflect.TypeOf((*MockURLBuilder)(nil).ImageURL), folder, imagePath)
+}
+
+// ObjectURL mocks base method.
+func (m *MockURLBuilder) ObjectURL(key string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ObjectURL", key)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ObjectURL indicates an expected call of ObjectURL.
+func (mr *MockURLBuilderMockRecorder) ObjectURL(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObjectURL", reflect.TypeOf((*MockURLBuilder)(nil).ObjectURL), key)
+}
+
+// VersionedKey mocks base method.
+func (m *MockURLBuilder) VersionedKey(folder string, id int) (string, string) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VersionedKey", folder, id)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	return ret0, ret1
+}
+
+// VersionedKey indicates an expected call of VersionedKey.
+func (mr *MockURLBuilderMockRecorder) VersionedKey(folder, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VersionedKey", reflect.TypeOf((*MockURLBuilder)(nil).VersionedKey), folder, id)
+}
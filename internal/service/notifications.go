@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"acsp/internal/apperror"
+	"acsp/internal/constants"
+	"acsp/internal/model"
+	"acsp/internal/repository"
+)
+
+// notificationPayload is the payload of constants.SendNotificationEvent.
+type notificationPayload struct {
+	// Key is the idempotency key of the event, the notification is added once however often the event is performed
+	Key    string          `json:"key"`
+	UserID int             `json:"user_id"`
+	Kind   string          `json:"kind"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// NotificationsService implements the Notifications interface.
+type NotificationsService struct {
+	repo repository.Notifications
+}
+
+// NewNotificationsService creates a new instance of NotificationsService.
+func NewNotificationsService(repo repository.Notifications) *NotificationsService {
+	return &NotificationsService{repo: repo}
+}
+
+// GetByUserID returns the latest notifications of the user, the latest first.
+func (s *NotificationsService) GetByUserID(ctx context.Context, userID string) ([]model.Notification, error) {
+	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByUserID(ctx, id, constants.NotificationsLimit)
+}
+
+// MarkRead marks the notification of the user as read.
+func (s *NotificationsService) MarkRead(ctx context.Context, userID, notificationID string) error {
+	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return err
+	}
+
+	nID, err := strconv.Atoi(notificationID)
+	if err != nil {
+		return apperror.ErrNotificationNotFound
+	}
+
+	return s.repo.MarkRead(ctx, id, nID)
+}
+
+// newNotificationEvent creates the event notifying the user, the source tells the notifications of one kind apart,
+// e.g. the id of the invitation.
+func newNotificationEvent(userID int, kind, source string, data interface{}) (model.OutboxEvent, error) {
+	d, err := json.Marshal(data)
+	if err != nil {
+		return model.OutboxEvent{}, errors.Wrap(err, "error when encoding notification data")
+	}
+
+	key := constants.SendNotificationEvent + ":" + kind + ":" + source
+
+	return newOutboxEvent(constants.SendNotificationEvent, key, notificationPayload{
+		Key:    key,
+		UserID: userID,
+		Kind:   kind,
+		Data:   d,
+	})
+}
+
+// newSendNotificationHandler creates the handler of constants.SendNotificationEvent.
+func newSendNotificationHandler(repo repository.Notifications) OutboxHandler {
+	return func(ctx context.Context, payload json.RawMessage) error {
+		var p notificationPayload
+
+		err := json.Unmarshal(payload, &p)
+		if err != nil {
+			return errors.Wrap(err, "error when decoding notification payload")
+		}
+
+		err = repo.Add(ctx, model.Notification{
+			UserID:         p.UserID,
+			Kind:           p.Kind,
+			Payload:        p.Data,
+			IdempotencyKey: p.Key,
+		})
+		if err != nil {
+			return errors.Wrap(err, "error when adding notification")
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"acsp/internal/constants"
+	"acsp/internal/model"
+	"acsp/internal/repository"
+)
+
+// fakeNotifications keeps the notifications by their idempotency keys like the unique index
+type fakeNotifications struct {
+	repository.Notifications
+	added []model.Notification
+	keys  map[string]bool
+}
+
+func (f *fakeNotifications) Add(ctx context.Context, notification model.Notification) error {
+	if !f.keys[notification.IdempotencyKey] {
+		f.keys[notification.IdempotencyKey] = true
+		f.added = append(f.added, notification)
+	}
+
+	return nil
+}
+
+// fakeInvitationCards only implements GetByID and CreateInvitation
+type fakeInvitationCards struct {
+	repository.Cards
+	cards map[int]model.Card
+}
+
+func (f *fakeInvitationCards) GetByID(ctx context.Context, cardID int) (*model.Card, error) {
+	card := f.cards[cardID]
+
+	return &card, nil
+}
+
+func (f *fakeInvitationCards) CreateInvitation(ctx context.Context, inviterID int, card model.Card) (int, error) {
+	return 15, nil
+}
+
+// fakeInvitationUsers only implements ExistsUserByID
+type fakeInvitationUsers struct {
+	repository.Users
+}
+
+func (f *fakeInvitationUsers) ExistsUserByID(ctx context.Context, id int) (bool, error) {
+	return true, nil
+}
+
+func TestCardsService_CreateInvitation(t *testing.T) {
+	ctx := context.Background()
+	outbox := &fakeOutbox{}
+	cards := &fakeInvitationCards{cards: map[int]model.Card{3: {ID: 3, UserID: 7}}}
+	s := NewCardsService(cards, &fakeInvitationUsers{}, outbox, fakeTransactional{}, nil)
+
+	require.NoError(t, s.CreateInvitation(ctx, "9", 3))
+
+	// The author of the card is notified with the invitation
+	require.Len(t, outbox.enqueued, 1)
+	event := outbox.enqueued[0]
+	assert.Equal(t, constants.SendNotificationEvent, event.EventType)
+	assert.Equal(t, "notification.send:card.invitation:15", event.IdempotencyKey)
+
+	notifications := &fakeNotifications{keys: map[string]bool{}}
+	h := newSendNotificationHandler(notifications)
+
+	// The second run repeats the first one that failed after adding the notification
+	for i := 0; i < 2; i++ {
+		require.NoError(t, h(ctx, event.Payload))
+	}
+
+	require.Len(t, notifications.added, 1)
+	assert.Equal(t, 7, notifications.added[0].UserID)
+	assert.Equal(t, constants.NotificationCardInvitation, notifications.added[0].Kind)
+	assert.JSONEq(t, `{"invitation_id": 15, "card_id": 3, "inviter_id": 9}`, string(notifications.added[0].Payload))
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"io"
+	"mime/multipart"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"acsp/internal/repository"
+)
+
+// ObjectStorageService implements the ObjectStorage interface.
+type ObjectStorageService struct {
+	repo repository.ObjectStorage
+}
+
+// NewObjectStorageService creates a new instance of ObjectStorageService.
+func NewObjectStorageService(repo repository.ObjectStorage) *ObjectStorageService {
+	return &ObjectStorageService{repo: repo}
+}
+
+func (s *ObjectStorageService) UploadFile(ctx context.Context, key string, file *multipart.FileHeader) error {
+	// Open the file
+	f, err := file.Open()
+	if err != nil {
+		return errors.Wrap(err, "Error occurred when opening file")
+	}
+	defer f.Close()
+
+	// Read the file contents into a byte slice
+	fileBytes, err := io.ReadAll(f)
+	if err != nil {
+		return errors.Wrap(err, "Error occurred when reading file")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// Put the object into the storage
+	return s.repo.PutObject(ctx, key, fileBytes)
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"acsp/internal/config"
+	"acsp/internal/logging"
+	"acsp/internal/model"
+	"acsp/internal/repository"
+)
+
+// OutboxHandler performs the side effect of an outbox event.
+// Handlers must be idempotent, an event may be performed more than once.
+type OutboxHandler func(ctx context.Context, payload json.RawMessage) error
+
+// OutboxService implements the OutboxWorker interface.
+type OutboxService struct {
+	repo     repository.Outbox
+	handlers map[string]OutboxHandler
+	// compensations undo what the events given up after the max attempts have left behind
+	compensations map[string]OutboxHandler
+	config        config.OutboxConfig
+	now           func() time.Time
+}
+
+// NewOutboxService creates a new instance of OutboxService.
+func NewOutboxService(repo repository.Outbox, c config.OutboxConfig) *OutboxService {
+	return &OutboxService{
+		repo:          repo,
+		handlers:      map[string]OutboxHandler{},
+		compensations: map[string]OutboxHandler{},
+		config:        c,
+		now:           time.Now,
+	}
+}
+
+// Register sets the handler of the events of the given type.
+func (o *OutboxService) Register(eventType string, h OutboxHandler) {
+	o.handlers[eventType] = h
+}
+
+// RegisterCompensation sets the handler called once an event of the given type has failed for good,
+// e.g. to point the owner of an upload that is never moved back to the default image.
+func (o *OutboxService) RegisterCompensation(eventType string, h OutboxHandler) {
+	o.compensations[eventType] = h
+}
+
+// ProcessBatch performs a batch of due events and returns the number of claimed events.
+func (o *OutboxService) ProcessBatch(ctx context.Context) (int, error) {
+	events, err := o.repo.Claim(ctx, o.config.BatchSize, o.config.Lease)
+	if err != nil {
+		return 0, errors.Wrap(err, "error when claiming outbox events")
+	}
+
+	for _, e := range events {
+		o.process(ctx, e)
+	}
+
+	return len(events), nil
+}
+
+// Run performs the due events every poll interval until the context is done.
+func (o *OutboxService) Run(ctx context.Context) {
+	l := logging.LoggerFromContext(ctx)
+
+	ticker := time.NewTicker(o.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			l.Info("Outbox worker stopped")
+
+			return
+		case <-ticker.C:
+			// Keep going while there are due events, so that a backlog doesn't wait for the next tick
+			for {
+				n, err := o.ProcessBatch(ctx)
+				if err != nil {
+					l.Error("Error when processing outbox events", zap.Error(err))
+				}
+
+				if err != nil || n < o.config.BatchSize || ctx.Err() != nil {
+					break
+				}
+			}
+		}
+	}
+}
+
+func (o *OutboxService) process(ctx context.Context, e model.OutboxEvent) {
+	l := logging.LoggerFromContext(ctx).With(
+		zap.Int("eventID", e.ID),
+		zap.String("eventType", e.EventType),
+		zap.String("idempotencyKey", e.IdempotencyKey),
+		zap.Int("attempt", e.Attempts),
+	)
+	ctx = logging.ContextWithLogger(ctx, l)
+
+	h, ok := o.handlers[e.EventType]
+	if !ok {
+		l.Error("No handler for outbox event")
+
+		err := o.repo.MarkFailed(ctx, e.ID, "no handler for event type")
+		if err != nil {
+			l.Error("Error when marking outbox event as failed", zap.Error(err))
+		}
+
+		return
+	}
+
+	err := h(ctx, e.Payload)
+	if err == nil {
+		err = o.repo.MarkDone(ctx, e.ID)
+		if err != nil {
+			// The event is performed again after the lease, handlers are idempotent
+			l.Error("Error when marking outbox event as done", zap.Error(err))
+		}
+
+		return
+	}
+
+	if e.Attempts >= o.config.MaxAttempts {
+		l.Error("Outbox event failed, giving up", zap.Error(err))
+
+		err = o.repo.MarkFailed(ctx, e.ID, err.Error())
+		if err != nil {
+			l.Error("Error when marking outbox event as failed", zap.Error(err))
+
+			// The event is claimed again after the lease, so it is compensated then
+			return
+		}
+
+		if compensate, ok := o.compensations[e.EventType]; ok {
+			err = compensate(ctx, e.Payload)
+			if err != nil {
+				l.Error("Error when compensating the failed outbox event", zap.Error(err))
+			}
+		}
+
+		return
+	}
+
+	nextAttemptAt := o.now().Add(o.backoff(e.Attempts))
+	l.Warn("Outbox event failed, retrying", zap.Error(err), zap.Time("nextAttemptAt", nextAttemptAt))
+
+	err = o.repo.Retry(ctx, e.ID, nextAttemptAt, err.Error())
+	if err != nil {
+		l.Error("Error when scheduling outbox event retry", zap.Error(err))
+	}
+}
+
+// backoff doubles the retry delay with every attempt up to the max retry delay.
+func (o *OutboxService) backoff(attempts int) time.Duration {
+	d := o.config.RetryDelay
+	for i := 1; i < attempts && d < o.config.MaxRetryDelay; i++ {
+		d *= 2
+	}
+
+	if d > o.config.MaxRetryDelay {
+		d = o.config.MaxRetryDelay
+	}
+
+	return d
+}
+
+// newOutboxEvent creates an outbox event with the JSON encoded payload.
+func newOutboxEvent(eventType, idempotencyKey string, payload interface{}) (model.OutboxEvent, error) {
+	p, err := json.Marshal(payload)
+	if err != nil {
+		return model.OutboxEvent{}, errors.Wrap(err, "error when encoding outbox event payload")
+	}
+
+	return model.OutboxEvent{EventType: eventType, IdempotencyKey: idempotencyKey, Payload: p}, nil
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"acsp/internal/apperror"
+	"acsp/internal/config"
+	"acsp/internal/model"
+)
+
+type fakeOutbox struct {
+	enqueued []model.OutboxEvent
+	events   []model.OutboxEvent
+	done     []int
+	failed   []int
+	retries  map[int]time.Time
+}
+
+func (f *fakeOutbox) Enqueue(ctx context.Context, event model.OutboxEvent) error {
+	f.enqueued = append(f.enqueued, event)
+
+	return nil
+}
+
+func (f *fakeOutbox) Claim(ctx context.Context, limit int, lease time.Duration) ([]model.OutboxEvent, error) {
+	return f.events, nil
+}
+
+func (f *fakeOutbox) MarkDone(ctx context.Context, eventID int) error {
+	f.done = append(f.done, eventID)
+
+	return nil
+}
+
+func (f *fakeOutbox) Retry(ctx context.Context, eventID int, nextAttemptAt time.Time, lastError string) error {
+	f.retries[eventID] = nextAttemptAt
+
+	return nil
+}
+
+func (f *fakeOutbox) MarkFailed(ctx context.Context, eventID int, lastError string) error {
+	f.failed = append(f.failed, eventID)
+
+	return nil
+}
+
+func TestOutboxService_ProcessBatch(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	repo := &fakeOutbox{
+		events: []model.OutboxEvent{
+			{ID: 1, EventType: "ok", Attempts: 1},
+			{ID: 2, EventType: "failing", Attempts: 1},
+			{ID: 3, EventType: "failing", Attempts: 4},
+			{ID: 4, EventType: "failing", Attempts: 5, Payload: json.RawMessage(`{"id":4}`)},
+			{ID: 5, EventType: "unknown", Attempts: 1},
+		},
+		retries: map[int]time.Time{},
+	}
+
+	o := NewOutboxService(repo, config.OutboxConfig{
+		BatchSize:     10,
+		MaxAttempts:   5,
+		RetryDelay:    time.Second,
+		MaxRetryDelay: 5 * time.Second,
+	})
+	o.now = func() time.Time { return now }
+	o.Register("ok", func(ctx context.Context, payload json.RawMessage) error { return nil })
+	o.Register("failing", func(ctx context.Context, payload json.RawMessage) error { return errors.New("failed") })
+
+	var compensated []string
+	o.RegisterCompensation("failing", func(ctx context.Context, payload json.RawMessage) error {
+		compensated = append(compensated, string(payload))
+
+		return nil
+	})
+
+	n, err := o.ProcessBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 5, n)
+	assert.Equal(t, []int{1}, repo.done)
+	assert.Equal(t, []int{4, 5}, repo.failed)
+	// Only the events given up are compensated
+	assert.Equal(t, []string{`{"id":4}`}, compensated)
+	assert.Equal(t, map[int]time.Time{
+		2: now.Add(time.Second),
+		3: now.Add(5 * time.Second),
+	}, repo.retries)
+}
+
+func TestFinalizeUploadHandler(t *testing.T) {
+	objects := &fakeStoredObjects{}
+	storage := &fakeObjectStorage{objects: map[string][]byte{"staging/abc": []byte("image")}}
+	h := newFinalizeUploadHandler(objects, storage)
+
+	event, err := newFinalizeUploadEvent("staging/abc", "articles/7/xyz", "articles", 7)
+	assert.NoError(t, err)
+	assert.Equal(t, "storage.finalize_upload:articles/7/xyz", event.IdempotencyKey)
+
+	// The second run repeats the first one that failed after moving the object
+	for i := 0; i < 2; i++ {
+		err = h(context.Background(), event.Payload)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, map[string][]byte{"articles/7/xyz": []byte("image")}, storage.objects)
+	assert.Equal(t, model.StoredObject{Key: "articles/7/xyz", OwnerType: "articles", OwnerID: 7}, objects.tracked[0])
+	assert.Equal(t, []string{"staging/abc", "staging/abc"}, objects.deleted)
+}
+
+func TestFinalizeUploadHandler_LostUpload(t *testing.T) {
+	objects := &fakeStoredObjects{}
+	storage := &fakeObjectStorage{objects: map[string][]byte{}}
+	h := newFinalizeUploadHandler(objects, storage)
+
+	event, err := newFinalizeUploadEvent("staging/abc", "articles/7/xyz", "articles", 7)
+	assert.NoError(t, err)
+
+	// Neither the staged nor the final object exists, so the event isn't done
+	err = h(context.Background(), event.Payload)
+	assert.ErrorIs(t, err, apperror.ErrObjectNotFound)
+	assert.Empty(t, objects.deleted)
+
+	err = newFinalizeUploadCompensation(objects)(context.Background(), event.Payload)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.StoredObject{{Key: "articles/7/xyz", OwnerType: "articles", OwnerID: 7}}, objects.reset)
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"acsp/internal/apperror"
+	"acsp/internal/config"
+	"acsp/internal/constants"
+	"acsp/internal/dto"
+	"acsp/internal/logging"
+	"acsp/internal/model"
+	"acsp/internal/repository"
+)
+
+// personalAccessTokenHintLength is the length of the end of the token shown in the list of the tokens
+const personalAccessTokenHintLength = 4
+
+// PersonalAccessTokensService implements the PersonalAccessTokens interface, the tokens are random and only
+// their hashes are stored.
+type PersonalAccessTokensService struct {
+	repo      repository.PersonalAccessTokens
+	roles     repository.Roles
+	twoFactor repository.TwoFactor
+	txManager repository.Transactional
+	audit     Auditor
+	config    config.PersonalAccessTokensConfig
+}
+
+// NewPersonalAccessTokensService creates a new instance of PersonalAccessTokensService.
+func NewPersonalAccessTokensService(
+	repo repository.PersonalAccessTokens,
+	roles repository.Roles,
+	twoFactor repository.TwoFactor,
+	t repository.Transactional,
+	a Auditor,
+	c config.PersonalAccessTokensConfig,
+) *PersonalAccessTokensService {
+	return &PersonalAccessTokensService{
+		repo:      repo,
+		roles:     roles,
+		twoFactor: twoFactor,
+		txManager: t,
+		audit:     a,
+		config:    c,
+	}
+}
+
+// Create creates a token of the user, the token is returned once. The scopes are read, write and the roles
+// of the user.
+func (s *PersonalAccessTokensService) Create(
+	ctx context.Context,
+	userID string,
+	input dto.CreatePersonalAccessToken,
+) (model.NewPersonalAccessToken, error) {
+	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return model.NewPersonalAccessToken{}, err
+	}
+
+	ttl := s.config.DefaultTTL
+	if input.ExpiresInDays > 0 {
+		ttl = time.Duration(input.ExpiresInDays) * 24 * time.Hour
+	}
+
+	if ttl > s.config.MaxTTL {
+		return model.NewPersonalAccessToken{}, apperror.NewValidationError(apperror.FieldError{
+			Field:   "expires_in_days",
+			Code:    "max",
+			Message: fmt.Sprintf("must be at most %d", int(s.config.MaxTTL.Hours()/24)),
+		})
+	}
+
+	roles, _, err := principalClaims(ctx, s.roles, s.twoFactor, id)
+	if err != nil {
+		return model.NewPersonalAccessToken{}, err
+	}
+
+	allowed := append([]string{constants.ScopeRead, constants.ScopeWrite}, roles...)
+
+	valid := make(map[string]bool, len(allowed))
+	for _, scope := range allowed {
+		valid[scope] = true
+	}
+
+	for _, scope := range input.Scopes {
+		if !valid[scope] {
+			return model.NewPersonalAccessToken{}, apperror.NewValidationError(apperror.FieldError{
+				Field:   "scopes",
+				Code:    "oneof",
+				Message: "must be one of " + strings.Join(allowed, ", "),
+			})
+		}
+	}
+
+	count, err := s.repo.CountByUser(ctx, id)
+	if err != nil {
+		return model.NewPersonalAccessToken{}, err
+	}
+
+	if count >= s.config.MaxPerUser {
+		return model.NewPersonalAccessToken{}, apperror.ErrTooManyTokens
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return model.NewPersonalAccessToken{}, err
+	}
+
+	token := constants.PersonalAccessTokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+
+	var created model.PersonalAccessToken
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		created, err = s.repo.Create(ctx, model.PersonalAccessToken{
+			UserID:    id,
+			Name:      input.Name,
+			TokenHash: hashPersonalAccessToken(token),
+			Hint:      token[len(token)-personalAccessTokenHintLength:],
+			Scopes:    input.Scopes,
+			ExpiresAt: time.Now().Add(ttl),
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.audit.RecordTx(ctx, constants.AuditActionCreate, constants.AuditTargetPersonalAccessToken,
+			strconv.Itoa(created.ID), nil, created)
+	})
+	if err != nil {
+		return model.NewPersonalAccessToken{}, err
+	}
+
+	logging.LoggerFromContext(ctx).Info("The personal access token is created",
+		zap.Int("tokenID", created.ID), zap.Strings("scopes", input.Scopes))
+
+	return model.NewPersonalAccessToken{PersonalAccessToken: created, Token: token}, nil
+}
+
+// GetAll returns the tokens of the user, including the expired ones.
+func (s *PersonalAccessTokensService) GetAll(ctx context.Context, userID string) ([]model.PersonalAccessToken, error) {
+	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByUser(ctx, id)
+}
+
+// Revoke deletes the token of the user, the requests with it are refused from now on.
+func (s *PersonalAccessTokensService) Revoke(ctx context.Context, userID, tokenID string) error {
+	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return err
+	}
+
+	tID, err := strconv.Atoi(tokenID)
+	if err != nil {
+		return apperror.ErrTokenNotFound
+	}
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.repo.Delete(ctx, id, tID)
+		if err != nil {
+			return err
+		}
+
+		return s.audit.RecordTx(ctx, constants.AuditActionRevoke, constants.AuditTargetPersonalAccessToken, tokenID,
+			nil, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	logging.LoggerFromContext(ctx).Info("The personal access token is revoked", zap.Int("tokenID", tID))
+
+	return nil
+}
+
+// Authenticate returns the user of the token. The roles are the current roles of the user, the roles of the
+// scopes are checked by Authorize.
+func (s *PersonalAccessTokensService) Authenticate(ctx context.Context, token string) (model.Principal, error) {
+	t, err := s.repo.GetByHash(ctx, hashPersonalAccessToken(token))
+	if apperror.KindOf(err) == apperror.KindNotFound {
+		return model.Principal{}, apperror.ErrInvalidToken
+	}
+
+	if err != nil {
+		return model.Principal{}, err
+	}
+
+	if !time.Now().Before(t.ExpiresAt) {
+		return model.Principal{}, apperror.ErrInvalidToken
+	}
+
+	roles, twoFactor, err := principalClaims(ctx, s.roles, s.twoFactor, t.UserID)
+	if err != nil {
+		return model.Principal{}, err
+	}
+
+	// The use is only shown to the user, the request goes on when it isn't recorded
+	if err := s.repo.Touch(ctx, t.ID); err != nil {
+		logging.LoggerFromContext(ctx).Error("Error when recording the use of the token", zap.Error(err))
+	}
+
+	return model.Principal{
+		UserID:    strconv.Itoa(t.UserID),
+		Roles:     roles,
+		TwoFactor: twoFactor,
+		TokenID:   t.ID,
+		Scopes:    t.Scopes,
+	}, nil
+}
+
+// hashPersonalAccessToken hashes the token. The tokens are random, a fast hash can't be brute forced.
+func hashPersonalAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
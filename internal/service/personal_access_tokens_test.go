@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"acsp/internal/apperror"
+	"acsp/internal/config"
+	"acsp/internal/constants"
+	"acsp/internal/dto"
+	"acsp/internal/model"
+)
+
+// fakePersonalAccessTokens keeps the tokens in memory by the id
+type fakePersonalAccessTokens struct {
+	tokens map[int]model.PersonalAccessToken
+	nextID int
+}
+
+func (f *fakePersonalAccessTokens) Create(
+	ctx context.Context,
+	token model.PersonalAccessToken,
+) (model.PersonalAccessToken, error) {
+	f.nextID++
+	token.ID = f.nextID
+	token.CreatedAt = time.Now()
+	f.tokens[token.ID] = token
+
+	return token, nil
+}
+
+func (f *fakePersonalAccessTokens) CountByUser(ctx context.Context, userID int) (int, error) {
+	tokens, _ := f.GetByUser(ctx, userID)
+
+	return len(tokens), nil
+}
+
+func (f *fakePersonalAccessTokens) GetByUser(ctx context.Context, userID int) ([]model.PersonalAccessToken, error) {
+	var tokens []model.PersonalAccessToken
+	for _, t := range f.tokens {
+		if t.UserID == userID {
+			tokens = append(tokens, t)
+		}
+	}
+
+	return tokens, nil
+}
+
+func (f *fakePersonalAccessTokens) GetByHash(ctx context.Context, hash string) (model.PersonalAccessToken, error) {
+	for _, t := range f.tokens {
+		if t.TokenHash == hash {
+			return t, nil
+		}
+	}
+
+	return model.PersonalAccessToken{}, apperror.WithKind(fmt.Errorf("no rows"), apperror.KindNotFound)
+}
+
+func (f *fakePersonalAccessTokens) Delete(ctx context.Context, userID, tokenID int) error {
+	t, ok := f.tokens[tokenID]
+	if !ok || t.UserID != userID {
+		return apperror.ErrTokenNotFound
+	}
+
+	delete(f.tokens, tokenID)
+
+	return nil
+}
+
+func (f *fakePersonalAccessTokens) Touch(ctx context.Context, tokenID int) error {
+	t := f.tokens[tokenID]
+	now := time.Now()
+	t.LastUsedAt = &now
+	f.tokens[tokenID] = t
+
+	return nil
+}
+
+func newTestPersonalAccessTokensService() (*PersonalAccessTokensService, *fakePersonalAccessTokens) {
+	repo := &fakePersonalAccessTokens{tokens: map[int]model.PersonalAccessToken{}}
+	roles := &fakeRoles{roles: map[int][]model.Role{2: {{ID: "2", Name: "admin"}}}}
+	twoFactor := &fakeTwoFactor{authenticators: map[int]model.TOTP{2: {UserID: 2, Enabled: true}}}
+
+	return NewPersonalAccessTokensService(repo, roles, twoFactor, fakeTransactional{},
+		NewAuditService(&fakeAuditEvents{}), config.PersonalAccessTokensConfig{
+			DefaultTTL: 30 * 24 * time.Hour,
+			MaxTTL:     90 * 24 * time.Hour,
+			MaxPerUser: 2,
+		}), repo
+}
+
+func TestPersonalAccessTokensService_CreateAndAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	s, repo := newTestPersonalAccessTokensService()
+
+	created, err := s.Create(ctx, "2", dto.CreatePersonalAccessToken{
+		Name:   "grading script",
+		Scopes: []string{constants.ScopeRead, "admin"},
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.Token, constants.PersonalAccessTokenPrefix))
+	assert.Equal(t, created.Token[len(created.Token)-4:], created.Hint)
+	assert.WithinDuration(t, time.Now().Add(30*24*time.Hour), created.ExpiresAt, time.Minute)
+
+	// Only the hash is stored
+	assert.NotContains(t, repo.tokens[created.ID].TokenHash, created.Token)
+
+	principal, err := s.Authenticate(ctx, created.Token)
+	require.NoError(t, err)
+	assert.Equal(t, model.Principal{
+		UserID:    "2",
+		Roles:     []string{"admin"},
+		TwoFactor: true,
+		TokenID:   created.ID,
+		Scopes:    []string{constants.ScopeRead, "admin"},
+	}, principal)
+	assert.NotNil(t, repo.tokens[created.ID].LastUsedAt)
+
+	assert.True(t, principal.HasScope(constants.ScopeRead))
+	assert.False(t, principal.HasScope(constants.ScopeWrite))
+
+	_, err = s.Authenticate(ctx, created.Token+"x")
+	assert.ErrorIs(t, err, apperror.ErrInvalidToken)
+
+	// The expired tokens are refused
+	expired := repo.tokens[created.ID]
+	expired.ExpiresAt = time.Now().Add(-time.Second)
+	repo.tokens[created.ID] = expired
+
+	_, err = s.Authenticate(ctx, created.Token)
+	assert.ErrorIs(t, err, apperror.ErrInvalidToken)
+}
+
+func TestPersonalAccessTokensService_Limits(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestPersonalAccessTokensService()
+
+	// The roles the user doesn't have aren't scopes
+	_, err := s.Create(ctx, "1", dto.CreatePersonalAccessToken{Name: "ci", Scopes: []string{"admin"}})
+
+	var validationErr *apperror.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "scopes", validationErr.Fields[0].Field)
+
+	_, err = s.Create(ctx, "1", dto.CreatePersonalAccessToken{
+		Name: "ci", Scopes: []string{constants.ScopeWrite}, ExpiresInDays: 91,
+	})
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "expires_in_days", validationErr.Fields[0].Field)
+
+	for i := 0; i < 2; i++ {
+		_, err = s.Create(ctx, "1", dto.CreatePersonalAccessToken{
+			Name: "ci", Scopes: []string{constants.ScopeWrite}, ExpiresInDays: 90,
+		})
+		require.NoError(t, err)
+	}
+
+	_, err = s.Create(ctx, "1", dto.CreatePersonalAccessToken{Name: "ci", Scopes: []string{constants.ScopeWrite}})
+	assert.ErrorIs(t, err, apperror.ErrTooManyTokens)
+}
+
+func TestPersonalAccessTokensService_Revoke(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestPersonalAccessTokensService()
+
+	created, err := s.Create(ctx, "1", dto.CreatePersonalAccessToken{Name: "ci", Scopes: []string{"read"}})
+	require.NoError(t, err)
+
+	tokenID := fmt.Sprint(created.ID)
+
+	// The tokens of the other users can't be revoked
+	assert.ErrorIs(t, s.Revoke(ctx, "2", tokenID), apperror.ErrTokenNotFound)
+	require.NoError(t, s.Revoke(ctx, "1", tokenID))
+
+	_, err = s.Authenticate(ctx, created.Token)
+	assert.ErrorIs(t, err, apperror.ErrInvalidToken)
+
+	tokens, err := s.GetAll(ctx, "1")
+	require.NoError(t, err)
+	assert.Empty(t, tokens)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	"acsp/internal/constants"
 	"acsp/internal/dto"
 	"acsp/internal/logging"
 	"acsp/internal/model"
@@ -15,10 +16,11 @@ import (
 type ProjectsService struct {
 	repo        repository.Projects
 	modulesRepo repository.ProjectModules
+	urls        URLBuilder
 }
 
-func NewProjectsService(repo repository.Projects, modulesRepo repository.ProjectModules) *ProjectsService {
-	return &ProjectsService{repo: repo, modulesRepo: modulesRepo}
+func NewProjectsService(repo repository.Projects, modulesRepo repository.ProjectModules, u URLBuilder) *ProjectsService {
+	return &ProjectsService{repo: repo, modulesRepo: modulesRepo, urls: u}
 }
 
 func (p *ProjectsService) Create(ctx context.Context, disciplineID int, input dto.CreateProject) error {
@@ -93,7 +95,7 @@ func (p *ProjectsService) GetAll(ctx context.Context) ([]model.Project, error) {
 		return nil, errors.Wrap(err, "error when getting all projects")
 	}
 
-	return projects, nil
+	return p.getFullURLs(projects), nil
 }
 
 func (p *ProjectsService) GetByID(ctx context.Context, disciplineID, projectID int) (model.Project, error) {
@@ -119,7 +121,7 @@ func (p *ProjectsService) GetByID(ctx context.Context, disciplineID, projectID i
 		project.Modules = m
 	}
 
-	return project, nil
+	return p.getFullURL(project), nil
 }
 
 func (p *ProjectsService) GetAllByDisciplineID(ctx context.Context, disciplineID int) ([]model.Project, error) {
@@ -132,5 +134,19 @@ func (p *ProjectsService) GetAllByDisciplineID(ctx context.Context, disciplineID
 		return nil, errors.Wrap(err, "error when getting all projects by discipline ID")
 	}
 
-	return projects, nil
+	return p.getFullURLs(projects), nil
+}
+
+func (p *ProjectsService) getFullURLs(projects []model.Project) []model.Project {
+	for i := range projects {
+		projects[i] = p.getFullURL(projects[i])
+	}
+
+	return projects
+}
+
+func (p *ProjectsService) getFullURL(project model.Project) model.Project {
+	project.ImageURL = p.urls.ImageURL(constants.ProjectsImagesFolder, project.ImageURL)
+
+	return project
 }
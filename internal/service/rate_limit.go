@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"acsp/internal/config"
+	"acsp/internal/constants"
+	"acsp/internal/model"
+	"acsp/internal/repository"
+)
+
+// RateLimitService implements the RateLimiter interface.
+type RateLimitService struct {
+	repo     repository.RateLimiter
+	settings Settings
+	config   config.RateLimitConfig
+}
+
+// NewRateLimitService creates a new instance of RateLimitService.
+func NewRateLimitService(repo repository.RateLimiter, settings Settings, c config.RateLimitConfig) *RateLimitService {
+	return &RateLimitService{
+		repo:     repo,
+		settings: settings,
+		config:   c,
+	}
+}
+
+// Allow counts the request of the client of the policy. The clients are told apart by the key of the policy,
+// the requests without a user are counted per IP. All requests are allowed when the rate limiter is disabled.
+func (r *RateLimitService) Allow(ctx context.Context, policy, ip, userID string) (model.RateLimitResult, error) {
+	if !r.config.Enabled {
+		return model.RateLimitResult{Allowed: true}, nil
+	}
+
+	p, err := r.policy(policy)
+	if err != nil {
+		return model.RateLimitResult{}, err
+	}
+
+	client := "ip:" + ip
+	if p.Key == config.RateLimitKeyUser && userID != "" {
+		client = "user:" + userID
+	}
+
+	return r.repo.Allow(ctx, policy, client, p.Max, p.Window)
+}
+
+func (r *RateLimitService) policy(name string) (config.RateLimitPolicy, error) {
+	switch name {
+	case constants.DefaultRateLimitPolicy:
+		// The default policy is changed by the admins at runtime
+		s := r.settings.Current().RateLimit
+
+		return config.RateLimitPolicy{
+			Max:    s.Max,
+			Window: time.Duration(s.WindowSeconds) * time.Second,
+			Key:    config.RateLimitKeyIP,
+		}, nil
+	case constants.AuthRateLimitPolicy:
+		return r.config.Auth, nil
+	case constants.WriteRateLimitPolicy:
+		return r.config.Write, nil
+	default:
+		return config.RateLimitPolicy{}, errors.Errorf("unknown rate limit policy %q", name)
+	}
+}
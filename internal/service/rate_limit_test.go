@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"acsp/internal/config"
+	"acsp/internal/constants"
+	"acsp/internal/model"
+)
+
+type rateLimitCall struct {
+	policy, client string
+	max            int
+	window         time.Duration
+}
+
+// fakeRateLimiter allows every request and records the calls
+type fakeRateLimiter struct {
+	calls []rateLimitCall
+}
+
+func (f *fakeRateLimiter) Allow(
+	ctx context.Context,
+	policy, client string,
+	max int,
+	window time.Duration,
+) (model.RateLimitResult, error) {
+	f.calls = append(f.calls, rateLimitCall{policy: policy, client: client, max: max, window: window})
+
+	return model.RateLimitResult{Allowed: true, Limit: max, Remaining: max - 1, Window: window}, nil
+}
+
+var testRateLimitConfig = config.RateLimitConfig{
+	Enabled: true,
+	Auth:    config.RateLimitPolicy{Max: 10, Window: time.Minute, Key: config.RateLimitKeyIP},
+	Write:   config.RateLimitPolicy{Max: 30, Window: time.Hour, Key: config.RateLimitKeyUser},
+}
+
+func TestRateLimitService_Allow(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeRateLimiter{}
+	settings := NewSettingsService(&fakeSettings{}, &fakeSettingsChanges{}, fakeTransactional{},
+		NewAuditService(&fakeAuditEvents{}), testSettingsConfig)
+	s := NewRateLimitService(repo, settings, testRateLimitConfig)
+
+	for _, policy := range []string{
+		constants.DefaultRateLimitPolicy,
+		constants.AuthRateLimitPolicy,
+		constants.WriteRateLimitPolicy,
+	} {
+		_, err := s.Allow(ctx, policy, "10.0.0.1", "7")
+		assert.NoError(t, err)
+	}
+
+	// The anonymous requests of a per user policy are counted per IP
+	_, err := s.Allow(ctx, constants.WriteRateLimitPolicy, "10.0.0.1", "")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []rateLimitCall{
+		{policy: constants.DefaultRateLimitPolicy, client: "ip:10.0.0.1",
+			max: testSettingsConfig.RateLimitMax, window: testSettingsConfig.RateLimitWindow},
+		{policy: constants.AuthRateLimitPolicy, client: "ip:10.0.0.1", max: 10, window: time.Minute},
+		{policy: constants.WriteRateLimitPolicy, client: "user:7", max: 30, window: time.Hour},
+		{policy: constants.WriteRateLimitPolicy, client: "ip:10.0.0.1", max: 30, window: time.Hour},
+	}, repo.calls)
+
+	_, err = s.Allow(ctx, "unknown", "10.0.0.1", "7")
+	assert.Error(t, err)
+}
+
+func TestRateLimitService_AllowDisabled(t *testing.T) {
+	repo := &fakeRateLimiter{}
+	c := testRateLimitConfig
+	c.Enabled = false
+	settings := NewSettingsService(&fakeSettings{}, &fakeSettingsChanges{}, fakeTransactional{},
+		NewAuditService(&fakeAuditEvents{}), testSettingsConfig)
+	s := NewRateLimitService(repo, settings, c)
+
+	res, err := s.Allow(context.Background(), constants.AuthRateLimitPolicy, "10.0.0.1", "")
+	assert.NoError(t, err)
+	assert.Equal(t, model.RateLimitResult{Allowed: true}, res)
+	assert.Empty(t, repo.calls)
+}
@@ -8,7 +8,9 @@ import (
 	_ "github.com/golang/mock/gomock"
 
 	"acsp/internal/config"
+	"acsp/internal/constants"
 	"acsp/internal/dto"
+	"acsp/internal/jwks"
 	"acsp/internal/model"
 	"acsp/internal/repository"
 )
@@ -31,14 +33,30 @@ type Service struct {
 	CourseModules
 	ModuleLessons
 	LessonComments
-	S3BucketService S3Bucket
+	Storage   ObjectStorage
+	Janitor   StorageJanitor
+	Outbox    OutboxWorker
+	Settings  Settings
+	Features  FeatureFlags
+	RateLimit RateLimiter
+	Lockout   Lockout
+	TwoFactor TwoFactor
+	SSO       SingleSignOn
+	Tokens    PersonalAccessTokens
+	Audit     Auditor
+	Health    Health
+	// Notifications are written by the outbox worker, see constants.SendNotificationEvent
+	Notifications Notifications
 }
 
 type Authorization interface {
 	CreateUser(ctx context.Context, dto dto.CreateUser) error
 	GetUserByID(ctx context.Context, userID string) (model.User, error)
-	GenerateTokenPair(ctx context.Context, email, password string) (*TokenDetails, error)
-	ParseToken(token string) (string, error)
+	Authenticate(ctx context.Context, email, password string) (model.User, error)
+	GenerateTokenPair(ctx context.Context, user model.User) (*TokenDetails, error)
+	ParseToken(token string) (model.Principal, error)
+	ParseRefreshToken(ctx context.Context, token string) (string, error)
+	PublicKeys() jwks.JWKSet
 	SaveRefreshToken(ctx context.Context, userID string, details *TokenDetails) error
 	DeleteRefreshToken(ctx context.Context, userID string) error
 }
@@ -47,7 +65,7 @@ type Users interface {
 	CreateUser(ctx context.Context, dto dto.CreateUser) error
 	UpdateUser(ctx context.Context, userID string, dto dto.UpdateUser) error
 	DeleteUser(ctx context.Context, userID string) error
-	UpdateUserImageURL(ctx context.Context, userID string) error
+	UploadUserImage(ctx context.Context, userID string, file *multipart.FileHeader) error
 	GetUserByID(ctx context.Context, userID string) (model.User, error)
 	GetAllUsers(userContext context.Context) ([]model.User, error)
 }
@@ -169,29 +187,170 @@ type LessonComments interface {
 	GetByID(ctx context.Context, commentID int) (model.CourseModuleLessonComment, error)
 }
 
-type S3Bucket interface {
+type ObjectStorage interface {
 	UploadFile(ctx context.Context, key string, file *multipart.FileHeader) error
 }
 
-func NewService(repo *repository.Repository, r *redis.Client, c config.AuthConfig, bucketName string) *Service {
+// StorageJanitor finds and deletes stored objects whose owners are gone.
+type StorageJanitor interface {
+	FindOrphans(ctx context.Context) ([]model.StoredObject, error)
+	Clean(ctx context.Context) (model.StorageCleanupReport, error)
+	Run(ctx context.Context)
+}
+
+// OutboxWorker performs the side effects written to the outbox.
+type OutboxWorker interface {
+	ProcessBatch(ctx context.Context) (int, error)
+	Run(ctx context.Context)
+}
+
+// Lockout protects the sign-in from brute force, the failed sign-ins are counted per account and per IP.
+type Lockout interface {
+	Check(ctx context.Context, email, ip string) error
+	Fail(ctx context.Context, email, ip string) error
+	Succeed(ctx context.Context, email string) error
+	Unlock(ctx context.Context, userID string) error
+}
+
+// TwoFactor is the second step of the sign-in with the codes of an authenticator app or the recovery codes.
+type TwoFactor interface {
+	Enroll(ctx context.Context, userID string) (model.TOTPEnrollment, error)
+	Enable(ctx context.Context, userID, code string) ([]string, error)
+	Disable(ctx context.Context, userID, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error)
+	Enabled(ctx context.Context, userID string) (bool, error)
+	NewChallenge(ctx context.Context, userID string) (model.MFAChallenge, error)
+	ChallengeUserID(ctx context.Context, token string) (string, error)
+	CompleteChallenge(ctx context.Context, token, code string) error
+	RequireForRole(role string, enabled bool) error
+}
+
+// SingleSignOn signs in the users with the OpenID Connect provider of the university.
+type SingleSignOn interface {
+	// AuthURL returns the URL of the provider and the state bound to the browser
+	AuthURL(ctx context.Context) (string, string, error)
+	// Callback returns the URL of the frontend with the one-time code to exchange
+	Callback(ctx context.Context, code, state string) (string, error)
+	Exchange(ctx context.Context, code string) (model.User, error)
+}
+
+// Notifications tell the users about what others did to their data, e.g. the invitations to their cards.
+type Notifications interface {
+	GetByUserID(ctx context.Context, userID string) ([]model.Notification, error)
+	MarkRead(ctx context.Context, userID, notificationID string) error
+}
+
+// PersonalAccessTokens authenticate the scripts of the users, a token is limited by its scopes and expires.
+type PersonalAccessTokens interface {
+	Create(ctx context.Context, userID string, input dto.CreatePersonalAccessToken) (model.NewPersonalAccessToken, error)
+	GetAll(ctx context.Context, userID string) ([]model.PersonalAccessToken, error)
+	Revoke(ctx context.Context, userID, tokenID string) error
+	Authenticate(ctx context.Context, token string) (model.Principal, error)
+}
+
+// Auditor records the administrative and security-sensitive actions with the actor of the context,
+// see ContextWithActor.
+type Auditor interface {
+	Record(ctx context.Context, action, targetType, targetID string, before, after interface{})
+	RecordTx(ctx context.Context, action, targetType, targetID string, before, after interface{}) error
+	GetEvents(ctx context.Context, input dto.GetAuditEvents) (model.AuditEventsPage, error)
+}
+
+// Settings holds the runtime settings, which are changed by admins without a restart.
+type Settings interface {
+	Current() model.RuntimeSettings
+	OnChange(fn func(model.RuntimeSettings))
+	Reload(ctx context.Context) error
+	Patch(ctx context.Context, userID string, patch dto.PatchSettings) (model.RuntimeSettings, error)
+	GetChanges(ctx context.Context) ([]model.SettingsChange, error)
+	Run(ctx context.Context)
+}
+
+// FeatureFlags gates the features per user, see model.FeatureFlag.
+type FeatureFlags interface {
+	IsEnabled(ctx context.Context, key, userID string) (bool, error)
+	GetAll(ctx context.Context) ([]model.FeatureFlag, error)
+	GetByKey(ctx context.Context, key string) (model.FeatureFlag, error)
+	Create(ctx context.Context, input dto.CreateFeatureFlag) error
+	Update(ctx context.Context, key string, input dto.UpdateFeatureFlag) error
+	Delete(ctx context.Context, key string) error
+}
+
+// RateLimiter limits the requests of the clients per policy, see constants.DefaultRateLimitPolicy.
+type RateLimiter interface {
+	Allow(ctx context.Context, policy, ip, userID string) (model.RateLimitResult, error)
+}
+
+// Health checks the dependencies of the app.
+type Health interface {
+	Check(ctx context.Context) model.HealthReport
+}
+
+// URLBuilder builds public URLs of the stored objects and keys for new uploads.
+type URLBuilder interface {
+	ObjectURL(key string) string
+	ImageURL(folder, imagePath string) string
+	VersionedKey(folder string, id int) (key string, imagePath string)
+}
+
+func NewService(
+	repo *repository.Repository,
+	r *redis.Client,
+	c config.AuthConfig,
+	k *jwks.KeySet,
+	sc config.StorageConfig,
+	oc config.OutboxConfig,
+	stc config.SettingsConfig,
+	fc config.FeatureFlagsConfig,
+	rc config.RateLimitConfig,
+	hc config.HealthConfig,
+	u URLBuilder,
+) *Service {
+	audit := NewAuditService(repo.AuditEvents)
+
 	service := &Service{
-		Authorization:   NewAuthService(repo.Users, repo.Roles, r, c),
-		S3BucketService: NewS3BucketService(repo.S3Bucket, bucketName),
-		Users:           NewUsersService(repo.Users),
-		Roles:           NewRolesService(repo.Roles, repo.Users),
-		Cards:           NewCardsService(repo.Cards, repo.Users),
-		Materials:       NewMaterialsService(repo.Materials, repo.Users),
-		Disciplines:     NewDisciplinesService(repo.Disciplines, repo.Projects),
-		Projects:        NewProjectsService(repo.Projects, repo.ProjectModules),
-		ProjectModules:  NewProjectModulesService(repo.ProjectModules),
-		Courses:         NewCoursesService(repo.Courses, repo.CourseModules),
-		CourseModules:   NewCourseModulesService(repo.CourseModules),
-		ModuleLessons:   NewCourseModuleLessonsService(repo.CourseLessons),
-		LessonComments:  NewCourseModuleLessonCommentsService(repo.CourseLessonComments),
-		Contests:        NewContestsService(repo.Contests),
+		Audit:          audit,
+		Authorization:  NewAuthService(repo.Users, repo.Roles, repo.TwoFactor, repo.Transactional, r, c, k, u),
+		Storage:        NewObjectStorageService(repo.ObjectStorage),
+		Roles:          NewRolesService(repo.Roles, repo.Users),
+		Cards:          NewCardsService(repo.Cards, repo.Users, repo.Outbox, repo.Transactional, u),
+		Materials:      NewMaterialsService(repo.Materials, repo.Users),
+		Disciplines:    NewDisciplinesService(repo.Disciplines, repo.Projects, repo.Transactional, u, audit),
+		Projects:       NewProjectsService(repo.Projects, repo.ProjectModules, u),
+		ProjectModules: NewProjectModulesService(repo.ProjectModules),
+		Courses:        NewCoursesService(repo.Courses, repo.CourseModules, repo.Transactional, u, audit),
+		CourseModules:  NewCourseModulesService(repo.CourseModules),
+		ModuleLessons:  NewCourseModuleLessonsService(repo.CourseLessons),
+		LessonComments: NewCourseModuleLessonCommentsService(repo.CourseLessonComments),
+		Contests:       NewContestsService(repo.Contests, repo.Transactional, audit),
+		Janitor:        NewStorageJanitorService(repo.StoredObjects, repo.ObjectStorage, sc.Janitor),
+		Settings:       NewSettingsService(repo.Settings, repo.SettingsChanges, repo.Transactional, audit, stc),
+		Health:         NewHealthService(repo.Health, repo.ObjectStorage, hc),
+		Lockout: NewLockoutService(
+			repo.LoginAttempts, repo.AccountLockouts, repo.Users, repo.Transactional, audit, c.Lockout),
+		TwoFactor: NewTwoFactorService(
+			repo.TwoFactor, repo.MFAChallenges, repo.Users, repo.Roles, repo.Transactional, audit, c.MFA),
+		SSO: NewSSOService(
+			repo.Identities, repo.OIDCStates, repo.Users, repo.Roles, repo.Transactional, c.OIDC),
+		Tokens: NewPersonalAccessTokensService(
+			repo.PersonalAccessTokens, repo.Roles, repo.TwoFactor, repo.Transactional, audit, c.Tokens),
+		Notifications: NewNotificationsService(repo.Notifications),
 	}
 
-	service.Articles = NewArticlesService(repo.Articles, repo.Users, service.S3BucketService, repo.Transactional)
+	service.Features = NewFeatureFlagsService(
+		repo.FeatureFlags, repo.FeatureFlagsCache, repo.Roles, repo.Transactional, audit, fc)
+	service.RateLimit = NewRateLimitService(repo.RateLimiter, service.Settings, rc)
+
+	outbox := NewOutboxService(repo.Outbox, oc)
+	outbox.Register(constants.FinalizeUploadEvent, newFinalizeUploadHandler(repo.StoredObjects, repo.ObjectStorage))
+	outbox.RegisterCompensation(constants.FinalizeUploadEvent, newFinalizeUploadCompensation(repo.StoredObjects))
+	outbox.Register(constants.SendNotificationEvent, newSendNotificationHandler(repo.Notifications))
+	service.Outbox = outbox
+
+	service.Users = NewUsersService(
+		repo.Users, repo.StoredObjects, repo.Outbox, repo.Transactional, service.Storage, u, audit)
+	service.Articles = NewArticlesService(
+		repo.Articles, repo.Users, repo.StoredObjects, repo.Outbox, service.Storage, repo.Transactional, u)
 
 	return service
 }
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"acsp/internal/config"
+	"acsp/internal/constants"
+	"acsp/internal/dto"
+	"acsp/internal/logging"
+	"acsp/internal/model"
+	"acsp/internal/repository"
+)
+
+// settingsChangesLimit is the number of the latest changes returned by GetChanges
+const settingsChangesLimit = 100
+
+// SettingsService implements the Settings interface.
+type SettingsService struct {
+	store     repository.Settings
+	changes   repository.SettingsChanges
+	txManager repository.Transactional
+	audit     Auditor
+	defaults  model.RuntimeSettings
+	interval  time.Duration
+
+	current atomic.Pointer[model.RuntimeSettings]
+
+	// mu serializes applying the settings, so that the listeners see the changes in order
+	mu        sync.Mutex
+	listeners []func(model.RuntimeSettings)
+}
+
+// NewSettingsService creates a new instance of SettingsService, the settings of the config are used until
+// the stored settings are loaded, see Reload.
+func NewSettingsService(
+	store repository.Settings,
+	changes repository.SettingsChanges,
+	t repository.Transactional,
+	a Auditor,
+	c config.SettingsConfig,
+) *SettingsService {
+	s := &SettingsService{
+		store:     store,
+		changes:   changes,
+		txManager: t,
+		audit:     a,
+		defaults: model.RuntimeSettings{
+			LogLevel:    c.LogLevel,
+			CORSOrigins: c.CORSOrigins,
+			RateLimit: model.RateLimitSettings{
+				Max:           c.RateLimitMax,
+				WindowSeconds: int(c.RateLimitWindow.Seconds()),
+			},
+		},
+		interval: c.ReloadInterval,
+	}
+
+	defaults := s.defaults
+	s.current.Store(&defaults)
+
+	return s
+}
+
+// Current returns the settings in effect.
+func (s *SettingsService) Current() model.RuntimeSettings {
+	return *s.current.Load()
+}
+
+// OnChange calls fn with the current settings and then after every change.
+func (s *SettingsService) OnChange(fn func(model.RuntimeSettings)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.listeners = append(s.listeners, fn)
+
+	fn(*s.current.Load())
+}
+
+// Reload applies the stored settings when they differ from the current ones.
+func (s *SettingsService) Reload(ctx context.Context) error {
+	stored, found, err := s.store.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		stored = s.defaults
+	}
+
+	if stored.Version != s.Current().Version {
+		s.apply(ctx, stored)
+	}
+
+	return nil
+}
+
+// Patch changes the stored settings, applies them to this instance and records the change.
+// Other instances apply the change on their next reload.
+func (s *SettingsService) Patch(
+	ctx context.Context, userID string, patch dto.PatchSettings) (model.RuntimeSettings, error) {
+	l := logging.LoggerFromContext(ctx).With(zap.String("userID", userID))
+
+	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return model.RuntimeSettings{}, errors.Wrap(err, "invalid user id")
+	}
+
+	var before model.RuntimeSettings
+
+	updated, err := s.store.Update(ctx,
+		func(current model.RuntimeSettings, found bool) (model.RuntimeSettings, error) {
+			if !found {
+				current = s.defaults
+			}
+
+			before = current
+
+			return patchSettings(current, patch), nil
+		})
+	if err != nil {
+		return model.RuntimeSettings{}, err
+	}
+
+	s.apply(ctx, updated)
+
+	change := model.SettingsChange{UserID: id}
+
+	change.Before, err = json.Marshal(before)
+	if err == nil {
+		change.After, err = json.Marshal(updated)
+	}
+
+	// The change and its audit event are recorded together, they show the same changes
+	if err == nil {
+		err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			err := s.changes.Add(ctx, change)
+			if err != nil {
+				return err
+			}
+
+			return s.audit.RecordTx(ctx, constants.AuditActionUpdate, constants.AuditTargetSettings,
+				constants.AuditSettingsID, before, updated)
+		})
+	}
+
+	// The settings are already changed in Redis, so they can't be rolled back with the record, and a failed
+	// record must not make the admin repeat the change
+	if err != nil {
+		l.Error("Error when recording the settings change", zap.Error(err))
+	}
+
+	return updated, nil
+}
+
+// GetChanges returns the latest changes of the settings, the newest first.
+func (s *SettingsService) GetChanges(ctx context.Context) ([]model.SettingsChange, error) {
+	return s.changes.GetLatest(ctx, settingsChangesLimit)
+}
+
+// Run reloads the settings every reload interval until the context is done.
+func (s *SettingsService) Run(ctx context.Context) {
+	l := logging.LoggerFromContext(ctx)
+
+	if s.interval <= 0 {
+		l.Info("Settings reload is disabled")
+
+		return
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			l.Info("Settings reload stopped")
+
+			return
+		case <-ticker.C:
+			err := s.Reload(ctx)
+			if err != nil {
+				l.Error("Error when reloading the settings", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (s *SettingsService) apply(ctx context.Context, settings model.RuntimeSettings) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// A concurrent reload may have applied a newer version already
+	if current := s.current.Load(); current.Version > settings.Version {
+		return
+	}
+
+	s.current.Store(&settings)
+
+	for _, fn := range s.listeners {
+		fn(settings)
+	}
+
+	logging.LoggerFromContext(ctx).Info("Settings applied", zap.Int64("version", settings.Version))
+}
+
+// patchSettings returns a copy of the settings with the fields of the patch
+func patchSettings(s model.RuntimeSettings, patch dto.PatchSettings) model.RuntimeSettings {
+	if patch.LogLevel != nil {
+		s.LogLevel = *patch.LogLevel
+	}
+
+	if patch.CORSOrigins != nil {
+		s.CORSOrigins = patch.CORSOrigins
+	}
+
+	if patch.RateLimit != nil {
+		if patch.RateLimit.Max != nil {
+			s.RateLimit.Max = *patch.RateLimit.Max
+		}
+
+		if patch.RateLimit.WindowSeconds != nil {
+			s.RateLimit.WindowSeconds = *patch.RateLimit.WindowSeconds
+		}
+	}
+
+	return s
+}
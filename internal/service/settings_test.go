@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"acsp/internal/config"
+	"acsp/internal/constants"
+	"acsp/internal/dto"
+	"acsp/internal/model"
+)
+
+type fakeSettings struct {
+	settings model.RuntimeSettings
+	found    bool
+}
+
+func (f *fakeSettings) Get(ctx context.Context) (model.RuntimeSettings, bool, error) {
+	return f.settings, f.found, nil
+}
+
+func (f *fakeSettings) Update(
+	ctx context.Context,
+	fn func(current model.RuntimeSettings, found bool) (model.RuntimeSettings, error),
+) (model.RuntimeSettings, error) {
+	updated, err := fn(f.settings, f.found)
+	if err != nil {
+		return model.RuntimeSettings{}, err
+	}
+
+	updated.Version++
+	f.settings, f.found = updated, true
+
+	return updated, nil
+}
+
+type fakeSettingsChanges struct {
+	changes []model.SettingsChange
+}
+
+func (f *fakeSettingsChanges) Add(ctx context.Context, change model.SettingsChange) error {
+	f.changes = append(f.changes, change)
+
+	return nil
+}
+
+func (f *fakeSettingsChanges) GetLatest(ctx context.Context, limit int) ([]model.SettingsChange, error) {
+	return f.changes, nil
+}
+
+var testSettingsConfig = config.SettingsConfig{
+	LogLevel:        "info",
+	CORSOrigins:     []string{"*"},
+	RateLimitMax:    100,
+	RateLimitWindow: time.Minute,
+}
+
+func TestSettingsService_Reload(t *testing.T) {
+	store := &fakeSettings{}
+	s := NewSettingsService(
+		store, &fakeSettingsChanges{}, fakeTransactional{}, NewAuditService(&fakeAuditEvents{}), testSettingsConfig)
+
+	var applied []string
+	s.OnChange(func(settings model.RuntimeSettings) { applied = append(applied, settings.LogLevel) })
+
+	// Missing settings keep the config
+	assert.NoError(t, s.Reload(context.Background()))
+	assert.Equal(t, "info", s.Current().LogLevel)
+	assert.Equal(t, 60, s.Current().RateLimit.WindowSeconds)
+
+	store.settings = model.RuntimeSettings{LogLevel: "debug", Version: 1}
+	store.found = true
+	assert.NoError(t, s.Reload(context.Background()))
+	assert.Equal(t, "debug", s.Current().LogLevel)
+
+	// The same version isn't applied twice
+	assert.NoError(t, s.Reload(context.Background()))
+	assert.Equal(t, []string{"info", "debug"}, applied)
+}
+
+func TestSettingsService_Patch(t *testing.T) {
+	store := &fakeSettings{}
+	changes := &fakeSettingsChanges{}
+	audit, events := newTestAuditService()
+	s := NewSettingsService(store, changes, fakeTransactional{}, audit, testSettingsConfig)
+
+	level := "warn"
+	limit := 10
+
+	updated, err := s.Patch(context.Background(), "7", dto.PatchSettings{
+		LogLevel:  &level,
+		RateLimit: &dto.PatchRateLimit{Max: &limit},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "warn", updated.LogLevel)
+	assert.Equal(t, model.RateLimitSettings{Max: 10, WindowSeconds: 60}, updated.RateLimit)
+	assert.Equal(t, []string{"*"}, updated.CORSOrigins)
+	assert.Equal(t, updated, s.Current())
+
+	origins := []string{"https://acsp.example.com"}
+
+	ctx := ContextWithActor(context.Background(), model.AuditActor{UserID: "7"})
+
+	_, err = s.Patch(ctx, "7", dto.PatchSettings{CORSOrigins: origins})
+	assert.NoError(t, err)
+	assert.Equal(t, origins, s.Current().CORSOrigins)
+	assert.Equal(t, "warn", s.Current().LogLevel)
+
+	if assert.Len(t, changes.changes, 2) {
+		var before, after model.RuntimeSettings
+
+		assert.NoError(t, json.Unmarshal(changes.changes[0].Before, &before))
+		assert.NoError(t, json.Unmarshal(changes.changes[0].After, &after))
+		assert.Equal(t, 7, changes.changes[0].UserID)
+		assert.Equal(t, "info", before.LogLevel)
+		assert.Equal(t, "warn", after.LogLevel)
+	}
+
+	// The audit log shows who changed the settings
+	if assert.Len(t, events.events, 2) {
+		assert.Equal(t, constants.AuditActionUpdate, events.events[1].Action)
+		assert.Equal(t, constants.AuditTargetSettings, events.events[1].TargetType)
+		assert.Equal(t, 7, *events.events[1].ActorID)
+		assert.Contains(t, string(events.events[1].Changes), `"cors_origins"`)
+	}
+
+	_, err = s.Patch(context.Background(), "admin", dto.PatchSettings{})
+	assert.Error(t, err)
+}
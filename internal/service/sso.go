@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"acsp/internal/apperror"
+	"acsp/internal/config"
+	"acsp/internal/logging"
+	"acsp/internal/model"
+	"acsp/internal/oidc"
+	"acsp/internal/repository"
+)
+
+// SSOService implements the SingleSignOn interface with an OpenID Connect provider.
+type SSOService struct {
+	provider   *oidc.Provider
+	identities repository.Identities
+	states     repository.OIDCStates
+	users      repository.Users
+	roles      repository.Roles
+	txManager  repository.Transactional
+	config     config.OIDCConfig
+}
+
+// NewSSOService creates a new instance of SSOService.
+func NewSSOService(
+	identities repository.Identities,
+	states repository.OIDCStates,
+	users repository.Users,
+	roles repository.Roles,
+	t repository.Transactional,
+	c config.OIDCConfig,
+) *SSOService {
+	return &SSOService{
+		provider: oidc.NewProvider(oidc.Config{
+			Issuer:       c.Issuer,
+			ClientID:     c.ClientID,
+			ClientSecret: c.ClientSecret,
+			RedirectURL:  c.RedirectURL,
+			Scopes:       c.Scopes,
+			GroupsClaim:  c.GroupsClaim,
+		}, nil),
+		identities: identities,
+		states:     states,
+		users:      users,
+		roles:      roles,
+		txManager:  t,
+		config:     c,
+	}
+}
+
+// AuthURL starts a sign-in at the provider and returns the URL the user is redirected to and the state,
+// the state is bound to the browser of the user to check the callback.
+func (s *SSOService) AuthURL(ctx context.Context) (string, string, error) {
+	if !s.config.Enabled {
+		return "", "", apperror.ErrSSODisabled
+	}
+
+	// The state, the nonce and the verifier are random, the state is the key of the others
+	var tokens [3]string
+	for i := range tokens {
+		t, err := oidc.RandomToken()
+		if err != nil {
+			return "", "", err
+		}
+
+		tokens[i] = t
+	}
+
+	state, nonce, verifier := tokens[0], tokens[1], tokens[2]
+
+	err := s.states.Save(ctx, state, model.OIDCState{Verifier: verifier, Nonce: nonce}, s.config.StateTTL)
+	if err != nil {
+		return "", "", err
+	}
+
+	authURL, err := s.provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return "", "", err
+	}
+
+	return authURL, state, nil
+}
+
+// Callback completes the sign-in at the provider and returns the URL of the frontend with a one-time code
+// of the user, the frontend exchanges it for the token pair. The user is linked to the identity by the email
+// or created on the first sign-in, the roles of the groups are granted.
+func (s *SSOService) Callback(ctx context.Context, code, state string) (string, error) {
+	if !s.config.Enabled {
+		return "", apperror.ErrSSODisabled
+	}
+
+	st, found, err := s.states.Take(ctx, state)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return "", apperror.ErrSSOStateNotFound
+	}
+
+	idToken, err := s.provider.Exchange(ctx, code, st.Verifier)
+	if err != nil {
+		return "", apperror.WithKind(err, apperror.KindUnauthorized)
+	}
+
+	claims, err := s.provider.Verify(ctx, idToken, st.Nonce)
+	if err != nil {
+		return "", apperror.WithKind(err, apperror.KindUnauthorized)
+	}
+
+	// A user is never left linked without the roles of the groups
+	var user model.User
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err = s.user(ctx, claims)
+		if err != nil {
+			return err
+		}
+
+		return s.grantRoles(ctx, user, claims.Groups)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return s.frontendURL(ctx, user)
+}
+
+// Exchange returns the user of the one-time code of a sign-in, a code is exchanged once.
+func (s *SSOService) Exchange(ctx context.Context, code string) (model.User, error) {
+	if !s.config.Enabled {
+		return model.User{}, apperror.ErrSSODisabled
+	}
+
+	userID, found, err := s.states.TakeCode(ctx, code)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	if !found {
+		return model.User{}, apperror.ErrSSOCodeNotFound
+	}
+
+	return s.users.GetByID(ctx, userID)
+}
+
+// frontendURL saves a one-time code of the user and returns the URL of the frontend with it. The tokens
+// aren't put in the URL, it is kept in the history of the browser and the logs of the proxies.
+func (s *SSOService) frontendURL(ctx context.Context, user model.User) (string, error) {
+	id, err := strconv.Atoi(user.ID)
+	if err != nil {
+		return "", err
+	}
+
+	code, err := oidc.RandomToken()
+	if err != nil {
+		return "", err
+	}
+
+	err = s.states.SaveCode(ctx, code, id, s.config.CodeTTL)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(s.config.FrontendURL)
+	if err != nil {
+		return "", err
+	}
+
+	q := u.Query()
+	q.Set("code", code)
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// user returns the user linked to the identity, the identity is linked on the first sign-in. It is run in the
+// transaction of the sign-in.
+func (s *SSOService) user(ctx context.Context, claims oidc.Claims) (model.User, error) {
+	id, err := s.identities.GetUserID(ctx, s.config.Issuer, claims.Subject)
+	if err == nil {
+		return s.users.GetByID(ctx, id)
+	}
+
+	if apperror.KindOf(err) != apperror.KindNotFound {
+		return model.User{}, err
+	}
+
+	// The accounts are linked by the email, so it has to be the one of the user
+	if !claims.EmailVerified {
+		return model.User{}, apperror.ErrSSOEmailNotVerified
+	}
+
+	email := strings.ToLower(claims.Email)
+	if s.config.EmailDomain != "" && !strings.HasSuffix(email, "@"+strings.ToLower(s.config.EmailDomain)) {
+		return model.User{}, apperror.ErrSSOEmailDomain
+	}
+
+	user, err := s.users.GetByEmail(ctx, email)
+	if apperror.KindOf(err) == apperror.KindNotFound {
+		user, err = s.createUser(ctx, email, claims.Name)
+	}
+
+	if err != nil {
+		return model.User{}, err
+	}
+
+	id, err = strconv.Atoi(user.ID)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	err = s.identities.Link(ctx, model.UserIdentity{
+		UserID:  id,
+		Issuer:  s.config.Issuer,
+		Subject: claims.Subject,
+		Email:   email,
+	})
+	if err != nil {
+		return model.User{}, err
+	}
+
+	logging.LoggerFromContext(ctx).Info("The identity is linked to the user",
+		zap.Int("userID", id), zap.String("subject", claims.Subject))
+
+	return *user, nil
+}
+
+// createUser creates the user of the identity. The password is random, the user signs in with the provider.
+func (s *SSOService) createUser(ctx context.Context, email, name string) (*model.User, error) {
+	password, err := oidc.RandomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := generatePasswordHash(password)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		name = email[:strings.Index(email, "@")]
+	}
+
+	err = s.users.CreateUser(ctx, model.User{Name: name, Email: email, Password: hash})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.users.GetByEmail(ctx, email)
+}
+
+// grantRoles grants the roles mapped to the groups the user doesn't have yet. The roles aren't revoked
+// when the user leaves a group, the admins revoke them.
+func (s *SSOService) grantRoles(ctx context.Context, user model.User, groups []string) error {
+	id, err := strconv.Atoi(user.ID)
+	if err != nil {
+		return err
+	}
+
+	current, err := s.roles.GetUserRoles(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	has := make(map[string]bool, len(current))
+	for _, r := range current {
+		has[r.Name] = true
+	}
+
+	for _, group := range groups {
+		name, ok := s.config.GroupRoles[group]
+		if !ok || has[name] {
+			continue
+		}
+
+		role, err := s.roles.GetRoleByName(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		roleID, err := strconv.Atoi(role.ID)
+		if err != nil {
+			return err
+		}
+
+		err = s.roles.SaveUserRole(ctx, id, roleID)
+		if err != nil {
+			return err
+		}
+
+		has[name] = true
+
+		logging.LoggerFromContext(ctx).Info("The role of the group is granted",
+			zap.Int("userID", id), zap.String("group", group), zap.String("role", name))
+	}
+
+	return nil
+}
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"acsp/internal/apperror"
+	"acsp/internal/config"
+	"acsp/internal/model"
+	"acsp/internal/oidc/oidctest"
+	"acsp/internal/repository"
+)
+
+type fakeTxKey struct{}
+
+// fakeSignInTransactional marks the context of the transaction, the writes of a sign-in check they are in it
+type fakeSignInTransactional struct{}
+
+func (fakeSignInTransactional) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(context.WithValue(ctx, fakeTxKey{}, true))
+}
+
+func inFakeTx(ctx context.Context) error {
+	if ctx.Value(fakeTxKey{}) == nil {
+		return fmt.Errorf("not in the transaction of the sign-in")
+	}
+
+	return nil
+}
+
+// fakeIdentities keeps the user ids of the subjects in memory
+type fakeIdentities struct {
+	users map[string]int
+}
+
+func (f *fakeIdentities) GetUserID(ctx context.Context, issuer, subject string) (int, error) {
+	id, ok := f.users[issuer+" "+subject]
+	if !ok {
+		return 0, apperror.WithKind(fmt.Errorf("no rows"), apperror.KindNotFound)
+	}
+
+	return id, nil
+}
+
+func (f *fakeIdentities) Link(ctx context.Context, identity model.UserIdentity) error {
+	if err := inFakeTx(ctx); err != nil {
+		return err
+	}
+
+	f.users[identity.Issuer+" "+identity.Subject] = identity.UserID
+
+	return nil
+}
+
+type fakeOIDCStates struct {
+	states map[string]model.OIDCState
+	codes  map[string]int
+}
+
+func (f *fakeOIDCStates) Save(ctx context.Context, state string, s model.OIDCState, ttl time.Duration) error {
+	f.states[state] = s
+
+	return nil
+}
+
+func (f *fakeOIDCStates) Take(ctx context.Context, state string) (model.OIDCState, bool, error) {
+	s, ok := f.states[state]
+	delete(f.states, state)
+
+	return s, ok, nil
+}
+
+func (f *fakeOIDCStates) SaveCode(ctx context.Context, code string, userID int, ttl time.Duration) error {
+	f.codes[code] = userID
+
+	return nil
+}
+
+func (f *fakeOIDCStates) TakeCode(ctx context.Context, code string) (int, bool, error) {
+	id, ok := f.codes[code]
+	delete(f.codes, code)
+
+	return id, ok, nil
+}
+
+// fakeSSOUsers keeps the users in memory, the ids are given in the order of creation
+type fakeSSOUsers struct {
+	repository.Users
+
+	users map[int]model.User
+}
+
+func (f *fakeSSOUsers) GetByID(ctx context.Context, id int) (model.User, error) {
+	u, ok := f.users[id]
+	if !ok {
+		return model.User{}, apperror.ErrUserNotFound
+	}
+
+	return u, nil
+}
+
+func (f *fakeSSOUsers) GetByEmail(ctx context.Context, email string) (*model.User, error) {
+	for _, u := range f.users {
+		if u.Email == email {
+			return &u, nil
+		}
+	}
+
+	return nil, apperror.ErrEmailNotFound
+}
+
+func (f *fakeSSOUsers) CreateUser(ctx context.Context, user model.User) error {
+	user.ID = strconv.Itoa(len(f.users) + 1)
+	f.users[len(f.users)+1] = user
+
+	return nil
+}
+
+type fakeSSORoles struct {
+	fakeRoles
+}
+
+func (f *fakeSSORoles) GetRoleByName(ctx context.Context, name string) (model.Role, error) {
+	if name != "admin" {
+		return model.Role{}, apperror.WithKind(fmt.Errorf("no rows"), apperror.KindNotFound)
+	}
+
+	return model.Role{ID: "2", Name: "admin"}, nil
+}
+
+func (f *fakeSSORoles) SaveUserRole(ctx context.Context, userID, roleID int) error {
+	if err := inFakeTx(ctx); err != nil {
+		return err
+	}
+
+	f.roles[userID] = append(f.roles[userID], model.Role{ID: strconv.Itoa(roleID), Name: "admin"})
+
+	return nil
+}
+
+var testSSOUser = oidctest.User{
+	Subject:       "s-42",
+	Email:         "Student@astanait.edu.kz",
+	EmailVerified: true,
+	Name:          "Student",
+}
+
+func newTestSSOService(t *testing.T) (*SSOService, *oidctest.Server, *fakeSSOUsers, *fakeSSORoles) {
+	idp, err := oidctest.NewServer("acsp", "secret", testSSOUser)
+	require.NoError(t, err)
+	t.Cleanup(idp.Close)
+
+	users := &fakeSSOUsers{users: map[int]model.User{
+		1: {ID: "1", Name: "Existing", Email: "existing@astanait.edu.kz"},
+	}}
+	roles := &fakeSSORoles{fakeRoles{roles: map[int][]model.Role{}}}
+
+	s := NewSSOService(&fakeIdentities{users: map[string]int{}}, &fakeOIDCStates{
+		states: map[string]model.OIDCState{},
+		codes:  map[string]int{},
+	},
+		users, roles, fakeSignInTransactional{}, config.OIDCConfig{
+			Enabled:      true,
+			Issuer:       idp.URL,
+			ClientID:     "acsp",
+			ClientSecret: "secret",
+			RedirectURL:  "http://localhost:8080/api/v1/auth/oidc/callback",
+			Scopes:       []string{"openid", "email", "profile"},
+			GroupsClaim:  "groups",
+			GroupRoles:   map[string]string{"acsp-admins": "admin"},
+			EmailDomain:  "astanait.edu.kz",
+			StateTTL:     time.Minute,
+			FrontendURL:  "http://localhost:3000/sign-in/sso?from=oidc",
+			CodeTTL:      time.Minute,
+		})
+
+	return s, idp, users, roles
+}
+
+// signIn signs in at the provider and returns the code and the state of the callback
+func signIn(t *testing.T, s *SSOService) (string, string) {
+	authURL, _, err := s.AuthURL(context.Background())
+	require.NoError(t, err)
+
+	client := &http.Client{CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+
+	resp, err := client.Get(authURL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+// callback signs in at the provider, completes the sign-in and exchanges the one-time code
+func callback(t *testing.T, s *SSOService) (model.User, error) {
+	code, state := signIn(t, s)
+
+	frontendURL, err := s.Callback(context.Background(), code, state)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	u, err := url.Parse(frontendURL)
+	require.NoError(t, err)
+
+	return s.Exchange(context.Background(), u.Query().Get("code"))
+}
+
+func TestSSOService_CreatesAndLinksUser(t *testing.T) {
+	s, idp, users, roles := newTestSSOService(t)
+
+	user, err := callback(t, s)
+	require.NoError(t, err)
+	assert.Equal(t, "2", user.ID)
+	assert.Equal(t, "student@astanait.edu.kz", user.Email)
+	assert.Equal(t, "Student", user.Name)
+	assert.Empty(t, roles.roles[2])
+
+	// The identity is linked, the user is found by the subject even when the email is changed
+	u := testSSOUser
+	u.Email = "renamed@astanait.edu.kz"
+	u.Groups = []string{"acsp-admins", "students"}
+	idp.SetUser(u)
+
+	user, err = callback(t, s)
+	require.NoError(t, err)
+	assert.Equal(t, "2", user.ID)
+	assert.Len(t, users.users, 2)
+	assert.Equal(t, []model.Role{{ID: "2", Name: "admin"}}, roles.roles[2])
+
+	// The granted roles aren't granted again
+	_, err = callback(t, s)
+	require.NoError(t, err)
+	assert.Len(t, roles.roles[2], 1)
+}
+
+func TestSSOService_LinksExistingUserByEmail(t *testing.T) {
+	s, idp, users, _ := newTestSSOService(t)
+
+	u := testSSOUser
+	u.Email = "existing@astanait.edu.kz"
+	idp.SetUser(u)
+
+	user, err := callback(t, s)
+	require.NoError(t, err)
+	assert.Equal(t, "1", user.ID)
+	assert.Len(t, users.users, 1)
+}
+
+func TestSSOService_RefusesUnknownEmails(t *testing.T) {
+	s, idp, users, _ := newTestSSOService(t)
+
+	u := testSSOUser
+	u.EmailVerified = false
+	idp.SetUser(u)
+
+	_, err := callback(t, s)
+	assert.ErrorIs(t, err, apperror.ErrSSOEmailNotVerified)
+
+	u = testSSOUser
+	u.Email = "someone@example.com"
+	idp.SetUser(u)
+
+	_, err = callback(t, s)
+	assert.ErrorIs(t, err, apperror.ErrSSOEmailDomain)
+	assert.Len(t, users.users, 1)
+}
+
+func TestSSOService_State(t *testing.T) {
+	ctx := context.Background()
+	s, _, _, _ := newTestSSOService(t)
+
+	code, state := signIn(t, s)
+
+	_, err := s.Callback(ctx, code, "unknown")
+	assert.ErrorIs(t, err, apperror.ErrSSOStateNotFound)
+
+	frontendURL, err := s.Callback(ctx, code, state)
+	require.NoError(t, err)
+
+	// The frontend gets a one-time code, not the tokens
+	u, err := url.Parse(frontendURL)
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:3000", u.Host)
+	assert.Equal(t, "/sign-in/sso", u.Path)
+	assert.Equal(t, "oidc", u.Query().Get("from"))
+
+	user, err := s.Exchange(ctx, u.Query().Get("code"))
+	require.NoError(t, err)
+	assert.Equal(t, "2", user.ID)
+
+	_, err = s.Exchange(ctx, u.Query().Get("code"))
+	assert.ErrorIs(t, err, apperror.ErrSSOCodeNotFound)
+
+	// The state is used once
+	_, err = s.Callback(ctx, code, state)
+	assert.ErrorIs(t, err, apperror.ErrSSOStateNotFound)
+
+	s.config.Enabled = false
+	_, _, err = s.AuthURL(ctx)
+	assert.ErrorIs(t, err, apperror.ErrSSODisabled)
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"acsp/internal/config"
+	"acsp/internal/logging"
+	"acsp/internal/model"
+	"acsp/internal/repository"
+)
+
+// StorageJanitorService implements the StorageJanitor interface.
+// It deletes the stored objects whose owning rows are deleted or point to another object.
+type StorageJanitorService struct {
+	objects repository.StoredObjects
+	storage repository.ObjectStorage
+	config  config.JanitorConfig
+	now     func() time.Time
+}
+
+// NewStorageJanitorService creates a new instance of StorageJanitorService.
+func NewStorageJanitorService(
+	o repository.StoredObjects, s repository.ObjectStorage, c config.JanitorConfig) *StorageJanitorService {
+	return &StorageJanitorService{objects: o, storage: s, config: c, now: time.Now}
+}
+
+// FindOrphans returns the orphaned objects older than the grace period without deleting them.
+func (j *StorageJanitorService) FindOrphans(ctx context.Context) ([]model.StoredObject, error) {
+	createdBefore := j.now().Add(-j.config.GracePeriod)
+
+	orphans, err := j.objects.GetOrphans(ctx, createdBefore, j.config.BatchSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when getting orphaned objects")
+	}
+
+	if orphans == nil {
+		orphans = []model.StoredObject{}
+	}
+
+	return orphans, nil
+}
+
+// Clean deletes a batch of orphaned objects from the storage and the registry.
+// In the dry-run mode the orphans are only reported.
+func (j *StorageJanitorService) Clean(ctx context.Context) (model.StorageCleanupReport, error) {
+	l := logging.LoggerFromContext(ctx).With(zap.Bool("dryRun", j.config.DryRun))
+
+	orphans, err := j.FindOrphans(ctx)
+	if err != nil {
+		return model.StorageCleanupReport{}, err
+	}
+
+	report := model.StorageCleanupReport{DryRun: j.config.DryRun, Orphans: orphans}
+
+	for _, o := range orphans {
+		ol := l.With(zap.String("objectKey", o.Key), zap.String("ownerType", o.OwnerType), zap.Int("ownerID", o.OwnerID))
+
+		if j.config.DryRun {
+			ol.Info("Found an orphaned object")
+
+			continue
+		}
+
+		// The object goes first, so that a failure leaves it in the registry for the next run
+		err := j.storage.DeleteObject(ctx, o.Key)
+		if err != nil {
+			ol.Error("Error when deleting an orphaned object from storage", zap.Error(err))
+			report.Failed++
+
+			continue
+		}
+
+		err = j.objects.Delete(ctx, o.Key)
+		if err != nil {
+			ol.Error("Error when deleting an orphaned object from registry", zap.Error(err))
+			report.Failed++
+
+			continue
+		}
+
+		ol.Info("Deleted an orphaned object")
+		report.Deleted++
+	}
+
+	return report, nil
+}
+
+// Run cleans the storage every interval until the context is done.
+func (j *StorageJanitorService) Run(ctx context.Context) {
+	l := logging.LoggerFromContext(ctx)
+
+	if j.config.Interval <= 0 {
+		l.Info("Storage janitor is disabled")
+
+		return
+	}
+
+	ticker := time.NewTicker(j.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			l.Info("Storage janitor stopped")
+
+			return
+		case <-ticker.C:
+			report, err := j.Clean(ctx)
+			if err != nil {
+				l.Error("Error when cleaning the storage", zap.Error(err))
+
+				continue
+			}
+
+			l.Info("Storage cleaned",
+				zap.Int("orphans", len(report.Orphans)),
+				zap.Int("deleted", report.Deleted),
+				zap.Int("failed", report.Failed))
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"acsp/internal/apperror"
+	"acsp/internal/config"
+	"acsp/internal/model"
+)
+
+type fakeStoredObjects struct {
+	orphans       []model.StoredObject
+	createdBefore time.Time
+	tracked       []model.StoredObject
+	deleted       []string
+	reset         []model.StoredObject
+}
+
+func (f *fakeStoredObjects) Track(ctx context.Context, object model.StoredObject) error {
+	f.tracked = append(f.tracked, object)
+
+	return nil
+}
+
+func (f *fakeStoredObjects) GetOrphans(
+	ctx context.Context, createdBefore time.Time, limit int) ([]model.StoredObject, error) {
+	f.createdBefore = createdBefore
+
+	return f.orphans, nil
+}
+
+func (f *fakeStoredObjects) Delete(ctx context.Context, key string) error {
+	f.deleted = append(f.deleted, key)
+
+	return nil
+}
+
+func (f *fakeStoredObjects) ResetOwnerImage(ctx context.Context, object model.StoredObject) error {
+	f.reset = append(f.reset, object)
+
+	return nil
+}
+
+type fakeObjectStorage struct {
+	objects map[string][]byte
+	failing map[string]bool
+	deleted []string
+}
+
+func (f *fakeObjectStorage) PutObject(ctx context.Context, key string, fileBytes []byte) error {
+	if f.objects == nil {
+		f.objects = map[string][]byte{}
+	}
+
+	f.objects[key] = fileBytes
+
+	return nil
+}
+
+func (f *fakeObjectStorage) CopyObject(ctx context.Context, srcKey, dstKey string) error {
+	b, ok := f.objects[srcKey]
+	if !ok {
+		return apperror.ErrObjectNotFound
+	}
+
+	return f.PutObject(ctx, dstKey, b)
+}
+
+func (f *fakeObjectStorage) HeadObject(ctx context.Context, key string) error {
+	if _, ok := f.objects[key]; !ok {
+		return apperror.ErrObjectNotFound
+	}
+
+	return nil
+}
+
+func (f *fakeObjectStorage) DeleteObject(ctx context.Context, key string) error {
+	if f.failing[key] {
+		return errors.New("storage is unavailable")
+	}
+
+	delete(f.objects, key)
+	f.deleted = append(f.deleted, key)
+
+	return nil
+}
+
+func (f *fakeObjectStorage) Ping(ctx context.Context) error {
+	return nil
+}
+
+func TestStorageJanitorService_Clean(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	orphans := []model.StoredObject{
+		{ID: 1, Key: "user-avatars/1/a", OwnerType: "user-avatars", OwnerID: 1},
+		{ID: 2, Key: "articles/2/b", OwnerType: "articles", OwnerID: 2},
+	}
+
+	tests := []struct {
+		name         string
+		dryRun       bool
+		failing      map[string]bool
+		wantDeleted  int
+		wantFailed   int
+		wantKeys     []string
+		wantRegistry []string
+	}{
+		{
+			name:         "Deletes orphans",
+			wantDeleted:  2,
+			wantKeys:     []string{"user-avatars/1/a", "articles/2/b"},
+			wantRegistry: []string{"user-avatars/1/a", "articles/2/b"},
+		},
+		{
+			name:   "Dry run keeps orphans",
+			dryRun: true,
+		},
+		{
+			name:         "Failed object stays in registry",
+			failing:      map[string]bool{"user-avatars/1/a": true},
+			wantDeleted:  1,
+			wantFailed:   1,
+			wantKeys:     []string{"articles/2/b"},
+			wantRegistry: []string{"articles/2/b"},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			objects := &fakeStoredObjects{orphans: orphans}
+			storage := &fakeObjectStorage{failing: testCase.failing}
+
+			j := NewStorageJanitorService(objects, storage, config.JanitorConfig{
+				GracePeriod: time.Hour,
+				BatchSize:   100,
+				DryRun:      testCase.dryRun,
+			})
+			j.now = func() time.Time { return now }
+
+			report, err := j.Clean(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.dryRun, report.DryRun)
+			assert.Equal(t, orphans, report.Orphans)
+			assert.Equal(t, testCase.wantDeleted, report.Deleted)
+			assert.Equal(t, testCase.wantFailed, report.Failed)
+			assert.Equal(t, testCase.wantKeys, storage.deleted)
+			assert.Equal(t, testCase.wantRegistry, objects.deleted)
+			assert.Equal(t, now.Add(-time.Hour), objects.createdBefore)
+		})
+	}
+}
@@ -0,0 +1,443 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"acsp/internal/apperror"
+	"acsp/internal/config"
+	"acsp/internal/constants"
+	"acsp/internal/logging"
+	"acsp/internal/model"
+	"acsp/internal/repository"
+	"acsp/internal/totp"
+)
+
+const (
+	// recoveryCodeAlphabet leaves out the characters that are easily confused, e.g. 0 and O
+	recoveryCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	recoveryCodeLength   = 10
+	// totpSkew accepts the codes of the previous and the next steps
+	totpSkew = 1
+)
+
+// twoFactorState is the audited state of the two-factor authentication of a user, the secret is never recorded
+type twoFactorState struct {
+	Enabled bool `json:"enabled"`
+}
+
+// TwoFactorService implements the TwoFactor interface.
+type TwoFactorService struct {
+	repo       repository.TwoFactor
+	challenges repository.MFAChallenges
+	users      repository.Users
+	roles      repository.Roles
+	txManager  repository.Transactional
+	audit      Auditor
+	config     config.MFAConfig
+}
+
+// NewTwoFactorService creates a new instance of TwoFactorService.
+func NewTwoFactorService(
+	repo repository.TwoFactor,
+	challenges repository.MFAChallenges,
+	users repository.Users,
+	roles repository.Roles,
+	t repository.Transactional,
+	a Auditor,
+	c config.MFAConfig,
+) *TwoFactorService {
+	return &TwoFactorService{
+		repo:       repo,
+		challenges: challenges,
+		users:      users,
+		roles:      roles,
+		txManager:  t,
+		audit:      a,
+		config:     c,
+	}
+}
+
+// Enroll generates a new secret of the user, it is enabled with a code of the authenticator app, see Enable.
+func (s *TwoFactorService) Enroll(ctx context.Context, userID string) (model.TOTPEnrollment, error) {
+	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return model.TOTPEnrollment{}, err
+	}
+
+	_, enabled, err := s.get(ctx, id)
+	if err != nil {
+		return model.TOTPEnrollment{}, err
+	}
+
+	if enabled {
+		return model.TOTPEnrollment{}, apperror.ErrTwoFactorEnabled
+	}
+
+	user, err := s.users.GetByID(ctx, id)
+	if err != nil {
+		return model.TOTPEnrollment{}, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return model.TOTPEnrollment{}, err
+	}
+
+	err = s.repo.SaveTOTP(ctx, id, secret)
+	if err != nil {
+		return model.TOTPEnrollment{}, err
+	}
+
+	return model.TOTPEnrollment{
+		Secret: secret,
+		URI:    totp.URI(s.config.Issuer, user.Email, secret),
+	}, nil
+}
+
+// Enable enables the enrolled authenticator of the user with its code and returns the recovery codes.
+// The recovery codes are shown once, only their hashes are stored.
+func (s *TwoFactorService) Enable(ctx context.Context, userID, code string) ([]string, error) {
+	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	authenticator, err := s.repo.GetTOTP(ctx, id)
+	if apperror.KindOf(err) == apperror.KindNotFound {
+		return nil, apperror.ErrTwoFactorNotEnrolled
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if authenticator.Enabled {
+		return nil, apperror.ErrTwoFactorEnabled
+	}
+
+	err = s.verify(ctx, authenticator, code, false)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes(s.config.RecoveryCodes)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.EnableTOTP(ctx, id); err != nil {
+			return err
+		}
+
+		if err := s.repo.ReplaceRecoveryCodes(ctx, id, hashes); err != nil {
+			return err
+		}
+
+		return s.audit.RecordTx(ctx, constants.AuditActionEnable, constants.AuditTargetTwoFactor, userID,
+			twoFactorState{Enabled: false}, twoFactorState{Enabled: true})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logging.LoggerFromContext(ctx).Info("Two-factor authentication is enabled", zap.Int("userID", id))
+
+	return codes, nil
+}
+
+// Disable removes the authenticator and the recovery codes of the user after checking a code. The users of
+// the roles requiring two-factor authentication can't disable it.
+func (s *TwoFactorService) Disable(ctx context.Context, userID, code string) error {
+	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return err
+	}
+
+	authenticator, enabled, err := s.get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !enabled {
+		return apperror.ErrTwoFactorNotEnabled
+	}
+
+	roles, err := s.roles.GetUserRoles(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	for _, role := range roles {
+		if s.required(role.Name) {
+			return apperror.ErrTwoFactorRequired
+		}
+	}
+
+	err = s.verify(ctx, authenticator, code, true)
+	if err != nil {
+		return err
+	}
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteTOTP(ctx, id); err != nil {
+			return err
+		}
+
+		return s.audit.RecordTx(ctx, constants.AuditActionDisable, constants.AuditTargetTwoFactor, userID,
+			twoFactorState{Enabled: true}, twoFactorState{Enabled: false})
+	})
+	if err != nil {
+		return err
+	}
+
+	logging.LoggerFromContext(ctx).Info("Two-factor authentication is disabled", zap.Int("userID", id))
+
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the user after checking a code of the authenticator.
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error) {
+	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	authenticator, enabled, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !enabled {
+		return nil, apperror.ErrTwoFactorNotEnabled
+	}
+
+	err = s.verify(ctx, authenticator, code, false)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes(s.config.RecoveryCodes)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.ReplaceRecoveryCodes(ctx, id, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Enabled tells whether the sign-ins of the user need a second step.
+func (s *TwoFactorService) Enabled(ctx context.Context, userID string) (bool, error) {
+	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return false, err
+	}
+
+	_, enabled, err := s.get(ctx, id)
+
+	return enabled, err
+}
+
+// NewChallenge starts the second step of the sign-in of the user, the token is completed with a code.
+func (s *TwoFactorService) NewChallenge(ctx context.Context, userID string) (model.MFAChallenge, error) {
+	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return model.MFAChallenge{}, err
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return model.MFAChallenge{}, err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	err = s.challenges.Create(ctx, token, id, s.config.ChallengeTTL)
+	if err != nil {
+		return model.MFAChallenge{}, err
+	}
+
+	return model.MFAChallenge{
+		MFARequired: true,
+		Token:       token,
+		ExpiresIn:   int(s.config.ChallengeTTL.Seconds()),
+	}, nil
+}
+
+// ChallengeUserID returns the user signing in with the challenge.
+func (s *TwoFactorService) ChallengeUserID(ctx context.Context, token string) (string, error) {
+	id, found, err := s.challenges.Get(ctx, token)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return "", apperror.ErrMFAChallengeNotFound
+	}
+
+	return strconv.Itoa(id), nil
+}
+
+// CompleteChallenge checks the code of the authenticator or a recovery code of the user of the challenge.
+// A challenge is completed once.
+func (s *TwoFactorService) CompleteChallenge(ctx context.Context, token, code string) error {
+	id, found, err := s.challenges.Get(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return apperror.ErrMFAChallengeNotFound
+	}
+
+	authenticator, enabled, err := s.get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !enabled {
+		return apperror.ErrTwoFactorNotEnabled
+	}
+
+	err = s.verify(ctx, authenticator, code, true)
+	if err != nil {
+		return err
+	}
+
+	completed, err := s.challenges.Complete(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	if !completed {
+		return apperror.ErrMFAChallengeNotFound
+	}
+
+	return nil
+}
+
+// RequireForRole returns an error when the role requires two-factor authentication and the user hasn't enabled it,
+// enabled is read from the access token, see model.Principal.
+func (s *TwoFactorService) RequireForRole(role string, enabled bool) error {
+	if s.required(role) && !enabled {
+		return apperror.ErrTwoFactorRequired
+	}
+
+	return nil
+}
+
+// get returns the authenticator of the user, enabled is false when the user has none.
+func (s *TwoFactorService) get(ctx context.Context, userID int) (model.TOTP, bool, error) {
+	authenticator, err := s.repo.GetTOTP(ctx, userID)
+	if apperror.KindOf(err) == apperror.KindNotFound {
+		return model.TOTP{}, false, nil
+	}
+
+	if err != nil {
+		return model.TOTP{}, false, err
+	}
+
+	return authenticator, authenticator.Enabled, nil
+}
+
+// verify checks the code of the authenticator, or a recovery code if they are allowed. A code is accepted once.
+func (s *TwoFactorService) verify(ctx context.Context, authenticator model.TOTP, code string, recovery bool) error {
+	code = normalizeCode(code)
+
+	if len(code) == totp.Digits {
+		step, ok := totp.Validate(authenticator.Secret, code, time.Now(), totpSkew)
+		if !ok {
+			return apperror.ErrInvalidMFACode
+		}
+
+		// The code can't be replayed while it is valid
+		fresh, err := s.challenges.UseCode(ctx, authenticator.UserID, step, (2*totpSkew+1)*totp.Period)
+		if err != nil {
+			return err
+		}
+
+		if !fresh {
+			return apperror.ErrInvalidMFACode
+		}
+
+		return nil
+	}
+
+	if !recovery || len(code) != recoveryCodeLength {
+		return apperror.ErrInvalidMFACode
+	}
+
+	used, err := s.repo.UseRecoveryCode(ctx, authenticator.UserID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+
+	if !used {
+		return apperror.ErrInvalidMFACode
+	}
+
+	logging.LoggerFromContext(ctx).Info("A recovery code is used", zap.Int("userID", authenticator.UserID))
+
+	return nil
+}
+
+func (s *TwoFactorService) required(role string) bool {
+	for _, r := range s.config.RequiredRoles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// generateRecoveryCodes returns n recovery codes formatted as XXXXX-XXXXX and their hashes.
+func generateRecoveryCodes(n int) ([]string, []string, error) {
+	codes := make([]string, 0, n)
+	hashes := make([]string, 0, n)
+
+	for i := 0; i < n; i++ {
+		b := make([]byte, recoveryCodeLength)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+
+		// The alphabet has 32 characters, so that every byte picks one uniformly
+		for j := range b {
+			b[j] = recoveryCodeAlphabet[b[j]%byte(len(recoveryCodeAlphabet))]
+		}
+
+		code := string(b)
+		codes = append(codes, code[:recoveryCodeLength/2]+"-"+code[recoveryCodeLength/2:])
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode hashes the normalized code. The codes are random, a fast hash can't be brute forced.
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(normalizeCode(code)))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeCode drops the separators the users type and upper cases the recovery codes.
+func normalizeCode(code string) string {
+	code = strings.NewReplacer(" ", "", "-", "").Replace(code)
+
+	return strings.ToUpper(code)
+}
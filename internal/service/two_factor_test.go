@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"acsp/internal/apperror"
+	"acsp/internal/config"
+	"acsp/internal/model"
+	"acsp/internal/totp"
+)
+
+// fakeTwoFactor keeps the authenticators and the hashes of the unused recovery codes in memory
+type fakeTwoFactor struct {
+	authenticators map[int]model.TOTP
+	recoveryCodes  map[int]map[string]bool
+}
+
+func (f *fakeTwoFactor) GetTOTP(ctx context.Context, userID int) (model.TOTP, error) {
+	a, ok := f.authenticators[userID]
+	if !ok {
+		return model.TOTP{}, apperror.WithKind(fmt.Errorf("no rows"), apperror.KindNotFound)
+	}
+
+	return a, nil
+}
+
+func (f *fakeTwoFactor) SaveTOTP(ctx context.Context, userID int, secret string) error {
+	if f.authenticators[userID].Enabled {
+		return nil
+	}
+
+	f.authenticators[userID] = model.TOTP{UserID: userID, Secret: secret}
+
+	return nil
+}
+
+func (f *fakeTwoFactor) EnableTOTP(ctx context.Context, userID int) error {
+	a := f.authenticators[userID]
+	a.Enabled = true
+	f.authenticators[userID] = a
+
+	return nil
+}
+
+func (f *fakeTwoFactor) DeleteTOTP(ctx context.Context, userID int) error {
+	delete(f.authenticators, userID)
+	delete(f.recoveryCodes, userID)
+
+	return nil
+}
+
+func (f *fakeTwoFactor) ReplaceRecoveryCodes(ctx context.Context, userID int, hashes []string) error {
+	f.recoveryCodes[userID] = map[string]bool{}
+	for _, h := range hashes {
+		f.recoveryCodes[userID][h] = true
+	}
+
+	return nil
+}
+
+func (f *fakeTwoFactor) UseRecoveryCode(ctx context.Context, userID int, hash string) (bool, error) {
+	if !f.recoveryCodes[userID][hash] {
+		return false, nil
+	}
+
+	delete(f.recoveryCodes[userID], hash)
+
+	return true, nil
+}
+
+type fakeMFAChallenges struct {
+	challenges map[string]int
+	usedCodes  map[string]bool
+}
+
+func (f *fakeMFAChallenges) Create(ctx context.Context, token string, userID int, ttl time.Duration) error {
+	f.challenges[token] = userID
+
+	return nil
+}
+
+func (f *fakeMFAChallenges) Get(ctx context.Context, token string) (int, bool, error) {
+	userID, ok := f.challenges[token]
+
+	return userID, ok, nil
+}
+
+func (f *fakeMFAChallenges) Complete(ctx context.Context, token string) (bool, error) {
+	_, ok := f.challenges[token]
+	delete(f.challenges, token)
+
+	return ok, nil
+}
+
+func (f *fakeMFAChallenges) UseCode(ctx context.Context, userID int, step int64, ttl time.Duration) (bool, error) {
+	key := fmt.Sprintf("%d:%d", userID, step)
+	if f.usedCodes[key] {
+		return false, nil
+	}
+
+	f.usedCodes[key] = true
+
+	return true, nil
+}
+
+// fakeTransactional runs the functions without a transaction
+type fakeTransactional struct{}
+
+func (fakeTransactional) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func newTestTwoFactorService() (*TwoFactorService, *fakeTwoFactor) {
+	repo := &fakeTwoFactor{authenticators: map[int]model.TOTP{}, recoveryCodes: map[int]map[string]bool{}}
+	challenges := &fakeMFAChallenges{challenges: map[string]int{}, usedCodes: map[string]bool{}}
+	users := &fakeLockoutUsers{users: map[int]model.User{
+		1: {ID: "1", Email: "user@example.com"},
+		2: {ID: "2", Email: "admin@example.com"},
+	}}
+	roles := &fakeRoles{roles: map[int][]model.Role{2: {{ID: "2", Name: "admin"}}}}
+
+	audit := NewAuditService(&fakeAuditEvents{})
+
+	return NewTwoFactorService(repo, challenges, users, roles, fakeTransactional{}, audit, config.MFAConfig{
+		Issuer:        "ACSP",
+		ChallengeTTL:  5 * time.Minute,
+		RecoveryCodes: 3,
+		RequiredRoles: []string{"admin"},
+	}), repo
+}
+
+// code returns the current code of the secret, offset by steps so that a test can use several codes
+func code(t *testing.T, secret string, steps int64) string {
+	c, err := totp.Code(secret, totp.Step(time.Now())+steps)
+	require.NoError(t, err)
+
+	return c
+}
+
+func TestTwoFactorService_EnableAndSignIn(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestTwoFactorService()
+
+	enrollment, err := s.Enroll(ctx, "1")
+	require.NoError(t, err)
+	assert.Contains(t, enrollment.URI, "otpauth://totp/ACSP:user@example.com?")
+
+	_, err = s.Enable(ctx, "1", "000000")
+	assert.ErrorIs(t, err, apperror.ErrInvalidMFACode)
+
+	codes, err := s.Enable(ctx, "1", code(t, enrollment.Secret, 0))
+	require.NoError(t, err)
+	assert.Len(t, codes, 3)
+
+	_, err = s.Enroll(ctx, "1")
+	assert.ErrorIs(t, err, apperror.ErrTwoFactorEnabled)
+
+	enabled, err := s.Enabled(ctx, "1")
+	require.NoError(t, err)
+	assert.True(t, enabled)
+
+	challenge, err := s.NewChallenge(ctx, "1")
+	require.NoError(t, err)
+	assert.True(t, challenge.MFARequired)
+
+	userID, err := s.ChallengeUserID(ctx, challenge.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "1", userID)
+
+	// The code that enabled the authenticator can't be replayed
+	err = s.CompleteChallenge(ctx, challenge.Token, code(t, enrollment.Secret, 0))
+	assert.ErrorIs(t, err, apperror.ErrInvalidMFACode)
+
+	require.NoError(t, s.CompleteChallenge(ctx, challenge.Token, code(t, enrollment.Secret, -1)))
+
+	// A challenge is completed once
+	err = s.CompleteChallenge(ctx, challenge.Token, code(t, enrollment.Secret, 1))
+	assert.ErrorIs(t, err, apperror.ErrMFAChallengeNotFound)
+}
+
+func TestTwoFactorService_RecoveryCodes(t *testing.T) {
+	ctx := context.Background()
+	s, repo := newTestTwoFactorService()
+
+	enrollment, err := s.Enroll(ctx, "1")
+	require.NoError(t, err)
+
+	codes, err := s.Enable(ctx, "1", code(t, enrollment.Secret, 0))
+	require.NoError(t, err)
+	assert.Regexp(t, `^[A-Z2-9]{5}-[A-Z2-9]{5}$`, codes[0])
+
+	// Only the hashes are stored
+	assert.NotContains(t, repo.recoveryCodes[1], codes[0])
+
+	challenge, err := s.NewChallenge(ctx, "1")
+	require.NoError(t, err)
+
+	// The recovery codes are typed without the separator and in lower case
+	recoveryCode := strings.ToLower(strings.ReplaceAll(codes[0], "-", ""))
+	require.NoError(t, s.CompleteChallenge(ctx, challenge.Token, recoveryCode))
+
+	challenge, err = s.NewChallenge(ctx, "1")
+	require.NoError(t, err)
+
+	err = s.CompleteChallenge(ctx, challenge.Token, codes[0])
+	assert.ErrorIs(t, err, apperror.ErrInvalidMFACode)
+
+	require.NoError(t, s.Disable(ctx, "1", codes[1]))
+
+	enabled, err := s.Enabled(ctx, "1")
+	require.NoError(t, err)
+	assert.False(t, enabled)
+}
+
+func TestTwoFactorService_RequireForRole(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestTwoFactorService()
+
+	assert.NoError(t, s.RequireForRole("user", false))
+	assert.ErrorIs(t, s.RequireForRole("admin", false), apperror.ErrTwoFactorRequired)
+	assert.NoError(t, s.RequireForRole("admin", true))
+
+	enrollment, err := s.Enroll(ctx, "2")
+	require.NoError(t, err)
+
+	_, err = s.Enable(ctx, "2", code(t, enrollment.Secret, 0))
+	require.NoError(t, err)
+
+	// The admins can't disable two-factor authentication
+	assert.ErrorIs(t, s.Disable(ctx, "2", code(t, enrollment.Secret, 1)), apperror.ErrTwoFactorRequired)
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"mime/multipart"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"acsp/internal/apperror"
+	"acsp/internal/constants"
+	"acsp/internal/logging"
+	"acsp/internal/model"
+	"acsp/internal/repository"
+)
+
+// finalizeUploadPayload is the payload of constants.FinalizeUploadEvent.
+type finalizeUploadPayload struct {
+	StagingKey string `json:"staging_key"`
+	Key        string `json:"key"`
+	OwnerType  string `json:"owner_type"`
+	OwnerID    int    `json:"owner_id"`
+}
+
+// stageUpload uploads a file under a new staging key before its owner is written.
+// The staged object is tracked, so that it is cleaned up if its owner is never committed.
+func stageUpload(
+	ctx context.Context,
+	objects repository.StoredObjects,
+	storage ObjectStorage,
+	ownerID int,
+	file *multipart.FileHeader,
+) (string, error) {
+	b := make([]byte, 16)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", errors.Wrap(err, "error when generating staging key")
+	}
+
+	key := constants.StagingFolder + "/" + hex.EncodeToString(b)
+
+	err = objects.Track(ctx, model.StoredObject{Key: key, OwnerType: constants.StagingFolder, OwnerID: ownerID})
+	if err != nil {
+		return "", errors.Wrap(err, "error when tracking staged upload")
+	}
+
+	err = storage.UploadFile(ctx, key, file)
+	if err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+// newFinalizeUploadEvent creates the event moving a staged upload to the final key of its owner.
+func newFinalizeUploadEvent(stagingKey, key, ownerType string, ownerID int) (model.OutboxEvent, error) {
+	return newOutboxEvent(constants.FinalizeUploadEvent, constants.FinalizeUploadEvent+":"+key, finalizeUploadPayload{
+		StagingKey: stagingKey,
+		Key:        key,
+		OwnerType:  ownerType,
+		OwnerID:    ownerID,
+	})
+}
+
+// newFinalizeUploadHandler creates the handler of constants.FinalizeUploadEvent.
+// It copies the staged object to its final key, tracks it and deletes the staged object.
+func newFinalizeUploadHandler(objects repository.StoredObjects, storage repository.ObjectStorage) OutboxHandler {
+	return func(ctx context.Context, payload json.RawMessage) error {
+		var p finalizeUploadPayload
+
+		err := json.Unmarshal(payload, &p)
+		if err != nil {
+			return errors.Wrap(err, "error when decoding finalize upload payload")
+		}
+
+		l := logging.LoggerFromContext(ctx).With(zap.String("stagingKey", p.StagingKey), zap.String("objectKey", p.Key))
+
+		err = objects.Track(ctx, model.StoredObject{Key: p.Key, OwnerType: p.OwnerType, OwnerID: p.OwnerID})
+		if err != nil {
+			return errors.Wrap(err, "error when tracking uploaded object")
+		}
+
+		// The staged object is deleted only after a successful copy, so a missing one was moved by a previous
+		// attempt, unless the final object is missing as well and the upload is lost
+		err = storage.CopyObject(ctx, p.StagingKey, p.Key)
+		if errors.Is(err, apperror.ErrObjectNotFound) {
+			err = storage.HeadObject(ctx, p.Key)
+			if err != nil {
+				return errors.Wrap(err, "error when checking moved upload")
+			}
+
+			l.Warn("Staged upload is already moved")
+		} else if err != nil {
+			return errors.Wrap(err, "error when copying staged upload")
+		}
+
+		err = storage.DeleteObject(ctx, p.StagingKey)
+		if err != nil {
+			return errors.Wrap(err, "error when deleting staged upload")
+		}
+
+		err = objects.Delete(ctx, p.StagingKey)
+		if err != nil {
+			return errors.Wrap(err, "error when untracking staged upload")
+		}
+
+		return nil
+	}
+}
+
+// newFinalizeUploadCompensation creates the compensation of constants.FinalizeUploadEvent. The owner points
+// to the final key since its transaction, so it is pointed back to the default image once the upload is given up.
+// The staged and the final objects are left to the storage janitor, they are orphans now.
+func newFinalizeUploadCompensation(objects repository.StoredObjects) OutboxHandler {
+	return func(ctx context.Context, payload json.RawMessage) error {
+		var p finalizeUploadPayload
+
+		err := json.Unmarshal(payload, &p)
+		if err != nil {
+			return errors.Wrap(err, "error when decoding finalize upload payload")
+		}
+
+		err = objects.ResetOwnerImage(ctx, model.StoredObject{Key: p.Key, OwnerType: p.OwnerType, OwnerID: p.OwnerID})
+		if err != nil {
+			return errors.Wrap(err, "error when resetting the image of the owner")
+		}
+
+		logging.LoggerFromContext(ctx).Warn("The image of the owner is reset after the upload failed",
+			zap.String("objectKey", p.Key), zap.String("ownerType", p.OwnerType), zap.Int("ownerID", p.OwnerID))
+
+		return nil
+	}
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"acsp/internal/config"
+)
+
+// ObjectURLBuilder implements the URLBuilder interface.
+type ObjectURLBuilder struct {
+	baseURL string
+	now     func() time.Time
+}
+
+// NewURLBuilder creates a new instance of ObjectURLBuilder.
+//
+// The base URL is taken from STORAGE_PUBLIC_URL (a CDN) when it is set, otherwise it is derived from the
+// storage driver: the static route of the app for the local storage or the virtual-hosted bucket URL for S3.
+func NewURLBuilder(c *config.Config) (*ObjectURLBuilder, error) {
+	base := c.Storage.PublicURL
+	if base == "" {
+		switch c.Storage.Driver {
+		case config.StorageDriverLocal:
+			base = "http://" + net.JoinHostPort(c.HTTP.Host, c.HTTP.Port) + c.Storage.LocalRoute
+
+		default:
+			base = bucketURL(c.Bucket.BucketName, c.Bucket.Endpoint)
+		}
+	}
+
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public url of objects %q: %w", base, err)
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("public url of objects %q must be absolute", base)
+	}
+
+	return &ObjectURLBuilder{
+		baseURL: strings.TrimSuffix(u.String(), "/"),
+		now:     time.Now,
+	}, nil
+}
+
+// ObjectURL returns the public URL of an object by its key.
+func (b *ObjectURLBuilder) ObjectURL(key string) string {
+	return b.baseURL + "/" + strings.TrimPrefix(key, "/")
+}
+
+// ImageURL returns the public URL of an image stored in the folder by its path (the image_url column).
+// Paths that are already absolute URLs are returned as is.
+func (b *ObjectURLBuilder) ImageURL(folder, imagePath string) string {
+	if imagePath == "" || strings.HasPrefix(imagePath, "http://") || strings.HasPrefix(imagePath, "https://") {
+		return imagePath
+	}
+
+	return b.ObjectURL(folder + "/" + strings.TrimPrefix(imagePath, "/"))
+}
+
+// VersionedKey generates a new object key of the entity in the folder and the image path to be saved
+// in the image_url column. Every call returns a new version, so that replaced images bypass caches.
+func (b *ObjectURLBuilder) VersionedKey(folder string, id int) (key string, imagePath string) {
+	imagePath = "/" + strconv.Itoa(id) + "/" + strconv.FormatInt(b.now().UnixNano(), 36)
+
+	return folder + imagePath, imagePath
+}
+
+// bucketURL builds a virtual-hosted style URL of the bucket, the endpoint may be given with or without a scheme.
+func bucketURL(bucket, endpoint string) string {
+	scheme := "https"
+	if i := strings.Index(endpoint, "://"); i >= 0 {
+		scheme, endpoint = endpoint[:i], endpoint[i+3:]
+	}
+
+	return scheme + "://" + bucket + "." + strings.TrimSuffix(endpoint, "/")
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"acsp/internal/config"
+)
+
+func TestNewURLBuilder(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *config.Config
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "S3 endpoint without scheme",
+			config: &config.Config{
+				Storage: &config.StorageConfig{Driver: config.StorageDriverS3},
+				Bucket:  &config.S3Config{BucketName: "acsp-avatars", Endpoint: "object.pscloud.io"},
+			},
+			want: "https://acsp-avatars.object.pscloud.io/articles/1",
+		},
+		{
+			name: "S3 endpoint with scheme",
+			config: &config.Config{
+				Storage: &config.StorageConfig{Driver: config.StorageDriverS3},
+				Bucket:  &config.S3Config{BucketName: "bucket", Endpoint: "http://minio:9000/"},
+			},
+			want: "http://bucket.minio:9000/articles/1",
+		},
+		{
+			name: "Local storage",
+			config: &config.Config{
+				HTTP:    &config.HTTPConfig{Host: "localhost", Port: "8080"},
+				Storage: &config.StorageConfig{Driver: config.StorageDriverLocal, LocalRoute: "/storage"},
+			},
+			want: "http://localhost:8080/storage/articles/1",
+		},
+		{
+			name: "CDN overrides the storage",
+			config: &config.Config{
+				Storage: &config.StorageConfig{Driver: config.StorageDriverS3, PublicURL: "https://cdn.example.com/"},
+				Bucket:  &config.S3Config{BucketName: "bucket", Endpoint: "object.pscloud.io"},
+			},
+			want: "https://cdn.example.com/articles/1",
+		},
+		{
+			name: "Relative public URL",
+			config: &config.Config{
+				Storage: &config.StorageConfig{Driver: config.StorageDriverLocal, PublicURL: "/storage"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			b, err := NewURLBuilder(testCase.config)
+			if testCase.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want, b.ImageURL("articles", "/1"))
+		})
+	}
+}
+
+func TestObjectURLBuilder_ImageURL(t *testing.T) {
+	b := &ObjectURLBuilder{baseURL: "https://cdn.example.com"}
+
+	tests := []struct {
+		name      string
+		imagePath string
+		want      string
+	}{
+		{name: "Default image", imagePath: "/default", want: "https://cdn.example.com/disciplines/default"},
+		{name: "Versioned image", imagePath: "/7/abc", want: "https://cdn.example.com/disciplines/7/abc"},
+		{name: "Absolute URL", imagePath: "https://example.com/a.png", want: "https://example.com/a.png"},
+		{name: "Empty path", imagePath: "", want: ""},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, b.ImageURL("disciplines", testCase.imagePath))
+		})
+	}
+}
+
+func TestObjectURLBuilder_VersionedKey(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	b := &ObjectURLBuilder{baseURL: "https://cdn.example.com", now: func() time.Time { return now }}
+
+	version := strconv.FormatInt(now.UnixNano(), 36)
+
+	key, imagePath := b.VersionedKey("user-avatars", 42)
+	assert.Equal(t, "user-avatars/42/"+version, key)
+	assert.Equal(t, "/42/"+version, imagePath)
+	assert.Equal(t, "https://cdn.example.com/"+key, b.ImageURL("user-avatars", imagePath))
+
+	now = now.Add(time.Second)
+	nextKey, _ := b.VersionedKey("user-avatars", 42)
+	assert.NotEqual(t, key, nextKey)
+}
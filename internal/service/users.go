@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"mime/multipart"
 	"strconv"
 
 	"go.uber.org/zap"
@@ -14,16 +15,51 @@ import (
 )
 
 type UserService struct {
-	repo repository.Users
+	repo      repository.Users
+	objects   repository.StoredObjects
+	outbox    repository.Outbox
+	txManager repository.Transactional
+	storage   ObjectStorage
+	urls      URLBuilder
+	audit     Auditor
 }
 
-func (u UserService) DeleteUser(ctx context.Context, userID string) error {
-	// TODO implement me
-	panic("implement me")
+func NewUsersService(
+	repo repository.Users,
+	o repository.StoredObjects,
+	ob repository.Outbox,
+	t repository.Transactional,
+	s ObjectStorage,
+	u URLBuilder,
+	a Auditor,
+) *UserService {
+	return &UserService{repo: repo, objects: o, outbox: ob, txManager: t, storage: s, urls: u, audit: a}
 }
 
-func NewUsersService(repo repository.Users) *UserService {
-	return &UserService{repo: repo}
+// DeleteUser deletes a user, the avatar of the user is deleted later by the storage janitor
+func (u UserService) DeleteUser(ctx context.Context, userID string) error {
+	l := logging.LoggerFromContext(ctx).With(zap.String("userID", userID))
+
+	userId, err := strconv.Atoi(userID)
+	if err != nil {
+		l.Error("Error converting string to int", zap.Error(err))
+
+		return err
+	}
+
+	before, err := u.repo.GetByID(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	return u.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := u.repo.Delete(ctx, userId)
+		if err != nil {
+			return err
+		}
+
+		return u.audit.RecordTx(ctx, constants.AuditActionDelete, constants.AuditTargetUser, userID, before, nil)
+	})
 }
 
 func (u UserService) GetAllUsers(ctx context.Context) ([]model.User, error) {
@@ -76,13 +112,40 @@ func (u UserService) GetUserByID(ctx context.Context, userID string) (model.User
 	return user, nil
 }
 
-func (u UserService) UpdateUserImageURL(ctx context.Context, userID string) error {
+// UploadUserImage uploads a new avatar of the user under a new versioned key and points the user to it.
+// The avatar is staged first and moved to its key by the outbox worker once the user is updated.
+func (u UserService) UploadUserImage(ctx context.Context, userID string, file *multipart.FileHeader) error {
+	l := logging.LoggerFromContext(ctx).With(zap.String("userID", userID))
+
 	userId, err := strconv.Atoi(userID)
 	if err != nil {
 		return err
 	}
 
-	return u.repo.UpdateImageURL(ctx, userId)
+	stagingKey, err := stageUpload(ctx, u.objects, u.storage, userId, file)
+	if err != nil {
+		l.Error("Error uploading user image to object storage", zap.Error(err))
+
+		return err
+	}
+
+	key, imagePath := u.urls.VersionedKey(constants.UsersAvatarsFolder, userId)
+
+	event, err := newFinalizeUploadEvent(stagingKey, key, constants.UsersAvatarsFolder, userId)
+	if err != nil {
+		return err
+	}
+
+	return u.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := u.repo.UpdateImageURL(ctx, userId, imagePath)
+		if err != nil {
+			l.Error("Error updating user image url", zap.Error(err))
+
+			return err
+		}
+
+		return u.outbox.Enqueue(ctx, event)
+	})
 }
 
 func (u UserService) UpdateUser(ctx context.Context, userID string, dto dto.UpdateUser) error {
@@ -91,6 +154,11 @@ func (u UserService) UpdateUser(ctx context.Context, userID string, dto dto.Upda
 		return err
 	}
 
+	before, err := u.repo.GetUserDetailsByUserId(ctx, userId)
+	if err != nil {
+		return err
+	}
+
 	userDetails := model.UserDetails{
 		FirstName:      dto.FirstName,
 		LastName:       dto.LastName,
@@ -98,27 +166,33 @@ func (u UserService) UpdateUser(ctx context.Context, userID string, dto dto.Upda
 		Specialization: dto.Specialization,
 	}
 
-	return u.repo.UpdateDetails(ctx, userId, userDetails)
+	return u.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := u.repo.UpdateDetails(ctx, userId, userDetails)
+		if err != nil {
+			return err
+		}
+
+		after, err := u.repo.GetUserDetailsByUserId(ctx, userId)
+		if err != nil {
+			return err
+		}
+
+		return u.audit.RecordTx(ctx, constants.AuditActionUpdate, constants.AuditTargetUser, userID, before, after)
+	})
 }
 
-// getFullURLForArticles function gets a slice of articles and changes every article's image_url to a full url
+// getFullURLForUsers function gets a slice of users and changes every user's image_url to a full url
 func (u UserService) getFullURLForUsers(users []model.User) []model.User {
 	for i := range users {
-		users[i].ImageURL = constants.BucketName + "." +
-			constants.EndPoint + "/" +
-			constants.UsersAvatarsFolder +
-			users[i].ImageURL
+		users[i].ImageURL = u.urls.ImageURL(constants.UsersAvatarsFolder, users[i].ImageURL)
 	}
 
 	return users
 }
 
-// getFullUrl function gets an article and changes its image_url to a full url
+// getFullUrl function gets a user and changes its image_url to a full url
 func (u UserService) getFullUrl(user model.User) model.User {
-	user.ImageURL = constants.BucketName + "." +
-		constants.EndPoint + "/" +
-		constants.UsersAvatarsFolder +
-		user.ImageURL
+	user.ImageURL = u.urls.ImageURL(constants.UsersAvatarsFolder, user.ImageURL)
 
 	return user
 }
@@ -0,0 +1,74 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The secret of the SHA1 test vectors of RFC 6238
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	// The last 6 digits of the 8 digit codes of the RFC
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got, tt.unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	step, ok := Validate(rfcSecret, "050471", now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now), step)
+
+	// The code of the previous step is accepted within the skew
+	_, ok = Validate(rfcSecret, "050471", now.Add(Period), 1)
+	assert.True(t, ok)
+
+	_, ok = Validate(rfcSecret, "050471", now.Add(2*Period), 1)
+	assert.False(t, ok)
+
+	_, ok = Validate(rfcSecret, "123456", now, 1)
+	assert.False(t, ok)
+
+	_, ok = Validate(rfcSecret, "0504", now, 1)
+	assert.False(t, ok)
+}
+
+func TestGenerateSecretAndURI(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	other, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+
+	u, err := url.Parse(URI("ACSP", "user@example.com", secret))
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", u.Scheme)
+	assert.Equal(t, "totp", u.Host)
+	assert.Equal(t, "/ACSP:user@example.com", u.Path)
+	assert.Equal(t, secret, u.Query().Get("secret"))
+	assert.Equal(t, "ACSP", u.Query().Get("issuer"))
+}
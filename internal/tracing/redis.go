@@ -0,0 +1,54 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-redis/redis/v9"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RedisHook records a span per redis command, see redis.Client.AddHook. The arguments aren't recorded,
+// they carry e.g. the refresh tokens.
+type RedisHook struct{}
+
+func (RedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (RedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span := Start(ctx, "redis "+cmd.Name(), trace.SpanKindClient,
+			semconv.DBSystemRedis, semconv.DBOperation(cmd.Name()))
+
+		err := next(ctx, cmd)
+
+		End(span, redisError(err))
+
+		return err
+	}
+}
+
+func (RedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span := Start(ctx, "redis pipeline", trace.SpanKindClient,
+			semconv.DBSystemRedis, semconv.DBOperation("pipeline"))
+		span.SetAttributes(pipelineLength.Int(len(cmds)))
+
+		err := next(ctx, cmds)
+
+		End(span, redisError(err))
+
+		return err
+	}
+}
+
+// redisError doesn't treat a missing key as an error
+func redisError(err error) error {
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,140 @@
+// Package tracing records OpenTelemetry spans of the requests, the queries and the calls of redis and S3.
+package tracing
+
+import (
+	"context"
+	"os"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"acsp/internal/config"
+)
+
+const (
+	serviceName         = "acsp-backend"
+	instrumentationName = "acsp"
+)
+
+// pipelineLength is the number of the commands of a redis pipeline
+const pipelineLength = attribute.Key("db.redis.pipeline_length")
+
+// NewProvider sets up the global tracer provider with the exporter of the config. The returned function
+// flushes the spans left and stops the exporter, it must be called before the app exits.
+func NewProvider(ctx context.Context, c config.TracingConfig, environment string) (func(context.Context) error, error) {
+	// The trace context of the callers is kept even when the app doesn't record spans
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+
+	if c.Exporter == config.TracingExporterNone {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, closeExporter, err := newExporter(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(c.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(serviceName),
+			semconv.DeploymentEnvironment(environment),
+		)),
+	)
+
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if err != nil {
+			return errors.Wrap(err, "couldn't shut down the tracer provider")
+		}
+
+		return closeExporter()
+	}, nil
+}
+
+// newExporter returns the exporter of the config and a function closing its output
+func newExporter(ctx context.Context, c config.TracingConfig) (sdktrace.SpanExporter, func() error, error) {
+	noop := func() error { return nil }
+
+	switch c.Exporter {
+	case config.TracingExporterOTLP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(c.OTLPEndpoint)}
+		if c.OTLPInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+
+		exporter, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "couldn't create the OTLP exporter")
+		}
+
+		return exporter, noop, nil
+
+	case config.TracingExporterFile:
+		f, err := os.OpenFile(c.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "couldn't open the traces file")
+		}
+
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			_ = f.Close()
+
+			return nil, nil, errors.Wrap(err, "couldn't create the file exporter")
+		}
+
+		return exporter, f.Close, nil
+
+	default:
+		exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "couldn't create the stdout exporter")
+		}
+
+		return exporter, noop, nil
+	}
+}
+
+// Start starts a span of the app, it is a child of the span of the context.
+func Start(
+	ctx context.Context, name string, kind trace.SpanKind, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+}
+
+// End records the error of the operation, if any, and ends the span.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// LogFields returns the ids of the trace and the span of the context, so that the log lines can be found
+// next to the spans; nothing when the context has no span.
+func LogFields(ctx context.Context) []zap.Field {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+
+	return []zap.Field{
+		zap.String("traceID", sc.TraceID().String()),
+		zap.String("spanID", sc.SpanID().String()),
+	}
+}
@@ -1,72 +0,0 @@
-package utils
-
-import (
-	"os"
-	"strconv"
-	"strings"
-
-	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
-)
-
-// TokenMetadata struct to describe metadata in JWT.
-type TokenMetadata struct {
-	UserID  string
-	Expires int64
-}
-
-// ExtractTokenMetadata func to extract metadata from JWT.
-func ExtractTokenMetadata(c *fiber.Ctx) (*TokenMetadata, error) {
-	token, err := verifyToken(c)
-	if err != nil {
-		return nil, err
-	}
-
-	// Setting and checking token and credentials.
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if ok && token.Valid {
-		// User ID
-		userID := claims["user_id"].(string)
-
-		// Expires time.
-		expires := int64(claims["exp"].(float64))
-
-		return &TokenMetadata{
-			UserID:  userID,
-			Expires: expires,
-		}, nil
-	}
-
-	return nil, err
-}
-
-func extractToken(c *fiber.Ctx) string {
-	bearToken := c.Get("Users")
-
-	onlyToken := strings.Split(bearToken, " ")
-	if len(onlyToken) == 2 {
-		return onlyToken[1]
-	}
-
-	return ""
-}
-
-func verifyToken(c *fiber.Ctx) (*jwt.Token, error) {
-	tokenString := extractToken(c)
-
-	token, err := jwt.Parse(tokenString, jwtKeyFunc)
-	if err != nil {
-		return nil, err
-	}
-
-	return token, nil
-}
-
-func jwtKeyFunc(token *jwt.Token) (interface{}, error) {
-	return []byte(os.Getenv("JWT_ACCESS_TOKEN_SECRET_KEY")), nil
-}
-
-// ParseRefreshToken func for parse second argument from refresh token.
-func ParseRefreshToken(refreshToken string) (int64, error) {
-	return strconv.ParseInt(strings.Split(refreshToken, ".")[1], 0, 64)
-}
@@ -0,0 +1,144 @@
+package validation
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"acsp/internal/constants"
+)
+
+const (
+	English = "en"
+	Russian = "ru"
+	Kazakh  = "kk"
+)
+
+// Languages are the languages of the messages, the first one is the default.
+var Languages = []string{English, Russian, Kazakh}
+
+// Language picks the language of the messages for an Accept-Language header: the supported language
+// with the highest weight, regional variants like ru-RU match their language. English is the default.
+func Language(acceptLanguage string) string {
+	best, bestWeight := English, 0.0
+
+	for _, spec := range strings.Split(acceptLanguage, ",") {
+		tag, weight := parseLanguageSpec(spec)
+
+		// Strictly greater, the first of the languages of the same weight is preferred
+		if weight <= bestWeight {
+			continue
+		}
+
+		for _, language := range Languages {
+			if tag == language {
+				best, bestWeight = language, weight
+
+				break
+			}
+		}
+	}
+
+	return best
+}
+
+// parseLanguageSpec parses a spec like ru-RU;q=0.9 into its primary language and weight
+func parseLanguageSpec(spec string) (string, float64) {
+	tag, params, _ := strings.Cut(strings.TrimSpace(spec), ";")
+
+	tag, _, _ = strings.Cut(tag, "-")
+	tag = strings.ToLower(strings.TrimSpace(tag))
+
+	weight := 1.0
+	for _, param := range strings.Split(params, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || key != "q" {
+			continue
+		}
+
+		q, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return tag, 0
+		}
+
+		weight = q
+	}
+
+	return tag, weight
+}
+
+// fallbackCode is the message of the rules without their own messages
+const fallbackCode = ""
+
+// messages of the codes per language, {field} and {param} are replaced by the field and the parameter of the rule
+var messages = map[string]map[string]string{
+	English: {
+		"required":   "{field} is required",
+		"email":      "{field} must be a valid email address",
+		"endswith":   "{field} must end with {param}",
+		"min":        "{field} must be at least {param}",
+		"max":        "{field} must be at most {param}",
+		"oneof":      "{field} must be one of: {param}",
+		"date":       "{field} must be a date, e.g. 2023-06-03 or 2023-06-03 14:30:00",
+		"dateafter":  "{field} must be after {param}",
+		"httpurl":    "{field} must be an http or https URL",
+		"level":      "{field} must be one of: {param}",
+		"skills":     "{field} must have 1 to {param} different skills of at most " + strconv.Itoa(maxSkillLength) + " characters",
+		fallbackCode: "{field} is invalid",
+	},
+	Russian: {
+		"required":   "Поле {field} обязательно",
+		"email":      "{field} должен быть корректным адресом электронной почты",
+		"endswith":   "{field} должен оканчиваться на {param}",
+		"min":        "{field} должен быть не меньше {param}",
+		"max":        "{field} должен быть не больше {param}",
+		"oneof":      "{field} должен быть одним из: {param}",
+		"date":       "{field} должен быть датой, например 2023-06-03 или 2023-06-03 14:30:00",
+		"dateafter":  "{field} должен быть позже {param}",
+		"httpurl":    "{field} должен быть ссылкой http или https",
+		"level":      "{field} должен быть одним из: {param}",
+		"skills":     "{field} должен содержать от 1 до {param} разных навыков не длиннее " + strconv.Itoa(maxSkillLength) + " символов",
+		fallbackCode: "Поле {field} заполнено неверно",
+	},
+	Kazakh: {
+		"required":   "{field} өрісі міндетті",
+		"email":      "{field} жарамды электрондық пошта мекенжайы болуы керек",
+		"endswith":   "{field} {param} деп аяқталуы керек",
+		"min":        "{field} кемінде {param} болуы керек",
+		"max":        "{field} ең көбі {param} болуы керек",
+		"oneof":      "{field} мына мәндердің бірі болуы керек: {param}",
+		"date":       "{field} күн болуы керек, мысалы 2023-06-03 немесе 2023-06-03 14:30:00",
+		"dateafter":  "{field} {param} кейін болуы керек",
+		"httpurl":    "{field} http немесе https сілтемесі болуы керек",
+		"level":      "{field} мына мәндердің бірі болуы керек: {param}",
+		"skills":     "{field} ұзындығы " + strconv.Itoa(maxSkillLength) + " таңбадан аспайтын 1-ден {param}-ға дейін әртүрлі дағды болуы керек",
+		fallbackCode: "{field} өрісі жарамсыз",
+	},
+}
+
+// params of the custom rules that don't take one in their tags
+var params = map[string]string{
+	"level":  strings.Join(constants.ProjectLevels, ", "),
+	"skills": strconv.Itoa(maxSkills),
+}
+
+// message of the field error in the language, English is used for the other languages
+func message(language string, fieldErr validator.FieldError) string {
+	catalog, ok := messages[language]
+	if !ok {
+		catalog = messages[English]
+	}
+
+	template, ok := catalog[fieldErr.Tag()]
+	if !ok {
+		template = catalog[fallbackCode]
+	}
+
+	param := fieldErr.Param()
+	if p, ok := params[fieldErr.Tag()]; ok {
+		param = p
+	}
+
+	return strings.NewReplacer("{field}", fieldName(fieldErr), "{param}", param).Replace(template)
+}
@@ -0,0 +1,114 @@
+package validation
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"acsp/internal/constants"
+)
+
+const (
+	maxSkills      = 20
+	maxSkillLength = 50
+)
+
+// dateLayouts are the layouts of the dates of the requests
+var dateLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"}
+
+// rules are the custom rules, they are used by their tags like the built-in ones
+var rules = map[string]validator.Func{
+	// date is a date of one of the dateLayouts
+	"date": func(fl validator.FieldLevel) bool {
+		_, ok := parseDate(fl.Field().String())
+
+		return ok
+	},
+	// dateafter=start_date is a date after the date of the start_date field of the same struct
+	"dateafter": func(fl validator.FieldLevel) bool {
+		date, ok := parseDate(fl.Field().String())
+		if !ok {
+			return false
+		}
+
+		other, found := fieldByJSONName(fl.Parent(), fl.Param())
+		if !found {
+			return false
+		}
+
+		// The other date is reported by its own rules
+		otherDate, ok := parseDate(other.String())
+
+		return !ok || date.After(otherDate)
+	},
+	// httpurl is an absolute http or https URL
+	"httpurl": func(fl validator.FieldLevel) bool {
+		u, err := url.Parse(fl.Field().String())
+
+		return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+	},
+	// level is one of the levels of the projects
+	"level": func(fl validator.FieldLevel) bool {
+		for _, level := range constants.ProjectLevels {
+			if fl.Field().String() == level {
+				return true
+			}
+		}
+
+		return false
+	},
+	// skills are 1 to maxSkills different skills, none of them is blank or longer than maxSkillLength
+	"skills": func(fl validator.FieldLevel) bool {
+		field := fl.Field()
+		if field.Kind() != reflect.Slice || field.Len() == 0 || field.Len() > maxSkills {
+			return false
+		}
+
+		seen := make(map[string]struct{}, field.Len())
+		for i := 0; i < field.Len(); i++ {
+			skill := strings.ToLower(strings.TrimSpace(field.Index(i).String()))
+			if skill == "" || len([]rune(skill)) > maxSkillLength {
+				return false
+			}
+
+			if _, ok := seen[skill]; ok {
+				return false
+			}
+
+			seen[skill] = struct{}{}
+		}
+
+		return true
+	},
+}
+
+func parseDate(value string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func fieldByJSONName(parent reflect.Value, name string) (reflect.Value, bool) {
+	for parent.Kind() == reflect.Ptr {
+		parent = parent.Elem()
+	}
+
+	if parent.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	for i := 0; i < parent.NumField(); i++ {
+		if jsonName(parent.Type().Field(i)) == name {
+			return parent.Field(i), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
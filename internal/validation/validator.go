@@ -1,21 +1,76 @@
 package validation
 
 import (
-	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
+
+	"acsp/internal/apperror"
 )
 
-func ValidatorErrors(err error) map[string]string {
-	fields := map[string]string{}
+// validate is safe for concurrent use and caches the rules of the structs, so it is shared
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+
+	// The fields are named as in the requests
+	v.RegisterTagNameFunc(jsonName)
+
+	for tag, rule := range rules {
+		if err := v.RegisterValidation(tag, rule); err != nil {
+			panic(errors.Wrapf(err, "error when registering the %s rule", tag))
+		}
+	}
 
-	for _, err := range err.(validator.ValidationErrors) {
-		structName := strings.Split(err.Namespace(), ".")[0]
+	return v
+}
+
+// Struct validates the fields of s. An invalid s is reported as an *apperror.ValidationError with
+// a code and a message in the language per invalid field, see Languages.
+func Struct(s interface{}, language string) error {
+	err := validate.Struct(s)
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		// nil, or s isn't a struct
+		return err
+	}
 
-		fields[err.Field()] = fmt.Sprintf("failed '%s' tag check (value '%s' is not valid for %s struct)",
-			err.Tag(), err.Value(), structName)
+	fields := make([]apperror.FieldError, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		fields = append(fields, apperror.FieldError{
+			Field:   fieldName(fieldErr),
+			Code:    fieldErr.Tag(),
+			Message: message(language, fieldErr),
+		})
 	}
 
-	return fields
+	return apperror.NewValidationError(fields...)
+}
+
+// fieldName is the path of the field without the name of the struct, e.g. rate_limit.max or roles[0]
+func fieldName(fieldErr validator.FieldError) string {
+	namespace := fieldErr.Namespace()
+	if i := strings.IndexByte(namespace, '.'); i >= 0 {
+		return namespace[i+1:]
+	}
+
+	return namespace
+}
+
+// jsonName is the name of the field in JSON, the name of the field is used when it has no json tag
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	default:
+		return name
+	}
 }
@@ -0,0 +1,125 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"acsp/internal/apperror"
+	"acsp/internal/dto"
+)
+
+func fieldErrors(t *testing.T, err error) map[string]apperror.FieldError {
+	t.Helper()
+
+	var validationErr *apperror.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+
+	fields := make(map[string]apperror.FieldError, len(validationErr.Fields))
+	for _, field := range validationErr.Fields {
+		fields[field.Field] = field
+	}
+
+	return fields
+}
+
+func TestStructValid(t *testing.T) {
+	assert.NoError(t, Struct(dto.CreateContest{
+		Name:        "Weekly Contest 348",
+		Description: "Sponsored by LeetCode",
+		Link:        "https://leetcode.com/contest/weekly-contest-348/",
+		StartDate:   "2023-06-03 02:30:00",
+		EndDate:     "2023-06-03T04:30:00Z",
+	}, English))
+
+	assert.NoError(t, Struct(dto.CreateCard{
+		Position:    "Backend developer",
+		Skills:      []string{"Go", "PostgreSQL"},
+		Description: "Looking for a team",
+	}, English))
+}
+
+func TestStructInvalid(t *testing.T) {
+	fields := fieldErrors(t, Struct(dto.CreateContest{
+		Description: "Sponsored by LeetCode",
+		Link:        "ftp://leetcode.com",
+		StartDate:   "2023-06-03",
+		EndDate:     "2023-06-02",
+	}, English))
+
+	assert.Equal(t, apperror.FieldError{Field: "name", Code: "required", Message: "name is required"}, fields["name"])
+	assert.Equal(t, "httpurl", fields["link"].Code)
+	assert.Equal(t, apperror.FieldError{
+		Field: "end_date", Code: "dateafter", Message: "end_date must be after start_date",
+	}, fields["end_date"])
+	assert.NotContains(t, fields, "start_date")
+
+	fields = fieldErrors(t, Struct(dto.CreateProject{Title: "Chat", Description: "A chat", Level: "expert"}, English))
+	assert.Equal(t, "level", fields["level"].Code)
+	assert.Equal(t, "level must be one of: beginner, intermediate, advanced", fields["level"].Message)
+	assert.Equal(t, "min", fields["work_hours"].Code)
+
+	fields = fieldErrors(t, Struct(dto.CreateCard{
+		Position:    "Backend developer",
+		Skills:      []string{"Go", " go "},
+		Description: "Looking for a team",
+	}, English))
+	assert.Equal(t, "skills", fields["skills"].Code)
+
+	// The fields are named by their path in JSON
+	max := 0
+	fields = fieldErrors(t, Struct(dto.PatchSettings{
+		CORSOrigins: []string{""},
+		RateLimit:   &dto.PatchRateLimit{Max: &max},
+	}, English))
+	assert.Contains(t, fields, "cors_origins[0]")
+	assert.Contains(t, fields, "rate_limit.max")
+}
+
+func TestStructLanguages(t *testing.T) {
+	tests := []struct {
+		language string
+		want     string
+	}{
+		{English, "title is required"},
+		{Russian, "Поле title обязательно"},
+		{Kazakh, "title өрісі міндетті"},
+		{"de", "title is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			fields := fieldErrors(t, Struct(dto.CreateCourse{Description: "Go basics"}, tt.language))
+			assert.Equal(t, tt.want, fields["title"].Message)
+		})
+	}
+}
+
+func TestStructNotStruct(t *testing.T) {
+	err := Struct("not a struct", English)
+	require.Error(t, err)
+	assert.Equal(t, apperror.KindInternal, apperror.KindOf(err))
+}
+
+func TestLanguage(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{"", English},
+		{"ru", Russian},
+		{"ru-RU,ru;q=0.9,en;q=0.8", Russian},
+		{"en;q=0.5, kk", Kazakh},
+		{"de-DE,kk;q=0.3", Kazakh},
+		{"de, fr", English},
+		{"KK-kz", Kazakh},
+		{"ru;q=0", English},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.acceptLanguage, func(t *testing.T) {
+			assert.Equal(t, tt.want, Language(tt.acceptLanguage))
+		})
+	}
+}
@@ -0,0 +1 @@
+DROP TABLE stored_objects;
@@ -0,0 +1,10 @@
+CREATE TABLE stored_objects
+(
+    id         BIGSERIAL   NOT NULL PRIMARY KEY,
+    object_key VARCHAR     NOT NULL UNIQUE,
+    owner_type VARCHAR     NOT NULL,
+    owner_id   BIGINT      NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX stored_objects_owner_idx ON stored_objects (owner_type, owner_id);
@@ -0,0 +1,15 @@
+CREATE TABLE outbox_events
+(
+    id              BIGSERIAL   NOT NULL PRIMARY KEY,
+    event_type      VARCHAR     NOT NULL,
+    idempotency_key VARCHAR     NOT NULL UNIQUE,
+    payload         JSONB       NOT NULL DEFAULT ('{}'),
+    status          VARCHAR     NOT NULL DEFAULT ('PENDING'),
+    attempts        INT         NOT NULL DEFAULT 0,
+    last_error      VARCHAR     NOT NULL DEFAULT (''),
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT (now()),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT (now()),
+    processed_at    TIMESTAMPTZ
+);
+
+CREATE INDEX outbox_events_pending_idx ON outbox_events (next_attempt_at) WHERE status = 'PENDING';
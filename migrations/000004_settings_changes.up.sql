@@ -0,0 +1,8 @@
+CREATE TABLE settings_changes
+(
+    id         BIGSERIAL   NOT NULL PRIMARY KEY,
+    user_id    BIGINT      NOT NULL,
+    before     JSONB       NOT NULL,
+    after      JSONB       NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (now())
+);
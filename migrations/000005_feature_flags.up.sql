@@ -0,0 +1,16 @@
+CREATE TABLE feature_flags
+(
+    key         VARCHAR(100) NOT NULL PRIMARY KEY,
+    description TEXT         NOT NULL DEFAULT '',
+    enabled     BOOLEAN      NOT NULL DEFAULT false,
+    roles       TEXT[]       NOT NULL DEFAULT '{}',
+    user_ids    BIGINT[]     NOT NULL DEFAULT '{}',
+    percentage  SMALLINT     NOT NULL DEFAULT 0 CHECK (percentage BETWEEN 0 AND 100),
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT (now()),
+    updated_at  TIMESTAMPTZ  NOT NULL DEFAULT (now())
+);
+
+-- The sections gated by the flags stay available to everyone after the upgrade
+INSERT INTO feature_flags (key, description, enabled, percentage)
+VALUES ('code-connection', 'Search for teammates', true, 100),
+       ('coding-lab', 'Project-based learning of the disciplines', true, 100);
@@ -0,0 +1,10 @@
+CREATE TABLE account_lockouts
+(
+    id           BIGSERIAL   NOT NULL PRIMARY KEY,
+    user_id      BIGINT,
+    email        TEXT        NOT NULL,
+    ip           TEXT        NOT NULL,
+    failures     INT         NOT NULL,
+    locked_until TIMESTAMPTZ NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT (now())
+);
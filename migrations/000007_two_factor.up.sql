@@ -0,0 +1,19 @@
+CREATE TABLE user_totp
+(
+    user_id    BIGINT      NOT NULL PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    secret     TEXT        NOT NULL,
+    enabled    BOOLEAN     NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (now()),
+    enabled_at TIMESTAMPTZ
+);
+
+CREATE TABLE mfa_recovery_codes
+(
+    id         BIGSERIAL   NOT NULL PRIMARY KEY,
+    user_id    BIGINT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash  TEXT        NOT NULL,
+    used_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX mfa_recovery_codes_user_id_idx ON mfa_recovery_codes (user_id);
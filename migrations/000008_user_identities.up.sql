@@ -0,0 +1,10 @@
+CREATE TABLE user_identities
+(
+    id         BIGSERIAL   NOT NULL PRIMARY KEY,
+    user_id    BIGINT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    issuer     TEXT        NOT NULL,
+    subject    TEXT        NOT NULL,
+    email      TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (now()),
+    UNIQUE (issuer, subject)
+);
@@ -0,0 +1,14 @@
+CREATE TABLE personal_access_tokens
+(
+    id           BIGSERIAL    NOT NULL PRIMARY KEY,
+    user_id      BIGINT       NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name         VARCHAR(100) NOT NULL,
+    token_hash   TEXT         NOT NULL UNIQUE,
+    hint         VARCHAR(8)   NOT NULL,
+    scopes       TEXT[]       NOT NULL,
+    expires_at   TIMESTAMPTZ  NOT NULL,
+    last_used_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX personal_access_tokens_user_id_idx ON personal_access_tokens (user_id);
@@ -0,0 +1,18 @@
+-- The actor is not a foreign key, the events outlive the deleted users
+CREATE TABLE audit_events
+(
+    id             BIGSERIAL   NOT NULL PRIMARY KEY,
+    actor_id       BIGINT,
+    actor_token_id BIGINT,
+    action         VARCHAR(50) NOT NULL,
+    target_type    VARCHAR(50) NOT NULL,
+    target_id      TEXT        NOT NULL DEFAULT '',
+    changes        JSONB       NOT NULL DEFAULT '{}',
+    ip             TEXT        NOT NULL DEFAULT '',
+    request_id     TEXT        NOT NULL DEFAULT '',
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX audit_events_actor_id_idx ON audit_events (actor_id);
+CREATE INDEX audit_events_target_idx ON audit_events (target_type, target_id);
+CREATE INDEX audit_events_created_at_idx ON audit_events (created_at);
@@ -0,0 +1,15 @@
+-- The notifications are written by the outbox worker, the idempotency key keeps a retried event from
+-- notifying twice
+CREATE TABLE notifications
+(
+    id              BIGSERIAL   NOT NULL PRIMARY KEY,
+    user_id         INT         NOT NULL,
+    kind            VARCHAR(50) NOT NULL,
+    payload         JSONB       NOT NULL DEFAULT '{}',
+    idempotency_key VARCHAR     NOT NULL UNIQUE,
+    read_at         TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT (now()),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX notifications_user_id_idx ON notifications (user_id, id DESC);
@@ -0,0 +1,3 @@
+-- The table keeps its new name: the down script of 000001_init, which is already released, drops it as
+-- scholar_article_comments. Applying the up script again does nothing
+ALTER TABLE IF EXISTS scholar_comments RENAME TO scholar_article_comments;
@@ -0,0 +1,3 @@
+-- 000001_init created the comments of the articles as scholar_comments, but its down script and the code name
+-- them scholar_article_comments like scholar_article_comment_votes
+ALTER TABLE IF EXISTS scholar_comments RENAME TO scholar_article_comments;
@@ -0,0 +1,9 @@
+// Package migrations embeds the SQL migrations into the binary, they are applied by the migrate command.
+package migrations
+
+import "embed"
+
+// FS holds the NNNNNN_name.up.sql and NNNNNN_name.down.sql files of the migrations.
+//
+//go:embed *.sql
+var FS embed.FS